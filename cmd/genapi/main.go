@@ -1,14 +1,18 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"go/ast"
 	"go/parser"
+	"go/printer"
 	"go/token"
 	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"runtime"
+	"sort"
 	"strings"
 	"text/template"
 )
@@ -28,6 +32,20 @@ type QueryInfo struct {
 	Params      []ParamInfo
 	ReturnType  string
 	SQLComment  string
+	ElemType    string      // row type of a :many query, e.g. Post
+	Fields      []FieldInfo // columns of ElemType, when it is a known struct
+	Stream      bool        // :many query annotated with "-- genapi:stream"
+	StreamInfo  StreamInfo
+
+	IDType        string      // Go type of the {id} path parameter
+	IDField       string      // field of ParamsType bound to {id}
+	ParamsType    string      // sqlc *Params struct taken by update queries
+	RequestFields []FieldInfo // ParamsType fields decoded from the request body
+
+	// Getter is the GET /{id} service method used to check If-Match before
+	// PUT, PATCH and DELETE; empty when the feature has none.
+	Getter         string
+	GetterElemType string
 }
 
 type ParamInfo struct {
@@ -35,12 +53,68 @@ type ParamInfo struct {
 	Type string
 }
 
+// StreamInfo holds the pieces of a sqlc :many function needed to re-run the
+// same query row by row instead of collecting a slice.
+type StreamInfo struct {
+	Params    string // parameters after ctx, as declared by sqlc
+	QueryArgs string // arguments passed to q.db.Query
+	ScanArgs  string // arguments passed to rows.Scan
+	Imports   []string
+}
+
+// ModelInfo describes a struct emitted by sqlc (a table model or a *Row type).
+type ModelInfo struct {
+	Name        string
+	Fields      []FieldInfo
+	XMLName     string // element name of one row, e.g. post
+	XMLListName string // element name of a list of rows, e.g. posts
+
+	VersionField   string // integer "version" column used for the ETag
+	UpdatedAtField string // non-null "updated_at" column used for Last-Modified
+}
+
+type FieldInfo struct {
+	Name         string
+	Type         string
+	JSONName     string
+	ResponseType string // type of the field in the generated response model
+	Convert      string // expression converting row.<Name> to ResponseType
+}
+
+// responseFieldTypes maps nullable pgtype columns onto plain pointer types so
+// the generated response models encode the same way as JSON, XML and CSV.
+// Anything not listed is copied across unchanged.
+var responseFieldTypes = map[string]struct{ Type, Convert string }{
+	"pgtype.Text":        {"*string", "render.Nullable(%[1]s.String, %[1]s.Valid)"},
+	"pgtype.Bool":        {"*bool", "render.Nullable(%[1]s.Bool, %[1]s.Valid)"},
+	"pgtype.Int2":        {"*int16", "render.Nullable(%[1]s.Int16, %[1]s.Valid)"},
+	"pgtype.Int4":        {"*int32", "render.Nullable(%[1]s.Int32, %[1]s.Valid)"},
+	"pgtype.Int8":        {"*int64", "render.Nullable(%[1]s.Int64, %[1]s.Valid)"},
+	"pgtype.Float4":      {"*float32", "render.Nullable(%[1]s.Float32, %[1]s.Valid)"},
+	"pgtype.Float8":      {"*float64", "render.Nullable(%[1]s.Float64, %[1]s.Valid)"},
+	"pgtype.Timestamp":   {"*time.Time", "render.Nullable(%[1]s.Time, %[1]s.Valid)"},
+	"pgtype.Timestamptz": {"*time.Time", "render.Nullable(%[1]s.Time, %[1]s.Valid)"},
+	"pgtype.Date":        {"*render.Date", "render.NullableDate(%[1]s.Time, %[1]s.Valid)"},
+}
+
+// modelImports lists the packages a generated response model may refer to.
+var modelImports = map[string]string{
+	"uuid.":   "github.com/google/uuid",
+	"time.":   "time",
+	"pgtype.": "github.com/jackc/pgx/v5/pgtype",
+	"netip.":  "net/netip",
+}
+
 type APIGenerationData struct {
 	Feature        string
 	Package        string
 	Queries        []QueryInfo
 	Imports        []string
 	HasHealthCheck bool
+	HasStreams     bool
+	Models         []ModelInfo // row types returned by Queries, deduplicated
+	ModelImports   []string
+	HasConnect     bool // a .proto and Connect server were generated
 }
 
 func main() {
@@ -86,11 +160,15 @@ func printSuccess(feature string) {
 	fmt.Printf("   📄 internal\\generated\\api\\%s\\handlers.go    - HTTP handlers with Swagger docs\n", feature)
 	fmt.Printf("   📄 internal\\generated\\api\\%s\\service.go     - Business logic layer\n", feature)
 	fmt.Printf("   📄 internal\\generated\\api\\%s\\router.go      - Chi router configuration\n", feature)
+	fmt.Printf("   📄 internal\\generated\\api\\%s\\models.go      - JSON/XML/CSV response models\n", feature)
+	fmt.Printf("   📄 internal\\generated\\api\\%s\\connect.go     - gRPC/Connect server\n", feature)
+	fmt.Printf("   📄 proto\\civilregistry\\%s\\v1\\%s.proto   - Protobuf service definition\n", strings.ToLower(feature), strings.ToLower(feature))
 	fmt.Println("")
 	fmt.Println("🎯 Next steps:")
 	fmt.Printf("   1. Add router to main router: %s.%sRouter(queries, log)\n", feature, strings.Title(feature))
-	fmt.Printf("   2. Run: task dev\n")
-	fmt.Printf("   3. Test: curl http://localhost:8080/api/%s/health\n", feature)
+	fmt.Printf("   2. Run: task proto (generates the protobuf Go code for connect.go)\n")
+	fmt.Printf("   3. Run: task dev\n")
+	fmt.Printf("   4. Test: curl http://localhost:8080/api/%s/health\n", feature)
 	fmt.Println("")
 	if runtime.GOOS == "windows" {
 		fmt.Println("💡 Windows Tip: Use PowerShell or Windows Terminal for best experience!")
@@ -113,6 +191,40 @@ func (g *Generator) Generate() error {
 		return nil
 	}
 
+	models, err := g.parseModels()
+	if err != nil {
+		return fmt.Errorf("failed to parse models: %w", err)
+	}
+
+	// Response models are built from the sqlc structs, so only queries
+	// returning one of those can be exposed
+	var supported []QueryInfo
+	for _, q := range queries {
+		model, ok := models[q.ElemType]
+		if !ok && !(q.HTTPMethod == "DELETE" && q.ReturnType == "error") {
+			fmt.Printf("⚠️  Skipping %s: result type %s is not a sqlc model\n", q.Name, q.ReturnType)
+			continue
+		}
+		q.Fields = model.Fields
+		g.resolveParams(&q, models)
+		supported = append(supported, q)
+	}
+	queries = supported
+
+	// PUT/PATCH/DELETE check If-Match against the row returned by GET /{id}
+	for i, q := range queries {
+		if !isWrite(q.HTTPMethod) {
+			continue
+		}
+		for _, getter := range queries {
+			if getter.HTTPMethod == "GET" && getter.URLPath == "/{id}" && getter.IDType == q.IDType {
+				queries[i].Getter = getter.ServiceName
+				queries[i].GetterElemType = getter.ElemType
+				break
+			}
+		}
+	}
+
 	fmt.Printf("📊 Found %d queries for feature '%s'\n", len(queries), g.Feature)
 	for _, q := range queries {
 		fmt.Printf("   🔹 %s %s → %s\n", q.HTTPMethod, q.URLPath, q.Name)
@@ -133,7 +245,27 @@ func (g *Generator) Generate() error {
 		HasHealthCheck: g.needsHealthCheck(queries),
 	}
 
+	seenModels := map[string]bool{}
+	for _, q := range queries {
+		if q.Stream {
+			data.HasStreams = true
+		}
+		if _, ok := models[q.ElemType]; ok && !seenModels[q.ElemType] {
+			seenModels[q.ElemType] = true
+			data.Models = append(data.Models, g.responseModel(models[q.ElemType]))
+		}
+	}
+	data.ModelImports = g.modelImports(data.Models)
+
 	// Generate files
+	if err := g.generateStreams(data); err != nil {
+		return fmt.Errorf("failed to generate streams: %w", err)
+	}
+
+	if err := g.generateModels(data); err != nil {
+		return fmt.Errorf("failed to generate models: %w", err)
+	}
+
 	if err := g.generateHandlers(data); err != nil {
 		return fmt.Errorf("failed to generate handlers: %w", err)
 	}
@@ -142,6 +274,12 @@ func (g *Generator) Generate() error {
 		return fmt.Errorf("failed to generate service: %w", err)
 	}
 
+	hasConnect, err := g.generateConnect(data)
+	if err != nil {
+		return fmt.Errorf("failed to generate gRPC service: %w", err)
+	}
+	data.HasConnect = hasConnect
+
 	if err := g.generateRouter(data); err != nil {
 		return fmt.Errorf("failed to generate router: %w", err)
 	}
@@ -215,6 +353,9 @@ func (g *Generator) parseRepositoryFile(filename string) ([]QueryInfo, error) {
 				if recv, ok := fn.Recv.List[0].Type.(*ast.StarExpr); ok {
 					if ident, ok := recv.X.(*ast.Ident); ok && ident.Name == "Queries" {
 						query := g.parseQueryFunction(fn)
+						if query.Stream {
+							query.StreamInfo = g.parseStreamInfo(fset, node, fn)
+						}
 						if query.Name != "" {
 							queries = append(queries, query)
 						}
@@ -292,10 +433,214 @@ func (g *Generator) parseQueryFunction(fn *ast.FuncDecl) QueryInfo {
 			query.ReturnType = returnType
 		}
 	}
+	query.ElemType = strings.TrimPrefix(query.ReturnType, "[]")
+
+	// sqlc copies comments written under "-- name:" into the doc comment
+	if fn.Doc != nil {
+		query.SQLComment = fn.Doc.Text()
+	}
+	query.Stream = query.Type == ":many" && strings.HasPrefix(query.ReturnType, "[]") &&
+		strings.Contains(query.SQLComment, "genapi:stream")
 
 	return query
 }
 
+// parseStreamInfo extracts the SQL constant, query arguments and scan targets
+// from a sqlc :many function so the same query can be iterated row by row.
+func (g *Generator) parseStreamInfo(fset *token.FileSet, file *ast.File, fn *ast.FuncDecl) StreamInfo {
+	var info StreamInfo
+
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		switch sel.Sel.Name {
+		case "Query":
+			// q.db.Query(ctx, getPublicPosts, args...) - drop ctx
+			if len(call.Args) > 1 {
+				info.QueryArgs = g.printExprs(fset, call.Args[1:])
+			}
+		case "Scan":
+			info.ScanArgs = g.printExprs(fset, call.Args)
+		}
+		return true
+	})
+
+	var params []string
+	usedPackages := map[string]bool{}
+	for _, param := range fn.Type.Params.List[1:] {
+		for _, name := range param.Names {
+			params = append(params, name.Name+" "+g.printExprs(fset, []ast.Expr{param.Type}))
+		}
+		ast.Inspect(param.Type, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if ident, ok := sel.X.(*ast.Ident); ok {
+					usedPackages[ident.Name] = true
+				}
+			}
+			return true
+		})
+	}
+	info.Params = strings.Join(params, ", ")
+
+	for _, imp := range file.Imports {
+		path := strings.Trim(imp.Path.Value, "\"")
+		name := filepath.Base(path)
+		if imp.Name != nil {
+			name = imp.Name.Name
+		}
+		if usedPackages[name] {
+			info.Imports = append(info.Imports, path)
+		}
+	}
+
+	return info
+}
+
+func (g *Generator) printExprs(fset *token.FileSet, exprs []ast.Expr) string {
+	parts := make([]string, 0, len(exprs))
+	for _, expr := range exprs {
+		var buf bytes.Buffer
+		printer.Fprint(&buf, fset, expr)
+		parts = append(parts, buf.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// parseModels collects every struct sqlc generated in the repository package,
+// keyed by type name.
+func (g *Generator) parseModels() (map[string]ModelInfo, error) {
+	models := map[string]ModelInfo{}
+
+	repoDir := filepath.Join("internal", "generated", "repository")
+	files, err := filepath.Glob(filepath.Join(repoDir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, file := range files {
+		fset := token.NewFileSet()
+		node, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, decl := range node.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				typeSpec := spec.(*ast.TypeSpec)
+				structType, ok := typeSpec.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+
+				model := ModelInfo{Name: typeSpec.Name.Name}
+				for _, field := range structType.Fields.List {
+					jsonName := ""
+					if field.Tag != nil {
+						tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`"))
+						jsonName = strings.Split(tag.Get("json"), ",")[0]
+					}
+					for _, name := range field.Names {
+						fieldJSON := jsonName
+						if fieldJSON == "" {
+							fieldJSON = toSnakeCase(name.Name)
+						}
+						model.Fields = append(model.Fields, FieldInfo{
+							Name:     name.Name,
+							Type:     g.typeToString(field.Type),
+							JSONName: fieldJSON,
+						})
+					}
+				}
+				models[model.Name] = model
+			}
+		}
+	}
+
+	return models, nil
+}
+
+// resolveParams works out how the {id} path parameter and request body map
+// onto the arguments of the sqlc function.
+func (g *Generator) resolveParams(q *QueryInfo, models map[string]ModelInfo) {
+	if len(q.Params) == 0 {
+		return
+	}
+
+	first := q.Params[0]
+	params, ok := models[first.Type]
+	if !ok {
+		// A plain argument, e.g. GetPostByID(ctx, id uuid.UUID)
+		q.IDType = first.Type
+		return
+	}
+
+	q.ParamsType = params.Name
+	for _, field := range params.Fields {
+		if q.IDField == "" && field.Name == "ID" && strings.Contains(q.URLPath, "{id}") {
+			q.IDField = field.Name
+			q.IDType = field.Type
+			continue
+		}
+		q.RequestFields = append(q.RequestFields, field)
+	}
+}
+
+// responseModel fills in the XML element names and response field types for
+// a sqlc model.
+func (g *Generator) responseModel(model ModelInfo) ModelInfo {
+	model.XMLName = toSnakeCase(model.Name)
+	model.XMLListName = model.XMLName + "s"
+
+	fields := make([]FieldInfo, len(model.Fields))
+	for i, field := range model.Fields {
+		field.ResponseType = field.Type
+		field.Convert = "row." + field.Name
+		if mapped, ok := responseFieldTypes[field.Type]; ok {
+			field.ResponseType = mapped.Type
+			field.Convert = fmt.Sprintf(mapped.Convert, "row."+field.Name)
+		}
+		fields[i] = field
+
+		switch {
+		case field.JSONName == "version" && strings.HasPrefix(field.ResponseType, "int"):
+			model.VersionField = field.Name
+		case field.JSONName == "updated_at" && field.ResponseType == "time.Time":
+			model.UpdatedAtField = field.Name
+		}
+	}
+	model.Fields = fields
+
+	return model
+}
+
+func (g *Generator) modelImports(models []ModelInfo) []string {
+	// time is always needed for LastModified
+	seen := map[string]bool{"time": true}
+	imports := []string{"time"}
+	for _, model := range models {
+		for _, field := range model.Fields {
+			for prefix, path := range modelImports {
+				if strings.Contains(field.ResponseType, prefix) && !seen[path] {
+					seen[path] = true
+					imports = append(imports, path)
+				}
+			}
+		}
+	}
+	sort.Strings(imports)
+	return imports
+}
+
 func (g *Generator) typeToString(expr ast.Expr) string {
 	switch t := expr.(type) {
 	case *ast.Ident:
@@ -365,36 +710,57 @@ func (g *Generator) generateHandlers(data APIGenerationData) error {
 	// Analyze what imports are actually needed
 	needsStrconv := false
 	needsUUID := false
+	needsRepository := data.HasStreams
+	imports := map[string]bool{}
 
 	for _, q := range data.Queries {
 		if strings.Contains(q.URLPath, "{id}") {
-			// Check if any query uses non-UUID parameters (would need strconv)
-			hasNonUUID := false
-			for _, param := range q.Params {
-				if !strings.Contains(param.Type, "uuid") && param.Name != "ctx" {
-					hasNonUUID = true
-					break
-				}
-			}
-			if hasNonUUID {
-				needsStrconv = true
-			} else {
+			if q.IDType == "uuid.UUID" {
 				needsUUID = true
+			} else {
+				needsStrconv = true
 			}
 		}
+		if q.RequestFields != nil && isWrite(q.HTTPMethod) {
+			needsRepository = true
+			for _, field := range q.RequestFields {
+				for prefix, path := range modelImports {
+					if strings.Contains(field.Type, prefix) {
+						imports[path] = true
+					}
+				}
+			}
+		}
+	}
+	if needsStrconv {
+		imports["strconv"] = true
+	}
+	if needsUUID {
+		imports["github.com/google/uuid"] = true
+	}
+	if needsRepository {
+		imports["github.com/eif-courses/civilregistry/internal/generated/repository"] = true
+	}
+	for _, path := range []string{
+		"encoding/json",
+		"errors",
+		"net/http",
+		"github.com/eif-courses/civilregistry/internal/render",
+		"github.com/go-chi/chi/v5",
+		"github.com/jackc/pgx/v5",
+		"go.uber.org/zap",
+	} {
+		imports[path] = true
 	}
 
 	tmpl := `// Code generated by genapi. DO NOT EDIT manually.
 package {{.Package}}
 
 import (
-	"encoding/json"
-	"net/http"
-	{{if .NeedsStrconv}}"strconv"{{end}}
-	
-	"github.com/go-chi/chi/v5"
-	{{if .NeedsUUID}}"github.com/google/uuid"{{end}}
-	"go.uber.org/zap"
+	{{range .HandlerImports}}{{if not (contains . ".")}}"{{.}}"
+	{{end}}{{end}}
+	{{range .HandlerImports}}{{if contains . "."}}"{{.}}"
+	{{end}}{{end -}}
 )
 
 type Handlers struct {
@@ -416,24 +782,46 @@ func NewHandlers(service *Service, logger *zap.SugaredLogger) *Handlers {
 // @Description Create a new {{$.Feature}} record
 // @Tags {{$.Feature}}
 // @Accept json
-// @Produce json
+// @Produce json,xml
 // @Param request body {{.HandlerName}}Request true "{{$.Feature}} data"
 // @Success 201 {object} map[string]interface{} "Created {{$.Feature}}"
 // @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/{{$.Feature}}{{.URLPath}} [{{.HTTPMethod | lower}}]
+func (h *Handlers) {{.HandlerName}}(w http.ResponseWriter, r *http.Request) {
+{{else if isWrite .HTTPMethod}}
+// {{.HandlerName}} {{if eq .HTTPMethod "DELETE"}}deletes{{else}}updates{{end}} a {{$.Feature}} by ID
+// @Summary {{if eq .HTTPMethod "DELETE"}}Delete{{else}}Update{{end}} {{$.Feature}}
+// @Description {{if eq .HTTPMethod "DELETE"}}Delete{{else}}Update{{end}} a specific {{$.Feature}}. Send If-Match with the ETag from a previous GET to guard against lost updates.
+// @Tags {{$.Feature}}
+// @Accept json
+// @Produce json,xml
+// @Param id path string true "{{$.Feature}} ID"
+// @Param If-Match header string false "ETag of the {{$.Feature}} being modified"
+{{if .RequestFields}}// @Param request body {{.HandlerName}}Request true "{{$.Feature}} data"
+{{end}}{{if eq .HTTPMethod "DELETE"}}// @Success 204 "{{$.Feature}} deleted"
+{{else}}// @Success 200 {object} map[string]interface{} "Updated {{$.Feature}}"
+{{end}}// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 404 {object} map[string]interface{} "{{$.Feature}} not found"
+// @Failure 412 {object} map[string]interface{} "If-Match does not match the current ETag"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/{{$.Feature}}{{.URLPath}} [{{.HTTPMethod | lower}}]
 func (h *Handlers) {{.HandlerName}}(w http.ResponseWriter, r *http.Request) {
 {{else if contains .URLPath "{id}"}}
 // {{.HandlerName}} retrieves a {{$.Feature}} by ID
 // @Summary Get {{$.Feature}} by ID
-// @Description Get a specific {{$.Feature}} by its ID
+// @Description Get a specific {{$.Feature}} by its ID. Supports If-None-Match and If-Modified-Since.
 // @Tags {{$.Feature}}
 // @Accept json
-// @Produce json
+// @Produce json,xml
 // @Param id path string true "{{$.Feature}} ID"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} map[string]interface{} "{{$.Feature}} found"
+// @Success 304 "Cached copy is still current"
 // @Failure 400 {object} map[string]interface{} "Invalid ID"
 // @Failure 404 {object} map[string]interface{} "{{$.Feature}} not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/{{$.Feature}}{{.URLPath}} [{{.HTTPMethod | lower}}]
 func (h *Handlers) {{.HandlerName}}(w http.ResponseWriter, r *http.Request) {
@@ -443,15 +831,28 @@ func (h *Handlers) {{.HandlerName}}(w http.ResponseWriter, r *http.Request) {
 // @Description Retrieve all {{$.Feature}} records
 // @Tags {{$.Feature}}
 // @Accept json
-// @Produce json
+// @Produce json,xml,text/csv{{if .Stream}},application/x-ndjson{{end}}
 // @Success 200 {object} map[string]interface{} "List of {{$.Feature}}s"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/{{$.Feature}}{{.URLPath}} [{{.HTTPMethod | lower}}]
 func (h *Handlers) {{.HandlerName}}(w http.ResponseWriter, r *http.Request) {
 {{end}}
+	{{if eq .Type ":many"}}
+	format := render.Negotiate(r, render.ContentTypeJSON, render.ContentTypeXML, render.ContentTypeCSV{{if .Stream}}, render.ContentTypeNDJSON{{end}})
+	{{else if ne .HTTPMethod "DELETE"}}
+	format := render.Negotiate(r, render.ContentTypeJSON, render.ContentTypeXML)
+	{{end -}}
+	{{if ne .HTTPMethod "DELETE" -}}
+	if format == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+	{{end}}
+
 	{{if contains .URLPath "{id}"}}
 	idParam := chi.URLParam(r, "id")
-	{{if contains (printf "%v" .Params) "uuid"}}
+	{{if eq .IDType "uuid.UUID"}}
 	id, err := uuid.Parse(idParam)
 	if err != nil {
 		h.logger.Errorf("Invalid UUID: %v", err)
@@ -468,6 +869,19 @@ func (h *Handlers) {{.HandlerName}}(w http.ResponseWriter, r *http.Request) {
 	{{end}}
 	{{end}}
 
+	{{if and (isWrite .HTTPMethod) .Getter}}
+	if r.Header.Get("If-Match") != "" {
+		current, err := h.service.{{.Getter}}(r.Context(), id)
+		if err != nil {
+			h.serviceError(w, err)
+			return
+		}
+		if render.PreconditionFailed(w, r, New{{.GetterElemType}}Response(*current).ETag(), {{if eq .HTTPMethod "DELETE"}}render.Negotiate(r, render.ContentTypeJSON, render.ContentTypeXML){{else}}format{{end}}) {
+			return
+		}
+	}
+	{{end}}
+
 	{{if eq .HTTPMethod "POST"}}
 	var req {{.HandlerName}}Request
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -477,44 +891,127 @@ func (h *Handlers) {{.HandlerName}}(w http.ResponseWriter, r *http.Request) {
 	}
 
 	result, err := h.service.{{.ServiceName}}(r.Context(), req.Title, req.Body)
+	{{else if eq .HTTPMethod "DELETE"}}
+	if err := h.service.{{.ServiceName}}(r.Context(), id); err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+{{else if isWrite .HTTPMethod}}
+	{{if .RequestFields}}
+	var req {{.HandlerName}}Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.{{.ServiceName}}(r.Context(), repository.{{.ParamsType}}{
+		{{.IDField}}: id,
+		{{range .RequestFields}}{{.Name}}: req.{{.Name}},
+		{{end}}
+	})
+	{{else}}
+	result, err := h.service.{{.ServiceName}}(r.Context(), id)
+	{{end}}
 	{{else if contains .URLPath "{id}"}}
 	result, err := h.service.{{.ServiceName}}(r.Context(), id)
 	{{else}}
+	{{if .Stream}}
+	switch format {
+	case render.ContentTypeNDJSON:
+		stream := render.NewNDJSONStream(w)
+		err := h.service.Stream{{.ServiceName}}(r.Context(), func(row repository.{{.ElemType}}) error {
+			return stream.Write(New{{.ElemType}}Response(row))
+		})
+		if err := render.FinishStream(w, stream, err); err != nil {
+			h.logger.Errorf("Stream error: %v", err)
+		}
+		return
+	case render.ContentTypeCSV:
+		stream := render.NewCSVStream(w, {{.ElemType | lowerFirst}}CSVHeader)
+		err := h.service.Stream{{.ServiceName}}(r.Context(), func(row repository.{{.ElemType}}) error {
+			return stream.Write(New{{.ElemType}}Response(row).CSVRecord())
+		})
+		if err := render.FinishStream(w, stream, err); err != nil {
+			h.logger.Errorf("Stream error: %v", err)
+		}
+		return
+	}
+	{{end}}
 	result, err := h.service.{{.ServiceName}}(r.Context())
 	{{end}}
+	{{if ne .HTTPMethod "DELETE"}}
 	if err != nil {
-		h.logger.Errorf("Service error: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.serviceError(w, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
 	{{if eq .HTTPMethod "POST"}}
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"message": "{{$.Feature}} created successfully",
-		"data": result,
+	err = render.Write(w, http.StatusCreated, format, {{.ElemType}}Envelope{
+		Message: "{{$.Feature}} created successfully",
+		Data:    New{{.ElemType}}Response(*result),
+	})
+	{{else if isWrite .HTTPMethod}}
+	resp := New{{.ElemType}}Response(*result)
+	w.Header().Set("ETag", render.RepresentationETag(resp.ETag(), format))
+	err = render.Write(w, http.StatusOK, format, {{.ElemType}}Envelope{
+		Message: "{{$.Feature}} updated successfully",
+		Data:    resp,
 	})
 	{{else if eq .Type ":many"}}
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"data":  result,
-		"count": len(result),
+	{{if not .Stream}}
+	if format == render.ContentTypeCSV {
+		if err := render.WriteCSV(w, http.StatusOK, {{.ElemType | lowerFirst}}CSVHeader, New{{.ElemType}}Responses(result)); err != nil {
+			h.logger.Errorf("Failed to write response: %v", err)
+		}
+		return
+	}
+	{{end}}
+	err = render.Write(w, http.StatusOK, format, {{.ElemType}}ListEnvelope{
+		Count: len(result),
+		Data:  New{{.ElemType}}Responses(result),
 	})
 	{{else}}
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"data": result,
+	resp := New{{.ElemType}}Response(*result)
+	if render.NotModified(w, r, resp.ETag(), format, resp.LastModified()) {
+		return
+	}
+	err = render.Write(w, http.StatusOK, format, {{.ElemType}}Envelope{
+		Data: resp,
 	})
 	{{end}}
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
 }
+{{end}}
 
 {{if eq .HTTPMethod "POST"}}
 type {{.HandlerName}}Request struct {
 	Title string ` + "`json:\"title\" example:\"My Post Title\"`" + `
 	Body  string ` + "`json:\"body\" example:\"This is the post content\"`" + `
 }
+{{else if .RequestFields}}
+type {{.HandlerName}}Request struct {
+	{{range .RequestFields}}{{.Name}} {{.Type}} ` + "`json:\"{{.JSONName}}\"`" + `
+	{{end}}
+}
 {{end}}
 {{end}}
 
+// serviceError maps a service failure onto an HTTP status.
+func (h *Handlers) serviceError(w http.ResponseWriter, err error) {
+	h.logger.Errorf("Service error: %v", err)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "{{.Feature}} not found", http.StatusNotFound)
+		return
+	}
+	http.Error(w, "Internal server error", http.StatusInternalServerError)
+}
+
 {{if .HasHealthCheck}}
 // HealthCheck checks the health of the {{.Feature}} service
 // @Summary Health check
@@ -541,15 +1038,19 @@ func (h *Handlers) HealthCheck(w http.ResponseWriter, r *http.Request) {
 {{end}}
 `
 
+	var sortedImports []string
+	for path := range imports {
+		sortedImports = append(sortedImports, path)
+	}
+	sort.Strings(sortedImports)
+
 	// Create extended template data
 	templateData := struct {
 		APIGenerationData
-		NeedsStrconv bool
-		NeedsUUID    bool
+		HandlerImports []string
 	}{
 		APIGenerationData: data,
-		NeedsStrconv:      needsStrconv,
-		NeedsUUID:         needsUUID,
+		HandlerImports:    sortedImports,
 	}
 
 	return g.writeTemplateWithData("handlers.go", tmpl, templateData)
@@ -557,35 +1058,156 @@ func (h *Handlers) HealthCheck(w http.ResponseWriter, r *http.Request) {
 
 // Add this new function to handle the extended template data
 func (g *Generator) writeTemplateWithData(filename, tmpl string, data interface{}) error {
-	// Add custom template functions
-	funcMap := template.FuncMap{
-		"title":      strings.Title,
-		"lower":      strings.ToLower,
-		"snakeCase":  toSnakeCase,
-		"contains":   strings.Contains,
-		"methodName": methodName,
+	return g.writeTemplate(filename, tmpl, data)
+}
+
+// Update getRequiredImports to not include unused imports
+func (g *Generator) getRequiredImports(queries []QueryInfo) []string {
+	// Don't include imports here since we handle them in templates
+	return []string{}
+}
+func (g *Generator) generateModels(data APIGenerationData) error {
+	tmpl := `// Code generated by genapi. DO NOT EDIT manually.
+package {{.Package}}
+
+import (
+	"encoding/xml"
+	{{range .ModelImports}}{{if not (contains . ".")}}"{{.}}"
+	{{end}}{{end}}
+	{{range .ModelImports}}{{if contains . "."}}"{{.}}"
+	{{end}}{{end -}}
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
+)
+{{range .Models}}
+// {{.Name}}Response is the wire form of repository.{{.Name}}, shared by the
+// JSON, XML and CSV encodings.
+type {{.Name}}Response struct {
+	XMLName xml.Name ` + "`json:\"-\" xml:\"{{.XMLName}}\"`" + `
+	{{range .Fields}}{{.Name}} {{.ResponseType}} ` + "`json:\"{{.JSONName}}\" xml:\"{{.JSONName}}{{if hasPrefix .ResponseType \"*\"}},omitempty{{end}}\"`" + `
+	{{end}}
+}
+
+func New{{.Name}}Response(row repository.{{.Name}}) {{.Name}}Response {
+	return {{.Name}}Response{
+		{{range .Fields}}{{.Name}}: {{.Convert}},
+		{{end}}
 	}
+}
 
-	t, err := template.New(filename).Funcs(funcMap).Parse(tmpl)
-	if err != nil {
-		return err
+func New{{.Name}}Responses(rows []repository.{{.Name}}) []{{.Name}}Response {
+	items := make([]{{.Name}}Response, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, New{{.Name}}Response(row))
 	}
+	return items
+}
+
+var {{.Name | lowerFirst}}CSVHeader = []string{ {{range $i, $f := .Fields}}{{if $i}}, {{end}}"{{$f.JSONName}}"{{end}} }
+
+func (m {{.Name}}Response) CSVRecord() []string {
+	return []string{
+		{{range .Fields}}render.CSVValue(m.{{.Name}}),
+		{{end}}
+	}
+}
+
+// ETag identifies the current state of the {{.XMLName}} for conditional requests.
+func (m {{.Name}}Response) ETag() string {
+	{{if .VersionField}}return render.VersionETag(int64(m.{{.VersionField}}))
+	{{else if .UpdatedAtField}}return render.TimeETag(m.{{.UpdatedAtField}})
+	{{else}}return render.HashETag(m)
+	{{end -}}
+}
+
+// LastModified is the zero time when the {{.XMLName}} has no updated_at column.
+func (m {{.Name}}Response) LastModified() time.Time {
+	{{if .UpdatedAtField}}return m.{{.UpdatedAtField}}{{else}}return time.Time{}{{end}}
+}
+
+// {{.Name}}Envelope is the response body for a single {{.XMLName}}. XML
+// clients receive the bare <{{.XMLName}}> element.
+type {{.Name}}Envelope struct {
+	Message string ` + "`json:\"message,omitempty\"`" + `
+	Data    {{.Name}}Response ` + "`json:\"data\"`" + `
+}
 
-	// Use filepath.Join for Windows compatibility
-	file, err := os.Create(filepath.Join("internal", "generated", "api", g.Feature, filename))
+func (e {{.Name}}Envelope) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	return enc.Encode(e.Data)
+}
+
+// {{.Name}}ListEnvelope is the response body for a list of {{.XMLListName}}.
+type {{.Name}}ListEnvelope struct {
+	XMLName xml.Name ` + "`json:\"-\" xml:\"{{.XMLListName}}\"`" + `
+	Count   int ` + "`json:\"count\" xml:\"count,attr\"`" + `
+	Data    []{{.Name}}Response ` + "`json:\"data\" xml:\"{{.XMLName}}\"`" + `
+}
+{{end}}
+`
+
+	return g.writeTemplate("models.go", tmpl, data)
+}
+
+// generateStreams writes row-by-row variants of the "-- genapi:stream" queries
+// into the repository package, where the unexported SQL constants live.
+func (g *Generator) generateStreams(data APIGenerationData) error {
+	path := filepath.Join("internal", "generated", "repository", g.Feature+"_stream.go")
+	if !data.HasStreams {
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	imports := map[string]bool{}
+	for _, q := range data.Queries {
+		for _, imp := range q.StreamInfo.Imports {
+			imports[imp] = true
+		}
+	}
+	delete(imports, "context")
+
+	tmpl := `// Code generated by genapi. DO NOT EDIT manually.
+package repository
+
+import (
+	"context"
+	{{range $imp, $_ := .Imports}}"{{$imp}}"
+	{{end}}
+)
+{{range .Queries}}{{if .Stream}}
+// Stream{{.Name}} runs the {{.Name}} query and calls fn for every row
+// instead of collecting the result set in memory. Iteration stops at the
+// first error returned by fn.
+func (q *Queries) Stream{{.Name}}(ctx context.Context, {{with .StreamInfo.Params}}{{.}}, {{end}}fn func({{.ElemType}}) error) error {
+	rows, err := q.db.Query(ctx, {{.StreamInfo.QueryArgs}})
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-
-	return t.Execute(file, data)
+	defer rows.Close()
+	for rows.Next() {
+		var i {{.ElemType}}
+		if err := rows.Scan({{.StreamInfo.ScanArgs}}); err != nil {
+			return err
+		}
+		if err := fn(i); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
 }
+{{end}}{{end}}
+`
 
-// Update getRequiredImports to not include unused imports
-func (g *Generator) getRequiredImports(queries []QueryInfo) []string {
-	// Don't include imports here since we handle them in templates
-	return []string{}
+	return g.writeTemplateTo(path, tmpl, struct {
+		Queries []QueryInfo
+		Imports map[string]bool
+	}{
+		Queries: data.Queries,
+		Imports: imports,
+	})
 }
+
 func (g *Generator) generateService(data APIGenerationData) error {
 	tmpl := `// Code generated by genapi. DO NOT EDIT manually.
 package {{.Package}}
@@ -595,6 +1217,7 @@ import (
 	"fmt"
 
 	"github.com/eif-courses/civilregistry/internal/generated/repository"  // ✅ Correct path
+	"github.com/eif-courses/civilregistry/internal/telemetry"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -613,14 +1236,17 @@ func NewService(repo *repository.Queries, logger *zap.SugaredLogger) *Service {
 
 {{range .Queries}}
 {{if eq .HTTPMethod "POST"}}
-func (s *Service) {{.ServiceName}}(ctx context.Context, title, body string) (*repository.Post, error) {
+func (s *Service) {{.ServiceName}}(ctx context.Context, title, body string) (_ *repository.{{.ElemType}}, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "{{$.Feature}}", "{{.ServiceName}}")
+	defer func() { op.End(err) }()
+
 	s.logger.Infof("Creating new {{.ServiceName}}: %s", title)
 
 	if title == "" || body == "" {
 		return nil, fmt.Errorf("title and body are required")
 	}
 
-	result, err := s.repo.{{.Name}}(ctx, repository.CreatePostParams{
+	result, err := s.repo.{{.Name}}(ctx, repository.{{.Name}}Params{
 		Title: title,
 		Body:  body,
 	})
@@ -632,8 +1258,42 @@ func (s *Service) {{.ServiceName}}(ctx context.Context, title, body string) (*re
 	s.logger.Infof("{{.ServiceName}} completed successfully with ID: %s", result.ID)
 	return &result, nil
 }
+{{else if eq .HTTPMethod "DELETE"}}
+func (s *Service) {{.ServiceName}}(ctx context.Context, id {{.IDType}}) (err error) {
+	ctx, op := telemetry.StartOperation(ctx, "{{$.Feature}}", "{{.ServiceName}}")
+	defer func() { op.End(err) }()
+
+	s.logger.Infof("{{.ServiceName}} called for ID: %v", id)
+
+	if err := s.repo.{{.Name}}(ctx, id); err != nil {
+		s.logger.Errorf("Failed {{.ServiceName}}: %v", err)
+		return fmt.Errorf("failed {{.ServiceName}}: %w", err)
+	}
+
+	s.logger.Info("{{.ServiceName}} completed successfully")
+	return nil
+}
+{{else if .ParamsType}}
+func (s *Service) {{.ServiceName}}(ctx context.Context, arg repository.{{.ParamsType}}) (_ *repository.{{.ElemType}}, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "{{$.Feature}}", "{{.ServiceName}}")
+	defer func() { op.End(err) }()
+
+	s.logger.Infof("{{.ServiceName}} called{{if .IDField}} for ID: %v", arg.{{.IDField}}{{else}}"{{end}})
+
+	result, err := s.repo.{{.Name}}(ctx, arg)
+	if err != nil {
+		s.logger.Errorf("Failed {{.ServiceName}}: %v", err)
+		return nil, fmt.Errorf("failed {{.ServiceName}}: %w", err)
+	}
+
+	s.logger.Info("{{.ServiceName}} completed successfully")
+	return &result, nil
+}
 {{else if contains .URLPath "{id}"}}
-func (s *Service) {{.ServiceName}}(ctx context.Context, id uuid.UUID) (*repository.Post, error) {
+func (s *Service) {{.ServiceName}}(ctx context.Context, id {{.IDType}}) (_ *repository.{{.ElemType}}, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "{{$.Feature}}", "{{.ServiceName}}")
+	defer func() { op.End(err) }()
+
 	s.logger.Infof("{{.ServiceName}} called for ID: %s", id)
 
 	result, err := s.repo.{{.Name}}(ctx, id)
@@ -646,7 +1306,10 @@ func (s *Service) {{.ServiceName}}(ctx context.Context, id uuid.UUID) (*reposito
 	return &result, nil
 }
 {{else}}
-func (s *Service) {{.ServiceName}}(ctx context.Context) ([]repository.Post, error) {
+func (s *Service) {{.ServiceName}}(ctx context.Context) (_ []repository.{{.ElemType}}, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "{{$.Feature}}", "{{.ServiceName}}")
+	defer func() { op.End(err) }()
+
 	s.logger.Info("{{.ServiceName}} called")
 
 	result, err := s.repo.{{.Name}}(ctx)
@@ -658,11 +1321,29 @@ func (s *Service) {{.ServiceName}}(ctx context.Context) ([]repository.Post, erro
 	s.logger.Infof("{{.ServiceName}} returned %d items", len(result))
 	return result, nil
 }
+{{if .Stream}}
+func (s *Service) Stream{{.ServiceName}}(ctx context.Context, fn func(repository.{{.ElemType}}) error) (err error) {
+	ctx, op := telemetry.StartOperation(ctx, "{{$.Feature}}", "Stream{{.ServiceName}}")
+	defer func() { op.End(err) }()
+
+	s.logger.Info("Stream{{.ServiceName}} called")
+
+	if err := s.repo.Stream{{.Name}}(ctx, fn); err != nil {
+		s.logger.Errorf("Failed Stream{{.ServiceName}}: %v", err)
+		return fmt.Errorf("failed Stream{{.ServiceName}}: %w", err)
+	}
+
+	return nil
+}
+{{end}}
 {{end}}
 {{end}}
 
 {{if .HasHealthCheck}}
-func (s *Service) HealthCheck(ctx context.Context) error {
+func (s *Service) HealthCheck(ctx context.Context) (err error) {
+	_, op := telemetry.StartOperation(ctx, "{{.Feature}}", "HealthCheck")
+	defer func() { op.End(err) }()
+
 	s.logger.Info("Performing health check")
 	return nil
 }
@@ -671,12 +1352,392 @@ func (s *Service) HealthCheck(ctx context.Context) error {
 
 	return g.writeTemplate("service.go", tmpl, data)
 }
+
+// protoFieldType describes how a sqlc column type travels over gRPC. ToProto
+// converts a response model value (%[1]s); FromProto stores request field
+// msg.%[2]s into %[1]s, returning %[3]s and the error on bad input.
+type protoFieldType struct {
+	Proto     string
+	ToProto   string
+	FromProto string
+}
+
+var protoFieldTypes = map[string]protoFieldType{
+	"string":  {"string", "%[1]s", "%[1]s = msg.%[2]s"},
+	"bool":    {"bool", "%[1]s", "%[1]s = msg.%[2]s"},
+	"int32":   {"int32", "%[1]s", "%[1]s = msg.%[2]s"},
+	"int64":   {"int64", "%[1]s", "%[1]s = msg.%[2]s"},
+	"float32": {"float", "%[1]s", "%[1]s = msg.%[2]s"},
+	"float64": {"double", "%[1]s", "%[1]s = msg.%[2]s"},
+	"uuid.UUID": {"string", "%[1]s.String()",
+		"if %[1]s, err = uuid.Parse(msg.%[2]s); err != nil {\n\t\treturn %[3]s, err\n\t}"},
+	"time.Time": {"google.protobuf.Timestamp", "timestamppb.New(%[1]s)", "%[1]s = msg.%[2]s.AsTime()"},
+	"pgtype.Text": {"optional string", "%[1]s",
+		"%[1]s = pgtype.Text{String: msg.Get%[2]s(), Valid: msg.%[2]s != nil}"},
+	"pgtype.Bool": {"optional bool", "%[1]s",
+		"%[1]s = pgtype.Bool{Bool: msg.Get%[2]s(), Valid: msg.%[2]s != nil}"},
+	"pgtype.Int4": {"optional int32", "%[1]s",
+		"%[1]s = pgtype.Int4{Int32: msg.Get%[2]s(), Valid: msg.%[2]s != nil}"},
+	"pgtype.Int8": {"optional int64", "%[1]s",
+		"%[1]s = pgtype.Int8{Int64: msg.Get%[2]s(), Valid: msg.%[2]s != nil}"},
+	"pgtype.Float8": {"optional double", "%[1]s",
+		"%[1]s = pgtype.Float8{Float64: msg.Get%[2]s(), Valid: msg.%[2]s != nil}"},
+	"pgtype.Timestamptz": {"google.protobuf.Timestamp", "protoconv.NullableTimestamp(%[1]s)",
+		"%[1]s = pgtype.Timestamptz{Time: msg.%[2]s.AsTime(), Valid: msg.%[2]s != nil}"},
+	"pgtype.Date": {"optional string", "protoconv.NullableDate(%[1]s)",
+		"if %[1]s, err = protoconv.ParseDate(msg.%[2]s); err != nil {\n\t\treturn %[3]s, err\n\t}"},
+}
+
+// ProtoField is one field of a generated proto message.
+type ProtoField struct {
+	Name    string // proto field name, e.g. updated_at
+	GoName  string // field name in the protoc-gen-go struct, e.g. UpdatedAt
+	Type    string
+	Number  int
+	ToProto string // expression reading the response model field
+}
+
+type ProtoMessage struct {
+	Name   string
+	Fields []ProtoField
+}
+
+// RPCInfo describes the unary RPC generated for a query, plus the
+// server-streaming variant for "-- genapi:stream" queries.
+type RPCInfo struct {
+	QueryInfo
+	RequestFields []ProtoField
+	ArgName       string   // variable holding the converted request, if any
+	ArgType       string   // its Go type
+	FromProto     []string // statements filling ArgName from msg
+	ResultField   string   // proto field of the response holding the result
+}
+
+type ConnectData struct {
+	APIGenerationData
+	ProtoPackage string // e.g. civilregistry.post.v1
+	GoPackage    string // e.g. postv1
+	GoImportPath string
+	ServiceName  string // e.g. PostService
+	Messages     []ProtoMessage
+	RPCs         []RPCInfo
+	ProtoImports []string
+	GoImports    []string
+}
+
+// buildConnectData maps the feature onto proto messages and RPCs. It reports
+// false when a column type has no proto mapping.
+func (g *Generator) buildConnectData(data APIGenerationData) (ConnectData, bool) {
+	goPackage := strings.ToLower(g.Feature) + "v1"
+	cd := ConnectData{
+		APIGenerationData: data,
+		ProtoPackage:      "civilregistry." + strings.ToLower(g.Feature) + ".v1",
+		GoPackage:         goPackage,
+		GoImportPath:      "github.com/eif-courses/civilregistry/internal/generated/proto/civilregistry/" + strings.ToLower(g.Feature) + "/v1",
+		ServiceName:       strings.Title(g.Feature) + "Service",
+	}
+
+	for _, model := range data.Models {
+		msg := ProtoMessage{Name: model.Name}
+		for i, field := range model.Fields {
+			mapping, ok := protoFieldTypes[field.Type]
+			if !ok {
+				fmt.Printf("⚠️  No proto mapping for %s.%s (%s), skipping gRPC generation\n", model.Name, field.Name, field.Type)
+				return cd, false
+			}
+			msg.Fields = append(msg.Fields, ProtoField{
+				Name:    field.JSONName,
+				GoName:  protoGoName(field.JSONName),
+				Type:    mapping.Proto,
+				Number:  i + 1,
+				ToProto: fmt.Sprintf(mapping.ToProto, "m."+field.Name),
+			})
+		}
+		cd.Messages = append(cd.Messages, msg)
+	}
+
+	for _, q := range data.Queries {
+		rpc := RPCInfo{QueryInfo: q}
+
+		var fields []FieldInfo
+		target := func(field FieldInfo) string { return "arg." + field.Name }
+		switch {
+		case q.HTTPMethod == "POST":
+			fields = q.RequestFields
+		case q.ParamsType != "":
+			rpc.ArgName, rpc.ArgType = "arg", "repository."+q.ParamsType
+			fields = append([]FieldInfo{{Name: q.IDField, Type: q.IDType, JSONName: "id"}}, q.RequestFields...)
+		case q.IDType != "":
+			rpc.ArgName, rpc.ArgType = "id", q.IDType
+			fields = []FieldInfo{{Name: "ID", Type: q.IDType, JSONName: "id"}}
+			target = func(FieldInfo) string { return "id" }
+		}
+
+		for i, field := range fields {
+			mapping, ok := protoFieldTypes[field.Type]
+			if !ok {
+				fmt.Printf("⚠️  No proto mapping for %s.%s (%s), skipping gRPC generation\n", q.Name, field.Name, field.Type)
+				return cd, false
+			}
+			pf := ProtoField{
+				Name:   field.JSONName,
+				GoName: protoGoName(field.JSONName),
+				Type:   mapping.Proto,
+				Number: i + 1,
+			}
+			rpc.RequestFields = append(rpc.RequestFields, pf)
+			if rpc.ArgName != "" {
+				rpc.FromProto = append(rpc.FromProto, fmt.Sprintf(mapping.FromProto, target(field), pf.GoName, rpc.ArgName))
+			}
+		}
+
+		switch {
+		case q.HTTPMethod == "DELETE":
+		case q.Type == ":many":
+			rpc.ResultField = toSnakeCase(q.ElemType) + "s"
+		default:
+			rpc.ResultField = toSnakeCase(q.ElemType)
+		}
+
+		cd.RPCs = append(cd.RPCs, rpc)
+	}
+
+	// Work out imports from the types and conversions actually used
+	var code strings.Builder
+	for _, msg := range cd.Messages {
+		for _, f := range msg.Fields {
+			code.WriteString(f.Type + " " + f.ToProto + "\n")
+		}
+	}
+	for _, rpc := range cd.RPCs {
+		for _, f := range rpc.RequestFields {
+			code.WriteString(f.Type + "\n")
+		}
+		code.WriteString(strings.Join(rpc.FromProto, "\n") + rpc.ArgType + "\n")
+	}
+	used := code.String()
+
+	if strings.Contains(used, "google.protobuf.Timestamp") {
+		cd.ProtoImports = append(cd.ProtoImports, "google/protobuf/timestamp.proto")
+	}
+	for prefix, path := range map[string]string{
+		"uuid.":        "github.com/google/uuid",
+		"pgtype.":      "github.com/jackc/pgx/v5/pgtype",
+		"timestamppb.": "google.golang.org/protobuf/types/known/timestamppb",
+		"protoconv.":   "github.com/eif-courses/civilregistry/internal/protoconv",
+	} {
+		if strings.Contains(used, prefix) {
+			cd.GoImports = append(cd.GoImports, path)
+		}
+	}
+	sort.Strings(cd.GoImports)
+
+	return cd, true
+}
+
+// generateConnect writes the feature's .proto and the Connect server that
+// delegates to Service. The Go message types come from "task proto".
+func (g *Generator) generateConnect(data APIGenerationData) (bool, error) {
+	protoPath := filepath.Join("proto", "civilregistry", strings.ToLower(g.Feature), "v1", strings.ToLower(g.Feature)+".proto")
+	connectPath := filepath.Join("internal", "generated", "api", g.Feature, "connect.go")
+
+	cd, ok := g.buildConnectData(data)
+	if !ok {
+		for _, path := range []string{protoPath, connectPath} {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return false, err
+			}
+		}
+		return false, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(protoPath), 0755); err != nil {
+		return false, err
+	}
+
+	protoTmpl := `// Code generated by genapi. DO NOT EDIT manually.
+syntax = "proto3";
+
+package {{.ProtoPackage}};
+{{range .ProtoImports}}
+import "{{.}}";
+{{end}}
+option go_package = "{{.GoImportPath}};{{.GoPackage}}";
+
+service {{.ServiceName}} {
+{{- range .RPCs}}
+  rpc {{.Name}}({{.Name}}Request) returns ({{.Name}}Response);
+{{- if .Stream}}
+  rpc Stream{{.Name}}({{.Name}}Request) returns (stream {{.ElemType}});
+{{- end}}
+{{- end}}
+}
+{{range .Messages}}
+message {{.Name}} {
+{{- range .Fields}}
+  {{.Type}} {{.Name}} = {{.Number}};
+{{- end}}
+}
+{{end}}{{range .RPCs}}
+message {{.Name}}Request {
+{{- range .RequestFields}}
+  {{.Type}} {{.Name}} = {{.Number}};
+{{- end}}
+}
+
+message {{.Name}}Response {
+{{- if .ResultField}}
+  {{if eq .Type ":many"}}repeated {{end}}{{.ElemType}} {{.ResultField}} = 1;
+{{- end}}
+}
+{{end}}`
+
+	if err := g.writeTemplateTo(protoPath, protoTmpl, cd); err != nil {
+		return false, err
+	}
+
+	connectTmpl := `// Code generated by genapi. DO NOT EDIT manually.
+package {{.Package}}
+
+import (
+	"context"
+	"errors"
+
+	"connectrpc.com/connect"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	{{.GoPackage}} "{{.GoImportPath}}"
+	"{{.GoImportPath}}/{{.GoPackage}}connect"
+	{{range .GoImports}}"{{.}}"
+	{{end -}}
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// ConnectServer serves {{.ServiceName}} over gRPC, gRPC-Web and the Connect
+// protocol by delegating to the same Service as the REST handlers.
+type ConnectServer struct {
+	service *Service
+	logger  *zap.SugaredLogger
+}
+
+var _ {{.GoPackage}}connect.{{.ServiceName}}Handler = (*ConnectServer)(nil)
+
+func NewConnectServer(service *Service, logger *zap.SugaredLogger) *ConnectServer {
+	return &ConnectServer{service: service, logger: logger}
+}
+{{range .RPCs}}
+func (s *ConnectServer) {{.Name}}(ctx context.Context, req *connect.Request[{{$.GoPackage}}.{{.Name}}Request]) (*connect.Response[{{$.GoPackage}}.{{.Name}}Response], error) {
+	{{- if .ArgName}}
+	{{.ArgName}}, err := {{.Name | lowerFirst}}FromProto(req.Msg)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, err)
+	}
+	{{end}}
+	{{- if eq .HTTPMethod "DELETE"}}
+	if err := s.service.{{.ServiceName}}(ctx, {{.ArgName}}); err != nil {
+		return nil, s.connectError(err)
+	}
+
+	return connect.NewResponse(&{{$.GoPackage}}.{{.Name}}Response{}), nil
+	{{- else}}
+	{{- if eq .HTTPMethod "POST"}}
+	result, err := s.service.{{.ServiceName}}(ctx, req.Msg.GetTitle(), req.Msg.GetBody())
+	{{- else if .ArgName}}
+	result, err := s.service.{{.ServiceName}}(ctx, {{.ArgName}})
+	{{- else}}
+	result, err := s.service.{{.ServiceName}}(ctx)
+	{{- end}}
+	if err != nil {
+		return nil, s.connectError(err)
+	}
+	{{if eq .Type ":many"}}
+	items := make([]*{{$.GoPackage}}.{{.ElemType}}, 0, len(result))
+	for _, row := range result {
+		items = append(items, {{.ElemType | lowerFirst}}ToProto(New{{.ElemType}}Response(row)))
+	}
+	return connect.NewResponse(&{{$.GoPackage}}.{{.Name}}Response{ {{protoGoName .ResultField}}: items }), nil
+	{{- else}}
+	return connect.NewResponse(&{{$.GoPackage}}.{{.Name}}Response{ {{protoGoName .ResultField}}: {{.ElemType | lowerFirst}}ToProto(New{{.ElemType}}Response(*result)) }), nil
+	{{- end}}
+	{{- end}}
+}
+{{if .Stream}}
+func (s *ConnectServer) Stream{{.Name}}(ctx context.Context, req *connect.Request[{{$.GoPackage}}.{{.Name}}Request], stream *connect.ServerStream[{{$.GoPackage}}.{{.ElemType}}]) error {
+	err := s.service.Stream{{.ServiceName}}(ctx, func(row repository.{{.ElemType}}) error {
+		return stream.Send({{.ElemType | lowerFirst}}ToProto(New{{.ElemType}}Response(row)))
+	})
+	if err != nil {
+		return s.connectError(err)
+	}
+	return nil
+}
+{{end}}
+{{- if .ArgName}}
+func {{.Name | lowerFirst}}FromProto(msg *{{$.GoPackage}}.{{.Name}}Request) ({{.ArgName}} {{.ArgType}}, err error) {
+	{{- range .FromProto}}
+	{{.}}
+	{{- end}}
+	return {{.ArgName}}, nil
+}
+{{end}}
+{{- end}}
+{{range .Messages}}
+func {{.Name | lowerFirst}}ToProto(m {{.Name}}Response) *{{$.GoPackage}}.{{.Name}} {
+	return &{{$.GoPackage}}.{{.Name}}{
+		{{range .Fields}}{{.GoName}}: {{.ToProto}},
+		{{end}}
+	}
+}
+{{end}}
+// connectError maps a service failure onto a Connect error code. Like
+// serviceError it logs the cause and keeps it out of the response.
+func (s *ConnectServer) connectError(err error) error {
+	s.logger.Errorf("Service error: %v", err)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return connect.NewError(connect.CodeNotFound, errors.New("{{.Feature}} not found"))
+	}
+	return connect.NewError(connect.CodeInternal, errors.New("internal server error"))
+}
+`
+
+	if err := g.writeTemplateTo(connectPath, connectTmpl, cd); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// protoGoName converts a proto field name to the Go field name protoc-gen-go
+// gives it: updated_at -> UpdatedAt, id -> Id.
+func protoGoName(name string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range name {
+		if r == '_' {
+			upper = true
+			continue
+		}
+		if upper {
+			b.WriteString(strings.ToUpper(string(r)))
+			upper = false
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
 func (g *Generator) generateRouter(data APIGenerationData) error {
 	tmpl := `// Code generated by genapi. DO NOT EDIT manually.
 package {{.Package}}
 
 import (
-	"github.com/eif-courses/civilregistry/internal/generated/repository"  // ✅ Correct path
+	{{if .HasConnect}}"net/http"
+
+	"connectrpc.com/connect"
+	{{end}}"github.com/eif-courses/civilregistry/internal/generated/repository"  // ✅ Correct path
+	{{if .HasConnect}}"github.com/eif-courses/civilregistry/internal/generated/proto/civilregistry/{{.Feature | lower}}/v1/{{.Feature | lower}}v1connect"
+	{{end}}"github.com/eif-courses/civilregistry/internal/telemetry"
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 )
@@ -688,33 +1749,52 @@ func {{.Feature | title}}Router(queries *repository.Queries, log *zap.SugaredLog
 	service := NewService(queries, log)
 	handlers := NewHandlers(service, log)
 
-	{{if .HasHealthCheck}}r.Get("/health", handlers.HealthCheck){{end}}
-	{{range .Queries}}r.{{.HTTPMethod | methodName}}("{{.URLPath}}", handlers.{{.HandlerName}})
+	{{if .HasHealthCheck}}r.Get("/health", telemetry.InstrumentHandler("{{.Feature}}", "HealthCheck", handlers.HealthCheck)){{end}}
+	{{range .Queries}}r.{{.HTTPMethod | methodName}}("{{.URLPath}}", telemetry.InstrumentHandler("{{$.Feature}}", "{{.HandlerName}}", handlers.{{.HandlerName}}))
 	{{end}}
 
 	return r
 }
-`
+{{if .HasConnect}}
+// {{.Feature | title}}ConnectHandler serves the same service over gRPC and Connect.
+// Mount the handler at the returned path on the main router. Every RPC is
+// instrumented like the matching REST route.
+func {{.Feature | title}}ConnectHandler(queries *repository.Queries, log *zap.SugaredLogger) (string, http.Handler) {
+	service := NewService(queries, log)
+	return {{.Feature | lower}}v1connect.New{{.Feature | title}}ServiceHandler(
+		NewConnectServer(service, log),
+		connect.WithInterceptors(telemetry.ConnectInterceptor("{{.Feature}}")),
+	)
+}
+{{end}}`
 
 	return g.writeTemplate("router.go", tmpl, data)
 }
 func (g *Generator) writeTemplate(filename, tmpl string, data interface{}) error {
+	// Use filepath.Join for Windows compatibility - UPDATED PATH
+	return g.writeTemplateTo(filepath.Join("internal", "generated", "api", g.Feature, filename), tmpl, data)
+}
+
+func (g *Generator) writeTemplateTo(path, tmpl string, data interface{}) error {
 	// Add custom template functions
 	funcMap := template.FuncMap{
-		"title":      strings.Title,
-		"lower":      strings.ToLower,
-		"snakeCase":  toSnakeCase,
-		"contains":   strings.Contains,
-		"methodName": methodName,
+		"title":       strings.Title,
+		"lower":       strings.ToLower,
+		"lowerFirst":  lowerFirst,
+		"hasPrefix":   strings.HasPrefix,
+		"isWrite":     isWrite,
+		"protoGoName": protoGoName,
+		"snakeCase":   toSnakeCase,
+		"contains":    strings.Contains,
+		"methodName":  methodName,
 	}
 
-	t, err := template.New(filename).Funcs(funcMap).Parse(tmpl)
+	t, err := template.New(filepath.Base(path)).Funcs(funcMap).Parse(tmpl)
 	if err != nil {
 		return err
 	}
 
-	// Use filepath.Join for Windows compatibility - UPDATED PATH
-	file, err := os.Create(filepath.Join("internal", "generated", "api", g.Feature, filename))
+	file, err := os.Create(path)
 	if err != nil {
 		return err
 	}
@@ -730,6 +1810,23 @@ func camelToKebab(s string) string {
 	return strings.ToLower(kebab)
 }
 
+// isWrite reports whether requests with this method modify an existing row
+// and therefore honour If-Match.
+func isWrite(httpMethod string) bool {
+	switch httpMethod {
+	case "PUT", "PATCH", "DELETE":
+		return true
+	}
+	return false
+}
+
+func lowerFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToLower(s[:1]) + s[1:]
+}
+
 func toSnakeCase(s string) string {
 	re := regexp.MustCompile("([a-z0-9])([A-Z])")
 	snake := re.ReplaceAllString(s, "${1}_${2}")
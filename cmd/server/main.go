@@ -6,9 +6,11 @@ import (
 	"net/http"
 
 	"github.com/eif-courses/civilregistry/internal/api"
+	"github.com/eif-courses/civilregistry/internal/api/appointment"
 	"github.com/eif-courses/civilregistry/internal/config"
 	"github.com/eif-courses/civilregistry/internal/generated/repository"
 	"github.com/eif-courses/civilregistry/internal/logger"
+	"github.com/eif-courses/civilregistry/internal/storage"
 
 	// Import generated swagger docs
 	_ "github.com/eif-courses/civilregistry/docs"
@@ -32,6 +34,11 @@ import (
 // @BasePath /
 // @schemes http https
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Registrar API token as "Bearer <token>"
+
 func main() {
 	log := logger.NewLogger()
 	defer log.Sync()
@@ -48,8 +55,28 @@ func main() {
 		log.Fatalw("Failed to ping database", "error", err)
 	}
 
+	store, err := storage.New(storage.Config{
+		Backend: cfg.BlobStore,
+		Dir:     cfg.BlobDir,
+		S3: storage.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			Region:    cfg.S3Region,
+			Bucket:    cfg.S3Bucket,
+			Prefix:    cfg.S3Prefix,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+		},
+	})
+	if err != nil {
+		log.Fatalw("Failed to set up blob store", "error", err)
+	}
+
 	queries := repository.New(dbpool)
-	router := api.NewRouter(queries, log)
+	router := api.NewRouter(cfg, dbpool, queries, store, log)
+
+	// Queue appointment reminders in the background
+	reminders := appointment.NewService(dbpool, queries, log)
+	go reminders.RunReminders(context.Background(), cfg.ReminderInterval, cfg.ReminderLead)
 
 	addr := fmt.Sprintf(":%d", cfg.Port)
 	log.Infow("Starting server",
@@ -58,7 +85,19 @@ func main() {
 		"swagger", fmt.Sprintf("http://localhost:%d/swagger/index.html", cfg.Port),
 	)
 
-	err = http.ListenAndServe(addr, router)
+	// Plain HTTP/2 (h2c) is enabled alongside HTTP/1.1 so gRPC clients can
+	// reach the Connect handlers on the same port without TLS.
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+
+	server := &http.Server{
+		Addr:      addr,
+		Handler:   router,
+		Protocols: &protocols,
+	}
+
+	err = server.ListenAndServe()
 	if err != nil {
 		log.Fatalw("Server failed", "error", err)
 	}
@@ -0,0 +1,333 @@
+package adoption
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/eif-courses/civilregistry/internal/api/request"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	service *Service
+	logger  *zap.SugaredLogger
+}
+
+func NewHandlers(service *Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type RegisterAdoptionRequest struct {
+	PersonID         uuid.UUID   `json:"person_id"`
+	AdoptiveMotherID *uuid.UUID  `json:"adoptive_mother_id,omitempty"`
+	AdoptiveFatherID *uuid.UUID  `json:"adoptive_father_id,omitempty"`
+	Court            string      `json:"court" example:"Vilniaus miesto apylinkės teismas"`
+	DecisionNumber   string      `json:"decision_number" example:"e2-1234-567/2026"`
+	DecidedOn        render.Date `json:"decided_on" swaggertype:"string" format:"date" example:"2026-09-01"`
+}
+
+// Params converts the request into service parameters.
+func (req RegisterAdoptionRequest) Params() RegisterAdoptionParams {
+	return RegisterAdoptionParams{
+		PersonID:         req.PersonID,
+		AdoptiveMotherID: request.UUID(req.AdoptiveMotherID),
+		AdoptiveFatherID: request.UUID(req.AdoptiveFatherID),
+		Court:            req.Court,
+		DecisionNumber:   req.DecisionNumber,
+		DecidedOn:        request.Date(req.DecidedOn),
+	}
+}
+
+type RevokeAdoptionRequest struct {
+	RevokedOn render.Date `json:"revoked_on" swaggertype:"string" format:"date" example:"2026-09-01"`
+}
+
+type OpenSealedRecordRequest struct {
+	Reason string `json:"reason" example:"court request 2-345/2026"`
+}
+
+// RegisterAdoption registers an adoption
+// @Summary Register adoption
+// @Description Record a court's adoption decision. The adoptive parents become the person's legal parents: certificates,
+// @Description kinship and birth records show them from then on, while the birth parents stay sealed on the original
+// @Description birth record. The person must be a living minor with a birth record; adoptive parents must be living
+// @Description adults born before them. At least one adoptive parent is required.
+// @Description The signed-in registrar's office must register births.
+// @Tags adoption
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RegisterAdoptionRequest true "adoption data"
+// @Success 201 {object} RegistrationEnvelope "Registered adoption"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Office does not register births"
+// @Failure 404 {object} map[string]interface{} "Person not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Person already adopted"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/adoption/ [post]
+func (h *Handlers) RegisterAdoption(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var req RegisterAdoptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.RegisterAdoption(r.Context(), req.Params())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/adoption/"+result.Adoption.ID.String())
+	h.writeRegistration(w, http.StatusCreated, "adoption registered successfully", *result)
+}
+
+// RevokeAdoption revokes an adoption
+// @Summary Revoke adoption
+// @Description Record a court's revocation of an active adoption. The birth parents are the person's legal parents again.
+// @Tags adoption
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "adoption ID"
+// @Param request body RevokeAdoptionRequest true "revocation date"
+// @Success 200 {object} RegistrationEnvelope "Revoked adoption"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Office does not register births"
+// @Failure 404 {object} map[string]interface{} "Adoption not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Adoption already revoked"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/adoption/{id}/revoke [post]
+func (h *Handlers) RevokeAdoption(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	var req RevokeAdoptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.RevokeAdoption(r.Context(), id, request.Date(req.RevokedOn))
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.writeRegistration(w, http.StatusOK, "adoption revoked successfully", *result)
+}
+
+// GetAdoption retrieves an adoption
+// @Summary Get adoption
+// @Description Get an adoption with the adopted person and the adoptive parents. The birth parents are not shown.
+// @Tags adoption
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "adoption ID"
+// @Success 200 {object} RegistrationEnvelope "Adoption found"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "Adoption not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/adoption/{id} [get]
+func (h *Handlers) GetAdoption(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.GetAdoption(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.writeRegistration(w, http.StatusOK, "", *result)
+}
+
+// ListAdoptionsForPerson lists the adoptions of a person
+// @Summary List adoptions by person
+// @Description List the adoptions of a person, as adopted child or as adoptive parent, newest first
+// @Tags adoption
+// @Produce json
+// @Security BearerAuth
+// @Param personID path string true "person ID"
+// @Success 200 {object} AdoptionListEnvelope "Adoptions"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/adoption/by-person/{personID} [get]
+func (h *Handlers) ListAdoptionsForPerson(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	personID, err := request.UUIDParam(r, "personID")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.ListAdoptionsForPerson(r.Context(), personID)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	err = render.Write(w, http.StatusOK, render.ContentTypeJSON, AdoptionListEnvelope{
+		Count: len(result),
+		Data:  NewAdoptionResponses(result),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// OpenSealedRecord opens the original birth record behind an adoption
+// @Summary Open sealed birth record
+// @Description Show the birth record behind an adoption with the birth parents. Only supervisors and admins may open
+// @Description it, and every opening is logged with the caller and the reason given.
+// @Tags adoption
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "adoption ID"
+// @Param request body OpenSealedRecordRequest true "reason for opening"
+// @Success 200 {object} SealedRecordEnvelope "Sealed birth record"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a supervisor or admin"
+// @Failure 404 {object} map[string]interface{} "Adoption not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/adoption/{id}/original [post]
+func (h *Handlers) OpenSealedRecord(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	var req OpenSealedRecordRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.OpenSealedRecord(r.Context(), id, req.Reason)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	err = render.Write(w, http.StatusOK, render.ContentTypeJSON, SealedRecordEnvelope{
+		Data: NewSealedRecordResponse(*result),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// ListSealedRecordAccess lists who opened a sealed birth record
+// @Summary List sealed record openings
+// @Description List who opened the birth record behind an adoption and why, most recent first. Supervisors and admins only.
+// @Tags adoption
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "adoption ID"
+// @Success 200 {object} SealedRecordAccessListEnvelope "Openings"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a supervisor or admin"
+// @Failure 404 {object} map[string]interface{} "Adoption not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/adoption/{id}/access [get]
+func (h *Handlers) ListSealedRecordAccess(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.ListSealedRecordAccess(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	err = render.Write(w, http.StatusOK, render.ContentTypeJSON, SealedRecordAccessListEnvelope{
+		Count: len(result),
+		Data:  NewSealedRecordAccessResponses(result),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+func (h *Handlers) writeRegistration(w http.ResponseWriter, status int, message string, reg Registration) {
+	err := render.Write(w, status, render.ContentTypeJSON, RegistrationEnvelope{
+		Message: message,
+		Data:    NewRegistrationResponse(reg),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// serviceError maps a service failure onto an HTTP status.
+func (h *Handlers) serviceError(w http.ResponseWriter, err error) {
+	status, msg := apperr.Status(err)
+	if status >= http.StatusInternalServerError {
+		h.logger.Errorf("Service error: %v", err)
+	}
+	if status == http.StatusNotFound && msg == "not found" {
+		msg = "adoption not found"
+	}
+	http.Error(w, msg, status)
+}
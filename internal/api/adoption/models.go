@@ -0,0 +1,150 @@
+package adoption
+
+import (
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/birth"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+)
+
+// AdoptionResponse is the wire form of repository.Adoption.
+type AdoptionResponse struct {
+	ID                 uuid.UUID    `json:"id"`
+	PersonID           uuid.UUID    `json:"person_id"`
+	AdoptiveMotherID   *uuid.UUID   `json:"adoptive_mother_id"`
+	AdoptiveFatherID   *uuid.UUID   `json:"adoptive_father_id"`
+	Court              string       `json:"court"`
+	DecisionNumber     string       `json:"decision_number"`
+	DecidedOn          render.Date  `json:"decided_on"`
+	Status             string       `json:"status" enums:"active,revoked"`
+	RevokedOn          *render.Date `json:"revoked_on"`
+	RegistrationOffice string       `json:"registration_office"`
+	Registrar          string       `json:"registrar"`
+	OfficeID           *uuid.UUID   `json:"office_id"`
+	RegistrarID        *uuid.UUID   `json:"registrar_id"`
+	CreatedAt          time.Time    `json:"created_at"`
+}
+
+func NewAdoptionResponse(row repository.Adoption) AdoptionResponse {
+	return AdoptionResponse{
+		ID:                 row.ID,
+		PersonID:           row.PersonID,
+		AdoptiveMotherID:   render.Nullable(uuid.UUID(row.AdoptiveMotherID.Bytes), row.AdoptiveMotherID.Valid),
+		AdoptiveFatherID:   render.Nullable(uuid.UUID(row.AdoptiveFatherID.Bytes), row.AdoptiveFatherID.Valid),
+		Court:              row.Court,
+		DecisionNumber:     row.DecisionNumber,
+		DecidedOn:          render.Date(row.DecidedOn.Time),
+		Status:             row.Status,
+		RevokedOn:          render.NullableDate(row.RevokedOn.Time, row.RevokedOn.Valid),
+		RegistrationOffice: row.RegistrationOffice,
+		Registrar:          row.Registrar,
+		OfficeID:           render.Nullable(uuid.UUID(row.OfficeID.Bytes), row.OfficeID.Valid),
+		RegistrarID:        render.Nullable(uuid.UUID(row.RegistrarID.Bytes), row.RegistrarID.Valid),
+		CreatedAt:          row.CreatedAt,
+	}
+}
+
+func NewAdoptionResponses(rows []repository.Adoption) []AdoptionResponse {
+	items := make([]AdoptionResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, NewAdoptionResponse(row))
+	}
+	return items
+}
+
+// RegistrationResponse is an adoption with the adopted person and their
+// adoptive parents.
+type RegistrationResponse struct {
+	Adoption       AdoptionResponse       `json:"adoption"`
+	Person         person.PersonResponse  `json:"person"`
+	AdoptiveMother *person.PersonResponse `json:"adoptive_mother"`
+	AdoptiveFather *person.PersonResponse `json:"adoptive_father"`
+}
+
+func NewRegistrationResponse(reg Registration) RegistrationResponse {
+	return RegistrationResponse{
+		Adoption:       NewAdoptionResponse(reg.Adoption),
+		Person:         person.NewPersonResponse(reg.Person),
+		AdoptiveMother: personResponse(reg.Mother),
+		AdoptiveFather: personResponse(reg.Father),
+	}
+}
+
+// SealedRecordResponse is the birth record behind an adoption with the
+// birth parents.
+type SealedRecordResponse struct {
+	Adoption    AdoptionResponse          `json:"adoption"`
+	Record      birth.BirthRecordResponse `json:"record"`
+	BirthMother *person.PersonResponse    `json:"birth_mother"`
+	BirthFather *person.PersonResponse    `json:"birth_father"`
+}
+
+func NewSealedRecordResponse(sealed SealedRecord) SealedRecordResponse {
+	return SealedRecordResponse{
+		Adoption:    NewAdoptionResponse(sealed.Adoption),
+		Record:      birth.NewBirthRecordResponse(sealed.Record),
+		BirthMother: personResponse(sealed.Mother),
+		BirthFather: personResponse(sealed.Father),
+	}
+}
+
+// SealedRecordAccessResponse is the wire form of
+// repository.SealedRecordAccess.
+type SealedRecordAccessResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	AdoptionID  uuid.UUID  `json:"adoption_id"`
+	RegistrarID *uuid.UUID `json:"registrar_id"`
+	AccessedBy  string     `json:"accessed_by"`
+	Reason      string     `json:"reason"`
+	AccessedAt  time.Time  `json:"accessed_at"`
+}
+
+func NewSealedRecordAccessResponses(rows []repository.SealedRecordAccess) []SealedRecordAccessResponse {
+	items := make([]SealedRecordAccessResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, SealedRecordAccessResponse{
+			ID:          row.ID,
+			AdoptionID:  row.AdoptionID,
+			RegistrarID: render.Nullable(uuid.UUID(row.RegistrarID.Bytes), row.RegistrarID.Valid),
+			AccessedBy:  row.AccessedBy,
+			Reason:      row.Reason,
+			AccessedAt:  row.AccessedAt,
+		})
+	}
+	return items
+}
+
+func personResponse(p *repository.Person) *person.PersonResponse {
+	if p == nil {
+		return nil
+	}
+	resp := person.NewPersonResponse(*p)
+	return &resp
+}
+
+// RegistrationEnvelope is the response body for a single adoption.
+type RegistrationEnvelope struct {
+	Message string               `json:"message,omitempty"`
+	Data    RegistrationResponse `json:"data"`
+}
+
+// AdoptionListEnvelope is the response body for a list of adoptions.
+type AdoptionListEnvelope struct {
+	Count int                `json:"count"`
+	Data  []AdoptionResponse `json:"data"`
+}
+
+// SealedRecordEnvelope is the response body for an opened sealed record.
+type SealedRecordEnvelope struct {
+	Data SealedRecordResponse `json:"data"`
+}
+
+// SealedRecordAccessListEnvelope is the response body for the openings of
+// a sealed record.
+type SealedRecordAccessListEnvelope struct {
+	Count int                          `json:"count"`
+	Data  []SealedRecordAccessResponse `json:"data"`
+}
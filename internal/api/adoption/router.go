@@ -0,0 +1,25 @@
+package adoption
+
+import (
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func AdoptionRouter(db txn.Beginner, queries *repository.Queries, log *zap.SugaredLogger) chi.Router {
+	r := chi.NewRouter()
+
+	service := NewService(db, queries, log)
+	handlers := NewHandlers(service, log)
+
+	r.Post("/", telemetry.InstrumentHandler("adoption", "RegisterAdoption", handlers.RegisterAdoption))
+	r.Get("/by-person/{personID}", telemetry.InstrumentHandler("adoption", "ListAdoptionsForPerson", handlers.ListAdoptionsForPerson))
+	r.Get("/{id}", telemetry.InstrumentHandler("adoption", "GetAdoption", handlers.GetAdoption))
+	r.Post("/{id}/revoke", telemetry.InstrumentHandler("adoption", "RevokeAdoption", handlers.RevokeAdoption))
+	r.Post("/{id}/original", telemetry.InstrumentHandler("adoption", "OpenSealedRecord", handlers.OpenSealedRecord))
+	r.Get("/{id}/access", telemetry.InstrumentHandler("adoption", "ListSealedRecordAccess", handlers.ListSealedRecordAccess))
+
+	return r
+}
@@ -0,0 +1,391 @@
+package adoption
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/office"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const (
+	StatusActive  = "active"
+	StatusRevoked = "revoked"
+
+	// AdultAge is the age from which a person can no longer be adopted and
+	// can adopt.
+	AdultAge = 18
+)
+
+type Service struct {
+	db     txn.Beginner
+	repo   *repository.Queries
+	logger *zap.SugaredLogger
+}
+
+func NewService(db txn.Beginner, repo *repository.Queries, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		db:     db,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// RegisterAdoptionParams describes a court's adoption decision. Either
+// adoptive parent may be NULL, but not both.
+type RegisterAdoptionParams struct {
+	PersonID         uuid.UUID
+	AdoptiveMotherID pgtype.UUID
+	AdoptiveFatherID pgtype.UUID
+	Court            string
+	DecisionNumber   string
+	DecidedOn        pgtype.Date
+}
+
+// Registration is an adoption with the adopted person and their adoptive
+// parents.
+type Registration struct {
+	Adoption repository.Adoption
+	Person   repository.Person
+	Mother   *repository.Person
+	Father   *repository.Person
+}
+
+// SealedRecord is the birth record behind an adoption as it was made, with
+// the birth parents.
+type SealedRecord struct {
+	Adoption repository.Adoption
+	Record   repository.BirthRecord
+	Mother   *repository.Person
+	Father   *repository.Person
+}
+
+// RegisterAdoption records an adoption, which makes the adoptive parents
+// the person's legal parents from then on. The person must be a living
+// minor with a birth record in the register, which is sealed; the adoptive
+// parents must be living adults. The registrar's office must register
+// births.
+func (s *Service) RegisterAdoption(ctx context.Context, arg RegisterAdoptionParams) (_ *Registration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "adoption", "RegisterAdoption")
+	defer func() { op.End(err) }()
+
+	arg.Court = strings.TrimSpace(arg.Court)
+	arg.DecisionNumber = strings.TrimSpace(arg.DecisionNumber)
+	if err := validate(arg); err != nil {
+		return nil, err
+	}
+	if _, err := auth.RequireRegistrar(ctx); err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Registering adoption of %s", arg.PersonID)
+
+	var reg Registration
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		registrar, err := office.Authorize(ctx, q, office.EventBirth, pgtype.Text{})
+		if err != nil {
+			return err
+		}
+		officeID, registrarID := office.RecordedBy(registrar)
+
+		ids := []uuid.UUID{arg.PersonID}
+		for _, id := range []pgtype.UUID{arg.AdoptiveMotherID, arg.AdoptiveFatherID} {
+			if id.Valid {
+				ids = append(ids, id.Bytes)
+			}
+		}
+		locked, err := person.LockInOrder(ctx, q, ids...)
+		if err != nil {
+			return err
+		}
+
+		reg.Person = locked[arg.PersonID]
+		if err := person.EnsureAlive(reg.Person); err != nil {
+			return err
+		}
+		if ageOn(reg.Person.BirthDate.Time, arg.DecidedOn.Time) >= AdultAge {
+			return apperr.Invalid("%s %s was not a minor on the decision date", reg.Person.FirstName, reg.Person.LastName)
+		}
+		if _, err := q.GetBirthRecordByPersonID(ctx, arg.PersonID); errors.Is(err, pgx.ErrNoRows) {
+			return apperr.Invalid("%s %s has no birth record in the register", reg.Person.FirstName, reg.Person.LastName)
+		} else if err != nil {
+			return fmt.Errorf("failed GetBirthRecordByPersonID: %w", err)
+		}
+
+		if reg.Mother, err = adoptiveParent(locked, arg.AdoptiveMotherID, "adoptive mother", person.SexFemale, reg.Person, arg.DecidedOn); err != nil {
+			return err
+		}
+		if reg.Father, err = adoptiveParent(locked, arg.AdoptiveFatherID, "adoptive father", person.SexMale, reg.Person, arg.DecidedOn); err != nil {
+			return err
+		}
+
+		reg.Adoption, err = q.CreateAdoption(ctx, repository.CreateAdoptionParams{
+			PersonID:           arg.PersonID,
+			AdoptiveMotherID:   arg.AdoptiveMotherID,
+			AdoptiveFatherID:   arg.AdoptiveFatherID,
+			Court:              arg.Court,
+			DecisionNumber:     arg.DecisionNumber,
+			DecidedOn:          arg.DecidedOn,
+			RegistrationOffice: registrar.Office.Name,
+			Registrar:          registrar.Registrar.FullName,
+			OfficeID:           officeID,
+			RegistrarID:        registrarID,
+		})
+		if err != nil {
+			if apperr.IsUniqueViolation(err, "") {
+				return apperr.Conflict("%s %s is already adopted; revoke that adoption first", reg.Person.FirstName, reg.Person.LastName)
+			}
+			return fmt.Errorf("failed CreateAdoption: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorf("Failed RegisterAdoption: %v", err)
+		return nil, err
+	}
+
+	s.logger.Infof("RegisterAdoption completed successfully with ID: %s", reg.Adoption.ID)
+	return &reg, nil
+}
+
+// RevokeAdoption records a court's revocation of an adoption; the birth
+// parents are the person's legal parents again from then on.
+func (s *Service) RevokeAdoption(ctx context.Context, id uuid.UUID, revokedOn pgtype.Date) (_ *Registration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "adoption", "RevokeAdoption")
+	defer func() { op.End(err) }()
+
+	if err := validateDate(revokedOn, "revoked_on"); err != nil {
+		return nil, err
+	}
+	if _, err := auth.RequireRegistrar(ctx); err != nil {
+		return nil, err
+	}
+
+	var adoption repository.Adoption
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		if _, err := office.Authorize(ctx, q, office.EventBirth, pgtype.Text{}); err != nil {
+			return err
+		}
+		current, err := q.GetAdoptionForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed GetAdoptionForUpdate: %w", err)
+		}
+		if current.Status != StatusActive {
+			return apperr.Conflict("adoption is already %s", current.Status)
+		}
+		if revokedOn.Time.Before(current.DecidedOn.Time) {
+			return apperr.Invalid("revoked_on must not be before the adoption was decided")
+		}
+		if adoption, err = q.RevokeAdoption(ctx, repository.RevokeAdoptionParams{ID: id, RevokedOn: revokedOn}); err != nil {
+			return fmt.Errorf("failed RevokeAdoption: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorf("Failed to revoke adoption %s: %v", id, err)
+		return nil, err
+	}
+	return s.expand(ctx, adoption)
+}
+
+// GetAdoption returns an adoption to a signed-in registrar. It names the
+// adoptive parents only; the birth parents stay sealed.
+func (s *Service) GetAdoption(ctx context.Context, id uuid.UUID) (_ *Registration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "adoption", "GetAdoption")
+	defer func() { op.End(err) }()
+
+	if auth.FromContext(ctx) == nil {
+		return nil, apperr.Unauthorized("sign in to see adoptions")
+	}
+	adoption, err := s.repo.GetAdoptionByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetAdoptionByID: %w", err)
+	}
+	return s.expand(ctx, adoption)
+}
+
+// ListAdoptionsForPerson returns the adoptions of the person and those
+// that made them an adoptive parent, newest first.
+func (s *Service) ListAdoptionsForPerson(ctx context.Context, personID uuid.UUID) (_ []repository.Adoption, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "adoption", "ListAdoptionsForPerson")
+	defer func() { op.End(err) }()
+
+	if auth.FromContext(ctx) == nil {
+		return nil, apperr.Unauthorized("sign in to see adoptions")
+	}
+	result, err := s.repo.ListAdoptionsForPerson(ctx, personID)
+	if err != nil {
+		s.logger.Errorf("Failed ListAdoptionsForPerson: %v", err)
+		return nil, fmt.Errorf("failed ListAdoptionsForPerson: %w", err)
+	}
+	return result, nil
+}
+
+// OpenSealedRecord shows a supervisor or admin the birth record behind an
+// adoption with the birth parents. Every opening is logged with the
+// caller and their reason.
+func (s *Service) OpenSealedRecord(ctx context.Context, id uuid.UUID, reason string) (_ *SealedRecord, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "adoption", "OpenSealedRecord")
+	defer func() { op.End(err) }()
+
+	p, err := auth.RequireSealedAccess(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, apperr.Invalid("reason is required to open a sealed record")
+	}
+
+	var sealed SealedRecord
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		if sealed.Adoption, err = q.GetAdoptionByID(ctx, id); err != nil {
+			return fmt.Errorf("failed GetAdoptionByID: %w", err)
+		}
+		if sealed.Record, err = q.GetBirthRecordByPersonID(ctx, sealed.Adoption.PersonID); err != nil {
+			return fmt.Errorf("failed GetBirthRecordByPersonID: %w", err)
+		}
+		if sealed.Mother, err = loadPerson(ctx, q, sealed.Record.MotherID); err != nil {
+			return err
+		}
+		if sealed.Father, err = loadPerson(ctx, q, sealed.Record.FatherID); err != nil {
+			return err
+		}
+
+		var registrarID pgtype.UUID
+		if p.Registrar != nil {
+			registrarID = pgtype.UUID{Bytes: p.Registrar.ID, Valid: true}
+		}
+		if _, err := q.CreateSealedRecordAccess(ctx, repository.CreateSealedRecordAccessParams{
+			AdoptionID:  id,
+			RegistrarID: registrarID,
+			AccessedBy:  p.Name(),
+			Reason:      reason,
+		}); err != nil {
+			return fmt.Errorf("failed CreateSealedRecordAccess: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorf("Failed to open sealed record of adoption %s: %v", id, err)
+		return nil, err
+	}
+
+	s.logger.Infof("%s opened the sealed record of adoption %s", p.Name(), id)
+	return &sealed, nil
+}
+
+// ListSealedRecordAccess returns who opened the sealed record of an
+// adoption and why, most recent first.
+func (s *Service) ListSealedRecordAccess(ctx context.Context, id uuid.UUID) (_ []repository.SealedRecordAccess, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "adoption", "ListSealedRecordAccess")
+	defer func() { op.End(err) }()
+
+	if _, err := auth.RequireSealedAccess(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetAdoptionByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed GetAdoptionByID: %w", err)
+	}
+	result, err := s.repo.ListSealedRecordAccess(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed ListSealedRecordAccess: %w", err)
+	}
+	return result, nil
+}
+
+func (s *Service) expand(ctx context.Context, adoption repository.Adoption) (*Registration, error) {
+	var err error
+	reg := Registration{Adoption: adoption}
+	if reg.Person, err = s.repo.GetPersonByID(ctx, adoption.PersonID); err != nil {
+		return nil, fmt.Errorf("failed GetPersonByID: %w", err)
+	}
+	if reg.Mother, err = loadPerson(ctx, s.repo, adoption.AdoptiveMotherID); err != nil {
+		return nil, err
+	}
+	if reg.Father, err = loadPerson(ctx, s.repo, adoption.AdoptiveFatherID); err != nil {
+		return nil, err
+	}
+	return &reg, nil
+}
+
+func validate(arg RegisterAdoptionParams) error {
+	switch {
+	case !arg.AdoptiveMotherID.Valid && !arg.AdoptiveFatherID.Valid:
+		return apperr.Invalid("adoptive_mother_id or adoptive_father_id is required")
+	case arg.AdoptiveMotherID.Valid && arg.AdoptiveMotherID.Bytes == arg.PersonID,
+		arg.AdoptiveFatherID.Valid && arg.AdoptiveFatherID.Bytes == arg.PersonID:
+		return apperr.Invalid("a person cannot adopt themselves")
+	case arg.AdoptiveMotherID.Valid && arg.AdoptiveFatherID.Valid && arg.AdoptiveMotherID.Bytes == arg.AdoptiveFatherID.Bytes:
+		return apperr.Invalid("adoptive mother and father must be different persons")
+	case arg.Court == "":
+		return apperr.Invalid("court is required")
+	case arg.DecisionNumber == "":
+		return apperr.Invalid("decision_number is required")
+	}
+	return validateDate(arg.DecidedOn, "decided_on")
+}
+
+// adoptiveParent checks an optional adoptive parent from the locked
+// persons.
+func adoptiveParent(locked map[uuid.UUID]repository.Person, id pgtype.UUID, role, sex string, child repository.Person, decidedOn pgtype.Date) (*repository.Person, error) {
+	if !id.Valid {
+		return nil, nil
+	}
+	p := locked[id.Bytes]
+	if err := person.EnsureAlive(p); err != nil {
+		return nil, err
+	}
+	if p.Sex != sex {
+		return nil, apperr.Invalid("%s must be %s", role, sex)
+	}
+	if ageOn(p.BirthDate.Time, decidedOn.Time) < AdultAge {
+		return nil, apperr.Invalid("%s must be at least %d on the decision date", role, AdultAge)
+	}
+	if !p.BirthDate.Time.Before(child.BirthDate.Time) {
+		return nil, apperr.Invalid("%s must be born before the child", role)
+	}
+	return &p, nil
+}
+
+func loadPerson(ctx context.Context, q *repository.Queries, id pgtype.UUID) (*repository.Person, error) {
+	if !id.Valid {
+		return nil, nil
+	}
+	p, err := q.GetPersonByID(ctx, id.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetPersonByID: %w", err)
+	}
+	return &p, nil
+}
+
+func validateDate(d pgtype.Date, field string) error {
+	if !d.Valid {
+		return apperr.Invalid("%s is required", field)
+	}
+	if d.Time.After(time.Now()) {
+		return apperr.Invalid("%s must not be in the future", field)
+	}
+	return nil
+}
+
+// ageOn returns the age in completed years of someone born on birth.
+func ageOn(birth, on time.Time) int {
+	age := on.Year() - birth.Year()
+	if on.Month() < birth.Month() || on.Month() == birth.Month() && on.Day() < birth.Day() {
+		age--
+	}
+	return age
+}
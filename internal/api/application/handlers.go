@@ -0,0 +1,355 @@
+package application
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/eif-courses/civilregistry/internal/api/request"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	service *Service
+	logger  *zap.SugaredLogger
+}
+
+func NewHandlers(service *Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type SubmitRequest struct {
+	Kind           string    `json:"kind" enums:"certificate_request,name_change,residence_declaration" example:"certificate_request"`
+	OfficeID       uuid.UUID `json:"office_id"`
+	ApplicantName  string    `json:"applicant_name" example:"Jonas Jonaitis"`
+	ApplicantEmail string    `json:"applicant_email" example:"jonas@example.com"`
+	ApplicantPhone string    `json:"applicant_phone,omitempty" example:"+37060000000"`
+	PersonalCode   string    `json:"personal_code" example:"39001010008"`
+	Details        Details   `json:"details"`
+}
+
+// Params converts the request into service parameters.
+func (req SubmitRequest) Params() SubmitParams {
+	return SubmitParams{
+		Kind:           req.Kind,
+		OfficeID:       req.OfficeID,
+		ApplicantName:  req.ApplicantName,
+		ApplicantEmail: req.ApplicantEmail,
+		ApplicantPhone: req.ApplicantPhone,
+		PersonalCode:   req.PersonalCode,
+		Details:        req.Details,
+	}
+}
+
+type TrackRequest struct {
+	Number string `json:"number" example:"APP-2026-000123"`
+	Email  string `json:"email" example:"jonas@example.com"`
+}
+
+type RespondRequest struct {
+	Number string `json:"number" example:"APP-2026-000123"`
+	Email  string `json:"email" example:"jonas@example.com"`
+	Note   string `json:"note" example:"The marriage took place in Kaunas in 1998."`
+}
+
+type TransitionRequest struct {
+	Status string `json:"status" enums:"in_review,needs_info,approved,rejected" example:"in_review"`
+	Note   string `json:"note,omitempty"`
+}
+
+// Params converts the request into service parameters.
+func (req TransitionRequest) Params() TransitionParams {
+	return TransitionParams{
+		Status: req.Status,
+		Note:   req.Note,
+	}
+}
+
+// Submit files a citizen application
+// @Summary Submit application
+// @Description File a certificate request, name change or residence declaration with an office. The details
+// @Description object carries the fields of the chosen kind: certificate_kind, copies and purpose; new_first_name,
+// @Description new_last_name and reason; or municipality, street, house, flat, postal_code and moved_in_on. The
+// @Description response carries the application number used to track it.
+// @Tags application
+// @Accept json
+// @Produce json
+// @Param request body SubmitRequest true "application data"
+// @Success 201 {object} TrackingEnvelope "Submitted application"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 404 {object} map[string]interface{} "Office not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Office does not accept applications"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/applications [post]
+func (h *Handlers) Submit(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var req SubmitRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.Submit(r.Context(), req.Params())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusCreated, TrackingEnvelope{
+		Message: "application submitted successfully",
+		Data:    NewTrackingResponse(*result),
+	})
+}
+
+// Track shows an application to its applicant
+// @Summary Track application
+// @Description Look up the status and history of an application by its number and the applicant's email address
+// @Tags application
+// @Accept json
+// @Produce json
+// @Param request body TrackRequest true "number and email"
+// @Success 200 {object} TrackingEnvelope "Application status"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 404 {object} map[string]interface{} "Application not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/applications/track [post]
+func (h *Handlers) Track(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var req TrackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.Track(r.Context(), req.Number, req.Email)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, TrackingEnvelope{Data: NewTrackingResponse(*result)})
+}
+
+// Respond answers a request for information
+// @Summary Answer request for information
+// @Description Answer a registrar's request for more information. The application returns to the queue as submitted.
+// @Tags application
+// @Accept json
+// @Produce json
+// @Param request body RespondRequest true "number, email and answer"
+// @Success 200 {object} TrackingEnvelope "Resubmitted application"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 404 {object} map[string]interface{} "Application not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Not waiting for information"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/applications/track/respond [post]
+func (h *Handlers) Respond(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var req RespondRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.Respond(r.Context(), req.Number, req.Email, req.Note)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, TrackingEnvelope{
+		Message: "answer sent successfully",
+		Data:    NewTrackingResponse(*result),
+	})
+}
+
+// Queue lists the office's applications
+// @Summary Application queue
+// @Description List the signed-in registrar's office applications, oldest first
+// @Tags application
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "submitted, in_review, needs_info, approved or rejected"
+// @Param kind query string false "certificate_request, name_change or residence_declaration"
+// @Param mine query bool false "only applications assigned to me"
+// @Param unassigned query bool false "only applications nobody has picked up"
+// @Param limit query int false "maximum applications (default 50, max 200)"
+// @Param offset query int false "applications to skip"
+// @Success 200 {object} QueueEnvelope "Applications"
+// @Failure 400 {object} map[string]interface{} "Invalid filter"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/applications [get]
+func (h *Handlers) Queue(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	arg := QueueFilter{
+		Status:     request.QueryText(r, "status"),
+		Kind:       request.QueryText(r, "kind"),
+		Mine:       r.URL.Query().Get("mine") == "true",
+		Unassigned: r.URL.Query().Get("unassigned") == "true",
+	}
+	var err error
+	if arg.Limit, arg.Offset, err = request.Page(r); err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.Queue(r.Context(), arg)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+	items, err := NewApplicationResponses(result)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, QueueEnvelope{
+		Count: len(items),
+		Data:  items,
+	})
+}
+
+// Get retrieves an application
+// @Summary Get application
+// @Description Get an application of the registrar's office with its status history
+// @Tags application
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "application ID"
+// @Success 200 {object} CaseEnvelope "Application"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Application of another office"
+// @Failure 404 {object} map[string]interface{} "Application not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/applications/{id} [get]
+func (h *Handlers) Get(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.Get(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+	data, err := NewCaseResponse(*result)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, CaseEnvelope{Data: data})
+}
+
+// Transition changes an application's status
+// @Summary Change application status
+// @Description Move an application along the workflow: submitted to in_review, in_review to needs_info, approved
+// @Description or rejected, and needs_info back to in_review. Taking an application into review assigns it to
+// @Description the registrar. A note is required for needs_info and rejected and is shown to the applicant.
+// @Tags application
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "application ID"
+// @Param request body TransitionRequest true "new status"
+// @Success 200 {object} CaseEnvelope "Updated application"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Application of another office"
+// @Failure 404 {object} map[string]interface{} "Application not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Transition not allowed"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/applications/{id}/status [post]
+func (h *Handlers) Transition(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+	var req TransitionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.Transition(r.Context(), id, req.Params())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+	data, err := NewCaseResponse(*result)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, CaseEnvelope{
+		Message: "application status changed successfully",
+		Data:    data,
+	})
+}
+
+func (h *Handlers) write(w http.ResponseWriter, status int, body any) {
+	if err := render.Write(w, status, render.ContentTypeJSON, body); err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// serviceError maps a service failure onto an HTTP status.
+func (h *Handlers) serviceError(w http.ResponseWriter, err error) {
+	status, msg := apperr.Status(err)
+	if status >= http.StatusInternalServerError {
+		h.logger.Errorf("Service error: %v", err)
+	}
+	if status == http.StatusNotFound && msg == "not found" {
+		msg = "application not found"
+	}
+	http.Error(w, msg, status)
+}
@@ -0,0 +1,157 @@
+package application
+
+import (
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/office"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+)
+
+// ApplicationResponse is the wire form of repository.Application as
+// registrars see it.
+type ApplicationResponse struct {
+	ID             uuid.UUID  `json:"id"`
+	Number         string     `json:"number"`
+	Kind           string     `json:"kind"`
+	Status         string     `json:"status"`
+	OfficeID       uuid.UUID  `json:"office_id"`
+	ApplicantName  string     `json:"applicant_name"`
+	ApplicantEmail string     `json:"applicant_email"`
+	ApplicantPhone string     `json:"applicant_phone"`
+	PersonalCode   string     `json:"personal_code"`
+	Details        Details    `json:"details"`
+	AssignedTo     *uuid.UUID `json:"assigned_to"`
+	SubmittedAt    time.Time  `json:"submitted_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+	DecidedAt      *time.Time `json:"decided_at"`
+}
+
+func NewApplicationResponse(row repository.Application) (ApplicationResponse, error) {
+	details, err := DecodeDetails(row)
+	if err != nil {
+		return ApplicationResponse{}, err
+	}
+	return ApplicationResponse{
+		ID:             row.ID,
+		Number:         row.Number,
+		Kind:           row.Kind,
+		Status:         row.Status,
+		OfficeID:       row.OfficeID,
+		ApplicantName:  row.ApplicantName,
+		ApplicantEmail: row.ApplicantEmail,
+		ApplicantPhone: row.ApplicantPhone,
+		PersonalCode:   row.PersonalCode,
+		Details:        details,
+		AssignedTo:     render.Nullable(uuid.UUID(row.AssignedTo.Bytes), row.AssignedTo.Valid),
+		SubmittedAt:    row.SubmittedAt,
+		UpdatedAt:      row.UpdatedAt,
+		DecidedAt:      render.Nullable(row.DecidedAt.Time, row.DecidedAt.Valid),
+	}, nil
+}
+
+func NewApplicationResponses(rows []repository.Application) ([]ApplicationResponse, error) {
+	items := make([]ApplicationResponse, 0, len(rows))
+	for _, row := range rows {
+		item, err := NewApplicationResponse(row)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// StatusChangeResponse is the wire form of repository.ApplicationStatusHistory.
+type StatusChangeResponse struct {
+	FromStatus *string   `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Note       string    `json:"note"`
+	ChangedBy  string    `json:"changed_by"`
+	ChangedAt  time.Time `json:"changed_at"`
+}
+
+func NewStatusChangeResponses(rows []repository.ApplicationStatusHistory) []StatusChangeResponse {
+	items := make([]StatusChangeResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, StatusChangeResponse{
+			FromStatus: render.Nullable(row.FromStatus.String, row.FromStatus.Valid),
+			ToStatus:   row.ToStatus,
+			Note:       row.Note,
+			ChangedBy:  row.ChangedBy,
+			ChangedAt:  row.ChangedAt,
+		})
+	}
+	return items
+}
+
+// CaseResponse is an application with its office and status history.
+type CaseResponse struct {
+	Application ApplicationResponse    `json:"application"`
+	Office      office.OfficeResponse  `json:"office"`
+	History     []StatusChangeResponse `json:"history"`
+}
+
+func NewCaseResponse(c Case) (CaseResponse, error) {
+	a, err := NewApplicationResponse(c.Application)
+	if err != nil {
+		return CaseResponse{}, err
+	}
+	return CaseResponse{
+		Application: a,
+		Office:      office.NewOfficeResponse(c.Office),
+		History:     NewStatusChangeResponses(c.History),
+	}, nil
+}
+
+// TrackingResponse is what the applicant sees when tracking an application.
+// It leaves out the personal code and the names of the registrars.
+type TrackingResponse struct {
+	Number      string                 `json:"number"`
+	Kind        string                 `json:"kind"`
+	Status      string                 `json:"status"`
+	Office      office.OfficeResponse  `json:"office"`
+	SubmittedAt time.Time              `json:"submitted_at"`
+	UpdatedAt   time.Time              `json:"updated_at"`
+	DecidedAt   *time.Time             `json:"decided_at"`
+	History     []StatusChangeResponse `json:"history"`
+}
+
+func NewTrackingResponse(c Case) TrackingResponse {
+	history := NewStatusChangeResponses(c.History)
+	for i := range history {
+		if history[i].ChangedBy != ChangedByApplicant {
+			history[i].ChangedBy = "registrar"
+		}
+	}
+	a := c.Application
+	return TrackingResponse{
+		Number:      a.Number,
+		Kind:        a.Kind,
+		Status:      a.Status,
+		Office:      office.NewOfficeResponse(c.Office),
+		SubmittedAt: a.SubmittedAt,
+		UpdatedAt:   a.UpdatedAt,
+		DecidedAt:   render.Nullable(a.DecidedAt.Time, a.DecidedAt.Valid),
+		History:     history,
+	}
+}
+
+// CaseEnvelope is the response body for a single application.
+type CaseEnvelope struct {
+	Message string       `json:"message,omitempty"`
+	Data    CaseResponse `json:"data"`
+}
+
+// TrackingEnvelope is the response body for the applicant's view.
+type TrackingEnvelope struct {
+	Message string           `json:"message,omitempty"`
+	Data    TrackingResponse `json:"data"`
+}
+
+// QueueEnvelope is the response body for the clerk queue.
+type QueueEnvelope struct {
+	Count int                   `json:"count"`
+	Data  []ApplicationResponse `json:"data"`
+}
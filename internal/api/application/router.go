@@ -0,0 +1,25 @@
+package application
+
+import (
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func ApplicationRouter(db txn.Beginner, queries *repository.Queries, log *zap.SugaredLogger) chi.Router {
+	r := chi.NewRouter()
+
+	service := NewService(db, queries, log)
+	handlers := NewHandlers(service, log)
+
+	r.Post("/", telemetry.InstrumentHandler("application", "Submit", handlers.Submit))
+	r.Get("/", telemetry.InstrumentHandler("application", "Queue", handlers.Queue))
+	r.Post("/track", telemetry.InstrumentHandler("application", "Track", handlers.Track))
+	r.Post("/track/respond", telemetry.InstrumentHandler("application", "Respond", handlers.Respond))
+	r.Get("/{id}", telemetry.InstrumentHandler("application", "Get", handlers.Get))
+	r.Post("/{id}/status", telemetry.InstrumentHandler("application", "Transition", handlers.Transition))
+
+	return r
+}
@@ -0,0 +1,566 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"github.com/eif-courses/civilregistry/internal/api/certificate"
+	"github.com/eif-courses/civilregistry/internal/api/residence"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/personalcode"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Kinds of applications citizens can submit.
+const (
+	KindCertificateRequest   = "certificate_request"
+	KindNameChange           = "name_change"
+	KindResidenceDeclaration = "residence_declaration"
+)
+
+// Application statuses. Approved and rejected are final.
+const (
+	StatusSubmitted = "submitted"
+	StatusInReview  = "in_review"
+	StatusNeedsInfo = "needs_info"
+	StatusApproved  = "approved"
+	StatusRejected  = "rejected"
+
+	// ChangedByApplicant marks status changes made by the applicant
+	// rather than by a registrar.
+	ChangedByApplicant = "applicant"
+)
+
+const (
+	defaultQueueLimit = 50
+	maxQueueLimit     = 200
+	maxCopies         = 10
+)
+
+// Statuses lists the application statuses in workflow order.
+var Statuses = []string{StatusSubmitted, StatusInReview, StatusNeedsInfo, StatusApproved, StatusRejected}
+
+// Kinds lists the application kinds.
+var Kinds = []string{KindCertificateRequest, KindNameChange, KindResidenceDeclaration}
+
+// clerkTransitions lists the statuses a registrar may move an application
+// to from each status. The applicant moves needs_info back to submitted by
+// answering; see Respond.
+var clerkTransitions = map[string][]string{
+	StatusSubmitted: {StatusInReview},
+	StatusInReview:  {StatusNeedsInfo, StatusApproved, StatusRejected},
+	StatusNeedsInfo: {StatusInReview},
+}
+
+type Service struct {
+	db     txn.Beginner
+	repo   *repository.Queries
+	logger *zap.SugaredLogger
+}
+
+func NewService(db txn.Beginner, repo *repository.Queries, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		db:     db,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Details holds the kind-specific fields of an application. Only the
+// fields of the application's kind are kept.
+type Details struct {
+	// certificate_request
+	CertificateKind string `json:"certificate_kind,omitempty"`
+	Copies          int    `json:"copies,omitempty"`
+	Purpose         string `json:"purpose,omitempty"`
+
+	// name_change
+	NewFirstName string `json:"new_first_name,omitempty"`
+	NewLastName  string `json:"new_last_name,omitempty"`
+	Reason       string `json:"reason,omitempty"`
+
+	// residence_declaration
+	Municipality string       `json:"municipality,omitempty"`
+	Street       string       `json:"street,omitempty"`
+	House        string       `json:"house,omitempty"`
+	Flat         string       `json:"flat,omitempty"`
+	PostalCode   string       `json:"postal_code,omitempty"`
+	MovedInOn    *render.Date `json:"moved_in_on,omitempty" swaggertype:"string" format:"date"`
+}
+
+// SubmitParams describes a citizen's application to an office.
+type SubmitParams struct {
+	Kind           string
+	OfficeID       uuid.UUID
+	ApplicantName  string
+	ApplicantEmail string
+	ApplicantPhone string
+	PersonalCode   string
+	Details        Details
+}
+
+// TransitionParams moves an application to Status. Note is required when
+// asking for more information or rejecting.
+type TransitionParams struct {
+	Status string
+	Note   string
+}
+
+// QueueFilter narrows the clerk queue. Mine keeps applications assigned to
+// the signed-in registrar and Unassigned those nobody has picked up.
+type QueueFilter struct {
+	Status     pgtype.Text
+	Kind       pgtype.Text
+	Mine       bool
+	Unassigned bool
+	Limit      int32
+	Offset     int32
+}
+
+// Case is an application with its office and status history, oldest
+// change first.
+type Case struct {
+	Application repository.Application
+	Office      repository.Office
+	History     []repository.ApplicationStatusHistory
+}
+
+// Submit records a new application in the submitted status and returns it
+// with the number the applicant tracks it by.
+func (s *Service) Submit(ctx context.Context, arg SubmitParams) (_ *Case, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "application", "Submit")
+	defer func() { op.End(err) }()
+
+	arg.ApplicantName = strings.TrimSpace(arg.ApplicantName)
+	arg.ApplicantEmail = strings.TrimSpace(arg.ApplicantEmail)
+	arg.ApplicantPhone = strings.TrimSpace(arg.ApplicantPhone)
+	arg.PersonalCode = strings.TrimSpace(arg.PersonalCode)
+	if arg.ApplicantName == "" {
+		return nil, apperr.Invalid("applicant_name is required")
+	}
+	if addr, err := mail.ParseAddress(arg.ApplicantEmail); err != nil || addr.Address != arg.ApplicantEmail {
+		return nil, apperr.Invalid("applicant_email must be a valid email address")
+	}
+	if err := personalcode.Validate(arg.PersonalCode); err != nil {
+		return nil, apperr.Invalid("personal_code: %v", err)
+	}
+	details, err := normalizeDetails(arg.Kind, arg.Details)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(details)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode application details: %w", err)
+	}
+
+	var c Case
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		c.Office, err = q.GetOfficeByID(ctx, arg.OfficeID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.NotFound("office %s not found", arg.OfficeID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed GetOfficeByID: %w", err)
+		}
+		if !c.Office.Active {
+			return apperr.Conflict("office %s does not accept applications", c.Office.Code)
+		}
+
+		c.Application, err = q.CreateApplication(ctx, repository.CreateApplicationParams{
+			Kind:           arg.Kind,
+			OfficeID:       arg.OfficeID,
+			ApplicantName:  arg.ApplicantName,
+			ApplicantEmail: arg.ApplicantEmail,
+			ApplicantPhone: arg.ApplicantPhone,
+			PersonalCode:   arg.PersonalCode,
+			Details:        body,
+		})
+		if err != nil {
+			return fmt.Errorf("failed CreateApplication: %w", err)
+		}
+		change, err := q.CreateApplicationStatusChange(ctx, repository.CreateApplicationStatusChangeParams{
+			ApplicationID: c.Application.ID,
+			ToStatus:      StatusSubmitted,
+			ChangedBy:     ChangedByApplicant,
+		})
+		if err != nil {
+			return fmt.Errorf("failed CreateApplicationStatusChange: %w", err)
+		}
+		c.History = []repository.ApplicationStatusHistory{change}
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorf("Failed Submit: %v", err)
+		return nil, err
+	}
+
+	s.logger.Infof("Submit completed successfully with number: %s", c.Application.Number)
+	return &c, nil
+}
+
+// Track looks an application up for its applicant. The email address must
+// match the one it was submitted with; a mismatch reads as not found so
+// numbers cannot be probed.
+func (s *Service) Track(ctx context.Context, number, email string) (_ *Case, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "application", "Track")
+	defer func() { op.End(err) }()
+
+	number = normalizeNumber(number)
+	a, err := s.repo.GetApplicationByNumber(ctx, number)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed GetApplicationByNumber: %w", err)
+	}
+	if err != nil || !strings.EqualFold(a.ApplicantEmail, strings.TrimSpace(email)) {
+		return nil, apperr.NotFound("no application %s for that email address", number)
+	}
+	return s.load(ctx, s.repo, a)
+}
+
+// Respond answers a request for more information and returns the
+// application to the queue as submitted.
+func (s *Service) Respond(ctx context.Context, number, email, note string) (_ *Case, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "application", "Respond")
+	defer func() { op.End(err) }()
+
+	note = strings.TrimSpace(note)
+	if note == "" {
+		return nil, apperr.Invalid("note is required")
+	}
+	found, err := s.Track(ctx, number, email)
+	if err != nil {
+		return nil, err
+	}
+
+	var c *Case
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		a, err := q.GetApplicationForUpdate(ctx, found.Application.ID)
+		if err != nil {
+			return fmt.Errorf("failed GetApplicationForUpdate: %w", err)
+		}
+		if a.Status != StatusNeedsInfo {
+			return apperr.Conflict("application %s is not waiting for information", a.Number)
+		}
+		a, err = setStatus(ctx, q, a, StatusSubmitted, note, ChangedByApplicant, pgtype.UUID{})
+		if err != nil {
+			return err
+		}
+		c, err = s.load(ctx, q, a)
+		return err
+	})
+	if err != nil {
+		s.logger.Errorf("Failed Respond: %v", err)
+		return nil, err
+	}
+	return c, nil
+}
+
+// Get returns an application of the signed-in registrar's office.
+func (s *Service) Get(ctx context.Context, id uuid.UUID) (_ *Case, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "application", "Get")
+	defer func() { op.End(err) }()
+
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	a, err := s.repo.GetApplicationByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetApplicationByID: %w", err)
+	}
+	if a.OfficeID != p.Office.ID {
+		return nil, apperr.Forbidden("the application belongs to another office")
+	}
+	return s.load(ctx, s.repo, a)
+}
+
+// Transition moves an application of the registrar's office along the
+// workflow. Taking an application into review assigns it to the registrar.
+// The row is locked so two clerks cannot decide it at once.
+func (s *Service) Transition(ctx context.Context, id uuid.UUID, arg TransitionParams) (_ *Case, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "application", "Transition")
+	defer func() { op.End(err) }()
+
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	arg.Note = strings.TrimSpace(arg.Note)
+	if err := validateStatus(arg.Status); err != nil {
+		return nil, err
+	}
+	if (arg.Status == StatusNeedsInfo || arg.Status == StatusRejected) && arg.Note == "" {
+		return nil, apperr.Invalid("note is required when moving an application to %s", arg.Status)
+	}
+
+	var c *Case
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		a, err := q.GetApplicationForUpdate(ctx, id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.NotFound("application %s not found", id)
+		}
+		if err != nil {
+			return fmt.Errorf("failed GetApplicationForUpdate: %w", err)
+		}
+		if a.OfficeID != p.Office.ID {
+			return apperr.Forbidden("the application belongs to another office")
+		}
+		if !CanTransition(a.Status, arg.Status) {
+			return apperr.Conflict("application %s cannot move from %s to %s", a.Number, a.Status, arg.Status)
+		}
+
+		var assign pgtype.UUID
+		if arg.Status == StatusInReview {
+			assign = pgtype.UUID{Bytes: p.Registrar.ID, Valid: true}
+		}
+		a, err = setStatus(ctx, q, a, arg.Status, arg.Note, p.Name(), assign)
+		if err != nil {
+			return err
+		}
+		c, err = s.load(ctx, q, a)
+		return err
+	})
+	if err != nil {
+		s.logger.Errorf("Failed Transition: %v", err)
+		return nil, err
+	}
+
+	s.logger.Infof("Transition completed successfully: %s is %s", c.Application.Number, c.Application.Status)
+	return c, nil
+}
+
+// Queue lists the signed-in registrar's office applications, oldest first.
+func (s *Service) Queue(ctx context.Context, arg QueueFilter) (_ []repository.Application, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "application", "Queue")
+	defer func() { op.End(err) }()
+
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if arg.Status.Valid {
+		if err := validateStatus(arg.Status.String); err != nil {
+			return nil, err
+		}
+	}
+	if arg.Kind.Valid {
+		if err := validateKind(arg.Kind.String); err != nil {
+			return nil, err
+		}
+	}
+	if arg.Mine && arg.Unassigned {
+		return nil, apperr.Invalid("mine and unassigned cannot be combined")
+	}
+	limit := arg.Limit
+	if limit <= 0 {
+		limit = defaultQueueLimit
+	}
+	if limit > maxQueueLimit {
+		return nil, apperr.Invalid("limit must not exceed %d", maxQueueLimit)
+	}
+	if arg.Offset < 0 {
+		return nil, apperr.Invalid("offset must not be negative")
+	}
+
+	var assignedTo pgtype.UUID
+	if arg.Mine {
+		assignedTo = pgtype.UUID{Bytes: p.Registrar.ID, Valid: true}
+	}
+	result, err := s.repo.ListApplicationQueue(ctx, repository.ListApplicationQueueParams{
+		OfficeID:   p.Office.ID,
+		Status:     arg.Status,
+		Kind:       arg.Kind,
+		AssignedTo: assignedTo,
+		Unassigned: arg.Unassigned,
+		RowLimit:   limit,
+		RowOffset:  arg.Offset,
+	})
+	if err != nil {
+		s.logger.Errorf("Failed ListApplicationQueue: %v", err)
+		return nil, fmt.Errorf("failed ListApplicationQueue: %w", err)
+	}
+	return result, nil
+}
+
+// CountByStatus returns how many applications of the registrar's office
+// are in each status.
+func (s *Service) CountByStatus(ctx context.Context) (_ map[string]int64, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "application", "CountByStatus")
+	defer func() { op.End(err) }()
+
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.CountApplicationsByStatus(ctx, p.Office.ID)
+	if err != nil {
+		s.logger.Errorf("Failed CountApplicationsByStatus: %v", err)
+		return nil, fmt.Errorf("failed CountApplicationsByStatus: %w", err)
+	}
+	counts := make(map[string]int64, len(Statuses))
+	for _, status := range Statuses {
+		counts[status] = 0
+	}
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+// CanTransition reports whether a registrar may move an application from
+// one status to another.
+func CanTransition(from, to string) bool {
+	for _, next := range clerkTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// NextStatuses returns the statuses a registrar may move an application to.
+func NextStatuses(status string) []string {
+	return clerkTransitions[status]
+}
+
+// DecodeDetails reads the kind-specific fields stored with an application.
+func DecodeDetails(a repository.Application) (Details, error) {
+	var details Details
+	if err := json.Unmarshal(a.Details, &details); err != nil {
+		return Details{}, fmt.Errorf("failed to decode application %s: %w", a.Number, err)
+	}
+	return details, nil
+}
+
+func (s *Service) load(ctx context.Context, q *repository.Queries, a repository.Application) (*Case, error) {
+	o, err := q.GetOfficeByID(ctx, a.OfficeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetOfficeByID: %w", err)
+	}
+	history, err := q.ListApplicationStatusHistory(ctx, a.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed ListApplicationStatusHistory: %w", err)
+	}
+	return &Case{Application: a, Office: o, History: history}, nil
+}
+
+func setStatus(ctx context.Context, q *repository.Queries, a repository.Application, status, note, by string, assign pgtype.UUID) (repository.Application, error) {
+	updated, err := q.SetApplicationStatus(ctx, repository.SetApplicationStatusParams{
+		ID:         a.ID,
+		Status:     status,
+		AssignedTo: assign,
+	})
+	if err != nil {
+		return repository.Application{}, fmt.Errorf("failed SetApplicationStatus: %w", err)
+	}
+	_, err = q.CreateApplicationStatusChange(ctx, repository.CreateApplicationStatusChangeParams{
+		ApplicationID: a.ID,
+		FromStatus:    pgtype.Text{String: a.Status, Valid: true},
+		ToStatus:      status,
+		Note:          note,
+		ChangedBy:     by,
+	})
+	if err != nil {
+		return repository.Application{}, fmt.Errorf("failed CreateApplicationStatusChange: %w", err)
+	}
+	return updated, nil
+}
+
+// normalizeDetails validates the fields of kind and drops the others.
+func normalizeDetails(kind string, d Details) (Details, error) {
+	if err := validateKind(kind); err != nil {
+		return Details{}, err
+	}
+	switch kind {
+	case KindCertificateRequest:
+		out := Details{
+			CertificateKind: d.CertificateKind,
+			Copies:          d.Copies,
+			Purpose:         strings.TrimSpace(d.Purpose),
+		}
+		switch out.CertificateKind {
+		case certificate.KindBirth, certificate.KindMarriage, certificate.KindDeath:
+		default:
+			return Details{}, apperr.Invalid("certificate_kind must be %q, %q or %q",
+				certificate.KindBirth, certificate.KindMarriage, certificate.KindDeath)
+		}
+		if out.Copies == 0 {
+			out.Copies = 1
+		}
+		if out.Copies < 1 || out.Copies > maxCopies {
+			return Details{}, apperr.Invalid("copies must be between 1 and %d", maxCopies)
+		}
+		return out, nil
+
+	case KindNameChange:
+		out := Details{
+			NewFirstName: strings.TrimSpace(d.NewFirstName),
+			NewLastName:  strings.TrimSpace(d.NewLastName),
+			Reason:       strings.TrimSpace(d.Reason),
+		}
+		if out.NewFirstName == "" && out.NewLastName == "" {
+			return Details{}, apperr.Invalid("new_first_name or new_last_name is required")
+		}
+		if out.Reason == "" {
+			return Details{}, apperr.Invalid("reason is required")
+		}
+		return out, nil
+
+	default:
+		out := Details{
+			Municipality: strings.TrimSpace(d.Municipality),
+			Street:       strings.TrimSpace(d.Street),
+			House:        strings.TrimSpace(d.House),
+			Flat:         strings.TrimSpace(d.Flat),
+			MovedInOn:    d.MovedInOn,
+		}
+		if out.Municipality == "" || out.House == "" {
+			return Details{}, apperr.Invalid("municipality and house are required")
+		}
+		if code := strings.TrimSpace(d.PostalCode); code != "" {
+			normalized, ok := residence.NormalizePostalCode(code)
+			if !ok {
+				return Details{}, apperr.Invalid("postal_code must look like LT-01100")
+			}
+			out.PostalCode = normalized
+		}
+		if out.MovedInOn == nil || out.MovedInOn.IsZero() {
+			return Details{}, apperr.Invalid("moved_in_on is required")
+		}
+		return out, nil
+	}
+}
+
+func validateKind(kind string) error {
+	for _, k := range Kinds {
+		if k == kind {
+			return nil
+		}
+	}
+	return apperr.Invalid("kind must be %q, %q or %q", KindCertificateRequest, KindNameChange, KindResidenceDeclaration)
+}
+
+func validateStatus(status string) error {
+	for _, s := range Statuses {
+		if s == status {
+			return nil
+		}
+	}
+	return apperr.Invalid("status must be one of %s", strings.Join(Statuses, ", "))
+}
+
+// normalizeNumber accepts application numbers typed in lower case.
+func normalizeNumber(number string) string {
+	return strings.ToUpper(strings.TrimSpace(number))
+}
@@ -0,0 +1,413 @@
+package appointment
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/request"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	service *Service
+	logger  *zap.SugaredLogger
+}
+
+func NewHandlers(service *Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type CreateSlotRequest struct {
+	Kind     string    `json:"kind" enums:"marriage_ceremony,office_visit" example:"marriage_ceremony"`
+	StartsAt time.Time `json:"starts_at" example:"2026-11-14T12:00:00+02:00"`
+	EndsAt   time.Time `json:"ends_at" example:"2026-11-14T12:30:00+02:00"`
+	Capacity int32     `json:"capacity" example:"1"`
+}
+
+// Params converts the request into service parameters.
+func (req CreateSlotRequest) Params() CreateSlotParams {
+	return CreateSlotParams{
+		Kind:     req.Kind,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+		Capacity: req.Capacity,
+	}
+}
+
+type BookRequest struct {
+	SlotID       uuid.UUID `json:"slot_id"`
+	FullName     string    `json:"full_name" example:"Jonas Jonaitis"`
+	Email        string    `json:"email" example:"jonas@example.com"`
+	Phone        string    `json:"phone,omitempty" example:"+37060000000"`
+	PersonalCode *string   `json:"personal_code,omitempty" example:"39001010008"`
+	Notes        string    `json:"notes,omitempty"`
+}
+
+// Params converts the request into service parameters.
+func (req BookRequest) Params() BookParams {
+	return BookParams{
+		SlotID:       req.SlotID,
+		FullName:     req.FullName,
+		Email:        req.Email,
+		Phone:        req.Phone,
+		PersonalCode: request.Text(req.PersonalCode),
+		Notes:        req.Notes,
+	}
+}
+
+// CreateSlot adds a slot to the office calendar
+// @Summary Create appointment slot
+// @Description Add bookable time to the signed-in registrar's office. Slots of the same kind may not overlap.
+// @Tags appointment
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateSlotRequest true "slot data"
+// @Success 201 {object} SlotEnvelope "Created slot"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Slot overlaps another slot"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/appointments/slots [post]
+func (h *Handlers) CreateSlot(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var req CreateSlotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.CreateSlot(r.Context(), req.Params())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusCreated, SlotEnvelope{
+		Message: "slot created successfully",
+		Data:    NewSlotResponse(*result),
+	})
+}
+
+// ListAvailableSlots searches for free slots
+// @Summary Available appointment slots
+// @Description List future slots with free places in active offices, earliest first. The days from and to are
+// @Description inclusive and default to the next two weeks.
+// @Tags appointment
+// @Produce json
+// @Param office_id query string false "office ID"
+// @Param kind query string false "marriage_ceremony or office_visit"
+// @Param from query string false "first day as YYYY-MM-DD"
+// @Param to query string false "last day as YYYY-MM-DD"
+// @Param limit query int false "maximum slots (default 100, max 500)"
+// @Success 200 {object} SlotListEnvelope "Available slots"
+// @Failure 400 {object} map[string]interface{} "Invalid filter"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/appointments/slots [get]
+func (h *Handlers) ListAvailableSlots(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	arg := SlotFilter{Kind: request.QueryText(r, "kind")}
+	var err error
+	if r.URL.Query().Get("office_id") != "" {
+		id, err := request.QueryUUID(r, "office_id")
+		if err != nil {
+			h.serviceError(w, err)
+			return
+		}
+		arg.OfficeID = pgtype.UUID{Bytes: id, Valid: true}
+	}
+	if arg.From, err = request.QueryDate(r, "from"); err != nil {
+		h.serviceError(w, err)
+		return
+	}
+	if arg.To, err = request.QueryDate(r, "to"); err != nil {
+		h.serviceError(w, err)
+		return
+	}
+	if arg.Limit, err = request.QueryInt32(r, "limit"); err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.ListAvailableSlots(r.Context(), arg)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, SlotListEnvelope{
+		Count: len(result),
+		Data:  NewSlotResponses(result),
+	})
+}
+
+// DeleteSlot removes an unused slot
+// @Summary Delete appointment slot
+// @Description Remove a slot of the registrar's office that was never booked
+// @Tags appointment
+// @Security BearerAuth
+// @Param id path string true "slot ID"
+// @Success 204 "Slot deleted"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Slot of another office"
+// @Failure 404 {object} map[string]interface{} "Slot not found"
+// @Failure 409 {object} map[string]interface{} "Slot has bookings"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/appointments/slots/{id} [delete]
+func (h *Handlers) DeleteSlot(w http.ResponseWriter, r *http.Request) {
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	if err := h.service.DeleteSlot(r.Context(), id); err != nil {
+		h.serviceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Book books a place in a slot
+// @Summary Book appointment
+// @Description Book a place in a slot. The response carries the reference needed to look up or cancel the
+// @Description booking. Marriage ceremonies need the personal code of the citizen booking them. One email
+// @Description address cannot hold two bookings at the same time.
+// @Tags appointment
+// @Accept json
+// @Produce json
+// @Param request body BookRequest true "booking data"
+// @Success 201 {object} BookingEnvelope "Booking"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 404 {object} map[string]interface{} "Slot not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Slot full, started or clashing with another booking"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/appointments/bookings [post]
+func (h *Handlers) Book(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var req BookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.Book(r.Context(), req.Params())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/appointments/bookings/"+result.Appointment.Reference)
+	h.write(w, http.StatusCreated, BookingEnvelope{
+		Message: "appointment booked successfully",
+		Data:    NewBookingResponse(*result),
+	})
+}
+
+// GetBooking retrieves a booking
+// @Summary Get booking
+// @Description Get a booking by its reference
+// @Tags appointment
+// @Produce json
+// @Param reference path string true "booking reference"
+// @Success 200 {object} BookingEnvelope "Booking"
+// @Failure 404 {object} map[string]interface{} "Booking not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/appointments/bookings/{reference} [get]
+func (h *Handlers) GetBooking(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	result, err := h.service.GetBooking(r.Context(), chi.URLParam(r, "reference"))
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, BookingEnvelope{Data: NewBookingResponse(*result)})
+}
+
+// CancelBooking cancels a booking
+// @Summary Cancel booking
+// @Description Cancel an upcoming booking and free its place. Anyone with the reference may cancel; a registrar
+// @Description of the slot's office is recorded by name.
+// @Tags appointment
+// @Produce json
+// @Param reference path string true "booking reference"
+// @Success 200 {object} BookingEnvelope "Cancelled booking"
+// @Failure 404 {object} map[string]interface{} "Booking not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Already cancelled or in the past"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/appointments/bookings/{reference}/cancel [post]
+func (h *Handlers) CancelBooking(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	result, err := h.service.Cancel(r.Context(), chi.URLParam(r, "reference"))
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, BookingEnvelope{
+		Message: "booking cancelled successfully",
+		Data:    NewBookingResponse(*result),
+	})
+}
+
+// Calendar shows the office calendar
+// @Summary Office calendar
+// @Description List the signed-in registrar's office slots and their bookings for a week from the given day
+// @Tags appointment
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "first day as YYYY-MM-DD (default today)"
+// @Success 200 {object} CalendarEnvelope "Calendar"
+// @Failure 400 {object} map[string]interface{} "Invalid day"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/appointments/calendar [get]
+func (h *Handlers) Calendar(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	from, err := request.QueryDate(r, "from")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.Calendar(r.Context(), from)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, CalendarEnvelope{Data: NewCalendarResponse(*result)})
+}
+
+// ListPendingReminders lists reminders to send
+// @Summary Pending reminders
+// @Description List queued reminders for upcoming bookings in the registrar's office that were not sent yet.
+// @Description Reminders are queued in the background for bookings starting within REMINDER_LEAD.
+// @Tags appointment
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} ReminderListEnvelope "Pending reminders"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/appointments/reminders [get]
+func (h *Handlers) ListPendingReminders(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	result, err := h.service.ListPendingReminders(r.Context())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, ReminderListEnvelope{
+		Count: len(result),
+		Data:  NewPendingReminderResponses(result),
+	})
+}
+
+// MarkReminderSent records a delivered reminder
+// @Summary Mark reminder sent
+// @Description Record that a reminder was delivered so it leaves the pending list
+// @Tags appointment
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "reminder ID"
+// @Success 200 {object} ReminderEnvelope "Sent reminder"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 404 {object} map[string]interface{} "No unsent reminder"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/appointments/reminders/{id}/sent [post]
+func (h *Handlers) MarkReminderSent(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.MarkReminderSent(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, ReminderEnvelope{
+		Message: "reminder marked as sent",
+		Data:    NewReminderResponse(*result),
+	})
+}
+
+func (h *Handlers) write(w http.ResponseWriter, status int, body any) {
+	if err := render.Write(w, status, render.ContentTypeJSON, body); err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// serviceError maps a service failure onto an HTTP status.
+func (h *Handlers) serviceError(w http.ResponseWriter, err error) {
+	status, msg := apperr.Status(err)
+	if status >= http.StatusInternalServerError {
+		h.logger.Errorf("Service error: %v", err)
+	}
+	if status == http.StatusNotFound && msg == "not found" {
+		msg = "slot or booking not found"
+	}
+	http.Error(w, msg, status)
+}
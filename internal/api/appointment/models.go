@@ -0,0 +1,197 @@
+package appointment
+
+import (
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/office"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+)
+
+// SlotResponse is the wire form of repository.AppointmentSlot. Available
+// is the number of free places.
+type SlotResponse struct {
+	ID        uuid.UUID `json:"id"`
+	OfficeID  uuid.UUID `json:"office_id"`
+	Kind      string    `json:"kind"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Capacity  int32     `json:"capacity"`
+	Available int32     `json:"available"`
+}
+
+func NewSlotResponse(row repository.AppointmentSlot) SlotResponse {
+	return SlotResponse{
+		ID:        row.ID,
+		OfficeID:  row.OfficeID,
+		Kind:      row.Kind,
+		StartsAt:  row.StartsAt,
+		EndsAt:    row.EndsAt,
+		Capacity:  row.Capacity,
+		Available: row.Capacity - row.Booked,
+	}
+}
+
+func NewSlotResponses(rows []repository.AppointmentSlot) []SlotResponse {
+	items := make([]SlotResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, NewSlotResponse(row))
+	}
+	return items
+}
+
+// AppointmentResponse is the wire form of repository.Appointment.
+type AppointmentResponse struct {
+	ID           uuid.UUID  `json:"id"`
+	SlotID       uuid.UUID  `json:"slot_id"`
+	Reference    string     `json:"reference"`
+	FullName     string     `json:"full_name"`
+	Email        string     `json:"email"`
+	Phone        string     `json:"phone"`
+	PersonalCode *string    `json:"personal_code"`
+	Notes        string     `json:"notes"`
+	Status       string     `json:"status"`
+	StartsAt     time.Time  `json:"starts_at"`
+	EndsAt       time.Time  `json:"ends_at"`
+	BookedAt     time.Time  `json:"booked_at"`
+	CancelledAt  *time.Time `json:"cancelled_at"`
+	CancelledBy  *string    `json:"cancelled_by"`
+}
+
+func NewAppointmentResponse(row repository.Appointment) AppointmentResponse {
+	return AppointmentResponse{
+		ID:           row.ID,
+		SlotID:       row.SlotID,
+		Reference:    row.Reference,
+		FullName:     row.FullName,
+		Email:        row.Email,
+		Phone:        row.Phone,
+		PersonalCode: render.Nullable(row.PersonalCode.String, row.PersonalCode.Valid),
+		Notes:        row.Notes,
+		Status:       row.Status,
+		StartsAt:     row.StartsAt,
+		EndsAt:       row.EndsAt,
+		BookedAt:     row.BookedAt,
+		CancelledAt:  render.Nullable(row.CancelledAt.Time, row.CancelledAt.Valid),
+		CancelledBy:  render.Nullable(row.CancelledBy.String, row.CancelledBy.Valid),
+	}
+}
+
+func NewAppointmentResponses(rows []repository.Appointment) []AppointmentResponse {
+	items := make([]AppointmentResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, NewAppointmentResponse(row))
+	}
+	return items
+}
+
+// BookingResponse is an appointment with its slot and office.
+type BookingResponse struct {
+	Appointment AppointmentResponse   `json:"appointment"`
+	Slot        SlotResponse          `json:"slot"`
+	Office      office.OfficeResponse `json:"office"`
+}
+
+func NewBookingResponse(b Booking) BookingResponse {
+	return BookingResponse{
+		Appointment: NewAppointmentResponse(b.Appointment),
+		Slot:        NewSlotResponse(b.Slot),
+		Office:      office.NewOfficeResponse(b.Office),
+	}
+}
+
+// CalendarSlotResponse is a calendar slot with its bookings.
+type CalendarSlotResponse struct {
+	SlotResponse
+	Appointments []AppointmentResponse `json:"appointments"`
+}
+
+// CalendarResponse is an office's calendar for [from, to).
+type CalendarResponse struct {
+	Office office.OfficeResponse  `json:"office"`
+	From   render.Date            `json:"from"`
+	To     render.Date            `json:"to"`
+	Slots  []CalendarSlotResponse `json:"slots"`
+}
+
+func NewCalendarResponse(cal Calendar) CalendarResponse {
+	slots := make([]CalendarSlotResponse, 0, len(cal.Slots))
+	for _, s := range cal.Slots {
+		slots = append(slots, CalendarSlotResponse{
+			SlotResponse: NewSlotResponse(s.Slot),
+			Appointments: NewAppointmentResponses(s.Appointments),
+		})
+	}
+	return CalendarResponse{
+		Office: office.NewOfficeResponse(cal.Office),
+		From:   render.Date(cal.From),
+		To:     render.Date(cal.To),
+		Slots:  slots,
+	}
+}
+
+// ReminderResponse is the wire form of repository.AppointmentReminder,
+// with the booking to remind about when listing pending reminders.
+type ReminderResponse struct {
+	ID            uuid.UUID            `json:"id"`
+	AppointmentID uuid.UUID            `json:"appointment_id"`
+	CreatedAt     time.Time            `json:"created_at"`
+	SentAt        *time.Time           `json:"sent_at"`
+	Appointment   *AppointmentResponse `json:"appointment,omitempty"`
+}
+
+func NewReminderResponse(row repository.AppointmentReminder) ReminderResponse {
+	return ReminderResponse{
+		ID:            row.ID,
+		AppointmentID: row.AppointmentID,
+		CreatedAt:     row.CreatedAt,
+		SentAt:        render.Nullable(row.SentAt.Time, row.SentAt.Valid),
+	}
+}
+
+func NewPendingReminderResponses(rows []repository.ListPendingAppointmentRemindersRow) []ReminderResponse {
+	items := make([]ReminderResponse, 0, len(rows))
+	for _, row := range rows {
+		item := NewReminderResponse(row.AppointmentReminder)
+		a := NewAppointmentResponse(row.Appointment)
+		item.Appointment = &a
+		items = append(items, item)
+	}
+	return items
+}
+
+// SlotEnvelope is the response body for a single slot.
+type SlotEnvelope struct {
+	Message string       `json:"message,omitempty"`
+	Data    SlotResponse `json:"data"`
+}
+
+// SlotListEnvelope is the response body for a slot search.
+type SlotListEnvelope struct {
+	Count int            `json:"count"`
+	Data  []SlotResponse `json:"data"`
+}
+
+// BookingEnvelope is the response body for a single booking.
+type BookingEnvelope struct {
+	Message string          `json:"message,omitempty"`
+	Data    BookingResponse `json:"data"`
+}
+
+// CalendarEnvelope is the response body for the clerk calendar.
+type CalendarEnvelope struct {
+	Data CalendarResponse `json:"data"`
+}
+
+// ReminderEnvelope is the response body for a single reminder.
+type ReminderEnvelope struct {
+	Message string           `json:"message,omitempty"`
+	Data    ReminderResponse `json:"data"`
+}
+
+// ReminderListEnvelope is the response body for pending reminders.
+type ReminderListEnvelope struct {
+	Count int                `json:"count"`
+	Data  []ReminderResponse `json:"data"`
+}
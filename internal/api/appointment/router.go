@@ -0,0 +1,28 @@
+package appointment
+
+import (
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func AppointmentRouter(db txn.Beginner, queries *repository.Queries, log *zap.SugaredLogger) chi.Router {
+	r := chi.NewRouter()
+
+	service := NewService(db, queries, log)
+	handlers := NewHandlers(service, log)
+
+	r.Post("/slots", telemetry.InstrumentHandler("appointment", "CreateSlot", handlers.CreateSlot))
+	r.Get("/slots", telemetry.InstrumentHandler("appointment", "ListAvailableSlots", handlers.ListAvailableSlots))
+	r.Delete("/slots/{id}", telemetry.InstrumentHandler("appointment", "DeleteSlot", handlers.DeleteSlot))
+	r.Post("/bookings", telemetry.InstrumentHandler("appointment", "Book", handlers.Book))
+	r.Get("/bookings/{reference}", telemetry.InstrumentHandler("appointment", "GetBooking", handlers.GetBooking))
+	r.Post("/bookings/{reference}/cancel", telemetry.InstrumentHandler("appointment", "CancelBooking", handlers.CancelBooking))
+	r.Get("/calendar", telemetry.InstrumentHandler("appointment", "Calendar", handlers.Calendar))
+	r.Get("/reminders", telemetry.InstrumentHandler("appointment", "ListPendingReminders", handlers.ListPendingReminders))
+	r.Post("/reminders/{id}/sent", telemetry.InstrumentHandler("appointment", "MarkReminderSent", handlers.MarkReminderSent))
+
+	return r
+}
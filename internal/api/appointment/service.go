@@ -0,0 +1,577 @@
+package appointment
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/personalcode"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Kinds of appointment slots.
+const (
+	KindMarriageCeremony = "marriage_ceremony"
+	KindOfficeVisit      = "office_visit"
+)
+
+const (
+	StatusBooked    = "booked"
+	StatusCancelled = "cancelled"
+
+	// CancelledByCitizen marks bookings cancelled with their reference
+	// rather than by a registrar.
+	CancelledByCitizen = "citizen"
+)
+
+const (
+	maxSlotLength   = 8 * time.Hour
+	maxSlotCapacity = 100
+
+	defaultSearchDays  = 14
+	maxSearchDays      = 92
+	defaultSearchLimit = 100
+	maxSearchLimit     = 500
+
+	// CalendarDays is how many days the clerk calendar shows at once.
+	CalendarDays = 7
+
+	// referenceAlphabet leaves out letters and digits that are easily
+	// confused when read out over the phone.
+	referenceAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	referenceLength   = 10
+)
+
+type Service struct {
+	db     txn.Beginner
+	repo   *repository.Queries
+	logger *zap.SugaredLogger
+}
+
+func NewService(db txn.Beginner, repo *repository.Queries, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		db:     db,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// CreateSlotParams describes bookable time in the registrar's own office.
+type CreateSlotParams struct {
+	Kind     string
+	StartsAt time.Time
+	EndsAt   time.Time
+	Capacity int32
+}
+
+// SlotFilter narrows the public slot search. From and To are days; an
+// unset From is today and an unset To is defaultSearchDays later.
+type SlotFilter struct {
+	OfficeID pgtype.UUID
+	Kind     pgtype.Text
+	From     pgtype.Date
+	To       pgtype.Date
+	Limit    int32
+}
+
+// BookParams describes a citizen's booking. Marriage ceremonies need the
+// personal code of the citizen booking them.
+type BookParams struct {
+	SlotID       uuid.UUID
+	FullName     string
+	Email        string
+	Phone        string
+	PersonalCode pgtype.Text
+	Notes        string
+}
+
+// Booking is an appointment with the slot and office it is for.
+type Booking struct {
+	Appointment repository.Appointment
+	Slot        repository.AppointmentSlot
+	Office      repository.Office
+}
+
+// SlotBookings is a calendar slot with its current appointments.
+type SlotBookings struct {
+	Slot         repository.AppointmentSlot
+	Appointments []repository.Appointment
+}
+
+// Calendar is an office's slots for the days [From, To).
+type Calendar struct {
+	Office repository.Office
+	From   time.Time
+	To     time.Time
+	Slots  []SlotBookings
+}
+
+// CreateSlot adds a slot to the signed-in registrar's office calendar.
+// Slots of the same kind may not overlap.
+func (s *Service) CreateSlot(ctx context.Context, arg CreateSlotParams) (_ *repository.AppointmentSlot, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "appointment", "CreateSlot")
+	defer func() { op.End(err) }()
+
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateKind(arg.Kind); err != nil {
+		return nil, err
+	}
+	switch {
+	case arg.StartsAt.IsZero() || arg.EndsAt.IsZero():
+		return nil, apperr.Invalid("starts_at and ends_at are required")
+	case !arg.StartsAt.After(time.Now()):
+		return nil, apperr.Invalid("starts_at must be in the future")
+	case !arg.EndsAt.After(arg.StartsAt):
+		return nil, apperr.Invalid("ends_at must be after starts_at")
+	case arg.EndsAt.Sub(arg.StartsAt) > maxSlotLength:
+		return nil, apperr.Invalid("a slot may last at most %s", maxSlotLength)
+	case arg.Capacity < 1 || arg.Capacity > maxSlotCapacity:
+		return nil, apperr.Invalid("capacity must be between 1 and %d", maxSlotCapacity)
+	}
+
+	result, err := s.repo.CreateAppointmentSlot(ctx, repository.CreateAppointmentSlotParams{
+		OfficeID:  p.Office.ID,
+		Kind:      arg.Kind,
+		StartsAt:  arg.StartsAt,
+		EndsAt:    arg.EndsAt,
+		Capacity:  arg.Capacity,
+		CreatedBy: p.Registrar.ID,
+	})
+	if apperr.IsExclusionViolation(err, "appointment_slot_no_overlap") {
+		return nil, apperr.Conflict("the slot overlaps another %s slot of office %s", arg.Kind, p.Office.Code)
+	}
+	if err != nil {
+		s.logger.Errorf("Failed CreateAppointmentSlot: %v", err)
+		return nil, fmt.Errorf("failed CreateAppointmentSlot: %w", err)
+	}
+
+	s.logger.Infof("CreateSlot completed successfully with ID: %s", result.ID)
+	return &result, nil
+}
+
+// DeleteSlot removes a slot of the registrar's office that was never booked.
+func (s *Service) DeleteSlot(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, op := telemetry.StartOperation(ctx, "appointment", "DeleteSlot")
+	defer func() { op.End(err) }()
+
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return err
+	}
+	slot, err := s.repo.GetAppointmentSlotByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed GetAppointmentSlotByID: %w", err)
+	}
+	if slot.OfficeID != p.Office.ID {
+		return apperr.Forbidden("the slot belongs to another office")
+	}
+
+	deleted, err := s.repo.DeleteUnusedAppointmentSlot(ctx, id)
+	if err != nil {
+		s.logger.Errorf("Failed DeleteUnusedAppointmentSlot: %v", err)
+		return fmt.Errorf("failed DeleteUnusedAppointmentSlot: %w", err)
+	}
+	if deleted == 0 {
+		return apperr.Conflict("the slot has been booked; cancel its bookings instead")
+	}
+	return nil
+}
+
+// ListAvailableSlots returns future slots with free places.
+func (s *Service) ListAvailableSlots(ctx context.Context, arg SlotFilter) (_ []repository.AppointmentSlot, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "appointment", "ListAvailableSlots")
+	defer func() { op.End(err) }()
+
+	if arg.Kind.Valid {
+		if err := validateKind(arg.Kind.String); err != nil {
+			return nil, err
+		}
+	}
+	from := startOfDay(time.Now())
+	if arg.From.Valid {
+		from = startOfDay(arg.From.Time)
+	}
+	to := from.AddDate(0, 0, defaultSearchDays)
+	if arg.To.Valid {
+		// To is inclusive
+		to = startOfDay(arg.To.Time).AddDate(0, 0, 1)
+	}
+	if !to.After(from) {
+		return nil, apperr.Invalid("to must not be before from")
+	}
+	if to.Sub(from) > maxSearchDays*24*time.Hour {
+		return nil, apperr.Invalid("search at most %d days at a time", maxSearchDays)
+	}
+	limit := arg.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		return nil, apperr.Invalid("limit must not exceed %d", maxSearchLimit)
+	}
+
+	result, err := s.repo.ListAvailableAppointmentSlots(ctx, repository.ListAvailableAppointmentSlotsParams{
+		FromTime: from,
+		ToTime:   to,
+		OfficeID: arg.OfficeID,
+		Kind:     arg.Kind,
+		RowLimit: limit,
+	})
+	if err != nil {
+		s.logger.Errorf("Failed ListAvailableAppointmentSlots: %v", err)
+		return nil, fmt.Errorf("failed ListAvailableAppointmentSlots: %w", err)
+	}
+	return result, nil
+}
+
+// GetSlot returns a slot with its office.
+func (s *Service) GetSlot(ctx context.Context, id uuid.UUID) (_ *repository.AppointmentSlot, _ *repository.Office, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "appointment", "GetSlot")
+	defer func() { op.End(err) }()
+
+	slot, err := s.repo.GetAppointmentSlotByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed GetAppointmentSlotByID: %w", err)
+	}
+	o, err := s.repo.GetOfficeByID(ctx, slot.OfficeID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed GetOfficeByID: %w", err)
+	}
+	return &slot, &o, nil
+}
+
+// Book reserves a place in a slot. The slot row is locked while its free
+// places are counted, so concurrent bookings cannot overbook it, and an
+// exclusion constraint stops one email address holding two bookings at
+// the same time.
+func (s *Service) Book(ctx context.Context, arg BookParams) (_ *Booking, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "appointment", "Book")
+	defer func() { op.End(err) }()
+
+	arg.FullName = strings.TrimSpace(arg.FullName)
+	arg.Email = strings.TrimSpace(arg.Email)
+	arg.Phone = strings.TrimSpace(arg.Phone)
+	arg.Notes = strings.TrimSpace(arg.Notes)
+	arg.PersonalCode.String = strings.TrimSpace(arg.PersonalCode.String)
+	arg.PersonalCode.Valid = arg.PersonalCode.String != ""
+	if arg.FullName == "" {
+		return nil, apperr.Invalid("full_name is required")
+	}
+	if addr, err := mail.ParseAddress(arg.Email); err != nil || addr.Address != arg.Email {
+		return nil, apperr.Invalid("email must be a valid email address")
+	}
+	if arg.PersonalCode.Valid {
+		if err := personalcode.Validate(arg.PersonalCode.String); err != nil {
+			return nil, apperr.Invalid("personal_code: %v", err)
+		}
+	}
+
+	reference, err := newReference()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate reference: %w", err)
+	}
+
+	var booking Booking
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		slot, err := q.GetAppointmentSlotForUpdate(ctx, arg.SlotID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.NotFound("slot %s not found", arg.SlotID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed GetAppointmentSlotForUpdate: %w", err)
+		}
+		o, err := q.GetOfficeByID(ctx, slot.OfficeID)
+		if err != nil {
+			return fmt.Errorf("failed GetOfficeByID: %w", err)
+		}
+
+		switch {
+		case !o.Active:
+			return apperr.Conflict("office %s does not take bookings", o.Code)
+		case !slot.StartsAt.After(time.Now()):
+			return apperr.Conflict("the slot has already started")
+		case slot.Booked >= slot.Capacity:
+			return apperr.Conflict("the slot is fully booked")
+		case slot.Kind == KindMarriageCeremony && !arg.PersonalCode.Valid:
+			return apperr.Invalid("personal_code is required to book a marriage ceremony")
+		}
+
+		booking.Appointment, err = q.CreateAppointment(ctx, repository.CreateAppointmentParams{
+			SlotID:       slot.ID,
+			Reference:    reference,
+			FullName:     arg.FullName,
+			Email:        arg.Email,
+			Phone:        arg.Phone,
+			PersonalCode: arg.PersonalCode,
+			Notes:        arg.Notes,
+			StartsAt:     slot.StartsAt,
+			EndsAt:       slot.EndsAt,
+		})
+		if apperr.IsExclusionViolation(err, "appointment_no_overlap") {
+			return apperr.Conflict("%s already has a booking at that time", arg.Email)
+		}
+		if err != nil {
+			return fmt.Errorf("failed CreateAppointment: %w", err)
+		}
+
+		booking.Slot, err = q.SetAppointmentSlotBooked(ctx, repository.SetAppointmentSlotBookedParams{
+			ID:     slot.ID,
+			Booked: slot.Booked + 1,
+		})
+		if err != nil {
+			return fmt.Errorf("failed SetAppointmentSlotBooked: %w", err)
+		}
+		booking.Office = o
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorf("Failed Book: %v", err)
+		return nil, err
+	}
+
+	s.logger.Infof("Book completed successfully with ID: %s", booking.Appointment.ID)
+	return &booking, nil
+}
+
+// GetBooking looks a booking up by its reference.
+func (s *Service) GetBooking(ctx context.Context, reference string) (_ *Booking, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "appointment", "GetBooking")
+	defer func() { op.End(err) }()
+
+	a, err := s.repo.GetAppointmentByReference(ctx, normalizeReference(reference))
+	if err != nil {
+		return nil, fmt.Errorf("failed GetAppointmentByReference: %w", err)
+	}
+	slot, o, err := s.GetSlot(ctx, a.SlotID)
+	if err != nil {
+		return nil, err
+	}
+	return &Booking{Appointment: a, Slot: *slot, Office: *o}, nil
+}
+
+// Cancel cancels a booking and frees its place. Citizens cancel with the
+// reference alone; a registrar of the slot's office is recorded by name.
+// The slot is locked before the appointment, in the same order as Book.
+func (s *Service) Cancel(ctx context.Context, reference string) (_ *Booking, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "appointment", "Cancel")
+	defer func() { op.End(err) }()
+
+	found, err := s.repo.GetAppointmentByReference(ctx, normalizeReference(reference))
+	if err != nil {
+		return nil, fmt.Errorf("failed GetAppointmentByReference: %w", err)
+	}
+
+	var booking Booking
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		slot, err := q.GetAppointmentSlotForUpdate(ctx, found.SlotID)
+		if err != nil {
+			return fmt.Errorf("failed GetAppointmentSlotForUpdate: %w", err)
+		}
+		a, err := q.GetAppointmentForUpdate(ctx, found.ID)
+		if err != nil {
+			return fmt.Errorf("failed GetAppointmentForUpdate: %w", err)
+		}
+		if a.Status == StatusCancelled {
+			return apperr.Conflict("the booking is already cancelled")
+		}
+		if !a.StartsAt.After(time.Now()) {
+			return apperr.Conflict("past bookings cannot be cancelled")
+		}
+
+		cancelledBy := CancelledByCitizen
+		if p := auth.FromContext(ctx); p != nil && p.Office != nil && p.Office.ID == slot.OfficeID {
+			cancelledBy = p.Name()
+		}
+		booking.Appointment, err = q.CancelAppointment(ctx, repository.CancelAppointmentParams{
+			ID:          a.ID,
+			CancelledBy: pgtype.Text{String: cancelledBy, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed CancelAppointment: %w", err)
+		}
+		booking.Slot, err = q.SetAppointmentSlotBooked(ctx, repository.SetAppointmentSlotBookedParams{
+			ID:     slot.ID,
+			Booked: slot.Booked - 1,
+		})
+		if err != nil {
+			return fmt.Errorf("failed SetAppointmentSlotBooked: %w", err)
+		}
+		booking.Office, err = q.GetOfficeByID(ctx, slot.OfficeID)
+		if err != nil {
+			return fmt.Errorf("failed GetOfficeByID: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorf("Failed Cancel: %v", err)
+		return nil, err
+	}
+
+	s.logger.Infof("Cancel completed successfully with ID: %s", booking.Appointment.ID)
+	return &booking, nil
+}
+
+// Calendar returns the signed-in registrar's office slots with their
+// bookings for CalendarDays days from the given day (default today).
+func (s *Service) Calendar(ctx context.Context, from pgtype.Date) (_ *Calendar, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "appointment", "Calendar")
+	defer func() { op.End(err) }()
+
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cal := Calendar{Office: *p.Office, From: startOfDay(time.Now())}
+	if from.Valid {
+		cal.From = startOfDay(from.Time)
+	}
+	cal.To = cal.From.AddDate(0, 0, CalendarDays)
+
+	slots, err := s.repo.ListAppointmentSlotsForOffice(ctx, repository.ListAppointmentSlotsForOfficeParams{
+		OfficeID: p.Office.ID,
+		FromTime: cal.From,
+		ToTime:   cal.To,
+	})
+	if err != nil {
+		s.logger.Errorf("Failed ListAppointmentSlotsForOffice: %v", err)
+		return nil, fmt.Errorf("failed ListAppointmentSlotsForOffice: %w", err)
+	}
+	appointments, err := s.repo.ListBookedAppointmentsForOffice(ctx, repository.ListBookedAppointmentsForOfficeParams{
+		OfficeID: p.Office.ID,
+		FromTime: cal.From,
+		ToTime:   cal.To,
+	})
+	if err != nil {
+		s.logger.Errorf("Failed ListBookedAppointmentsForOffice: %v", err)
+		return nil, fmt.Errorf("failed ListBookedAppointmentsForOffice: %w", err)
+	}
+
+	bySlot := make(map[uuid.UUID][]repository.Appointment)
+	for _, a := range appointments {
+		bySlot[a.SlotID] = append(bySlot[a.SlotID], a)
+	}
+	cal.Slots = make([]SlotBookings, 0, len(slots))
+	for _, slot := range slots {
+		cal.Slots = append(cal.Slots, SlotBookings{Slot: slot, Appointments: bySlot[slot.ID]})
+	}
+	return &cal, nil
+}
+
+// GenerateReminders queues a reminder for every booking that starts within
+// lead and has none yet. It is safe to run repeatedly.
+func (s *Service) GenerateReminders(ctx context.Context, lead time.Duration) (_ []repository.AppointmentReminder, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "appointment", "GenerateReminders")
+	defer func() { op.End(err) }()
+
+	result, err := s.repo.CreateDueAppointmentReminders(ctx, time.Now().Add(lead))
+	if err != nil {
+		s.logger.Errorf("Failed CreateDueAppointmentReminders: %v", err)
+		return nil, fmt.Errorf("failed CreateDueAppointmentReminders: %w", err)
+	}
+	if len(result) > 0 {
+		s.logger.Infof("Queued %d appointment reminders", len(result))
+	}
+	return result, nil
+}
+
+// RunReminders calls GenerateReminders every interval until ctx is done.
+func (s *Service) RunReminders(ctx context.Context, interval, lead time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Failures are logged by GenerateReminders; the next tick retries
+		_, _ = s.GenerateReminders(ctx, lead)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ListPendingReminders returns the unsent reminders for upcoming bookings
+// in the signed-in registrar's office.
+func (s *Service) ListPendingReminders(ctx context.Context) (_ []repository.ListPendingAppointmentRemindersRow, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "appointment", "ListPendingReminders")
+	defer func() { op.End(err) }()
+
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.repo.ListPendingAppointmentReminders(ctx, p.Office.ID)
+	if err != nil {
+		s.logger.Errorf("Failed ListPendingAppointmentReminders: %v", err)
+		return nil, fmt.Errorf("failed ListPendingAppointmentReminders: %w", err)
+	}
+	return result, nil
+}
+
+// MarkReminderSent records that a reminder was delivered.
+func (s *Service) MarkReminderSent(ctx context.Context, id uuid.UUID) (_ *repository.AppointmentReminder, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "appointment", "MarkReminderSent")
+	defer func() { op.End(err) }()
+
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := s.repo.MarkAppointmentReminderSent(ctx, repository.MarkAppointmentReminderSentParams{
+		ID:       id,
+		OfficeID: p.Office.ID,
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperr.NotFound("no unsent reminder %s in office %s", id, p.Office.Code)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed MarkAppointmentReminderSent: %w", err)
+	}
+	return &result, nil
+}
+
+func validateKind(kind string) error {
+	if kind != KindMarriageCeremony && kind != KindOfficeVisit {
+		return apperr.Invalid("kind must be %q or %q", KindMarriageCeremony, KindOfficeVisit)
+	}
+	return nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+}
+
+// newReference returns a random booking reference. With 32 symbols and 10
+// characters it carries 50 bits, enough that references cannot be guessed.
+func newReference() (string, error) {
+	b := make([]byte, referenceLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = referenceAlphabet[int(b[i])%len(referenceAlphabet)]
+	}
+	return string(b), nil
+}
+
+// normalizeReference accepts references typed in lower case or with spaces.
+func normalizeReference(reference string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(reference), " ", ""))
+}
@@ -0,0 +1,325 @@
+package attachment
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+
+	"github.com/eif-courses/civilregistry/internal/api/request"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// multipartMemory is how much of a multipart form is kept in memory; the
+// rest of the file spills to a temporary file.
+const multipartMemory = 1 << 20
+
+type Handlers struct {
+	service *Service
+	links   Links
+	logger  *zap.SugaredLogger
+}
+
+func NewHandlers(service *Service, links Links, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		service: service,
+		links:   links,
+		logger:  logger,
+	}
+}
+
+type ApplicantRequest struct {
+	Number string `json:"number" example:"APP-2026-000123"`
+	Email  string `json:"email" example:"jonas@example.com"`
+}
+
+// Upload attaches a file to an application or record
+// @Summary Upload attachment
+// @Description Attach a scanned document to an application or a birth, marriage or death record of the
+// @Description registrar's office. PDF, JPEG and PNG files are accepted, detected from their content, up to
+// @Description ATTACHMENT_MAX_BYTES (default 10 MB). Files are stored once per SHA-256 digest.
+// @Tags attachment
+// @Accept mpfd
+// @Produce json
+// @Security BearerAuth
+// @Param owner_kind formData string true "application, birth, marriage or death"
+// @Param owner_id formData string true "ID of the application or record"
+// @Param file formData file true "document"
+// @Success 201 {object} AttachmentEnvelope "Stored attachment"
+// @Failure 400 {object} map[string]interface{} "Invalid request, file type or size"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Owner belongs to another office"
+// @Failure 404 {object} map[string]interface{} "Owner not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Too many attachments"
+// @Failure 413 {object} map[string]interface{} "Request too large"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/attachments [post]
+func (h *Handlers) Upload(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	file, header, ok := h.readUpload(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	ownerID, err := uuid.Parse(r.PostFormValue("owner_id"))
+	if err != nil {
+		http.Error(w, "owner_id must be a UUID", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.Upload(r.Context(), UploadParams{
+		OwnerKind: r.PostFormValue("owner_kind"),
+		OwnerID:   ownerID,
+		Filename:  header.Filename,
+		Content:   file,
+	})
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusCreated, AttachmentEnvelope{
+		Message: "attachment uploaded successfully",
+		Data:    NewAttachmentResponse(*result, h.links.URL(result.ID, result.UploadedAt)),
+	})
+}
+
+// UploadForApplicant attaches a file to the applicant's own application
+// @Summary Upload attachment as applicant
+// @Description Attach a document to an application, identified by its number and the applicant's email. Files can
+// @Description be added while the application is submitted or needs information.
+// @Tags attachment
+// @Accept mpfd
+// @Produce json
+// @Param number formData string true "application number"
+// @Param email formData string true "applicant's email"
+// @Param file formData file true "document"
+// @Success 201 {object} AttachmentEnvelope "Stored attachment"
+// @Failure 400 {object} map[string]interface{} "Invalid request, file type or size"
+// @Failure 404 {object} map[string]interface{} "Application not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Application closed for files or too many attachments"
+// @Failure 413 {object} map[string]interface{} "Request too large"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/attachments/applicant [post]
+func (h *Handlers) UploadForApplicant(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	file, header, ok := h.readUpload(w, r)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.UploadForApplicant(r.Context(), r.PostFormValue("number"), r.PostFormValue("email"), header.Filename, file)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusCreated, AttachmentEnvelope{
+		Message: "attachment uploaded successfully",
+		Data:    NewApplicantResponses([]repository.Attachment{*result}, h.links)[0],
+	})
+}
+
+// List lists the attachments of an application or record
+// @Summary List attachments
+// @Description List the attachments of an application or record of the registrar's office, with download links
+// @Description valid for ATTACHMENT_LINK_TTL
+// @Tags attachment
+// @Produce json
+// @Security BearerAuth
+// @Param owner_kind query string true "application, birth, marriage or death"
+// @Param owner_id query string true "ID of the application or record"
+// @Success 200 {object} AttachmentListEnvelope "Attachments"
+// @Failure 400 {object} map[string]interface{} "Invalid owner"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Owner belongs to another office"
+// @Failure 404 {object} map[string]interface{} "Owner not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/attachments [get]
+func (h *Handlers) List(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	ownerID, err := request.QueryUUID(r, "owner_id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.List(r.Context(), r.URL.Query().Get("owner_kind"), ownerID)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, AttachmentListEnvelope{
+		Count: len(result),
+		Data:  NewAttachmentResponses(result, h.links),
+	})
+}
+
+// ListForApplicant lists the attachments of the applicant's application
+// @Summary List attachments as applicant
+// @Description List the attachments of an application, identified by its number and the applicant's email
+// @Tags attachment
+// @Accept json
+// @Produce json
+// @Param request body ApplicantRequest true "number and email"
+// @Success 200 {object} AttachmentListEnvelope "Attachments"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 404 {object} map[string]interface{} "Application not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/attachments/applicant/list [post]
+func (h *Handlers) ListForApplicant(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var req ApplicantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.ListForApplicant(r.Context(), req.Number, req.Email)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, AttachmentListEnvelope{
+		Count: len(result),
+		Data:  NewApplicantResponses(result, h.links),
+	})
+}
+
+// Download streams an attachment
+// @Summary Download attachment
+// @Description Download an attachment with a signed link from a listing, or as a registrar of its office
+// @Tags attachment
+// @Produce application/pdf,image/jpeg,image/png
+// @Security BearerAuth
+// @Param id path string true "attachment ID"
+// @Param token query string false "download token from download_url"
+// @Success 200 {file} file "Attachment content"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in and no token"
+// @Failure 403 {object} map[string]interface{} "Invalid or expired link, or another office"
+// @Failure 404 {object} map[string]interface{} "Attachment not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/attachments/{id}/content [get]
+func (h *Handlers) Download(w http.ResponseWriter, r *http.Request) {
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	a, content, err := h.service.Open(r.Context(), id, r.URL.Query().Get("token"))
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", a.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(a.SizeBytes, 10))
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": a.Filename}))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "private, no-store")
+	if _, err := io.Copy(w, content); err != nil {
+		h.logger.Errorf("Failed to send attachment %s: %v", a.ID, err)
+	}
+}
+
+// Delete removes an attachment
+// @Summary Delete attachment
+// @Description Remove an attachment of the registrar's office. Its content is deleted once no attachment uses it.
+// @Tags attachment
+// @Security BearerAuth
+// @Param id path string true "attachment ID"
+// @Success 204 "Attachment deleted"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Attachment of another office"
+// @Failure 404 {object} map[string]interface{} "Attachment not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/attachments/{id} [delete]
+func (h *Handlers) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		h.serviceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// readUpload parses a multipart upload no larger than the file limit plus
+// room for the other fields and returns its "file" part. It answers the
+// request itself when that fails.
+func (h *Handlers) readUpload(w http.ResponseWriter, r *http.Request) (multipart.File, *multipart.FileHeader, bool) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.service.MaxSize()+multipartMemory)
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Request too large", http.StatusRequestEntityTooLarge)
+			return nil, nil, false
+		}
+		http.Error(w, "Invalid multipart form", http.StatusBadRequest)
+		return nil, nil, false
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "file is required", http.StatusBadRequest)
+		return nil, nil, false
+	}
+	return file, header, true
+}
+
+func (h *Handlers) write(w http.ResponseWriter, status int, body any) {
+	if err := render.Write(w, status, render.ContentTypeJSON, body); err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// serviceError maps a service failure onto an HTTP status.
+func (h *Handlers) serviceError(w http.ResponseWriter, err error) {
+	status, msg := apperr.Status(err)
+	if status >= http.StatusInternalServerError {
+		h.logger.Errorf("Service error: %v", err)
+	}
+	if status == http.StatusNotFound && msg == "not found" {
+		msg = "attachment not found"
+	}
+	http.Error(w, msg, status)
+}
@@ -0,0 +1,54 @@
+package attachment
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Links signs the download URLs handed out with attachments. A token is the
+// link's expiry as Unix seconds and an HMAC-SHA256 of the attachment ID and
+// expiry, so a link works for one attachment until TTL runs out.
+type Links struct {
+	Key     []byte
+	BaseURL string
+	TTL     time.Duration
+}
+
+// Token returns a download token for an attachment that expires TTL after now.
+func (l Links) Token(id uuid.UUID, now time.Time) string {
+	expires := strconv.FormatInt(now.Add(l.TTL).Unix(), 10)
+	return expires + "." + base64.RawURLEncoding.EncodeToString(l.sign(id, expires))
+}
+
+// URL returns a signed download URL for an attachment.
+func (l Links) URL(id uuid.UUID, now time.Time) string {
+	return strings.TrimRight(l.BaseURL, "/") + "/api/attachments/" + id.String() + "/content?token=" + l.Token(id, now)
+}
+
+// Valid reports whether token was signed for the attachment and has not
+// expired.
+func (l Links) Valid(id uuid.UUID, token string, now time.Time) bool {
+	expires, mac, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || now.Unix() > unix {
+		return false
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(mac)
+	return err == nil && hmac.Equal(sig, l.sign(id, expires))
+}
+
+func (l Links) sign(id uuid.UUID, expires string) []byte {
+	mac := hmac.New(sha256.New, l.Key)
+	mac.Write(id[:])
+	mac.Write([]byte(expires))
+	return mac.Sum(nil)
+}
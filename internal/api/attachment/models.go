@@ -0,0 +1,72 @@
+package attachment
+
+import (
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/application"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/google/uuid"
+)
+
+// AttachmentResponse is the wire form of repository.Attachment. DownloadURL
+// is a signed link that works for a limited time.
+type AttachmentResponse struct {
+	ID          uuid.UUID `json:"id"`
+	OwnerKind   string    `json:"owner_kind"`
+	OwnerID     uuid.UUID `json:"owner_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	SHA256      string    `json:"sha256"`
+	UploadedBy  string    `json:"uploaded_by"`
+	UploadedAt  time.Time `json:"uploaded_at"`
+	DownloadURL string    `json:"download_url"`
+}
+
+func NewAttachmentResponse(row repository.Attachment, downloadURL string) AttachmentResponse {
+	return AttachmentResponse{
+		ID:          row.ID,
+		OwnerKind:   row.OwnerKind,
+		OwnerID:     row.OwnerID,
+		Filename:    row.Filename,
+		ContentType: row.ContentType,
+		SizeBytes:   row.SizeBytes,
+		SHA256:      row.Sha256,
+		UploadedBy:  row.UploadedBy,
+		UploadedAt:  row.UploadedAt,
+		DownloadURL: downloadURL,
+	}
+}
+
+func NewAttachmentResponses(rows []repository.Attachment, links Links) []AttachmentResponse {
+	now := time.Now()
+	items := make([]AttachmentResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, NewAttachmentResponse(row, links.URL(row.ID, now)))
+	}
+	return items
+}
+
+// NewApplicantResponses lists attachments as the applicant sees them,
+// without the names of the registrars who added files.
+func NewApplicantResponses(rows []repository.Attachment, links Links) []AttachmentResponse {
+	items := NewAttachmentResponses(rows, links)
+	for i := range items {
+		if items[i].UploadedBy != application.ChangedByApplicant {
+			items[i].UploadedBy = "registrar"
+		}
+	}
+	return items
+}
+
+// AttachmentEnvelope is the response body for a single attachment.
+type AttachmentEnvelope struct {
+	Message string             `json:"message,omitempty"`
+	Data    AttachmentResponse `json:"data"`
+}
+
+// AttachmentListEnvelope is the response body for a list of attachments.
+type AttachmentListEnvelope struct {
+	Count int                  `json:"count"`
+	Data  []AttachmentResponse `json:"data"`
+}
@@ -0,0 +1,26 @@
+package attachment
+
+import (
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/storage"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func AttachmentRouter(db txn.Beginner, queries *repository.Queries, store storage.BlobStore, opts Options, log *zap.SugaredLogger) chi.Router {
+	r := chi.NewRouter()
+
+	service := NewService(db, queries, store, opts, log)
+	handlers := NewHandlers(service, opts.Links, log)
+
+	r.Post("/", telemetry.InstrumentHandler("attachment", "Upload", handlers.Upload))
+	r.Get("/", telemetry.InstrumentHandler("attachment", "List", handlers.List))
+	r.Post("/applicant", telemetry.InstrumentHandler("attachment", "UploadForApplicant", handlers.UploadForApplicant))
+	r.Post("/applicant/list", telemetry.InstrumentHandler("attachment", "ListForApplicant", handlers.ListForApplicant))
+	r.Get("/{id}/content", telemetry.InstrumentHandler("attachment", "Download", handlers.Download))
+	r.Delete("/{id}", telemetry.InstrumentHandler("attachment", "Delete", handlers.Delete))
+
+	return r
+}
@@ -0,0 +1,435 @@
+package attachment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/eif-courses/civilregistry/internal/api/application"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/storage"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// What attachments can belong to.
+const (
+	OwnerApplication = "application"
+	OwnerBirth       = "birth"
+	OwnerMarriage    = "marriage"
+	OwnerDeath       = "death"
+)
+
+const (
+	// MaxPerOwner caps the attachments of one application or record.
+	MaxPerOwner = 20
+
+	maxFilenameLength = 200
+)
+
+// allowedTypes are the accepted content types, sniffed from the file's
+// first bytes rather than taken from the upload.
+var allowedTypes = map[string]bool{
+	"application/pdf": true,
+	"image/jpeg":      true,
+	"image/png":       true,
+}
+
+// Options configures uploads and downloads. MaxSize is the largest file
+// accepted, in bytes.
+type Options struct {
+	MaxSize int64
+	Links   Links
+}
+
+type Service struct {
+	db           txn.Beginner
+	repo         *repository.Queries
+	store        storage.BlobStore
+	applications *application.Service
+	opts         Options
+	logger       *zap.SugaredLogger
+}
+
+func NewService(db txn.Beginner, repo *repository.Queries, store storage.BlobStore, opts Options, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		db:           db,
+		repo:         repo,
+		store:        store,
+		applications: application.NewService(db, repo, logger),
+		opts:         opts,
+		logger:       logger,
+	}
+}
+
+// UploadParams describes a file a registrar attaches to an application or
+// record of their office.
+type UploadParams struct {
+	OwnerKind string
+	OwnerID   uuid.UUID
+	Filename  string
+	Content   io.Reader
+}
+
+// owner is what an attachment belongs to and the office it belongs to.
+type owner struct {
+	kind   string
+	id     uuid.UUID
+	office uuid.UUID
+}
+
+// Upload attaches a file for the signed-in registrar.
+func (s *Service) Upload(ctx context.Context, arg UploadParams) (_ *repository.Attachment, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "attachment", "Upload")
+	defer func() { op.End(err) }()
+
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	o, err := s.owner(ctx, arg.OwnerKind, arg.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	if o.office != p.Office.ID {
+		return nil, apperr.Forbidden("the %s belongs to another office", arg.OwnerKind)
+	}
+	return s.save(ctx, o, arg.Filename, arg.Content, p.Name())
+}
+
+// UploadForApplicant attaches a file to an application on behalf of its
+// applicant, who proves who they are with the number and email address as
+// when tracking it. Applicants can add files until a registrar takes the
+// application into review, and again while it waits for information.
+func (s *Service) UploadForApplicant(ctx context.Context, number, email, filename string, content io.Reader) (_ *repository.Attachment, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "attachment", "UploadForApplicant")
+	defer func() { op.End(err) }()
+
+	c, err := s.applications.Track(ctx, number, email)
+	if err != nil {
+		return nil, err
+	}
+	a := c.Application
+	if a.Status != application.StatusSubmitted && a.Status != application.StatusNeedsInfo {
+		return nil, apperr.Conflict("files can only be added while the application is submitted or needs information")
+	}
+	o := owner{kind: OwnerApplication, id: a.ID, office: a.OfficeID}
+	return s.save(ctx, o, filename, content, application.ChangedByApplicant)
+}
+
+// List returns the attachments of an application or record of the
+// registrar's office, oldest first.
+func (s *Service) List(ctx context.Context, ownerKind string, ownerID uuid.UUID) (_ []repository.Attachment, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "attachment", "List")
+	defer func() { op.End(err) }()
+
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	o, err := s.owner(ctx, ownerKind, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if o.office != p.Office.ID {
+		return nil, apperr.Forbidden("the %s belongs to another office", ownerKind)
+	}
+	return s.list(ctx, o)
+}
+
+// ListForApplicant returns the attachments of an application to its
+// applicant.
+func (s *Service) ListForApplicant(ctx context.Context, number, email string) (_ []repository.Attachment, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "attachment", "ListForApplicant")
+	defer func() { op.End(err) }()
+
+	c, err := s.applications.Track(ctx, number, email)
+	if err != nil {
+		return nil, err
+	}
+	return s.list(ctx, owner{kind: OwnerApplication, id: c.Application.ID, office: c.Application.OfficeID})
+}
+
+// Open returns an attachment with its content; the caller closes it. A
+// valid download token opens it for anyone holding the link; without one
+// the caller must be a registrar of the attachment's office.
+func (s *Service) Open(ctx context.Context, id uuid.UUID, token string) (_ *repository.Attachment, _ io.ReadCloser, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "attachment", "Open")
+	defer func() { op.End(err) }()
+
+	if token != "" && !s.opts.Links.Valid(id, token, time.Now()) {
+		return nil, nil, apperr.Forbidden("the download link is invalid or has expired")
+	}
+	a, err := s.repo.GetAttachmentByID(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed GetAttachmentByID: %w", err)
+	}
+	if token == "" {
+		p, err := auth.RequireRegistrar(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+		if a.OfficeID != p.Office.ID {
+			return nil, nil, apperr.Forbidden("the attachment belongs to another office")
+		}
+	}
+
+	content, err := s.store.Open(ctx, a.Sha256)
+	if err != nil {
+		s.logger.Errorf("Failed to open blob %s of attachment %s: %v", a.Sha256, a.ID, err)
+		return nil, nil, fmt.Errorf("failed to open attachment content: %w", err)
+	}
+	return &a, content, nil
+}
+
+// Delete removes an attachment of the registrar's office. Its blob is
+// removed too, once the deletion has committed, unless another attachment
+// has the same content.
+func (s *Service) Delete(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, op := telemetry.StartOperation(ctx, "attachment", "Delete")
+	defer func() { op.End(err) }()
+
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return err
+	}
+	a, err := s.repo.GetAttachmentByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed GetAttachmentByID: %w", err)
+	}
+	if a.OfficeID != p.Office.ID {
+		return apperr.Forbidden("the attachment belongs to another office")
+	}
+
+	var unused string
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		var err error
+		unused, err = Remove(ctx, q, a)
+		return err
+	})
+	if err != nil {
+		s.logger.Errorf("Failed Delete: %v", err)
+		return err
+	}
+	if err := DeleteBlobs(ctx, s.db, s.repo, s.store, unused); err != nil {
+		s.logger.Errorf("Failed to delete blob %s of attachment %s: %v", unused, a.ID, err)
+	}
+
+	s.logger.Infof("Delete completed successfully with ID: %s", a.ID)
+	return nil
+}
+
+// Remove deletes an attachment inside the caller's transaction. It returns
+// the attachment's digest when no other attachment has the same content,
+// and an empty string otherwise. The blob is left in place: the caller
+// passes the digest to DeleteBlobs once the transaction has committed, so
+// a rollback never leaves attachments pointing at missing content.
+func Remove(ctx context.Context, q *repository.Queries, a repository.Attachment) (string, error) {
+	if err := q.LockAttachmentBlob(ctx, a.Sha256); err != nil {
+		return "", fmt.Errorf("failed LockAttachmentBlob: %w", err)
+	}
+	if _, err := q.DeleteAttachment(ctx, a.ID); err != nil {
+		return "", fmt.Errorf("failed DeleteAttachment: %w", err)
+	}
+	users, err := q.CountAttachmentsBySHA256(ctx, a.Sha256)
+	if err != nil {
+		return "", fmt.Errorf("failed CountAttachmentsBySHA256: %w", err)
+	}
+	if users == 0 {
+		return a.Sha256, nil
+	}
+	return "", nil
+}
+
+// DeleteBlobs removes the blobs of digests that Remove reported unused.
+// Each digest is counted again under its lock, since an upload of the same
+// content may have committed after Remove's transaction did. Empty digests
+// are skipped.
+func DeleteBlobs(ctx context.Context, db txn.Beginner, repo *repository.Queries, store storage.BlobStore, digests ...string) error {
+	var errs []error
+	for _, digest := range digests {
+		if digest == "" {
+			continue
+		}
+		err := txn.Run(ctx, db, repo, func(q *repository.Queries) error {
+			if err := q.LockAttachmentBlob(ctx, digest); err != nil {
+				return fmt.Errorf("failed LockAttachmentBlob: %w", err)
+			}
+			users, err := q.CountAttachmentsBySHA256(ctx, digest)
+			if err != nil {
+				return fmt.Errorf("failed CountAttachmentsBySHA256: %w", err)
+			}
+			if users > 0 {
+				return nil
+			}
+			return store.Delete(ctx, digest)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("blob %s: %w", digest, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// MaxSize is the largest file accepted, in bytes.
+func (s *Service) MaxSize() int64 {
+	return s.opts.MaxSize
+}
+
+// save stages the content to learn its digest, size and type, then stores
+// the blob and records the attachment. The owner lock is held from counting
+// to committing so concurrent uploads cannot go past MaxPerOwner, and the
+// blob lock so a concurrent Delete of the same content cannot remove the
+// blob in between.
+func (s *Service) save(ctx context.Context, o owner, filename string, content io.Reader, by string) (*repository.Attachment, error) {
+	filename = cleanFilename(filename)
+
+	staged, err := storage.Stage(content, s.opts.MaxSize)
+	if errors.Is(err, storage.ErrTooLarge) {
+		return nil, apperr.Invalid("files may be at most %d MB", s.opts.MaxSize>>20)
+	}
+	if err != nil {
+		s.logger.Errorf("Failed to stage upload: %v", err)
+		return nil, err
+	}
+	defer staged.Close()
+
+	if staged.Size == 0 {
+		return nil, apperr.Invalid("the file is empty")
+	}
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(staged.Head))
+	if !allowedTypes[contentType] {
+		return nil, apperr.Invalid("only PDF, JPEG and PNG files are accepted")
+	}
+
+	var result repository.Attachment
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		if err := q.LockAttachmentOwner(ctx, repository.LockAttachmentOwnerParams{
+			OwnerKind: o.kind,
+			OwnerID:   o.id,
+		}); err != nil {
+			return fmt.Errorf("failed LockAttachmentOwner: %w", err)
+		}
+		count, err := q.CountAttachmentsByOwner(ctx, repository.CountAttachmentsByOwnerParams{
+			OwnerKind: o.kind,
+			OwnerID:   o.id,
+		})
+		if err != nil {
+			return fmt.Errorf("failed CountAttachmentsByOwner: %w", err)
+		}
+		if count >= MaxPerOwner {
+			return apperr.Conflict("a %s can have at most %d attachments", o.kind, MaxPerOwner)
+		}
+		if err := q.LockAttachmentBlob(ctx, staged.Key); err != nil {
+			return fmt.Errorf("failed LockAttachmentBlob: %w", err)
+		}
+		if err := s.store.Put(ctx, staged.Key, staged.File, staged.Size); err != nil {
+			return fmt.Errorf("failed to store attachment content: %w", err)
+		}
+		result, err = q.CreateAttachment(ctx, repository.CreateAttachmentParams{
+			OwnerKind:   o.kind,
+			OwnerID:     o.id,
+			OfficeID:    o.office,
+			Filename:    filename,
+			ContentType: contentType,
+			SizeBytes:   staged.Size,
+			Sha256:      staged.Key,
+			UploadedBy:  by,
+		})
+		if err != nil {
+			return fmt.Errorf("failed CreateAttachment: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorf("Failed to save attachment: %v", err)
+		return nil, err
+	}
+
+	s.logger.Infof("Upload completed successfully with ID: %s", result.ID)
+	return &result, nil
+}
+
+func (s *Service) list(ctx context.Context, o owner) ([]repository.Attachment, error) {
+	result, err := s.repo.ListAttachmentsByOwner(ctx, repository.ListAttachmentsByOwnerParams{
+		OwnerKind: o.kind,
+		OwnerID:   o.id,
+	})
+	if err != nil {
+		s.logger.Errorf("Failed ListAttachmentsByOwner: %v", err)
+		return nil, fmt.Errorf("failed ListAttachmentsByOwner: %w", err)
+	}
+	return result, nil
+}
+
+// owner looks up what an attachment is for and the office it belongs to.
+// Records registered before offices were tracked cannot take attachments.
+func (s *Service) owner(ctx context.Context, kind string, id uuid.UUID) (owner, error) {
+	var (
+		office pgtype.UUID
+		err    error
+	)
+	switch kind {
+	case OwnerApplication:
+		var a repository.Application
+		a, err = s.repo.GetApplicationByID(ctx, id)
+		office = pgtype.UUID{Bytes: a.OfficeID, Valid: err == nil}
+	case OwnerBirth:
+		var r repository.BirthRecord
+		r, err = s.repo.GetBirthRecordByID(ctx, id)
+		office = r.OfficeID
+	case OwnerMarriage:
+		var m repository.Marriage
+		m, err = s.repo.GetMarriageByID(ctx, id)
+		office = m.OfficeID
+	case OwnerDeath:
+		var r repository.DeathRecord
+		r, err = s.repo.GetDeathRecordByID(ctx, id)
+		office = r.OfficeID
+	default:
+		return owner{}, apperr.Invalid("owner_kind must be %q, %q, %q or %q", OwnerApplication, OwnerBirth, OwnerMarriage, OwnerDeath)
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return owner{}, apperr.NotFound("%s %s not found", kind, id)
+	}
+	if err != nil {
+		return owner{}, fmt.Errorf("failed to look up %s %s: %w", kind, id, err)
+	}
+	if !office.Valid {
+		return owner{}, apperr.Conflict("the %s was registered before offices were tracked and cannot take attachments", kind)
+	}
+	return owner{kind: kind, id: id, office: office.Bytes}, nil
+}
+
+// cleanFilename keeps the base name of an uploaded file without control
+// characters, since it is echoed back in Content-Disposition headers.
+func cleanFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		return "attachment"
+	}
+	if runes := []rune(name); len(runes) > maxFilenameLength {
+		name = string(runes[len(runes)-maxFilenameLength:])
+	}
+	return name
+}
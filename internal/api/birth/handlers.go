@@ -0,0 +1,319 @@
+package birth
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/eif-courses/civilregistry/internal/api/foreign"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/api/request"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	service *Service
+	logger  *zap.SugaredLogger
+}
+
+func NewHandlers(service *Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type RegisterBirthRequest struct {
+	Child    person.CreatePersonRequest `json:"child"`
+	MotherID *uuid.UUID                 `json:"mother_id,omitempty" example:"6f1c2a9e-1b7a-4c55-9d0e-3f2b8a4d5e61"`
+	FatherID *uuid.UUID                 `json:"father_id,omitempty"`
+}
+
+// Params converts the request into service parameters.
+func (req RegisterBirthRequest) Params() RegisterBirthParams {
+	return RegisterBirthParams{
+		Child:    req.Child.Params(),
+		MotherID: request.UUID(req.MotherID),
+		FatherID: request.UUID(req.FatherID),
+	}
+}
+
+type RegisterForeignBirthRequest struct {
+	RegisterBirthRequest
+	Document foreign.DocumentRequest `json:"document"`
+}
+
+// Params converts the request into service parameters.
+func (req RegisterForeignBirthRequest) Params() RegisterBirthParams {
+	arg := req.RegisterBirthRequest.Params()
+	doc := req.Document.Params()
+	arg.Document = &doc
+	return arg
+}
+
+// RegisterBirth registers a birth
+// @Summary Register birth
+// @Description Create the child and their birth record, linked to existing mother/father persons, in one transaction.
+// @Description Leave child.personal_code empty to assign the next free personal code for the birth date.
+// @Description The signed-in registrar's office must have jurisdiction over births at child.birth_place.
+// @Tags birth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RegisterBirthRequest true "birth data"
+// @Success 201 {object} BirthRegistrationEnvelope "Registered birth"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Outside the office's jurisdiction"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Personal code already registered"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/birth/ [post]
+func (h *Handlers) RegisterBirth(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var req RegisterBirthRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.RegisterBirth(r.Context(), req.Params())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/birth/"+result.Record.ID.String())
+	err = render.Write(w, http.StatusCreated, render.ContentTypeJSON, BirthRegistrationEnvelope{
+		Message: "birth registered successfully",
+		Data:    NewBirthRegistrationResponse(*result),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// RegisterForeignBirth transcribes a birth abroad
+// @Summary Register birth abroad
+// @Description Transcribe a birth that took place abroad from its foreign birth certificate. Like a birth in
+// @Description Lithuania, this creates the child and their birth record in one transaction, and the record also
+// @Description keeps the source document. The document needs apostille or legalisation details unless exempt, and a
+// @Description translation unless it is in Lithuanian. Any office with jurisdiction over births may transcribe one.
+// @Tags birth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RegisterForeignBirthRequest true "birth and source document"
+// @Success 201 {object} BirthRegistrationEnvelope "Transcribed birth"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Office does not register births"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Personal code already registered"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/birth/foreign [post]
+func (h *Handlers) RegisterForeignBirth(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var req RegisterForeignBirthRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.RegisterBirth(r.Context(), req.Params())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/birth/"+result.Record.ID.String())
+	err = render.Write(w, http.StatusCreated, render.ContentTypeJSON, BirthRegistrationEnvelope{
+		Message: "birth abroad transcribed successfully",
+		Data:    NewBirthRegistrationResponse(*result),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// GetBirthRegistration retrieves a birth record
+// @Summary Get birth record
+// @Description Get a birth record with the child and parents
+// @Tags birth
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "birth record ID"
+// @Success 200 {object} BirthRegistrationEnvelope "Birth record found"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "Birth record not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/birth/{id} [get]
+func (h *Handlers) GetBirthRegistration(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.GetBirthRegistration(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.writeRegistration(w, *result)
+}
+
+// GetBirthRegistrationByPerson retrieves the birth record of a person
+// @Summary Get birth record by person
+// @Description Get the birth record registered for a person
+// @Tags birth
+// @Produce json
+// @Security BearerAuth
+// @Param personID path string true "person ID"
+// @Success 200 {object} BirthRegistrationEnvelope "Birth record found"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "Birth record not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/birth/by-person/{personID} [get]
+func (h *Handlers) GetBirthRegistrationByPerson(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	personID, err := request.UUIDParam(r, "personID")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.GetBirthRegistrationByPersonID(r.Context(), personID)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.writeRegistration(w, *result)
+}
+
+// GetBirthRegistrationByNumber retrieves a birth record by registry number
+// @Summary Get birth record by registry number
+// @Description Get the birth record with a registry number such as VIL-2026-000123
+// @Tags birth
+// @Produce json
+// @Security BearerAuth
+// @Param number path string true "registry number"
+// @Success 200 {object} BirthRegistrationEnvelope "Birth record found"
+// @Failure 400 {object} map[string]interface{} "Invalid registry number"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "Birth record not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/birth/by-number/{number} [get]
+func (h *Handlers) GetBirthRegistrationByNumber(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	result, err := h.service.GetBirthRegistrationByRegistryNumber(r.Context(), chi.URLParam(r, "number"))
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.writeRegistration(w, *result)
+}
+
+// ListBirthRecords lists registered births
+// @Summary List birth records
+// @Description List birth records, most recently registered first. origin=foreign keeps births abroad, optionally
+// @Description in one country.
+// @Tags birth
+// @Produce json
+// @Security BearerAuth
+// @Param origin query string false "domestic or foreign" Enums(domestic, foreign)
+// @Param country query string false "country of a birth abroad, ISO 3166-1 alpha-2"
+// @Param limit query int false "page size (default 50, max 200)"
+// @Param offset query int false "rows to skip"
+// @Success 200 {object} BirthRecordListEnvelope "Birth records"
+// @Failure 400 {object} map[string]interface{} "Invalid paging or filter"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/birth/ [get]
+func (h *Handlers) ListBirthRecords(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	limit, offset, err := request.Page(r)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.ListBirthRecords(r.Context(), foreign.ParseFilter(r), limit, offset)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	err = render.Write(w, http.StatusOK, render.ContentTypeJSON, BirthRecordListEnvelope{
+		Count:  len(result),
+		Limit:  limit,
+		Offset: offset,
+		Data:   NewBirthRecordResponses(result),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+func (h *Handlers) writeRegistration(w http.ResponseWriter, reg BirthRegistration) {
+	err := render.Write(w, http.StatusOK, render.ContentTypeJSON, BirthRegistrationEnvelope{
+		Data: NewBirthRegistrationResponse(reg),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// serviceError maps a service failure onto an HTTP status.
+func (h *Handlers) serviceError(w http.ResponseWriter, err error) {
+	status, msg := apperr.Status(err)
+	if status >= http.StatusInternalServerError {
+		h.logger.Errorf("Service error: %v", err)
+	}
+	if status == http.StatusNotFound && msg == "not found" {
+		msg = "birth record not found"
+	}
+	http.Error(w, msg, status)
+}
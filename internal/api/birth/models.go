@@ -0,0 +1,95 @@
+package birth
+
+import (
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/foreign"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+)
+
+// BirthRecordResponse is the wire form of repository.BirthRecord.
+type BirthRecordResponse struct {
+	ID                 uuid.UUID  `json:"id"`
+	PersonID           uuid.UUID  `json:"person_id"`
+	MotherID           *uuid.UUID `json:"mother_id"`
+	FatherID           *uuid.UUID `json:"father_id"`
+	BirthPlace         string     `json:"birth_place"`
+	RegistrationOffice string     `json:"registration_office"`
+	Registrar          string     `json:"registrar"`
+	OfficeID           *uuid.UUID `json:"office_id"`
+	RegistrarID        *uuid.UUID `json:"registrar_id"`
+	RegistryNumber     *string    `json:"registry_number" example:"VIL-2026-000123"`
+	Origin             string     `json:"origin" enums:"domestic,foreign"`
+	ForeignDocumentID  *uuid.UUID `json:"foreign_document_id"`
+	RegisteredAt       time.Time  `json:"registered_at"`
+}
+
+func NewBirthRecordResponse(row repository.BirthRecord) BirthRecordResponse {
+	return BirthRecordResponse{
+		ID:                 row.ID,
+		PersonID:           row.PersonID,
+		MotherID:           render.Nullable(uuid.UUID(row.MotherID.Bytes), row.MotherID.Valid),
+		FatherID:           render.Nullable(uuid.UUID(row.FatherID.Bytes), row.FatherID.Valid),
+		BirthPlace:         row.BirthPlace,
+		RegistrationOffice: row.RegistrationOffice,
+		Registrar:          row.Registrar,
+		OfficeID:           render.Nullable(uuid.UUID(row.OfficeID.Bytes), row.OfficeID.Valid),
+		RegistrarID:        render.Nullable(uuid.UUID(row.RegistrarID.Bytes), row.RegistrarID.Valid),
+		RegistryNumber:     render.Nullable(row.RegistryNumber.String, row.RegistryNumber.Valid),
+		Origin:             foreign.Origin(row.ForeignDocumentID),
+		ForeignDocumentID:  render.Nullable(uuid.UUID(row.ForeignDocumentID.Bytes), row.ForeignDocumentID.Valid),
+		RegisteredAt:       row.RegisteredAt,
+	}
+}
+
+func NewBirthRecordResponses(rows []repository.BirthRecord) []BirthRecordResponse {
+	items := make([]BirthRecordResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, NewBirthRecordResponse(row))
+	}
+	return items
+}
+
+// BirthRegistrationResponse is a birth record with the child and parents
+// it links, and the source document of a birth abroad.
+type BirthRegistrationResponse struct {
+	Record          BirthRecordResponse       `json:"record"`
+	Child           person.PersonResponse     `json:"child"`
+	Mother          *person.PersonResponse    `json:"mother"`
+	Father          *person.PersonResponse    `json:"father"`
+	ForeignDocument *foreign.DocumentResponse `json:"foreign_document"`
+}
+
+func NewBirthRegistrationResponse(reg BirthRegistration) BirthRegistrationResponse {
+	resp := BirthRegistrationResponse{
+		Record:          NewBirthRecordResponse(reg.Record),
+		Child:           person.NewPersonResponse(reg.Child),
+		ForeignDocument: foreign.NewDocumentResponse(reg.Document),
+	}
+	if reg.Mother != nil {
+		mother := person.NewPersonResponse(*reg.Mother)
+		resp.Mother = &mother
+	}
+	if reg.Father != nil {
+		father := person.NewPersonResponse(*reg.Father)
+		resp.Father = &father
+	}
+	return resp
+}
+
+// BirthRegistrationEnvelope is the response body for a single birth.
+type BirthRegistrationEnvelope struct {
+	Message string                    `json:"message,omitempty"`
+	Data    BirthRegistrationResponse `json:"data"`
+}
+
+// BirthRecordListEnvelope is the response body for a page of birth records.
+type BirthRecordListEnvelope struct {
+	Count  int                   `json:"count"`
+	Limit  int32                 `json:"limit"`
+	Offset int32                 `json:"offset"`
+	Data   []BirthRecordResponse `json:"data"`
+}
@@ -0,0 +1,25 @@
+package birth
+
+import (
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func BirthRouter(db txn.Beginner, queries *repository.Queries, log *zap.SugaredLogger) chi.Router {
+	r := chi.NewRouter()
+
+	service := NewService(db, queries, log)
+	handlers := NewHandlers(service, log)
+
+	r.Post("/", telemetry.InstrumentHandler("birth", "RegisterBirth", handlers.RegisterBirth))
+	r.Post("/foreign", telemetry.InstrumentHandler("birth", "RegisterForeignBirth", handlers.RegisterForeignBirth))
+	r.Get("/", telemetry.InstrumentHandler("birth", "ListBirthRecords", handlers.ListBirthRecords))
+	r.Get("/by-person/{personID}", telemetry.InstrumentHandler("birth", "GetBirthRegistrationByPerson", handlers.GetBirthRegistrationByPerson))
+	r.Get("/by-number/{number}", telemetry.InstrumentHandler("birth", "GetBirthRegistrationByNumber", handlers.GetBirthRegistrationByNumber))
+	r.Get("/{id}", telemetry.InstrumentHandler("birth", "GetBirthRegistration", handlers.GetBirthRegistration))
+
+	return r
+}
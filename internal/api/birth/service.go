@@ -0,0 +1,334 @@
+package birth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/foreign"
+	"github.com/eif-courses/civilregistry/internal/api/office"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+type Service struct {
+	db     txn.Beginner
+	repo   *repository.Queries
+	logger *zap.SugaredLogger
+}
+
+func NewService(db txn.Beginner, repo *repository.Queries, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		db:     db,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// RegisterBirthParams describes a birth to register. The child's birth place
+// doubles as the place recorded on the birth record. An empty personal code
+// gets the next free one for the birth date. Document is set for a birth
+// abroad transcribed from a foreign certificate.
+type RegisterBirthParams struct {
+	Child    repository.CreatePersonParams
+	MotherID pgtype.UUID
+	FatherID pgtype.UUID
+	Document *repository.CreateForeignDocumentParams
+}
+
+// BirthRegistration is a birth record together with the persons it links
+// and, for a birth abroad, its source document.
+type BirthRegistration struct {
+	Record   repository.BirthRecord
+	Child    repository.Person
+	Mother   *repository.Person
+	Father   *repository.Person
+	Document *repository.ForeignDocument
+}
+
+// RegisterBirth creates the child and their birth record in one transaction,
+// so a rejected record never leaves an orphaned person behind. The record
+// is made by the signed-in registrar, whose office must have jurisdiction
+// over the birth place. A birth abroad may be transcribed by any office
+// that registers births.
+func (s *Service) RegisterBirth(ctx context.Context, arg RegisterBirthParams) (_ *BirthRegistration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "birth", "RegisterBirth")
+	defer func() { op.End(err) }()
+
+	if _, err := auth.RequireRegistrar(ctx); err != nil {
+		return nil, err
+	}
+	if !arg.Child.BirthPlace.Valid || strings.TrimSpace(arg.Child.BirthPlace.String) == "" {
+		return nil, apperr.Invalid("birth_place is required")
+	}
+	if arg.MotherID.Valid && arg.FatherID.Valid && arg.MotherID.Bytes == arg.FatherID.Bytes {
+		return nil, apperr.Invalid("mother and father must be different persons")
+	}
+	if arg.Child, err = person.PrepareCreate(arg.Child); err != nil {
+		return nil, err
+	}
+	place := arg.Child.BirthPlace
+	if arg.Document != nil {
+		doc, err := foreign.PrepareDocument(*arg.Document, arg.Child.BirthDate)
+		if err != nil {
+			return nil, err
+		}
+		arg.Document = &doc
+		place = pgtype.Text{}
+	}
+
+	s.logger.Infof("Registering birth of %s %s", arg.Child.FirstName, arg.Child.LastName)
+
+	var reg BirthRegistration
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		registrar, err := office.Authorize(ctx, q, office.EventBirth, place)
+		if err != nil {
+			return err
+		}
+		officeID, registrarID := office.RecordedBy(registrar)
+
+		if reg.Mother, err = loadParent(ctx, q, arg.MotherID, "mother", person.SexFemale, arg.Child.BirthDate); err != nil {
+			return err
+		}
+		if reg.Father, err = loadParent(ctx, q, arg.FatherID, "father", person.SexMale, arg.Child.BirthDate); err != nil {
+			return err
+		}
+
+		if arg.Child.PersonalCode == "" {
+			code, err := person.NextPersonalCode(ctx, q, arg.Child.BirthDate, arg.Child.Sex)
+			if err != nil {
+				return err
+			}
+			arg.Child.PersonalCode = code
+		}
+
+		arg.Child.OfficeID, arg.Child.RegistrarID = officeID, registrarID
+		if reg.Child, err = q.CreatePerson(ctx, arg.Child); err != nil {
+			return person.CreateError(err, arg.Child.PersonalCode)
+		}
+
+		if arg.Document != nil {
+			if reg.Document, err = foreign.CreateDocument(ctx, q, *arg.Document); err != nil {
+				return err
+			}
+		}
+
+		number, err := office.NextRegistryNumber(ctx, q, registrar, office.EventBirth, time.Now().Year())
+		if err != nil {
+			return err
+		}
+
+		reg.Record, err = q.CreateBirthRecord(ctx, repository.CreateBirthRecordParams{
+			PersonID:           reg.Child.ID,
+			MotherID:           arg.MotherID,
+			FatherID:           arg.FatherID,
+			BirthPlace:         arg.Child.BirthPlace.String,
+			RegistrationOffice: registrar.Office.Name,
+			Registrar:          registrar.Registrar.FullName,
+			OfficeID:           officeID,
+			RegistrarID:        registrarID,
+			RegistryNumber:     pgtype.Text{String: number, Valid: true},
+			ForeignDocumentID:  foreign.Ref(reg.Document),
+		})
+		if err != nil {
+			return fmt.Errorf("failed CreateBirthRecord: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorf("Failed RegisterBirth: %v", err)
+		return nil, err
+	}
+
+	s.logger.Infof("RegisterBirth completed successfully with ID: %s", reg.Record.ID)
+	return &reg, nil
+}
+
+func (s *Service) GetBirthRegistration(ctx context.Context, id uuid.UUID) (_ *BirthRegistration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "birth", "GetBirthRegistration")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	record, err := s.repo.GetBirthRecordByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetBirthRecordByID: %w", err)
+	}
+	return s.expand(ctx, record)
+}
+
+func (s *Service) GetBirthRegistrationByPersonID(ctx context.Context, personID uuid.UUID) (_ *BirthRegistration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "birth", "GetBirthRegistrationByPersonID")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	record, err := s.repo.GetBirthRecordByPersonID(ctx, personID)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetBirthRecordByPersonID: %w", err)
+	}
+	return s.expand(ctx, record)
+}
+
+// GetBirthRegistrationByRegistryNumber finds a birth record by its registry
+// number, e.g. VIL-2026-000123, in any letter case.
+func (s *Service) GetBirthRegistrationByRegistryNumber(ctx context.Context, number string) (_ *BirthRegistration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "birth", "GetBirthRegistrationByRegistryNumber")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	if number, err = office.NormalizeRegistryNumber(number); err != nil {
+		return nil, err
+	}
+	record, err := s.repo.GetBirthRecordByRegistryNumber(ctx, pgtype.Text{String: number, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed GetBirthRecordByRegistryNumber: %w", err)
+	}
+	return s.expand(ctx, record)
+}
+
+// ListBirthRecords returns the most recently registered births first,
+// optionally only those in Lithuania or abroad. A zero limit selects the
+// default page size.
+func (s *Service) ListBirthRecords(ctx context.Context, f foreign.Filter, limit, offset int32) (_ []repository.BirthRecord, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "birth", "ListBirthRecords")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	if limit < 0 || limit > maxListLimit {
+		return nil, apperr.Invalid("limit must be between 1 and %d", maxListLimit)
+	}
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	if offset < 0 {
+		return nil, apperr.Invalid("offset must not be negative")
+	}
+	if f, err = foreign.NormalizeFilter(f); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.ListBirthRecords(ctx, repository.ListBirthRecordsParams{
+		Origin:  f.Origin,
+		Country: f.Country,
+		Limit:   limit,
+		Offset:  offset,
+	})
+	if err != nil {
+		s.logger.Errorf("Failed ListBirthRecords: %v", err)
+		return nil, fmt.Errorf("failed ListBirthRecords: %w", err)
+	}
+	if err := s.seal(ctx, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// seal shows adopted persons' records with their adoptive parents. The
+// birth parents stay on the sealed record, which only supervisors can open
+// through the adoption.
+func (s *Service) seal(ctx context.Context, records []repository.BirthRecord) error {
+	ids := make([]uuid.UUID, 0, len(records))
+	for _, r := range records {
+		ids = append(ids, r.PersonID)
+	}
+	parents, err := s.repo.ListLegalParentage(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed ListLegalParentage: %w", err)
+	}
+	adopted := make(map[uuid.UUID]repository.LegalParentage, len(parents))
+	for _, p := range parents {
+		if p.AdoptionID.Valid {
+			adopted[p.PersonID] = p
+		}
+	}
+	for i, r := range records {
+		if p, ok := adopted[r.PersonID]; ok {
+			records[i].MotherID, records[i].FatherID = p.MotherID, p.FatherID
+		}
+	}
+	return nil
+}
+
+func (s *Service) expand(ctx context.Context, record repository.BirthRecord) (*BirthRegistration, error) {
+	sealed := []repository.BirthRecord{record}
+	if err := s.seal(ctx, sealed); err != nil {
+		return nil, err
+	}
+	record = sealed[0]
+	reg := BirthRegistration{Record: record}
+
+	child, err := s.repo.GetPersonByID(ctx, record.PersonID)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetPersonByID: %w", err)
+	}
+	reg.Child = child
+
+	for _, parent := range []struct {
+		id   pgtype.UUID
+		dest **repository.Person
+	}{{record.MotherID, &reg.Mother}, {record.FatherID, &reg.Father}} {
+		if !parent.id.Valid {
+			continue
+		}
+		p, err := s.repo.GetPersonByID(ctx, parent.id.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("failed GetPersonByID: %w", err)
+		}
+		*parent.dest = &p
+	}
+
+	if reg.Document, err = foreign.LoadDocument(ctx, s.repo, record.ForeignDocumentID); err != nil {
+		return nil, err
+	}
+	return &reg, nil
+}
+
+// loadParent resolves an optional parent and checks that they can be the
+// child's parent in the given role.
+func loadParent(ctx context.Context, q *repository.Queries, id pgtype.UUID, role, sex string, childBirth pgtype.Date) (*repository.Person, error) {
+	if !id.Valid {
+		return nil, nil
+	}
+
+	p, err := q.GetPersonByID(ctx, id.Bytes)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperr.Invalid("%s %s not found", role, uuid.UUID(id.Bytes))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed GetPersonByID: %w", err)
+	}
+
+	if p.Sex != sex {
+		return nil, apperr.Invalid("%s must be %s", role, sex)
+	}
+	if !p.BirthDate.Time.Before(childBirth.Time) {
+		return nil, apperr.Invalid("%s must be born before the child", role)
+	}
+	return &p, nil
+}
@@ -0,0 +1,361 @@
+package certificate
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/eif-courses/civilregistry/internal/api/request"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	service      *Service
+	verification Verification
+	logger       *zap.SugaredLogger
+}
+
+func NewHandlers(service *Service, verification Verification, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		service:      service,
+		verification: verification,
+		logger:       logger,
+	}
+}
+
+type RevokeCertificateRequest struct {
+	Reason string `json:"reason" example:"Issued for the wrong record"`
+}
+
+type IssueCertificateRequest struct {
+	Kind     string     `json:"kind" enums:"birth,marriage,death" example:"birth"`
+	RecordID uuid.UUID  `json:"record_id"`
+	PersonID *uuid.UUID `json:"person_id,omitempty"`
+}
+
+// Params converts the request into service parameters.
+func (req IssueCertificateRequest) Params() IssueCertificateParams {
+	arg := IssueCertificateParams{
+		Kind:     req.Kind,
+		RecordID: req.RecordID,
+	}
+	if req.PersonID != nil {
+		arg.PersonID = *req.PersonID
+	}
+	return arg
+}
+
+// IssueCertificate issues a certificate
+// @Summary Issue certificate
+// @Description Issue a birth, marriage or death certificate for a registry record. The certificate gets the next
+// @Description serial number and a snapshot of the record; person_id names the spouse a marriage certificate is for.
+// @Description The signed-in registrar and their office are recorded as the issuer.
+// @Tags certificate
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body IssueCertificateRequest true "certificate data"
+// @Success 201 {object} CertificateEnvelope "Issued certificate"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/certificate/ [post]
+func (h *Handlers) IssueCertificate(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var req IssueCertificateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.IssueCertificate(r.Context(), req.Params())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	resp, err := NewCertificateResponse(*result, h.verification)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/certificate/"+result.ID.String())
+	err = render.Write(w, http.StatusCreated, render.ContentTypeJSON, CertificateEnvelope{
+		Message: "certificate issued successfully",
+		Data:    resp,
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// GetCertificate retrieves an issued certificate
+// @Summary Get certificate
+// @Description Get an issued certificate with its printed fields. Registrars only; the public check is /verify/{token}.
+// @Tags certificate
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "certificate ID"
+// @Success 200 {object} CertificateEnvelope "Certificate found"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 404 {object} map[string]interface{} "Certificate not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/certificate/{id} [get]
+func (h *Handlers) GetCertificate(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.GetCertificate(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	resp, err := NewCertificateResponse(*result, h.verification)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	if err := render.Write(w, http.StatusOK, render.ContentTypeJSON, CertificateEnvelope{Data: resp}); err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// GetCertificatePDF renders an issued certificate
+// @Summary Certificate PDF
+// @Description Download the printable certificate with its verification QR code. The printed content never
+// @Description changes once issued, so the response can be cached; If-None-Match and If-Modified-Since are honoured.
+// @Description Registrars only.
+// @Tags certificate
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param id path string true "certificate ID"
+// @Success 200 {file} file "Certificate PDF"
+// @Success 304 "Cached copy is still current"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 404 {object} map[string]interface{} "Certificate not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/certificate/{id}.pdf [get]
+func (h *Handlers) GetCertificatePDF(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypePDF) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.GetCertificate(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	if render.NotModified(w, r, render.TimeETag(result.CreatedAt), render.ContentTypePDF, result.CreatedAt) {
+		return
+	}
+
+	// Render into a buffer so a failure can still become a 500
+	var buf bytes.Buffer
+	if err := WritePDF(&buf, *result, h.verification.URL(result.ID)); err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", render.ContentTypePDF)
+	w.Header().Set("Content-Disposition", `inline; filename="`+result.SerialNumber+`.pdf"`)
+	if _, err := buf.WriteTo(w); err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// RevokeCertificate revokes an issued certificate
+// @Summary Revoke certificate
+// @Description Withdraw an issued certificate. Its verification page and endpoint report it as revoked afterwards.
+// @Description Only the issuing office may revoke it; the signed-in registrar is recorded as the one who did.
+// @Tags certificate
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "certificate ID"
+// @Param request body RevokeCertificateRequest true "revocation data"
+// @Success 200 {object} CertificateEnvelope "Revoked certificate"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar of the issuing office"
+// @Failure 404 {object} map[string]interface{} "Certificate not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Certificate already revoked"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/certificate/{id}/revoke [post]
+func (h *Handlers) RevokeCertificate(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	var req RevokeCertificateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.RevokeCertificate(r.Context(), id, req.Reason)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	resp, err := NewCertificateResponse(*result, h.verification)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	err = render.Write(w, http.StatusOK, render.ContentTypeJSON, CertificateEnvelope{
+		Message: "certificate revoked successfully",
+		Data:    resp,
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// VerifyCertificate checks a verification token
+// @Summary Verify certificate
+// @Description Public lookup behind the QR code on a certificate. Confirms the certificate was issued here and
+// @Description whether it is still valid, without revealing who it names.
+// @Tags certificate
+// @Produce json
+// @Param token path string true "verification token"
+// @Success 200 {object} VerificationEnvelope "Certificate is authentic; check status for revocation"
+// @Failure 404 {object} map[string]interface{} "Certificate could not be verified"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/certificate/verify/{token} [get]
+func (h *Handlers) VerifyCertificate(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := h.verification.Parse(chi.URLParam(r, "token"))
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.VerifyCertificate(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	resp, err := NewVerificationResponse(*result)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	// Revocation must show up promptly, so verification results are not cached
+	w.Header().Set("Cache-Control", "no-store")
+	if err := render.Write(w, http.StatusOK, render.ContentTypeJSON, VerificationEnvelope{Data: resp}); err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// ListCertificatesForPerson lists the certificates issued for a person
+// @Summary List certificates
+// @Description List the certificates issued for a person, newest first. Registrars only.
+// @Tags certificate
+// @Produce json
+// @Security BearerAuth
+// @Param person_id query string true "person ID"
+// @Success 200 {object} CertificateListEnvelope "Certificates"
+// @Failure 400 {object} map[string]interface{} "Invalid person ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/certificate/ [get]
+func (h *Handlers) ListCertificatesForPerson(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	personID, err := uuid.Parse(r.URL.Query().Get("person_id"))
+	if err != nil {
+		h.serviceError(w, apperr.Invalid("person_id must be a UUID"))
+		return
+	}
+
+	result, err := h.service.ListCertificatesForPerson(r.Context(), personID)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	items, err := NewCertificateResponses(result, h.verification)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	err = render.Write(w, http.StatusOK, render.ContentTypeJSON, CertificateListEnvelope{
+		Count: len(items),
+		Data:  items,
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// serviceError maps a service failure onto an HTTP status.
+func (h *Handlers) serviceError(w http.ResponseWriter, err error) {
+	status, msg := apperr.Status(err)
+	if status >= http.StatusInternalServerError {
+		h.logger.Errorf("Service error: %v", err)
+	}
+	if status == http.StatusNotFound && msg == "not found" {
+		msg = "certificate not found"
+	}
+	http.Error(w, msg, status)
+}
@@ -0,0 +1,122 @@
+package certificate
+
+import (
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+)
+
+// CertificateResponse is the wire form of repository.Certificate with its
+// content decoded.
+type CertificateResponse struct {
+	ID                  uuid.UUID   `json:"id"`
+	SerialNumber        string      `json:"serial_number"`
+	Kind                string      `json:"kind"`
+	RecordID            uuid.UUID   `json:"record_id"`
+	PersonID            uuid.UUID   `json:"person_id"`
+	Title               string      `json:"title"`
+	Fields              []Field     `json:"fields"`
+	IssuedOn            render.Date `json:"issued_on"`
+	IssuedBy            string      `json:"issued_by"`
+	OfficeID            *uuid.UUID  `json:"office_id"`
+	RegistrarID         *uuid.UUID  `json:"registrar_id"`
+	Status              string      `json:"status" enums:"valid,revoked"`
+	RevokedAt           *time.Time  `json:"revoked_at,omitempty"`
+	RevokedBy           *string     `json:"revoked_by,omitempty"`
+	RevokingRegistrarID *uuid.UUID  `json:"revoking_registrar_id,omitempty"`
+	RevocationReason    *string     `json:"revocation_reason,omitempty"`
+	CreatedAt           time.Time   `json:"created_at"`
+	PDFURL              string      `json:"pdf_url"`
+	VerificationURL     string      `json:"verification_url"`
+}
+
+func NewCertificateResponse(row repository.Certificate, v Verification) (CertificateResponse, error) {
+	content, err := DecodeContent(row)
+	if err != nil {
+		return CertificateResponse{}, err
+	}
+	return CertificateResponse{
+		ID:                  row.ID,
+		SerialNumber:        row.SerialNumber,
+		Kind:                row.Kind,
+		RecordID:            row.RecordID,
+		PersonID:            row.PersonID,
+		Title:               content.Title,
+		Fields:              content.Fields,
+		IssuedOn:            render.Date(row.IssuedOn.Time),
+		IssuedBy:            row.IssuedBy,
+		OfficeID:            render.Nullable(uuid.UUID(row.OfficeID.Bytes), row.OfficeID.Valid),
+		RegistrarID:         render.Nullable(uuid.UUID(row.RegistrarID.Bytes), row.RegistrarID.Valid),
+		Status:              Status(row),
+		RevokedAt:           render.Nullable(row.RevokedAt.Time, row.RevokedAt.Valid),
+		RevokedBy:           render.Nullable(row.RevokedBy.String, row.RevokedBy.Valid),
+		RevokingRegistrarID: render.Nullable(uuid.UUID(row.RevokingRegistrarID.Bytes), row.RevokingRegistrarID.Valid),
+		RevocationReason:    render.Nullable(row.RevocationReason.String, row.RevocationReason.Valid),
+		CreatedAt:           row.CreatedAt,
+		PDFURL:              PDFPath(row.ID),
+		VerificationURL:     v.URL(row.ID),
+	}, nil
+}
+
+func NewCertificateResponses(rows []repository.Certificate, v Verification) ([]CertificateResponse, error) {
+	items := make([]CertificateResponse, 0, len(rows))
+	for _, row := range rows {
+		item, err := NewCertificateResponse(row, v)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// PDFPath is where the printable certificate is served.
+func PDFPath(id uuid.UUID) string {
+	return "/api/certificate/" + id.String() + ".pdf"
+}
+
+// CertificateEnvelope is the response body for a single certificate.
+type CertificateEnvelope struct {
+	Message string              `json:"message,omitempty"`
+	Data    CertificateResponse `json:"data"`
+}
+
+// CertificateListEnvelope is the response body for a person's certificates.
+type CertificateListEnvelope struct {
+	Count int                   `json:"count"`
+	Data  []CertificateResponse `json:"data"`
+}
+
+// VerificationResponse is what the public verification endpoint reveals
+// about a certificate: enough to match it against the paper copy, nothing
+// about the people named on it.
+type VerificationResponse struct {
+	Status       string       `json:"status" enums:"valid,revoked"`
+	SerialNumber string       `json:"serial_number"`
+	Kind         string       `json:"kind"`
+	Title        string       `json:"title"`
+	IssuedOn     render.Date  `json:"issued_on"`
+	RevokedOn    *render.Date `json:"revoked_on,omitempty"`
+}
+
+func NewVerificationResponse(row repository.Certificate) (VerificationResponse, error) {
+	content, err := DecodeContent(row)
+	if err != nil {
+		return VerificationResponse{}, err
+	}
+	return VerificationResponse{
+		Status:       Status(row),
+		SerialNumber: row.SerialNumber,
+		Kind:         row.Kind,
+		Title:        content.Title,
+		IssuedOn:     render.Date(row.IssuedOn.Time),
+		RevokedOn:    render.NullableDate(row.RevokedAt.Time, row.RevokedAt.Valid),
+	}, nil
+}
+
+// VerificationEnvelope is the response body for a verification lookup.
+type VerificationEnvelope struct {
+	Data VerificationResponse `json:"data"`
+}
@@ -0,0 +1,85 @@
+package certificate
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// WritePDF renders an issued certificate as a one-page A4 PDF with a QR
+// code linking to verifyURL. The core PDF fonts lack Lithuanian letters, so
+// the Go fonts are embedded instead. The document dates come from the
+// issuance record, so reprints are identical.
+func WritePDF(w io.Writer, cert repository.Certificate, verifyURL string) error {
+	content, err := DecodeContent(cert)
+	if err != nil {
+		return err
+	}
+	qr, err := qrcode.Encode(verifyURL, qrcode.Medium, 512)
+	if err != nil {
+		return fmt.Errorf("failed to encode QR code: %w", err)
+	}
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetCreationDate(cert.CreatedAt)
+	pdf.SetModificationDate(cert.CreatedAt)
+	pdf.SetCatalogSort(true)
+	pdf.SetTitle(content.Title+" "+cert.SerialNumber, true)
+	pdf.SetCreator("civilregistry", true)
+	pdf.AddUTF8FontFromBytes("go", "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes("go", "B", gobold.TTF)
+	pdf.SetMargins(25, 25, 25)
+	pdf.SetAutoPageBreak(false, 25)
+	pdf.AddPage()
+
+	pdf.SetFont("go", "B", 11)
+	pdf.CellFormat(0, 6, "REPUBLIC OF LITHUANIA", "", 1, "C", false, 0, "")
+	pdf.SetFont("go", "", 10)
+	pdf.CellFormat(0, 5, "Civil Registry", "", 1, "C", false, 0, "")
+	pdf.Ln(14)
+
+	pdf.SetFont("go", "B", 22)
+	pdf.CellFormat(0, 10, strings.ToUpper(content.Title), "", 1, "C", false, 0, "")
+	pdf.SetFont("go", "", 10)
+	pdf.CellFormat(0, 6, "Serial No. "+cert.SerialNumber, "", 1, "C", false, 0, "")
+	pdf.Ln(12)
+
+	for _, f := range content.Fields {
+		pdf.SetFont("go", "", 9)
+		pdf.SetTextColor(90, 90, 90)
+		pdf.CellFormat(55, 9, f.Label, "B", 0, "L", false, 0, "")
+		pdf.SetFont("go", "", 11)
+		pdf.SetTextColor(0, 0, 0)
+		pdf.MultiCell(0, 9, f.Value, "B", "L", false)
+	}
+	pdf.Ln(20)
+
+	pdf.SetFont("go", "", 10)
+	pdf.CellFormat(80, 6, "Issued on "+formatDate(cert.IssuedOn), "", 0, "L", false, 0, "")
+	pdf.CellFormat(0, 6, "____________________________", "", 1, "R", false, 0, "")
+	pdf.CellFormat(80, 6, "Serial No. "+cert.SerialNumber, "", 0, "L", false, 0, "")
+	pdf.CellFormat(0, 6, cert.IssuedBy, "", 1, "R", false, 0, "")
+
+	// Verification block pinned to the bottom of the page
+	const qrSize = 32
+	_, pageHeight := pdf.GetPageSize()
+	top := pageHeight - 25 - qrSize
+	pdf.RegisterImageOptionsReader("qr", fpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qr))
+	pdf.ImageOptions("qr", 25, top, qrSize, qrSize, false, fpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	pdf.SetXY(25+qrSize+5, top+8)
+	pdf.SetFont("go", "", 9)
+	pdf.SetTextColor(90, 90, 90)
+	pdf.MultiCell(0, 5, "Scan the code or open the address below to check that this certificate is authentic and has not been revoked.", "", "L", false)
+	pdf.SetX(25 + qrSize + 5)
+	pdf.SetFont("go", "", 8)
+	pdf.MultiCell(0, 5, verifyURL, "", "L", false)
+
+	return pdf.Output(w)
+}
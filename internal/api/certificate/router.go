@@ -0,0 +1,24 @@
+package certificate
+
+import (
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func CertificateRouter(queries *repository.Queries, verification Verification, log *zap.SugaredLogger) chi.Router {
+	r := chi.NewRouter()
+
+	service := NewService(queries, log)
+	handlers := NewHandlers(service, verification, log)
+
+	r.Post("/", telemetry.InstrumentHandler("certificate", "IssueCertificate", handlers.IssueCertificate))
+	r.Get("/", telemetry.InstrumentHandler("certificate", "ListCertificatesForPerson", handlers.ListCertificatesForPerson))
+	r.Get("/verify/{token}", telemetry.InstrumentHandler("certificate", "VerifyCertificate", handlers.VerifyCertificate))
+	r.Get("/{id}.pdf", telemetry.InstrumentHandler("certificate", "GetCertificatePDF", handlers.GetCertificatePDF))
+	r.Get("/{id}", telemetry.InstrumentHandler("certificate", "GetCertificate", handlers.GetCertificate))
+	r.Post("/{id}/revoke", telemetry.InstrumentHandler("certificate", "RevokeCertificate", handlers.RevokeCertificate))
+
+	return r
+}
@@ -0,0 +1,380 @@
+package certificate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/eif-courses/civilregistry/internal/api/marriage"
+	"github.com/eif-courses/civilregistry/internal/api/office"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const (
+	KindBirth    = "birth"
+	KindMarriage = "marriage"
+	KindDeath    = "death"
+
+	StatusValid   = "valid"
+	StatusRevoked = "revoked"
+)
+
+type Service struct {
+	repo   *repository.Queries
+	logger *zap.SugaredLogger
+}
+
+func NewService(repo *repository.Queries, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// IssueCertificateParams selects the record to certify. PersonID picks the
+// spouse a marriage certificate is issued to; birth and death certificates
+// are issued for the record's subject, so it may be left zero for them.
+type IssueCertificateParams struct {
+	Kind     string
+	RecordID uuid.UUID
+	PersonID uuid.UUID
+}
+
+// Content is the printed body of a certificate, stored with the issuance
+// record as it was at the time of issue.
+type Content struct {
+	Title  string  `json:"title"`
+	Fields []Field `json:"fields"`
+}
+
+// Field is one labelled line of a certificate.
+type Field struct {
+	Label string `json:"label"`
+	Value string `json:"value"`
+}
+
+// IssueCertificate snapshots the record into a new certificate with the
+// next serial number, issued by the signed-in registrar and their office.
+func (s *Service) IssueCertificate(ctx context.Context, arg IssueCertificateParams) (_ *repository.Certificate, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "certificate", "IssueCertificate")
+	defer func() { op.End(err) }()
+
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Issuing %s certificate for record %s", arg.Kind, arg.RecordID)
+
+	var (
+		content Content
+		subject uuid.UUID
+	)
+	switch arg.Kind {
+	case KindBirth:
+		content, subject, err = s.birthContent(ctx, arg.RecordID)
+	case KindMarriage:
+		content, subject, err = s.marriageContent(ctx, arg.RecordID, arg.PersonID)
+	case KindDeath:
+		content, subject, err = s.deathContent(ctx, arg.RecordID)
+	default:
+		return nil, apperr.Invalid("kind must be %q, %q or %q", KindBirth, KindMarriage, KindDeath)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if arg.PersonID != uuid.Nil && arg.PersonID != subject {
+		return nil, apperr.Invalid("the %s record is not about person %s", arg.Kind, arg.PersonID)
+	}
+
+	body, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode certificate content: %w", err)
+	}
+
+	officeID, registrarID := office.RecordedBy(p)
+	result, err := s.repo.CreateCertificate(ctx, repository.CreateCertificateParams{
+		Kind:        arg.Kind,
+		RecordID:    arg.RecordID,
+		PersonID:    subject,
+		Content:     body,
+		IssuedBy:    p.Name(),
+		OfficeID:    officeID,
+		RegistrarID: registrarID,
+	})
+	if err != nil {
+		s.logger.Errorf("Failed CreateCertificate: %v", err)
+		return nil, fmt.Errorf("failed CreateCertificate: %w", err)
+	}
+
+	s.logger.Infof("IssueCertificate completed successfully with serial number %s", result.SerialNumber)
+	return &result, nil
+}
+
+func (s *Service) GetCertificate(ctx context.Context, id uuid.UUID) (_ *repository.Certificate, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "certificate", "GetCertificate")
+	defer func() { op.End(err) }()
+
+	// The full certificate names the person; only /verify is public
+	if _, err := auth.RequireRegistrar(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.GetCertificateByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetCertificateByID: %w", err)
+	}
+	return &result, nil
+}
+
+// VerifyCertificate looks up the certificate behind a verified token. A
+// missing certificate gets the same message as a forged token.
+func (s *Service) VerifyCertificate(ctx context.Context, id uuid.UUID) (_ *repository.Certificate, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "certificate", "VerifyCertificate")
+	defer func() { op.End(err) }()
+
+	result, err := s.repo.GetCertificateByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperr.NotFound("certificate could not be verified")
+	}
+	if err != nil {
+		s.logger.Errorf("Failed VerifyCertificate: %v", err)
+		return nil, fmt.Errorf("failed VerifyCertificate: %w", err)
+	}
+	return &result, nil
+}
+
+// RevokeCertificate withdraws an issued certificate on behalf of the
+// signed-in registrar. Its verification page reports it as revoked from
+// then on.
+func (s *Service) RevokeCertificate(ctx context.Context, id uuid.UUID, reason string) (_ *repository.Certificate, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "certificate", "RevokeCertificate")
+	defer func() { op.End(err) }()
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, apperr.Invalid("reason is required")
+	}
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.GetCertificateByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetCertificateByID: %w", err)
+	}
+	// Certificates issued before offices were tracked may be revoked by any
+	// office; later ones only by the office that issued them
+	if current.OfficeID.Valid && uuid.UUID(current.OfficeID.Bytes) != p.Office.ID {
+		return nil, apperr.Forbidden("certificate %s was issued by another office", current.SerialNumber)
+	}
+
+	s.logger.Infof("Revoking certificate %s", id)
+
+	result, err := s.repo.RevokeCertificate(ctx, repository.RevokeCertificateParams{
+		ID:                  id,
+		RevokedBy:           pgtype.Text{String: p.Name(), Valid: true},
+		RevocationReason:    pgtype.Text{String: reason, Valid: true},
+		RevokingRegistrarID: pgtype.UUID{Bytes: p.Registrar.ID, Valid: true},
+	})
+	if errors.Is(err, pgx.ErrNoRows) {
+		// Revoked meanwhile; read it again for the date
+		if current, err = s.repo.GetCertificateByID(ctx, id); err != nil {
+			return nil, fmt.Errorf("failed GetCertificateByID: %w", err)
+		}
+		return nil, apperr.Conflict("certificate %s was already revoked on %s", current.SerialNumber, current.RevokedAt.Time.Format("2006-01-02"))
+	}
+	if err != nil {
+		s.logger.Errorf("Failed RevokeCertificate: %v", err)
+		return nil, fmt.Errorf("failed RevokeCertificate: %w", err)
+	}
+	return &result, nil
+}
+
+// ListCertificatesForPerson returns the certificates issued for a person,
+// newest first.
+func (s *Service) ListCertificatesForPerson(ctx context.Context, personID uuid.UUID) (_ []repository.Certificate, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "certificate", "ListCertificatesForPerson")
+	defer func() { op.End(err) }()
+
+	if _, err := auth.RequireRegistrar(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.ListCertificatesForPerson(ctx, personID)
+	if err != nil {
+		s.logger.Errorf("Failed ListCertificatesForPerson: %v", err)
+		return nil, fmt.Errorf("failed ListCertificatesForPerson: %w", err)
+	}
+	return result, nil
+}
+
+// DecodeContent reads the content snapshot stored with a certificate.
+func DecodeContent(cert repository.Certificate) (Content, error) {
+	var content Content
+	if err := json.Unmarshal(cert.Content, &content); err != nil {
+		return Content{}, fmt.Errorf("failed to decode certificate %s: %w", cert.SerialNumber, err)
+	}
+	return content, nil
+}
+
+// Status reports whether a certificate is still valid.
+func Status(cert repository.Certificate) string {
+	if cert.RevokedAt.Valid {
+		return StatusRevoked
+	}
+	return StatusValid
+}
+
+func (s *Service) birthContent(ctx context.Context, id uuid.UUID) (Content, uuid.UUID, error) {
+	record, err := s.repo.GetBirthRecordByID(ctx, id)
+	if err != nil {
+		return Content{}, uuid.Nil, recordError(err, "birth record", id)
+	}
+	child, err := s.person(ctx, record.PersonID)
+	if err != nil {
+		return Content{}, uuid.Nil, err
+	}
+	// An adoption replaces the birth parents named on the certificate
+	parents, err := s.repo.GetLegalParentage(ctx, record.PersonID)
+	if err != nil {
+		return Content{}, uuid.Nil, fmt.Errorf("failed GetLegalParentage: %w", err)
+	}
+	mother, err := s.parent(ctx, parents.MotherID)
+	if err != nil {
+		return Content{}, uuid.Nil, err
+	}
+	father, err := s.parent(ctx, parents.FatherID)
+	if err != nil {
+		return Content{}, uuid.Nil, err
+	}
+
+	return Content{
+		Title: "Birth Certificate",
+		Fields: []Field{
+			{"Name", child.FirstName + " " + child.LastName},
+			{"Personal code", child.PersonalCode},
+			{"Date of birth", formatDate(child.BirthDate)},
+			{"Place of birth", record.BirthPlace},
+			{"Sex", child.Sex},
+			{"Mother", mother},
+			{"Father", father},
+			{"Registration office", record.RegistrationOffice},
+			{"Registered on", record.RegisteredAt.Format("2006-01-02")},
+		},
+	}, child.ID, nil
+}
+
+func (s *Service) marriageContent(ctx context.Context, id, personID uuid.UUID) (Content, uuid.UUID, error) {
+	record, err := s.repo.GetMarriageByID(ctx, id)
+	if err != nil {
+		return Content{}, uuid.Nil, recordError(err, "marriage", id)
+	}
+	// Whoever it is issued to, a marriage certificate names both spouses
+	if personID == uuid.Nil {
+		return Content{}, uuid.Nil, apperr.Invalid("person_id is required for marriage certificates")
+	}
+	if personID != record.Spouse1ID && personID != record.Spouse2ID {
+		return Content{}, uuid.Nil, apperr.Invalid("person %s is not a spouse in marriage %s", personID, id)
+	}
+	spouse1, err := s.person(ctx, record.Spouse1ID)
+	if err != nil {
+		return Content{}, uuid.Nil, err
+	}
+	spouse2, err := s.person(ctx, record.Spouse2ID)
+	if err != nil {
+		return Content{}, uuid.Nil, err
+	}
+
+	fields := []Field{
+		{"Spouse", spouse1.FirstName + " " + surname(record.Spouse1NewName, record.Spouse1PreviousName)},
+		{"Personal code", spouse1.PersonalCode},
+		{"Surname before marriage", record.Spouse1PreviousName},
+		{"Spouse", spouse2.FirstName + " " + surname(record.Spouse2NewName, record.Spouse2PreviousName)},
+		{"Personal code", spouse2.PersonalCode},
+		{"Surname before marriage", record.Spouse2PreviousName},
+		{"Date of marriage", formatDate(record.RegisteredOn)},
+		{"Registration office", record.RegistrationOffice},
+	}
+	if record.Status != marriage.StatusActive {
+		fields = append(fields, Field{"Marriage " + record.Status, formatDate(record.EndedOn)})
+	}
+	return Content{Title: "Marriage Certificate", Fields: fields}, personID, nil
+}
+
+func (s *Service) deathContent(ctx context.Context, id uuid.UUID) (Content, uuid.UUID, error) {
+	record, err := s.repo.GetDeathRecordByID(ctx, id)
+	if err != nil {
+		return Content{}, uuid.Nil, recordError(err, "death record", id)
+	}
+	deceased, err := s.person(ctx, record.PersonID)
+	if err != nil {
+		return Content{}, uuid.Nil, err
+	}
+
+	return Content{
+		Title: "Death Certificate",
+		Fields: []Field{
+			{"Name", deceased.FirstName + " " + deceased.LastName},
+			{"Personal code", deceased.PersonalCode},
+			{"Date of birth", formatDate(deceased.BirthDate)},
+			{"Date of death", formatDate(record.DateOfDeath)},
+			{"Place of death", record.PlaceOfDeath},
+			{"Registration office", record.RegistrationOffice},
+			{"Registered on", record.RegisteredAt.Format("2006-01-02")},
+		},
+	}, deceased.ID, nil
+}
+
+func (s *Service) person(ctx context.Context, id uuid.UUID) (repository.Person, error) {
+	p, err := s.repo.GetPersonByID(ctx, id)
+	if err != nil {
+		return repository.Person{}, fmt.Errorf("failed GetPersonByID: %w", err)
+	}
+	return p, nil
+}
+
+// parent formats an optional parent as "First Last (code)".
+func (s *Service) parent(ctx context.Context, id pgtype.UUID) (string, error) {
+	if !id.Valid {
+		return "Not recorded", nil
+	}
+	p, err := s.person(ctx, id.Bytes)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s (%s)", p.FirstName, p.LastName, p.PersonalCode), nil
+}
+
+// recordError reports a missing source record as invalid input, so it is
+// not confused with a missing certificate.
+func recordError(err error, what string, id uuid.UUID) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperr.Invalid("%s %s not found", what, id)
+	}
+	return fmt.Errorf("failed to load %s: %w", what, err)
+}
+
+func surname(newName pgtype.Text, previous string) string {
+	if newName.Valid {
+		return newName.String
+	}
+	return previous
+}
+
+func formatDate(d pgtype.Date) string {
+	if !d.Valid {
+		return ""
+	}
+	return d.Time.Format("2006-01-02")
+}
@@ -0,0 +1,65 @@
+package certificate
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/google/uuid"
+)
+
+// Verification signs the tokens printed on certificates as QR codes and
+// builds the public URLs they point to. A token is the certificate ID and
+// an HMAC-SHA256 of it, both base64url encoded, so it cannot be guessed or
+// pointed at another certificate without the key.
+type Verification struct {
+	Key     []byte
+	BaseURL string
+}
+
+// Token returns the verification token for a certificate.
+func (v Verification) Token(id uuid.UUID) string {
+	return encode(id[:]) + "." + encode(v.sign(id))
+}
+
+// URL returns the public verification page for a certificate.
+func (v Verification) URL(id uuid.UUID) string {
+	return strings.TrimRight(v.BaseURL, "/") + "/verify/" + v.Token(id)
+}
+
+// Parse checks a token's signature and returns the certificate ID. Every
+// malformed or forged token is reported the same way, as not found, so the
+// response says nothing about why a token was rejected.
+func (v Verification) Parse(token string) (uuid.UUID, error) {
+	notFound := apperr.NotFound("certificate could not be verified")
+
+	payload, mac, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.Nil, notFound
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return uuid.Nil, notFound
+	}
+	id, err := uuid.FromBytes(raw)
+	if err != nil {
+		return uuid.Nil, notFound
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(mac)
+	if err != nil || !hmac.Equal(sig, v.sign(id)) {
+		return uuid.Nil, notFound
+	}
+	return id, nil
+}
+
+func (v Verification) sign(id uuid.UUID) []byte {
+	mac := hmac.New(sha256.New, v.Key)
+	mac.Write(id[:])
+	return mac.Sum(nil)
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
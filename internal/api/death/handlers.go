@@ -0,0 +1,312 @@
+package death
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/eif-courses/civilregistry/internal/api/foreign"
+	"github.com/eif-courses/civilregistry/internal/api/request"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	service *Service
+	logger  *zap.SugaredLogger
+}
+
+func NewHandlers(service *Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type RegisterDeathRequest struct {
+	PersonID          uuid.UUID   `json:"person_id"`
+	DateOfDeath       render.Date `json:"date_of_death" swaggertype:"string" format:"date" example:"2026-09-01"`
+	PlaceOfDeath      string      `json:"place_of_death" example:"Vilnius"`
+	CauseCode         string      `json:"cause_code" example:"I21.9"`
+	InformantName     string      `json:"informant_name" example:"Petras Petraitis"`
+	InformantPersonID *uuid.UUID  `json:"informant_person_id,omitempty"`
+}
+
+// Params converts the request into service parameters.
+func (req RegisterDeathRequest) Params() RegisterDeathParams {
+	return RegisterDeathParams{
+		PersonID:          req.PersonID,
+		DateOfDeath:       request.Date(req.DateOfDeath),
+		PlaceOfDeath:      req.PlaceOfDeath,
+		CauseCode:         req.CauseCode,
+		InformantName:     req.InformantName,
+		InformantPersonID: request.UUID(req.InformantPersonID),
+	}
+}
+
+type RegisterForeignDeathRequest struct {
+	RegisterDeathRequest
+	Document foreign.DocumentRequest `json:"document"`
+}
+
+// Params converts the request into service parameters.
+func (req RegisterForeignDeathRequest) Params() RegisterDeathParams {
+	arg := req.RegisterDeathRequest.Params()
+	doc := req.Document.Params()
+	arg.Document = &doc
+	return arg
+}
+
+// RegisterDeath registers a death
+// @Summary Register death
+// @Description Record a death, mark the person deceased, close an active marriage as widowed and end the declared residence. The person's records are frozen afterwards.
+// @Description The signed-in registrar's office must have jurisdiction over deaths at place_of_death.
+// @Tags death
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RegisterDeathRequest true "death data"
+// @Success 201 {object} DeathRegistrationEnvelope "Registered death"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Outside the office's jurisdiction"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Death already registered or person merged"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/death/ [post]
+func (h *Handlers) RegisterDeath(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var req RegisterDeathRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.RegisterDeath(r.Context(), req.Params())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/death/"+result.Record.ID.String())
+	h.writeRegistration(w, http.StatusCreated, "death registered successfully", *result)
+}
+
+// RegisterForeignDeath transcribes a death abroad
+// @Summary Register death abroad
+// @Description Transcribe a death that took place abroad from its foreign death certificate. The person, marriage
+// @Description and residence are updated as for a death in Lithuania, and the record also keeps the source document.
+// @Description The document needs apostille or legalisation details unless exempt, and a translation unless it is in
+// @Description Lithuanian. Any office with jurisdiction over deaths may transcribe one.
+// @Tags death
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RegisterForeignDeathRequest true "death and source document"
+// @Success 201 {object} DeathRegistrationEnvelope "Transcribed death"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Office does not register deaths"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Death already registered or person merged"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/death/foreign [post]
+func (h *Handlers) RegisterForeignDeath(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var req RegisterForeignDeathRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.RegisterDeath(r.Context(), req.Params())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/death/"+result.Record.ID.String())
+	h.writeRegistration(w, http.StatusCreated, "death abroad transcribed successfully", *result)
+}
+
+// GetDeathRegistration retrieves a death record
+// @Summary Get death record
+// @Description Get a death record with the deceased
+// @Tags death
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "death record ID"
+// @Success 200 {object} DeathRegistrationEnvelope "Death record found"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "Death record not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/death/{id} [get]
+func (h *Handlers) GetDeathRegistration(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.GetDeathRegistration(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.writeRegistration(w, http.StatusOK, "", *result)
+}
+
+// GetDeathRegistrationByPerson retrieves the death record of a person
+// @Summary Get death record by person
+// @Description Get the death record registered for a person
+// @Tags death
+// @Produce json
+// @Security BearerAuth
+// @Param personID path string true "person ID"
+// @Success 200 {object} DeathRegistrationEnvelope "Death record found"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "Death record not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/death/by-person/{personID} [get]
+func (h *Handlers) GetDeathRegistrationByPerson(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	personID, err := request.UUIDParam(r, "personID")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.GetDeathRegistrationByPersonID(r.Context(), personID)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.writeRegistration(w, http.StatusOK, "", *result)
+}
+
+// GetDeathRegistrationByNumber retrieves a death record by registry number
+// @Summary Get death record by registry number
+// @Description Get the death record with a registry number such as VIL-2026-000123
+// @Tags death
+// @Produce json
+// @Security BearerAuth
+// @Param number path string true "registry number"
+// @Success 200 {object} DeathRegistrationEnvelope "Death record found"
+// @Failure 400 {object} map[string]interface{} "Invalid registry number"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "Death record not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/death/by-number/{number} [get]
+func (h *Handlers) GetDeathRegistrationByNumber(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	result, err := h.service.GetDeathRegistrationByRegistryNumber(r.Context(), chi.URLParam(r, "number"))
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.writeRegistration(w, http.StatusOK, "", *result)
+}
+
+// ListDeathRecords lists registered deaths
+// @Summary List death records
+// @Description List death records, most recently registered first. origin=foreign keeps deaths abroad, optionally
+// @Description in one country.
+// @Tags death
+// @Produce json
+// @Security BearerAuth
+// @Param origin query string false "domestic or foreign" Enums(domestic, foreign)
+// @Param country query string false "country of a death abroad, ISO 3166-1 alpha-2"
+// @Param limit query int false "page size (default 50, max 200)"
+// @Param offset query int false "rows to skip"
+// @Success 200 {object} DeathRecordListEnvelope "Death records"
+// @Failure 400 {object} map[string]interface{} "Invalid paging or filter"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/death/ [get]
+func (h *Handlers) ListDeathRecords(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	limit, offset, err := request.Page(r)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.ListDeathRecords(r.Context(), foreign.ParseFilter(r), limit, offset)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	err = render.Write(w, http.StatusOK, render.ContentTypeJSON, DeathRecordListEnvelope{
+		Count:  len(result),
+		Limit:  limit,
+		Offset: offset,
+		Data:   NewDeathRecordResponses(result),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+func (h *Handlers) writeRegistration(w http.ResponseWriter, status int, message string, reg DeathRegistration) {
+	err := render.Write(w, status, render.ContentTypeJSON, DeathRegistrationEnvelope{
+		Message: message,
+		Data:    NewDeathRegistrationResponse(reg),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// serviceError maps a service failure onto an HTTP status.
+func (h *Handlers) serviceError(w http.ResponseWriter, err error) {
+	status, msg := apperr.Status(err)
+	if status >= http.StatusInternalServerError {
+		h.logger.Errorf("Service error: %v", err)
+	}
+	if status == http.StatusNotFound && msg == "not found" {
+		msg = "death record not found"
+	}
+	http.Error(w, msg, status)
+}
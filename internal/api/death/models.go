@@ -0,0 +1,108 @@
+package death
+
+import (
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/foreign"
+	"github.com/eif-courses/civilregistry/internal/api/marriage"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/api/residence"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+)
+
+// DeathRecordResponse is the wire form of repository.DeathRecord.
+type DeathRecordResponse struct {
+	ID                 uuid.UUID   `json:"id"`
+	PersonID           uuid.UUID   `json:"person_id"`
+	DateOfDeath        render.Date `json:"date_of_death"`
+	PlaceOfDeath       string      `json:"place_of_death"`
+	CauseCode          string      `json:"cause_code"`
+	InformantName      string      `json:"informant_name"`
+	InformantPersonID  *uuid.UUID  `json:"informant_person_id"`
+	RegistrationOffice string      `json:"registration_office"`
+	Registrar          string      `json:"registrar"`
+	OfficeID           *uuid.UUID  `json:"office_id"`
+	RegistrarID        *uuid.UUID  `json:"registrar_id"`
+	RegistryNumber     *string     `json:"registry_number" example:"VIL-2026-000123"`
+	Origin             string      `json:"origin" enums:"domestic,foreign"`
+	ForeignDocumentID  *uuid.UUID  `json:"foreign_document_id"`
+	RegisteredAt       time.Time   `json:"registered_at"`
+}
+
+func NewDeathRecordResponse(row repository.DeathRecord) DeathRecordResponse {
+	return DeathRecordResponse{
+		ID:                 row.ID,
+		PersonID:           row.PersonID,
+		DateOfDeath:        render.Date(row.DateOfDeath.Time),
+		PlaceOfDeath:       row.PlaceOfDeath,
+		CauseCode:          row.CauseCode,
+		InformantName:      row.InformantName,
+		InformantPersonID:  render.Nullable(uuid.UUID(row.InformantPersonID.Bytes), row.InformantPersonID.Valid),
+		RegistrationOffice: row.RegistrationOffice,
+		Registrar:          row.Registrar,
+		OfficeID:           render.Nullable(uuid.UUID(row.OfficeID.Bytes), row.OfficeID.Valid),
+		RegistrarID:        render.Nullable(uuid.UUID(row.RegistrarID.Bytes), row.RegistrarID.Valid),
+		RegistryNumber:     render.Nullable(row.RegistryNumber.String, row.RegistryNumber.Valid),
+		Origin:             foreign.Origin(row.ForeignDocumentID),
+		ForeignDocumentID:  render.Nullable(uuid.UUID(row.ForeignDocumentID.Bytes), row.ForeignDocumentID.Valid),
+		RegisteredAt:       row.RegisteredAt,
+	}
+}
+
+func NewDeathRecordResponses(rows []repository.DeathRecord) []DeathRecordResponse {
+	items := make([]DeathRecordResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, NewDeathRecordResponse(row))
+	}
+	return items
+}
+
+// DeathRegistrationResponse is a death record with the deceased. Marriage
+// and spouse are only present when registering a death ended a marriage,
+// residence when it ended a residence declaration. ForeignDocument is the
+// source document of a death abroad.
+type DeathRegistrationResponse struct {
+	Record          DeathRecordResponse                  `json:"record"`
+	Deceased        person.PersonResponse                `json:"deceased"`
+	Marriage        *marriage.MarriageResponse           `json:"marriage,omitempty"`
+	Spouse          *person.PersonResponse               `json:"spouse,omitempty"`
+	Residence       *residence.DeclarationRecordResponse `json:"residence,omitempty"`
+	ForeignDocument *foreign.DocumentResponse            `json:"foreign_document"`
+}
+
+func NewDeathRegistrationResponse(reg DeathRegistration) DeathRegistrationResponse {
+	resp := DeathRegistrationResponse{
+		Record:          NewDeathRecordResponse(reg.Record),
+		Deceased:        person.NewPersonResponse(reg.Deceased),
+		ForeignDocument: foreign.NewDocumentResponse(reg.Document),
+	}
+	if reg.Marriage != nil {
+		m := marriage.NewMarriageResponse(*reg.Marriage)
+		resp.Marriage = &m
+	}
+	if reg.Spouse != nil {
+		spouse := person.NewPersonResponse(*reg.Spouse)
+		resp.Spouse = &spouse
+	}
+	if reg.Residence != nil {
+		ended := residence.NewDeclarationRecordResponse(*reg.Residence)
+		resp.Residence = &ended
+	}
+	return resp
+}
+
+// DeathRegistrationEnvelope is the response body for a single death.
+type DeathRegistrationEnvelope struct {
+	Message string                    `json:"message,omitempty"`
+	Data    DeathRegistrationResponse `json:"data"`
+}
+
+// DeathRecordListEnvelope is the response body for a page of death records.
+type DeathRecordListEnvelope struct {
+	Count  int                   `json:"count"`
+	Limit  int32                 `json:"limit"`
+	Offset int32                 `json:"offset"`
+	Data   []DeathRecordResponse `json:"data"`
+}
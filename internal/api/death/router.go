@@ -0,0 +1,25 @@
+package death
+
+import (
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func DeathRouter(db txn.Beginner, queries *repository.Queries, log *zap.SugaredLogger) chi.Router {
+	r := chi.NewRouter()
+
+	service := NewService(db, queries, log)
+	handlers := NewHandlers(service, log)
+
+	r.Post("/", telemetry.InstrumentHandler("death", "RegisterDeath", handlers.RegisterDeath))
+	r.Post("/foreign", telemetry.InstrumentHandler("death", "RegisterForeignDeath", handlers.RegisterForeignDeath))
+	r.Get("/", telemetry.InstrumentHandler("death", "ListDeathRecords", handlers.ListDeathRecords))
+	r.Get("/by-person/{personID}", telemetry.InstrumentHandler("death", "GetDeathRegistrationByPerson", handlers.GetDeathRegistrationByPerson))
+	r.Get("/by-number/{number}", telemetry.InstrumentHandler("death", "GetDeathRegistrationByNumber", handlers.GetDeathRegistrationByNumber))
+	r.Get("/{id}", telemetry.InstrumentHandler("death", "GetDeathRegistration", handlers.GetDeathRegistration))
+
+	return r
+}
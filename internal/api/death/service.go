@@ -0,0 +1,412 @@
+package death
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/foreign"
+	"github.com/eif-courses/civilregistry/internal/api/marriage"
+	"github.com/eif-courses/civilregistry/internal/api/office"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+// causeCodePattern matches an ICD-10 code such as I21 or I21.9.
+var causeCodePattern = regexp.MustCompile(`^[A-Z][0-9]{2}(\.[0-9]{1,2})?$`)
+
+type Service struct {
+	db     txn.Beginner
+	repo   *repository.Queries
+	logger *zap.SugaredLogger
+}
+
+func NewService(db txn.Beginner, repo *repository.Queries, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		db:     db,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// RegisterDeathParams describes a death to register. The informant is named
+// in free text and optionally linked to their own person record. Document
+// is set for a death abroad transcribed from a foreign certificate.
+type RegisterDeathParams struct {
+	PersonID          uuid.UUID
+	DateOfDeath       pgtype.Date
+	PlaceOfDeath      string
+	CauseCode         string
+	InformantName     string
+	InformantPersonID pgtype.UUID
+	Document          *repository.CreateForeignDocumentParams
+}
+
+// DeathRegistration is a death record with the deceased and, when they were
+// married, the marriage that the death ended. Residence is the declared
+// residence the death ended, if any. Document is the source document of a
+// death abroad.
+type DeathRegistration struct {
+	Record    repository.DeathRecord
+	Deceased  repository.Person
+	Marriage  *repository.Marriage
+	Spouse    *repository.Person
+	Residence *repository.ResidenceDeclaration
+	Document  *repository.ForeignDocument
+}
+
+// RegisterDeath records the death, marks the person deceased, closes an
+// active marriage as widowed for the surviving spouse and ends the declared
+// residence, in one transaction. A death abroad may be transcribed by any
+// office that registers deaths.
+func (s *Service) RegisterDeath(ctx context.Context, arg RegisterDeathParams) (_ *DeathRegistration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "death", "RegisterDeath")
+	defer func() { op.End(err) }()
+
+	arg.PlaceOfDeath = strings.TrimSpace(arg.PlaceOfDeath)
+	arg.CauseCode = strings.ToUpper(strings.TrimSpace(arg.CauseCode))
+	arg.InformantName = strings.TrimSpace(arg.InformantName)
+	if err := validate(arg); err != nil {
+		return nil, err
+	}
+	place := pgtype.Text{String: arg.PlaceOfDeath, Valid: true}
+	if arg.Document != nil {
+		doc, err := foreign.PrepareDocument(*arg.Document, arg.DateOfDeath)
+		if err != nil {
+			return nil, err
+		}
+		arg.Document = &doc
+		place = pgtype.Text{}
+	}
+	if _, err := auth.RequireRegistrar(ctx); err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Registering death of %s", arg.PersonID)
+
+	var reg DeathRegistration
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		registrar, err := office.Authorize(ctx, q, office.EventDeath, place)
+		if err != nil {
+			return err
+		}
+		officeID, registrarID := office.RecordedBy(registrar)
+
+		// Lock in the same order as the marriage workflows (marriage row,
+		// then persons by ID) so a concurrent divorce cannot deadlock us
+		var active *repository.Marriage
+		found, err := q.GetActiveMarriageForPerson(ctx, arg.PersonID)
+		switch {
+		case err == nil:
+			if found, err = q.GetMarriageForUpdate(ctx, found.ID); err != nil {
+				return fmt.Errorf("failed GetMarriageForUpdate: %w", err)
+			}
+			active = &found
+		case !errors.Is(err, pgx.ErrNoRows):
+			return fmt.Errorf("failed GetActiveMarriageForPerson: %w", err)
+		}
+
+		ids := []uuid.UUID{arg.PersonID}
+		if active != nil {
+			ids = append(ids, spouseOf(*active, arg.PersonID))
+		}
+		locked, err := person.LockInOrder(ctx, q, ids...)
+		if err != nil {
+			return err
+		}
+
+		deceased := locked[arg.PersonID]
+		if deceased.MergedInto.Valid {
+			return person.EnsureAlive(deceased)
+		}
+		if deceased.Status == person.StatusDeceased {
+			return apperr.Conflict("death of %s %s is already registered", deceased.FirstName, deceased.LastName)
+		}
+		if arg.DateOfDeath.Time.Before(deceased.BirthDate.Time) {
+			return apperr.Invalid("date_of_death must not be before the birth date")
+		}
+
+		// A marriage registered between our first read and the lock would
+		// otherwise be left active
+		if err := checkMarriageUnchanged(ctx, q, arg.PersonID, active); err != nil {
+			return err
+		}
+
+		if arg.InformantPersonID.Valid {
+			if _, err := q.GetPersonByID(ctx, arg.InformantPersonID.Bytes); errors.Is(err, pgx.ErrNoRows) {
+				return apperr.Invalid("informant %s not found", uuid.UUID(arg.InformantPersonID.Bytes))
+			} else if err != nil {
+				return fmt.Errorf("failed GetPersonByID: %w", err)
+			}
+		}
+
+		if active != nil && arg.DateOfDeath.Time.Before(active.RegisteredOn.Time) {
+			return apperr.Invalid("date_of_death must not be before the active marriage was registered")
+		}
+
+		if arg.Document != nil {
+			if reg.Document, err = foreign.CreateDocument(ctx, q, *arg.Document); err != nil {
+				return err
+			}
+		}
+
+		number, err := office.NextRegistryNumber(ctx, q, registrar, office.EventDeath, time.Now().Year())
+		if err != nil {
+			return err
+		}
+
+		reg.Record, err = q.CreateDeathRecord(ctx, repository.CreateDeathRecordParams{
+			PersonID:           arg.PersonID,
+			DateOfDeath:        arg.DateOfDeath,
+			PlaceOfDeath:       arg.PlaceOfDeath,
+			CauseCode:          arg.CauseCode,
+			InformantName:      arg.InformantName,
+			InformantPersonID:  arg.InformantPersonID,
+			RegistrationOffice: registrar.Office.Name,
+			Registrar:          registrar.Registrar.FullName,
+			OfficeID:           officeID,
+			RegistrarID:        registrarID,
+			RegistryNumber:     pgtype.Text{String: number, Valid: true},
+			ForeignDocumentID:  foreign.Ref(reg.Document),
+		})
+		if err != nil {
+			return fmt.Errorf("failed CreateDeathRecord: %w", err)
+		}
+
+		// Both the deceased's and the spouse's changes apply from the date of death
+		amendment := person.Amendment{
+			LegalBasis:    "death record " + reg.Record.ID.String(),
+			EffectiveDate: arg.DateOfDeath,
+		}
+
+		if active != nil {
+			ended, err := q.EndMarriage(ctx, repository.EndMarriageParams{
+				ID:      active.ID,
+				Status:  marriage.StatusWidowed,
+				EndedOn: arg.DateOfDeath,
+			})
+			if err != nil {
+				return fmt.Errorf("failed EndMarriage: %w", err)
+			}
+			reg.Marriage = &ended
+
+			spouse := locked[spouseOf(*active, arg.PersonID)]
+			widowed, err := q.SetPersonMaritalStatus(ctx, repository.SetPersonMaritalStatusParams{
+				ID:            spouse.ID,
+				MaritalStatus: person.MaritalWidowed,
+				LastName:      spouse.LastName,
+			})
+			if err != nil {
+				return fmt.Errorf("failed SetPersonMaritalStatus: %w", err)
+			}
+			if err := person.RecordAmendment(ctx, q, spouse, widowed, amendment); err != nil {
+				return err
+			}
+			reg.Spouse = &widowed
+		}
+
+		if reg.Residence, err = endResidence(ctx, q, arg.PersonID, arg.DateOfDeath); err != nil {
+			return err
+		}
+
+		if reg.Deceased, err = q.SetPersonDeceased(ctx, arg.PersonID); err != nil {
+			return fmt.Errorf("failed SetPersonDeceased: %w", err)
+		}
+		return person.RecordAmendment(ctx, q, deceased, reg.Deceased, amendment)
+	})
+	if err != nil {
+		s.logger.Errorf("Failed RegisterDeath: %v", err)
+		return nil, err
+	}
+
+	s.logger.Infof("RegisterDeath completed successfully with ID: %s", reg.Record.ID)
+	return &reg, nil
+}
+
+func (s *Service) GetDeathRegistration(ctx context.Context, id uuid.UUID) (_ *DeathRegistration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "death", "GetDeathRegistration")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	record, err := s.repo.GetDeathRecordByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetDeathRecordByID: %w", err)
+	}
+	return s.expand(ctx, record)
+}
+
+func (s *Service) GetDeathRegistrationByPersonID(ctx context.Context, personID uuid.UUID) (_ *DeathRegistration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "death", "GetDeathRegistrationByPersonID")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	record, err := s.repo.GetDeathRecordByPersonID(ctx, personID)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetDeathRecordByPersonID: %w", err)
+	}
+	return s.expand(ctx, record)
+}
+
+// GetDeathRegistrationByRegistryNumber finds a death record by its registry
+// number, e.g. VIL-2026-000123, in any letter case.
+func (s *Service) GetDeathRegistrationByRegistryNumber(ctx context.Context, number string) (_ *DeathRegistration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "death", "GetDeathRegistrationByRegistryNumber")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	if number, err = office.NormalizeRegistryNumber(number); err != nil {
+		return nil, err
+	}
+	record, err := s.repo.GetDeathRecordByRegistryNumber(ctx, pgtype.Text{String: number, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed GetDeathRecordByRegistryNumber: %w", err)
+	}
+	return s.expand(ctx, record)
+}
+
+// ListDeathRecords returns the most recently registered deaths first,
+// optionally only those in Lithuania or abroad. A zero limit selects the
+// default page size.
+func (s *Service) ListDeathRecords(ctx context.Context, f foreign.Filter, limit, offset int32) (_ []repository.DeathRecord, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "death", "ListDeathRecords")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	if limit < 0 || limit > maxListLimit {
+		return nil, apperr.Invalid("limit must be between 1 and %d", maxListLimit)
+	}
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	if offset < 0 {
+		return nil, apperr.Invalid("offset must not be negative")
+	}
+
+	if f, err = foreign.NormalizeFilter(f); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.ListDeathRecords(ctx, repository.ListDeathRecordsParams{
+		Origin:  f.Origin,
+		Country: f.Country,
+		Limit:   limit,
+		Offset:  offset,
+	})
+	if err != nil {
+		s.logger.Errorf("Failed ListDeathRecords: %v", err)
+		return nil, fmt.Errorf("failed ListDeathRecords: %w", err)
+	}
+	return result, nil
+}
+
+// expand loads the deceased for a stored record. The ended marriage is not
+// looked up again; it is only reported when the death is registered.
+func (s *Service) expand(ctx context.Context, record repository.DeathRecord) (*DeathRegistration, error) {
+	deceased, err := s.repo.GetPersonByID(ctx, record.PersonID)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetPersonByID: %w", err)
+	}
+	doc, err := foreign.LoadDocument(ctx, s.repo, record.ForeignDocumentID)
+	if err != nil {
+		return nil, err
+	}
+	return &DeathRegistration{Record: record, Deceased: deceased, Document: doc}, nil
+}
+
+func checkMarriageUnchanged(ctx context.Context, q *repository.Queries, personID uuid.UUID, active *repository.Marriage) error {
+	current, err := q.GetActiveMarriageForPerson(ctx, personID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		if active == nil {
+			return nil
+		}
+		return apperr.Conflict("the person's marriage changed during registration; please retry")
+	}
+	if err != nil {
+		return fmt.Errorf("failed GetActiveMarriageForPerson: %w", err)
+	}
+	if active == nil || current.ID != active.ID {
+		return apperr.Conflict("the person's marriage changed during registration; please retry")
+	}
+	return nil
+}
+
+// endResidence ends the person's current residence declaration, if any.
+// The deceased was resident on the day of death, so it ends the day after.
+func endResidence(ctx context.Context, q *repository.Queries, personID uuid.UUID, dateOfDeath pgtype.Date) (*repository.ResidenceDeclaration, error) {
+	current, err := q.GetCurrentResidenceDeclaration(ctx, personID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed GetCurrentResidenceDeclaration: %w", err)
+	}
+	if dateOfDeath.Time.Before(current.StartDate.Time) {
+		return nil, apperr.Invalid("date_of_death must not be before the declared residence started")
+	}
+
+	ended, err := q.EndResidenceDeclaration(ctx, repository.EndResidenceDeclarationParams{
+		ID:      current.ID,
+		EndDate: pgtype.Date{Time: dateOfDeath.Time.AddDate(0, 0, 1), Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed EndResidenceDeclaration: %w", err)
+	}
+	return &ended, nil
+}
+
+func spouseOf(m repository.Marriage, personID uuid.UUID) uuid.UUID {
+	if m.Spouse1ID == personID {
+		return m.Spouse2ID
+	}
+	return m.Spouse1ID
+}
+
+func validate(arg RegisterDeathParams) error {
+	if !arg.DateOfDeath.Valid {
+		return apperr.Invalid("date_of_death is required")
+	}
+	if arg.DateOfDeath.Time.After(time.Now()) {
+		return apperr.Invalid("date_of_death must not be in the future")
+	}
+	if arg.PlaceOfDeath == "" {
+		return apperr.Invalid("place_of_death is required")
+	}
+	if !causeCodePattern.MatchString(arg.CauseCode) {
+		return apperr.Invalid("cause_code must be an ICD-10 code such as I21.9")
+	}
+	if arg.InformantName == "" {
+		return apperr.Invalid("informant_name is required")
+	}
+	if arg.InformantPersonID.Valid && arg.InformantPersonID.Bytes == arg.PersonID {
+		return apperr.Invalid("the deceased cannot be the informant")
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+// Package foreign holds the source documents of births, marriages and
+// deaths abroad that are transcribed into the register.
+package foreign
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Origins of a record: an event in Lithuania or one abroad transcribed
+// from a foreign document.
+const (
+	OriginDomestic = "domestic"
+	OriginForeign  = "foreign"
+)
+
+// How a foreign document is certified for use in Lithuania.
+const (
+	LegalisationApostille = "apostille"
+	LegalisationConsular  = "legalisation"
+	LegalisationExempt    = "exempt"
+)
+
+// HomeCountry is the country whose register this is; its events are not
+// foreign.
+const HomeCountry = "LT"
+
+// homeLanguage needs no translation.
+const homeLanguage = "lt"
+
+var (
+	countryPattern  = regexp.MustCompile(`^[A-Z]{2}$`)
+	languagePattern = regexp.MustCompile(`^[a-z]{2}$`)
+)
+
+// Filter narrows record listings by origin. Country implies foreign.
+type Filter struct {
+	Origin  pgtype.Text
+	Country pgtype.Text
+}
+
+// Origin names where the event of a record with the given source
+// document took place.
+func Origin(documentID pgtype.UUID) string {
+	if documentID.Valid {
+		return OriginForeign
+	}
+	return OriginDomestic
+}
+
+// NormalizeFilter checks a listing filter and upper-cases its country.
+func NormalizeFilter(f Filter) (Filter, error) {
+	if f.Origin.Valid && f.Origin.String != OriginDomestic && f.Origin.String != OriginForeign {
+		return f, apperr.Invalid("origin must be domestic or foreign")
+	}
+	if f.Country.Valid {
+		f.Country.String = strings.ToUpper(strings.TrimSpace(f.Country.String))
+		if !countryPattern.MatchString(f.Country.String) {
+			return f, apperr.Invalid("country must be an ISO 3166-1 alpha-2 code")
+		}
+		if f.Origin.Valid && f.Origin.String == OriginDomestic {
+			return f, apperr.Invalid("country only applies to foreign records")
+		}
+	}
+	return f, nil
+}
+
+// PrepareDocument trims and validates the source document of an event that
+// took place on eventDate. The document must be issued after the event,
+// legalised unless exempt, and translated unless it is in Lithuanian.
+func PrepareDocument(arg repository.CreateForeignDocumentParams, eventDate pgtype.Date) (repository.CreateForeignDocumentParams, error) {
+	arg.Country = strings.ToUpper(strings.TrimSpace(arg.Country))
+	arg.IssuingAuthority = strings.TrimSpace(arg.IssuingAuthority)
+	arg.DocumentNumber = strings.TrimSpace(arg.DocumentNumber)
+	arg.DocumentLanguage = strings.ToLower(strings.TrimSpace(arg.DocumentLanguage))
+	arg.Legalisation = strings.TrimSpace(arg.Legalisation)
+	arg.LegalisationAuthority = trim(arg.LegalisationAuthority)
+	arg.LegalisationNumber = trim(arg.LegalisationNumber)
+	arg.Translator = trim(arg.Translator)
+
+	switch {
+	case !countryPattern.MatchString(arg.Country):
+		return arg, apperr.Invalid("country must be an ISO 3166-1 alpha-2 code")
+	case arg.Country == HomeCountry:
+		return arg, apperr.Invalid("events in %s are registered, not transcribed", HomeCountry)
+	case arg.IssuingAuthority == "":
+		return arg, apperr.Invalid("issuing_authority is required")
+	case arg.DocumentNumber == "":
+		return arg, apperr.Invalid("document_number is required")
+	case !languagePattern.MatchString(arg.DocumentLanguage):
+		return arg, apperr.Invalid("document_language must be an ISO 639-1 code")
+	}
+	if err := checkDate(arg.IssuedOn, "issued_on"); err != nil {
+		return arg, err
+	}
+	if eventDate.Valid && arg.IssuedOn.Time.Before(eventDate.Time) {
+		return arg, apperr.Invalid("issued_on must not be before the event")
+	}
+
+	switch arg.Legalisation {
+	case LegalisationApostille, LegalisationConsular:
+		if !arg.LegalisationAuthority.Valid || !arg.LegalisationNumber.Valid {
+			return arg, apperr.Invalid("legalisation_authority and legalisation_number are required for %s", arg.Legalisation)
+		}
+		if err := checkDate(arg.LegalisedOn, "legalised_on"); err != nil {
+			return arg, err
+		}
+		if arg.LegalisedOn.Time.Before(arg.IssuedOn.Time) {
+			return arg, apperr.Invalid("legalised_on must not be before issued_on")
+		}
+	case LegalisationExempt:
+		if arg.LegalisationAuthority.Valid || arg.LegalisationNumber.Valid || arg.LegalisedOn.Valid {
+			return arg, apperr.Invalid("an exempt document has no legalisation details")
+		}
+	default:
+		return arg, apperr.Invalid("legalisation must be apostille, legalisation or exempt")
+	}
+
+	if arg.DocumentLanguage == homeLanguage {
+		if arg.Translator.Valid || arg.TranslatedOn.Valid {
+			return arg, apperr.Invalid("a document in Lithuanian needs no translation")
+		}
+		return arg, nil
+	}
+	if !arg.Translator.Valid {
+		return arg, apperr.Invalid("translator is required for a document in %q", arg.DocumentLanguage)
+	}
+	if err := checkDate(arg.TranslatedOn, "translated_on"); err != nil {
+		return arg, err
+	}
+	if arg.TranslatedOn.Time.Before(arg.IssuedOn.Time) {
+		return arg, apperr.Invalid("translated_on must not be before issued_on")
+	}
+	return arg, nil
+}
+
+// CreateDocument stores a document checked by PrepareDocument.
+func CreateDocument(ctx context.Context, q *repository.Queries, arg repository.CreateForeignDocumentParams) (*repository.ForeignDocument, error) {
+	doc, err := q.CreateForeignDocument(ctx, arg)
+	if err != nil {
+		return nil, fmt.Errorf("failed CreateForeignDocument: %w", err)
+	}
+	return &doc, nil
+}
+
+// LoadDocument returns the source document a record points at, or nil for
+// a domestic record.
+func LoadDocument(ctx context.Context, q *repository.Queries, id pgtype.UUID) (*repository.ForeignDocument, error) {
+	if !id.Valid {
+		return nil, nil
+	}
+	doc, err := q.GetForeignDocumentByID(ctx, id.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetForeignDocumentByID: %w", err)
+	}
+	return &doc, nil
+}
+
+// Ref converts a stored document into the reference kept on its record.
+func Ref(doc *repository.ForeignDocument) pgtype.UUID {
+	if doc == nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: doc.ID, Valid: true}
+}
+
+func checkDate(d pgtype.Date, field string) error {
+	if !d.Valid {
+		return apperr.Invalid("%s is required", field)
+	}
+	if d.Time.After(time.Now()) {
+		return apperr.Invalid("%s must not be in the future", field)
+	}
+	return nil
+}
+
+func trim(s pgtype.Text) pgtype.Text {
+	s.String = strings.TrimSpace(s.String)
+	s.Valid = s.Valid && s.String != ""
+	return s
+}
@@ -0,0 +1,94 @@
+package foreign
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/request"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+)
+
+// DocumentRequest describes the foreign source document of a transcribed
+// record. Legalisation details are left out for exempt documents and the
+// translation for documents in Lithuanian.
+type DocumentRequest struct {
+	Country               string      `json:"country" example:"DE"`
+	IssuingAuthority      string      `json:"issuing_authority" example:"Standesamt Berlin-Mitte"`
+	DocumentNumber        string      `json:"document_number" example:"G 1234/2025"`
+	IssuedOn              render.Date `json:"issued_on" swaggertype:"string" format:"date" example:"2025-03-14"`
+	DocumentLanguage      string      `json:"document_language" example:"de"`
+	Legalisation          string      `json:"legalisation" enums:"apostille,legalisation,exempt" example:"apostille"`
+	LegalisationAuthority *string     `json:"legalisation_authority,omitempty" example:"Landgericht Berlin"`
+	LegalisationNumber    *string     `json:"legalisation_number,omitempty" example:"AP-2025-0042"`
+	LegalisedOn           render.Date `json:"legalised_on,omitempty" swaggertype:"string" format:"date" example:"2025-03-20"`
+	Translator            *string     `json:"translator,omitempty" example:"Ona Vertėja"`
+	TranslatedOn          render.Date `json:"translated_on,omitempty" swaggertype:"string" format:"date" example:"2025-04-02"`
+}
+
+// Params converts the request into repository parameters.
+func (req DocumentRequest) Params() repository.CreateForeignDocumentParams {
+	return repository.CreateForeignDocumentParams{
+		Country:               req.Country,
+		IssuingAuthority:      req.IssuingAuthority,
+		DocumentNumber:        req.DocumentNumber,
+		IssuedOn:              request.Date(req.IssuedOn),
+		DocumentLanguage:      req.DocumentLanguage,
+		Legalisation:          req.Legalisation,
+		LegalisationAuthority: request.Text(req.LegalisationAuthority),
+		LegalisationNumber:    request.Text(req.LegalisationNumber),
+		LegalisedOn:           request.Date(req.LegalisedOn),
+		Translator:            request.Text(req.Translator),
+		TranslatedOn:          request.Date(req.TranslatedOn),
+	}
+}
+
+// DocumentResponse is the wire form of repository.ForeignDocument.
+type DocumentResponse struct {
+	ID                    uuid.UUID    `json:"id"`
+	Country               string       `json:"country"`
+	IssuingAuthority      string       `json:"issuing_authority"`
+	DocumentNumber        string       `json:"document_number"`
+	IssuedOn              render.Date  `json:"issued_on"`
+	DocumentLanguage      string       `json:"document_language"`
+	Legalisation          string       `json:"legalisation"`
+	LegalisationAuthority *string      `json:"legalisation_authority"`
+	LegalisationNumber    *string      `json:"legalisation_number"`
+	LegalisedOn           *render.Date `json:"legalised_on"`
+	Translator            *string      `json:"translator"`
+	TranslatedOn          *render.Date `json:"translated_on"`
+	CreatedAt             time.Time    `json:"created_at"`
+}
+
+// NewDocumentResponse converts a document; nil stays nil for domestic
+// records.
+func NewDocumentResponse(doc *repository.ForeignDocument) *DocumentResponse {
+	if doc == nil {
+		return nil
+	}
+	return &DocumentResponse{
+		ID:                    doc.ID,
+		Country:               doc.Country,
+		IssuingAuthority:      doc.IssuingAuthority,
+		DocumentNumber:        doc.DocumentNumber,
+		IssuedOn:              render.Date(doc.IssuedOn.Time),
+		DocumentLanguage:      doc.DocumentLanguage,
+		Legalisation:          doc.Legalisation,
+		LegalisationAuthority: render.Nullable(doc.LegalisationAuthority.String, doc.LegalisationAuthority.Valid),
+		LegalisationNumber:    render.Nullable(doc.LegalisationNumber.String, doc.LegalisationNumber.Valid),
+		LegalisedOn:           render.NullableDate(doc.LegalisedOn.Time, doc.LegalisedOn.Valid),
+		Translator:            render.Nullable(doc.Translator.String, doc.Translator.Valid),
+		TranslatedOn:          render.NullableDate(doc.TranslatedOn.Time, doc.TranslatedOn.Valid),
+		CreatedAt:             doc.CreatedAt,
+	}
+}
+
+// ParseFilter reads the origin and country query parameters of a record
+// listing.
+func ParseFilter(r *http.Request) Filter {
+	return Filter{
+		Origin:  request.QueryText(r, "origin"),
+		Country: request.QueryText(r, "country"),
+	}
+}
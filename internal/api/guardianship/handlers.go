@@ -0,0 +1,198 @@
+package guardianship
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/eif-courses/civilregistry/internal/api/request"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	service *Service
+	logger  *zap.SugaredLogger
+}
+
+func NewHandlers(service *Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type RegisterGuardianshipRequest struct {
+	WardID         uuid.UUID   `json:"ward_id"`
+	GuardianID     uuid.UUID   `json:"guardian_id"`
+	Kind           string      `json:"kind" enums:"guardianship,curatorship" example:"guardianship"`
+	Court          string      `json:"court" example:"Kauno apylinkės teismas"`
+	DecisionNumber string      `json:"decision_number" example:"e2-2345-678/2026"`
+	DecidedOn      render.Date `json:"decided_on" swaggertype:"string" format:"date" example:"2026-09-01"`
+	StartDate      render.Date `json:"start_date" swaggertype:"string" format:"date" example:"2026-09-15"`
+}
+
+// Params converts the request into service parameters.
+func (req RegisterGuardianshipRequest) Params() RegisterGuardianshipParams {
+	return RegisterGuardianshipParams{
+		WardID:         req.WardID,
+		GuardianID:     req.GuardianID,
+		Kind:           req.Kind,
+		Court:          req.Court,
+		DecisionNumber: req.DecisionNumber,
+		DecidedOn:      request.Date(req.DecidedOn),
+		StartDate:      request.Date(req.StartDate),
+	}
+}
+
+type EndGuardianshipRequest struct {
+	EndDate render.Date `json:"end_date" swaggertype:"string" format:"date" example:"2026-12-01"`
+}
+
+// RegisterGuardianship registers a guardianship
+// @Summary Register guardianship
+// @Description Record a court's appointment of a guardian over a minor, or a curator over an adult of limited capacity,
+// @Description from start_date on. The guardian must be a living adult; overlapping appointments of the same guardian
+// @Description over the same ward are refused. Guardianship does not change parentage.
+// @Tags guardianship
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RegisterGuardianshipRequest true "guardianship data"
+// @Success 201 {object} RegistrationEnvelope "Registered guardianship"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "Person not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Overlapping guardianship"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/guardianship/ [post]
+func (h *Handlers) RegisterGuardianship(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var req RegisterGuardianshipRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.RegisterGuardianship(r.Context(), req.Params())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.writeRegistration(w, http.StatusCreated, "guardianship registered successfully", *result)
+}
+
+// EndGuardianship ends a guardianship
+// @Summary End guardianship
+// @Description End an open guardianship or curatorship; it no longer covers end_date
+// @Tags guardianship
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "guardianship ID"
+// @Param request body EndGuardianshipRequest true "end date"
+// @Success 200 {object} RegistrationEnvelope "Ended guardianship"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "Guardianship not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Guardianship already ended"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/guardianship/{id}/end [post]
+func (h *Handlers) EndGuardianship(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	var req EndGuardianshipRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.EndGuardianship(r.Context(), id, request.Date(req.EndDate))
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.writeRegistration(w, http.StatusOK, "guardianship ended successfully", *result)
+}
+
+// ListGuardianshipsForPerson lists the guardianships of a person
+// @Summary List guardianships by person
+// @Description List the guardianships over a person and those in which they are the guardian, latest first
+// @Tags guardianship
+// @Produce json
+// @Security BearerAuth
+// @Param personID path string true "person ID"
+// @Success 200 {object} GuardianshipListEnvelope "Guardianships"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/guardianship/by-person/{personID} [get]
+func (h *Handlers) ListGuardianshipsForPerson(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	personID, err := request.UUIDParam(r, "personID")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.ListGuardianshipsForPerson(r.Context(), personID)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	err = render.Write(w, http.StatusOK, render.ContentTypeJSON, GuardianshipListEnvelope{
+		Count: len(result),
+		Data:  NewGuardianshipResponses(result),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+func (h *Handlers) writeRegistration(w http.ResponseWriter, status int, message string, reg Registration) {
+	err := render.Write(w, status, render.ContentTypeJSON, RegistrationEnvelope{
+		Message: message,
+		Data:    NewRegistrationResponse(reg),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// serviceError maps a service failure onto an HTTP status.
+func (h *Handlers) serviceError(w http.ResponseWriter, err error) {
+	status, msg := apperr.Status(err)
+	if status >= http.StatusInternalServerError {
+		h.logger.Errorf("Service error: %v", err)
+	}
+	if status == http.StatusNotFound && msg == "not found" {
+		msg = "guardianship not found"
+	}
+	http.Error(w, msg, status)
+}
@@ -0,0 +1,83 @@
+package guardianship
+
+import (
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+)
+
+// GuardianshipResponse is the wire form of repository.Guardianship.
+type GuardianshipResponse struct {
+	ID                 uuid.UUID    `json:"id"`
+	WardID             uuid.UUID    `json:"ward_id"`
+	GuardianID         uuid.UUID    `json:"guardian_id"`
+	Kind               string       `json:"kind" enums:"guardianship,curatorship"`
+	Court              string       `json:"court"`
+	DecisionNumber     string       `json:"decision_number"`
+	DecidedOn          render.Date  `json:"decided_on"`
+	StartDate          render.Date  `json:"start_date"`
+	EndDate            *render.Date `json:"end_date"`
+	RegistrationOffice string       `json:"registration_office"`
+	Registrar          string       `json:"registrar"`
+	OfficeID           *uuid.UUID   `json:"office_id"`
+	RegistrarID        *uuid.UUID   `json:"registrar_id"`
+	CreatedAt          time.Time    `json:"created_at"`
+}
+
+func NewGuardianshipResponse(row repository.Guardianship) GuardianshipResponse {
+	return GuardianshipResponse{
+		ID:                 row.ID,
+		WardID:             row.WardID,
+		GuardianID:         row.GuardianID,
+		Kind:               row.Kind,
+		Court:              row.Court,
+		DecisionNumber:     row.DecisionNumber,
+		DecidedOn:          render.Date(row.DecidedOn.Time),
+		StartDate:          render.Date(row.StartDate.Time),
+		EndDate:            render.NullableDate(row.EndDate.Time, row.EndDate.Valid),
+		RegistrationOffice: row.RegistrationOffice,
+		Registrar:          row.Registrar,
+		OfficeID:           render.Nullable(uuid.UUID(row.OfficeID.Bytes), row.OfficeID.Valid),
+		RegistrarID:        render.Nullable(uuid.UUID(row.RegistrarID.Bytes), row.RegistrarID.Valid),
+		CreatedAt:          row.CreatedAt,
+	}
+}
+
+func NewGuardianshipResponses(rows []repository.Guardianship) []GuardianshipResponse {
+	items := make([]GuardianshipResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, NewGuardianshipResponse(row))
+	}
+	return items
+}
+
+// RegistrationResponse is a guardianship with the ward and the guardian.
+type RegistrationResponse struct {
+	Guardianship GuardianshipResponse  `json:"guardianship"`
+	Ward         person.PersonResponse `json:"ward"`
+	Guardian     person.PersonResponse `json:"guardian"`
+}
+
+func NewRegistrationResponse(reg Registration) RegistrationResponse {
+	return RegistrationResponse{
+		Guardianship: NewGuardianshipResponse(reg.Guardianship),
+		Ward:         person.NewPersonResponse(reg.Ward),
+		Guardian:     person.NewPersonResponse(reg.Guardian),
+	}
+}
+
+// RegistrationEnvelope is the response body for a single guardianship.
+type RegistrationEnvelope struct {
+	Message string               `json:"message,omitempty"`
+	Data    RegistrationResponse `json:"data"`
+}
+
+// GuardianshipListEnvelope is the response body for a list of
+// guardianships.
+type GuardianshipListEnvelope struct {
+	Count int                    `json:"count"`
+	Data  []GuardianshipResponse `json:"data"`
+}
@@ -0,0 +1,22 @@
+package guardianship
+
+import (
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func GuardianshipRouter(db txn.Beginner, queries *repository.Queries, log *zap.SugaredLogger) chi.Router {
+	r := chi.NewRouter()
+
+	service := NewService(db, queries, log)
+	handlers := NewHandlers(service, log)
+
+	r.Post("/", telemetry.InstrumentHandler("guardianship", "RegisterGuardianship", handlers.RegisterGuardianship))
+	r.Get("/by-person/{personID}", telemetry.InstrumentHandler("guardianship", "ListGuardianshipsForPerson", handlers.ListGuardianshipsForPerson))
+	r.Post("/{id}/end", telemetry.InstrumentHandler("guardianship", "EndGuardianship", handlers.EndGuardianship))
+
+	return r
+}
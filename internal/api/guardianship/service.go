@@ -0,0 +1,254 @@
+package guardianship
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/office"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const (
+	// KindGuardianship is over a minor, KindCuratorship over an adult of
+	// limited capacity.
+	KindGuardianship = "guardianship"
+	KindCuratorship  = "curatorship"
+
+	// AdultAge is the age of majority; guardians must have reached it.
+	AdultAge = 18
+)
+
+type Service struct {
+	db     txn.Beginner
+	repo   *repository.Queries
+	logger *zap.SugaredLogger
+}
+
+func NewService(db txn.Beginner, repo *repository.Queries, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		db:     db,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// RegisterGuardianshipParams describes a court's appointment of a guardian
+// or curator from StartDate on.
+type RegisterGuardianshipParams struct {
+	WardID         uuid.UUID
+	GuardianID     uuid.UUID
+	Kind           string
+	Court          string
+	DecisionNumber string
+	DecidedOn      pgtype.Date
+	StartDate      pgtype.Date
+}
+
+// Registration is a guardianship with the ward and the guardian.
+type Registration struct {
+	Guardianship repository.Guardianship
+	Ward         repository.Person
+	Guardian     repository.Person
+}
+
+// RegisterGuardianship records a guardian appointed over a minor, or a
+// curator over an adult, as of the start date. Both must be living and the
+// guardian an adult; the same guardian may not be appointed twice over the
+// same ward for overlapping periods. Guardianship leaves parentage alone.
+func (s *Service) RegisterGuardianship(ctx context.Context, arg RegisterGuardianshipParams) (_ *Registration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "guardianship", "RegisterGuardianship")
+	defer func() { op.End(err) }()
+
+	arg.Kind = strings.TrimSpace(arg.Kind)
+	arg.Court = strings.TrimSpace(arg.Court)
+	arg.DecisionNumber = strings.TrimSpace(arg.DecisionNumber)
+	if err := validate(arg); err != nil {
+		return nil, err
+	}
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	officeID, registrarID := office.RecordedBy(p)
+
+	s.logger.Infof("Registering %s of %s by %s", arg.Kind, arg.WardID, arg.GuardianID)
+
+	var reg Registration
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		locked, err := person.LockInOrder(ctx, q, arg.WardID, arg.GuardianID)
+		if err != nil {
+			return err
+		}
+		reg.Ward, reg.Guardian = locked[arg.WardID], locked[arg.GuardianID]
+		if err := person.EnsureAlive(reg.Ward); err != nil {
+			return err
+		}
+		if err := person.EnsureAlive(reg.Guardian); err != nil {
+			return err
+		}
+
+		wardAge := ageOn(reg.Ward.BirthDate.Time, arg.StartDate.Time)
+		switch {
+		case wardAge < 0:
+			return apperr.Invalid("start_date must not be before the ward was born")
+		case arg.Kind == KindGuardianship && wardAge >= AdultAge:
+			return apperr.Invalid("guardianship is for minors; appoint a curator for an adult")
+		case arg.Kind == KindCuratorship && wardAge < AdultAge:
+			return apperr.Invalid("curatorship is for adults; appoint a guardian for a minor")
+		}
+		if ageOn(reg.Guardian.BirthDate.Time, arg.StartDate.Time) < AdultAge {
+			return apperr.Invalid("guardian must be at least %d on start_date", AdultAge)
+		}
+
+		overlapping, err := q.CountOverlappingGuardianships(ctx, repository.CountOverlappingGuardianshipsParams{
+			WardID:     arg.WardID,
+			GuardianID: arg.GuardianID,
+			StartDate:  arg.StartDate,
+		})
+		if err != nil {
+			return fmt.Errorf("failed CountOverlappingGuardianships: %w", err)
+		}
+		if overlapping > 0 {
+			return apperr.Conflict("%s %s is already guardian of %s %s for that period", reg.Guardian.FirstName, reg.Guardian.LastName, reg.Ward.FirstName, reg.Ward.LastName)
+		}
+
+		reg.Guardianship, err = q.CreateGuardianship(ctx, repository.CreateGuardianshipParams{
+			WardID:             arg.WardID,
+			GuardianID:         arg.GuardianID,
+			Kind:               arg.Kind,
+			Court:              arg.Court,
+			DecisionNumber:     arg.DecisionNumber,
+			DecidedOn:          arg.DecidedOn,
+			StartDate:          arg.StartDate,
+			RegistrationOffice: p.Office.Name,
+			Registrar:          p.Registrar.FullName,
+			OfficeID:           officeID,
+			RegistrarID:        registrarID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed CreateGuardianship: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorf("Failed RegisterGuardianship: %v", err)
+		return nil, err
+	}
+
+	s.logger.Infof("RegisterGuardianship completed successfully with ID: %s", reg.Guardianship.ID)
+	return &reg, nil
+}
+
+// EndGuardianship ends an open guardianship; it no longer covers endDate.
+func (s *Service) EndGuardianship(ctx context.Context, id uuid.UUID, endDate pgtype.Date) (_ *Registration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "guardianship", "EndGuardianship")
+	defer func() { op.End(err) }()
+
+	if !endDate.Valid {
+		return nil, apperr.Invalid("end_date is required")
+	}
+	if _, err := auth.RequireRegistrar(ctx); err != nil {
+		return nil, err
+	}
+
+	var ended repository.Guardianship
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		current, err := q.GetGuardianshipForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed GetGuardianshipForUpdate: %w", err)
+		}
+		if current.EndDate.Valid {
+			return apperr.Conflict("guardianship already ended on %s", current.EndDate.Time.Format(time.DateOnly))
+		}
+		if !endDate.Time.After(current.StartDate.Time) {
+			return apperr.Invalid("end_date must be after start_date")
+		}
+		ended, err = q.EndGuardianship(ctx, repository.EndGuardianshipParams{ID: id, EndDate: endDate})
+		if err != nil {
+			return fmt.Errorf("failed EndGuardianship: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorf("Failed to end guardianship %s: %v", id, err)
+		return nil, err
+	}
+
+	reg := Registration{Guardianship: ended}
+	if reg.Ward, err = s.person(ctx, ended.WardID); err != nil {
+		return nil, err
+	}
+	if reg.Guardian, err = s.person(ctx, ended.GuardianID); err != nil {
+		return nil, err
+	}
+	return &reg, nil
+}
+
+// ListGuardianshipsForPerson returns the guardianships over the person and
+// those in which they are the guardian, latest first.
+func (s *Service) ListGuardianshipsForPerson(ctx context.Context, personID uuid.UUID) (_ []repository.Guardianship, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "guardianship", "ListGuardianshipsForPerson")
+	defer func() { op.End(err) }()
+
+	if auth.FromContext(ctx) == nil {
+		return nil, apperr.Unauthorized("sign in to see guardianships")
+	}
+	result, err := s.repo.ListGuardianshipsForPerson(ctx, personID)
+	if err != nil {
+		s.logger.Errorf("Failed ListGuardianshipsForPerson: %v", err)
+		return nil, fmt.Errorf("failed ListGuardianshipsForPerson: %w", err)
+	}
+	return result, nil
+}
+
+func (s *Service) person(ctx context.Context, id uuid.UUID) (repository.Person, error) {
+	p, err := s.repo.GetPersonByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.Person{}, apperr.NotFound("person %s not found", id)
+	}
+	if err != nil {
+		return repository.Person{}, fmt.Errorf("failed GetPersonByID: %w", err)
+	}
+	return p, nil
+}
+
+func validate(arg RegisterGuardianshipParams) error {
+	switch {
+	case arg.Kind != KindGuardianship && arg.Kind != KindCuratorship:
+		return apperr.Invalid("kind must be guardianship or curatorship")
+	case arg.WardID == arg.GuardianID:
+		return apperr.Invalid("a person cannot be their own guardian")
+	case arg.Court == "":
+		return apperr.Invalid("court is required")
+	case arg.DecisionNumber == "":
+		return apperr.Invalid("decision_number is required")
+	case !arg.DecidedOn.Valid:
+		return apperr.Invalid("decided_on is required")
+	case arg.DecidedOn.Time.After(time.Now()):
+		return apperr.Invalid("decided_on must not be in the future")
+	case !arg.StartDate.Valid:
+		return apperr.Invalid("start_date is required")
+	}
+	return nil
+}
+
+// ageOn returns the age in completed years of someone born on birth.
+func ageOn(birth, on time.Time) int {
+	age := on.Year() - birth.Year()
+	if on.Month() < birth.Month() || on.Month() == birth.Month() && on.Day() < birth.Day() {
+		age--
+	}
+	return age
+}
@@ -0,0 +1,123 @@
+package kinship
+
+import (
+	"net/http"
+
+	"github.com/eif-courses/civilregistry/internal/api/request"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	service *Service
+	logger  *zap.SugaredLogger
+}
+
+func NewHandlers(service *Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetRelationship computes the kinship between two persons
+// @Summary Relationship between persons
+// @Description Work out how relative_id is related to person_id from legal parent links, adoptions included, (up to 10 generations)
+// @Description and, failing that, marriages. degree is the civil-law degree of kinship, the number of births between them.
+// @Tags kinship
+// @Produce json
+// @Security BearerAuth
+// @Param person_id query string true "person ID"
+// @Param relative_id query string true "relative's person ID"
+// @Success 200 {object} RelationshipEnvelope "Relationship; kind is none when they are not related"
+// @Failure 400 {object} map[string]interface{} "Invalid IDs"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "Person not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/kinship/relationship [get]
+func (h *Handlers) GetRelationship(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	personID, err := request.QueryUUID(r, "person_id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+	relativeID, err := request.QueryUUID(r, "relative_id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.FindRelationship(r.Context(), personID, relativeID)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	err = render.Write(w, http.StatusOK, render.ContentTypeJSON, RelationshipEnvelope{Data: NewRelationshipResponse(*result)})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// GetFamilyTree returns a person's family tree
+// @Summary Family tree
+// @Description Get a person's ancestors and descendants up to the given number of generations, with their spouses,
+// @Description as a graph. Node generation is positive for ancestors and negative for descendants.
+// @Tags kinship
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "person ID"
+// @Param generations query int false "generations in each direction (default 3, max 10)"
+// @Success 200 {object} TreeEnvelope "Family tree"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "Person not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/kinship/{id}/tree [get]
+func (h *Handlers) GetFamilyTree(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+	generations, err := request.QueryInt32(r, "generations")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.FamilyTree(r.Context(), id, generations)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	if err := render.Write(w, http.StatusOK, render.ContentTypeJSON, TreeEnvelope{Data: NewTreeResponse(*result)}); err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// serviceError maps a service failure onto an HTTP status.
+func (h *Handlers) serviceError(w http.ResponseWriter, err error) {
+	status, msg := apperr.Status(err)
+	if status >= http.StatusInternalServerError {
+		h.logger.Errorf("Service error: %v", err)
+	}
+	if status == http.StatusNotFound && msg == "not found" {
+		msg = "person not found"
+	}
+	http.Error(w, msg, status)
+}
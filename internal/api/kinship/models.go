@@ -0,0 +1,97 @@
+package kinship
+
+import (
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+)
+
+// RelationshipResponse is the wire form of a Relationship. Degree is only
+// present for blood relatives.
+type RelationshipResponse struct {
+	Person          person.PersonResponse   `json:"person"`
+	Relative        person.PersonResponse   `json:"relative"`
+	Kind            string                  `json:"kind" enums:"ancestor,descendant,sibling,collateral,spouse,former_spouse,none"`
+	Label           string                  `json:"label" example:"grandmother"`
+	Degree          *int32                  `json:"degree,omitempty" example:"2"`
+	CommonAncestors []person.PersonResponse `json:"common_ancestors,omitempty"`
+}
+
+func NewRelationshipResponse(rel Relationship) RelationshipResponse {
+	resp := RelationshipResponse{
+		Person:          person.NewPersonResponse(rel.Person),
+		Relative:        person.NewPersonResponse(rel.Relative),
+		Kind:            rel.Kind,
+		Label:           rel.Label,
+		Degree:          render.Nullable(rel.Degree, rel.Degree > 0),
+		CommonAncestors: person.NewPersonResponses(rel.CommonAncestors),
+	}
+	if len(rel.CommonAncestors) == 0 {
+		resp.CommonAncestors = nil
+	}
+	return resp
+}
+
+// RelationshipEnvelope is the response body for a relationship lookup.
+type RelationshipEnvelope struct {
+	Data RelationshipResponse `json:"data"`
+}
+
+// NodeResponse is a family tree member.
+type NodeResponse struct {
+	ID           uuid.UUID   `json:"id"`
+	PersonalCode string      `json:"personal_code"`
+	FirstName    string      `json:"first_name"`
+	LastName     string      `json:"last_name"`
+	BirthDate    render.Date `json:"birth_date"`
+	Sex          string      `json:"sex"`
+	Status       string      `json:"status"`
+	Generation   int32       `json:"generation" example:"1"`
+}
+
+// EdgeResponse links two family tree members. Status is only set for
+// spouse edges.
+type EdgeResponse struct {
+	From   uuid.UUID `json:"from"`
+	To     uuid.UUID `json:"to"`
+	Kind   string    `json:"kind" enums:"mother,father,spouse"`
+	Status string    `json:"status,omitempty" enums:"active,divorced,annulled,widowed"`
+}
+
+// TreeResponse is a family tree as a graph.
+type TreeResponse struct {
+	Root        uuid.UUID      `json:"root"`
+	Generations int32          `json:"generations"`
+	Nodes       []NodeResponse `json:"nodes"`
+	Edges       []EdgeResponse `json:"edges"`
+}
+
+func NewTreeResponse(tree Tree) TreeResponse {
+	resp := TreeResponse{
+		Root:        tree.Root,
+		Generations: tree.Generations,
+		Nodes:       make([]NodeResponse, 0, len(tree.Nodes)),
+		Edges:       make([]EdgeResponse, 0, len(tree.Edges)),
+	}
+	for _, n := range tree.Nodes {
+		resp.Nodes = append(resp.Nodes, NodeResponse{
+			ID:           n.Person.ID,
+			PersonalCode: n.Person.PersonalCode,
+			FirstName:    n.Person.FirstName,
+			LastName:     n.Person.LastName,
+			BirthDate:    render.Date(n.Person.BirthDate.Time),
+			Sex:          n.Person.Sex,
+			Status:       n.Person.Status,
+			Generation:   n.Generation,
+		})
+	}
+	for _, e := range tree.Edges {
+		resp.Edges = append(resp.Edges, EdgeResponse(e))
+	}
+	return resp
+}
+
+// TreeEnvelope is the response body for a family tree.
+type TreeEnvelope struct {
+	Data TreeResponse `json:"data"`
+}
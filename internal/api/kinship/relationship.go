@@ -0,0 +1,96 @@
+package kinship
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/eif-courses/civilregistry/internal/api/person"
+)
+
+const (
+	KindAncestor     = "ancestor"
+	KindDescendant   = "descendant"
+	KindSibling      = "sibling"
+	KindCollateral   = "collateral"
+	KindSpouse       = "spouse"
+	KindFormerSpouse = "former_spouse"
+	KindNone         = "none"
+)
+
+var ordinals = []string{"first", "second", "third", "fourth", "fifth", "sixth", "seventh", "eighth", "ninth"}
+
+// bloodLabel names the relative of a person whose nearest common ancestor
+// is up generations above the person and down generations above the
+// relative. The label follows the relative's sex where English has
+// separate words.
+func bloodLabel(up, down int32, sex string, half bool) (kind, label string) {
+	switch {
+	case down == 0:
+		return KindAncestor, lineal(up, gendered(sex, "mother", "father"))
+	case up == 0:
+		return KindDescendant, lineal(down, gendered(sex, "daughter", "son"))
+	case up == 1 && down == 1:
+		label = gendered(sex, "sister", "brother")
+		if half {
+			label = "half-" + label
+		}
+		return KindSibling, label
+	case up == 1:
+		return KindCollateral, greats(down-2) + gendered(sex, "niece", "nephew")
+	case down == 1:
+		return KindCollateral, greats(up-2) + gendered(sex, "aunt", "uncle")
+	}
+
+	// Cousins: the degree counts from the nearer side, and the difference
+	// in generations is how many times removed they are
+	degree, removed := min(up, down)-1, up-down
+	if removed < 0 {
+		removed = -removed
+	}
+	label = ordinal(degree) + " cousin"
+	switch removed {
+	case 0:
+	case 1:
+		label += " once removed"
+	case 2:
+		label += " twice removed"
+	default:
+		label += fmt.Sprintf(" %d times removed", removed)
+	}
+	return KindCollateral, label
+}
+
+func spouseLabel(sex string, former bool) (kind, label string) {
+	label = gendered(sex, "wife", "husband")
+	if former {
+		return KindFormerSpouse, "former " + label
+	}
+	return KindSpouse, label
+}
+
+// lineal names a direct ancestor or descendant n generations away, e.g.
+// mother, grandmother, great-grandmother.
+func lineal(n int32, base string) string {
+	if n == 1 {
+		return base
+	}
+	return greats(n-2) + "grand" + base
+}
+
+func greats(n int32) string {
+	return strings.Repeat("great-", int(max(n, 0)))
+}
+
+func ordinal(n int32) string {
+	if n >= 1 && int(n) <= len(ordinals) {
+		return ordinals[n-1]
+	}
+	return fmt.Sprintf("%dth", n)
+}
+
+func gendered(sex, female, male string) string {
+	if sex == person.SexFemale {
+		return female
+	}
+	return male
+}
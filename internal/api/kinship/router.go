@@ -0,0 +1,20 @@
+package kinship
+
+import (
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func KinshipRouter(queries *repository.Queries, log *zap.SugaredLogger) chi.Router {
+	r := chi.NewRouter()
+
+	service := NewService(queries, log)
+	handlers := NewHandlers(service, log)
+
+	r.Get("/relationship", telemetry.InstrumentHandler("kinship", "GetRelationship", handlers.GetRelationship))
+	r.Get("/{id}/tree", telemetry.InstrumentHandler("kinship", "GetFamilyTree", handlers.GetFamilyTree))
+
+	return r
+}
@@ -0,0 +1,277 @@
+package kinship
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/eif-courses/civilregistry/internal/api/marriage"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	// MaxGenerations bounds how far the recursive queries walk the parent
+	// links, both for family trees and for relationship searches.
+	MaxGenerations = 10
+
+	defaultTreeGenerations = 3
+
+	EdgeMother = "mother"
+	EdgeFather = "father"
+	EdgeSpouse = "spouse"
+)
+
+type Service struct {
+	repo   *repository.Queries
+	logger *zap.SugaredLogger
+}
+
+func NewService(repo *repository.Queries, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Relationship describes how Relative is related to Person, e.g. Kind
+// "ancestor" and Label "grandmother". Degree is the civil-law degree of
+// kinship, the number of births separating the two; it is zero when they
+// are not blood relatives. CommonAncestors are the nearest shared
+// ancestors of collateral relatives.
+type Relationship struct {
+	Person          repository.Person
+	Relative        repository.Person
+	Kind            string
+	Label           string
+	Degree          int32
+	CommonAncestors []repository.Person
+}
+
+// Node is a family tree member. Generation is positive for ancestors,
+// negative for descendants and zero for the root and their spouses.
+type Node struct {
+	Person     repository.Person
+	Generation int32
+}
+
+// Edge links two family tree members: a mother or father to their child,
+// or two spouses, in which case Status is the marriage status.
+type Edge struct {
+	From   uuid.UUID
+	To     uuid.UUID
+	Kind   string
+	Status string
+}
+
+// Tree is a family tree around Root, as a graph.
+type Tree struct {
+	Root        uuid.UUID
+	Generations int32
+	Nodes       []Node
+	Edges       []Edge
+}
+
+// FindRelationship computes how relativeID is related to personID. Kinship
+// through legal parents, adoptive ones included, is tried first, then
+// marriage between the two. Persons who are neither get KindNone.
+func (s *Service) FindRelationship(ctx context.Context, personID, relativeID uuid.UUID) (_ *Relationship, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "kinship", "FindRelationship")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	if personID == relativeID {
+		return nil, apperr.Invalid("person_id and relative_id must be different persons")
+	}
+
+	p, err := s.person(ctx, personID)
+	if err != nil {
+		return nil, err
+	}
+	relative, err := s.person(ctx, relativeID)
+	if err != nil {
+		return nil, err
+	}
+	rel := &Relationship{Person: p, Relative: relative}
+
+	common, err := s.repo.ListCommonAncestors(ctx, repository.ListCommonAncestorsParams{
+		PersonID:   personID,
+		RelativeID: relativeID,
+		MaxDepth:   MaxGenerations,
+	})
+	if err != nil {
+		s.logger.Errorf("Failed ListCommonAncestors: %v", err)
+		return nil, fmt.Errorf("failed ListCommonAncestors: %w", err)
+	}
+	if len(common) > 0 {
+		if err := s.bloodRelationship(ctx, rel, common); err != nil {
+			return nil, err
+		}
+		return rel, nil
+	}
+
+	marriages, err := s.repo.ListMarriagesForPerson(ctx, personID)
+	if err != nil {
+		return nil, fmt.Errorf("failed ListMarriagesForPerson: %w", err)
+	}
+	rel.Kind, rel.Label = KindNone, "not related"
+	for _, m := range marriages {
+		if m.Spouse1ID != relativeID && m.Spouse2ID != relativeID {
+			continue
+		}
+		// Remarrying the same person leaves an ended marriage next to the
+		// active one; the active one wins
+		if m.Status == marriage.StatusDivorced || m.Status == marriage.StatusAnnulled {
+			if rel.Kind == KindNone {
+				rel.Kind, rel.Label = spouseLabel(relative.Sex, true)
+			}
+			continue
+		}
+		rel.Kind, rel.Label = spouseLabel(relative.Sex, false)
+	}
+	return rel, nil
+}
+
+// bloodRelationship fills in rel from the common ancestors, which come
+// nearest first.
+func (s *Service) bloodRelationship(ctx context.Context, rel *Relationship, common []repository.ListCommonAncestorsRow) error {
+	nearest := common[0]
+	up, down := nearest.PersonDepth, nearest.RelativeDepth
+
+	var shared []uuid.UUID
+	for _, row := range common {
+		if row.PersonDepth == up && row.RelativeDepth == down {
+			shared = append(shared, row.AncestorID)
+		}
+	}
+
+	half := false
+	if up == 1 && down == 1 && len(shared) == 1 {
+		// One shared parent only makes half-siblings when both have two
+		// legal parents; otherwise the other may be unrecorded
+		complete, err := s.bothParentsRecorded(ctx, rel.Person.ID, rel.Relative.ID)
+		if err != nil {
+			return err
+		}
+		half = complete
+	}
+
+	rel.Kind, rel.Label = bloodLabel(up, down, rel.Relative.Sex, half)
+	rel.Degree = up + down
+
+	// For direct lines the shared ancestor is one of the two persons
+	if up == 0 || down == 0 {
+		return nil
+	}
+	for _, id := range shared {
+		ancestor, err := s.person(ctx, id)
+		if err != nil {
+			return err
+		}
+		rel.CommonAncestors = append(rel.CommonAncestors, ancestor)
+	}
+	return nil
+}
+
+func (s *Service) bothParentsRecorded(ctx context.Context, ids ...uuid.UUID) (bool, error) {
+	for _, id := range ids {
+		parents, err := s.repo.GetLegalParentage(ctx, id)
+		if err != nil {
+			return false, fmt.Errorf("failed GetLegalParentage: %w", err)
+		}
+		if !parents.MotherID.Valid || !parents.FatherID.Valid {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// FamilyTree returns the person's ancestors and descendants up to the given
+// number of generations, with their spouses. Zero generations selects the
+// default depth.
+func (s *Service) FamilyTree(ctx context.Context, personID uuid.UUID, generations int32) (_ *Tree, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "kinship", "FamilyTree")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	if generations == 0 {
+		generations = defaultTreeGenerations
+	}
+	if generations < 1 || generations > MaxGenerations {
+		return nil, apperr.Invalid("generations must be between 1 and %d", MaxGenerations)
+	}
+
+	rows, err := s.repo.ListFamilyTree(ctx, repository.ListFamilyTreeParams{
+		PersonID:    personID,
+		Generations: generations,
+	})
+	if err != nil {
+		s.logger.Errorf("Failed ListFamilyTree: %v", err)
+		return nil, fmt.Errorf("failed ListFamilyTree: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, apperr.NotFound("person %s not found", personID)
+	}
+
+	// Oldest generation first, then by birth date, so the tree reads top down
+	slices.SortStableFunc(rows, func(a, b repository.ListFamilyTreeRow) int {
+		if a.Generation != b.Generation {
+			return cmp.Compare(b.Generation, a.Generation)
+		}
+		return a.Person.BirthDate.Time.Compare(b.Person.BirthDate.Time)
+	})
+
+	tree := &Tree{Root: personID, Generations: generations}
+	members := make(map[uuid.UUID]bool, len(rows))
+	ids := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		tree.Nodes = append(tree.Nodes, Node{Person: row.Person, Generation: row.Generation})
+		members[row.Person.ID] = true
+		ids = append(ids, row.Person.ID)
+	}
+
+	// Spouses pulled in by marriage have their own parents, who are only
+	// linked when they are part of the tree as well
+	for _, row := range rows {
+		if row.MotherID.Valid && members[row.MotherID.Bytes] {
+			tree.Edges = append(tree.Edges, Edge{From: row.MotherID.Bytes, To: row.Person.ID, Kind: EdgeMother})
+		}
+		if row.FatherID.Valid && members[row.FatherID.Bytes] {
+			tree.Edges = append(tree.Edges, Edge{From: row.FatherID.Bytes, To: row.Person.ID, Kind: EdgeFather})
+		}
+	}
+
+	marriages, err := s.repo.ListMarriagesAmongPersons(ctx, ids)
+	if err != nil {
+		s.logger.Errorf("Failed ListMarriagesAmongPersons: %v", err)
+		return nil, fmt.Errorf("failed ListMarriagesAmongPersons: %w", err)
+	}
+	for _, m := range marriages {
+		tree.Edges = append(tree.Edges, Edge{From: m.Spouse1ID, To: m.Spouse2ID, Kind: EdgeSpouse, Status: m.Status})
+	}
+	return tree, nil
+}
+
+func (s *Service) person(ctx context.Context, id uuid.UUID) (repository.Person, error) {
+	p, err := s.repo.GetPersonByID(ctx, id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.Person{}, apperr.NotFound("person %s not found", id)
+	}
+	if err != nil {
+		return repository.Person{}, fmt.Errorf("failed GetPersonByID: %w", err)
+	}
+	return p, nil
+}
@@ -0,0 +1,385 @@
+package marriage
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/eif-courses/civilregistry/internal/api/foreign"
+	"github.com/eif-courses/civilregistry/internal/api/request"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	service *Service
+	logger  *zap.SugaredLogger
+}
+
+func NewHandlers(service *Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type RegisterMarriageRequest struct {
+	Spouse1ID      uuid.UUID   `json:"spouse1_id"`
+	Spouse2ID      uuid.UUID   `json:"spouse2_id"`
+	RegisteredOn   render.Date `json:"registered_on" swaggertype:"string" format:"date" example:"2026-06-20"`
+	Spouse1NewName *string     `json:"spouse1_new_name,omitempty"`
+	Spouse2NewName *string     `json:"spouse2_new_name,omitempty" example:"Jonaitienė"`
+}
+
+// Params converts the request into service parameters.
+func (req RegisterMarriageRequest) Params() RegisterMarriageParams {
+	return RegisterMarriageParams{
+		Spouse1ID:      req.Spouse1ID,
+		Spouse2ID:      req.Spouse2ID,
+		RegisteredOn:   request.Date(req.RegisteredOn),
+		Spouse1NewName: request.Text(req.Spouse1NewName),
+		Spouse2NewName: request.Text(req.Spouse2NewName),
+	}
+}
+
+type RegisterForeignMarriageRequest struct {
+	RegisterMarriageRequest
+	Document foreign.DocumentRequest `json:"document"`
+}
+
+// Params converts the request into service parameters.
+func (req RegisterForeignMarriageRequest) Params() RegisterMarriageParams {
+	arg := req.RegisterMarriageRequest.Params()
+	doc := req.Document.Params()
+	arg.Document = &doc
+	return arg
+}
+
+type EndMarriageRequest struct {
+	EndedOn render.Date `json:"ended_on" swaggertype:"string" format:"date" example:"2026-09-01"`
+}
+
+// RegisterMarriage registers a marriage
+// @Summary Register marriage
+// @Description Register a marriage between two living adults who are not married, optionally changing their surnames
+// @Description The signed-in registrar's office must have jurisdiction over marriages.
+// @Tags marriage
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RegisterMarriageRequest true "marriage data"
+// @Success 201 {object} MarriageRegistrationEnvelope "Registered marriage"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Outside the office's jurisdiction"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "A spouse cannot marry"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/marriage/ [post]
+func (h *Handlers) RegisterMarriage(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var req RegisterMarriageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.RegisterMarriage(r.Context(), req.Params())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/marriage/"+result.Record.ID.String())
+	h.writeRegistration(w, http.StatusCreated, "marriage registered successfully", *result)
+}
+
+// RegisterForeignMarriage transcribes a marriage abroad
+// @Summary Register marriage abroad
+// @Description Transcribe a marriage that took place abroad from its foreign marriage certificate. registered_on is
+// @Description the day of the wedding. The spouses are checked and updated as for a marriage in Lithuania, and the
+// @Description record also keeps the source document. The document needs apostille or legalisation details unless
+// @Description exempt, and a translation unless it is in Lithuanian.
+// @Tags marriage
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body RegisterForeignMarriageRequest true "marriage and source document"
+// @Success 201 {object} MarriageRegistrationEnvelope "Transcribed marriage"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Office does not register marriages"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "A spouse cannot marry"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/marriage/foreign [post]
+func (h *Handlers) RegisterForeignMarriage(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var req RegisterForeignMarriageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.RegisterMarriage(r.Context(), req.Params())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/marriage/"+result.Record.ID.String())
+	h.writeRegistration(w, http.StatusCreated, "marriage abroad transcribed successfully", *result)
+}
+
+// Divorce registers a divorce
+// @Summary Register divorce
+// @Description Close an active marriage as divorced. Both spouses become divorced.
+// @Tags marriage
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "marriage ID"
+// @Param request body EndMarriageRequest true "divorce date"
+// @Success 200 {object} MarriageRegistrationEnvelope "Divorced marriage"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Office does not register marriages"
+// @Failure 404 {object} map[string]interface{} "Marriage not found"
+// @Failure 409 {object} map[string]interface{} "Marriage is not active"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/marriage/{id}/divorce [post]
+func (h *Handlers) Divorce(w http.ResponseWriter, r *http.Request) {
+	h.end(w, r, h.service.Divorce, "divorce registered successfully")
+}
+
+// Annul registers an annulment
+// @Summary Annul marriage
+// @Description Void an active marriage. Both spouses get back their previous surname and marital status.
+// @Tags marriage
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "marriage ID"
+// @Param request body EndMarriageRequest true "annulment date"
+// @Success 200 {object} MarriageRegistrationEnvelope "Annulled marriage"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Office does not register marriages"
+// @Failure 404 {object} map[string]interface{} "Marriage not found"
+// @Failure 409 {object} map[string]interface{} "Marriage is not active"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/marriage/{id}/annul [post]
+func (h *Handlers) Annul(w http.ResponseWriter, r *http.Request) {
+	h.end(w, r, h.service.Annul, "annulment registered successfully")
+}
+
+// GetMarriage retrieves a marriage
+// @Summary Get marriage
+// @Description Get a marriage record with both spouses
+// @Tags marriage
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "marriage ID"
+// @Success 200 {object} MarriageRegistrationEnvelope "Marriage found"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "Marriage not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/marriage/{id} [get]
+func (h *Handlers) GetMarriage(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.GetMarriage(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.writeRegistration(w, http.StatusOK, "", *result)
+}
+
+// GetMarriageByNumber retrieves a marriage by registry number
+// @Summary Get marriage by registry number
+// @Description Get the marriage record with a registry number such as VIL-2026-000123
+// @Tags marriage
+// @Produce json
+// @Security BearerAuth
+// @Param number path string true "registry number"
+// @Success 200 {object} MarriageRegistrationEnvelope "Marriage found"
+// @Failure 400 {object} map[string]interface{} "Invalid registry number"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "Marriage not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/marriage/by-number/{number} [get]
+func (h *Handlers) GetMarriageByNumber(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	result, err := h.service.GetMarriageByRegistryNumber(r.Context(), chi.URLParam(r, "number"))
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.writeRegistration(w, http.StatusOK, "", *result)
+}
+
+// ListMarriages lists marriages
+// @Summary List marriages
+// @Description List marriages, most recent first. origin=foreign keeps marriages abroad, optionally in one country.
+// @Tags marriage
+// @Produce json
+// @Security BearerAuth
+// @Param origin query string false "domestic or foreign" Enums(domestic, foreign)
+// @Param country query string false "country of a marriage abroad, ISO 3166-1 alpha-2"
+// @Param limit query int false "page size (default 50, max 200)"
+// @Param offset query int false "rows to skip"
+// @Success 200 {object} MarriageListEnvelope "Marriages"
+// @Failure 400 {object} map[string]interface{} "Invalid paging or filter"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/marriage/ [get]
+func (h *Handlers) ListMarriages(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	limit, offset, err := request.Page(r)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.ListMarriages(r.Context(), foreign.ParseFilter(r), limit, offset)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.writeList(w, NewMarriageResponses(result))
+}
+
+// ListMarriagesForPerson lists the marriages of a person
+// @Summary List marriages of a person
+// @Description List all marriages, active or ended, a person has been part of
+// @Tags marriage
+// @Produce json
+// @Security BearerAuth
+// @Param personID path string true "person ID"
+// @Success 200 {object} MarriageListEnvelope "Marriages"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/marriage/by-person/{personID} [get]
+func (h *Handlers) ListMarriagesForPerson(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	personID, err := request.UUIDParam(r, "personID")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.ListMarriagesForPerson(r.Context(), personID)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.writeList(w, NewMarriageResponses(result))
+}
+
+type endFunc func(ctx context.Context, id uuid.UUID, endedOn pgtype.Date) (*MarriageRegistration, error)
+
+func (h *Handlers) end(w http.ResponseWriter, r *http.Request, end endFunc, message string) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	var req EndMarriageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := end(r.Context(), id, request.Date(req.EndedOn))
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.writeRegistration(w, http.StatusOK, message, *result)
+}
+
+func (h *Handlers) writeRegistration(w http.ResponseWriter, status int, message string, reg MarriageRegistration) {
+	err := render.Write(w, status, render.ContentTypeJSON, MarriageRegistrationEnvelope{
+		Message: message,
+		Data:    NewMarriageRegistrationResponse(reg),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+func (h *Handlers) writeList(w http.ResponseWriter, items []MarriageResponse) {
+	err := render.Write(w, http.StatusOK, render.ContentTypeJSON, MarriageListEnvelope{
+		Count: len(items),
+		Data:  items,
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// serviceError maps a service failure onto an HTTP status.
+func (h *Handlers) serviceError(w http.ResponseWriter, err error) {
+	status, msg := apperr.Status(err)
+	if status >= http.StatusInternalServerError {
+		h.logger.Errorf("Service error: %v", err)
+	}
+	if status == http.StatusNotFound && msg == "not found" {
+		msg = "marriage not found"
+	}
+	http.Error(w, msg, status)
+}
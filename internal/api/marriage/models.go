@@ -0,0 +1,100 @@
+package marriage
+
+import (
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/foreign"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+)
+
+// MarriageResponse is the wire form of repository.Marriage.
+type MarriageResponse struct {
+	ID                    uuid.UUID    `json:"id"`
+	Spouse1ID             uuid.UUID    `json:"spouse1_id"`
+	Spouse2ID             uuid.UUID    `json:"spouse2_id"`
+	RegisteredOn          render.Date  `json:"registered_on"`
+	RegistrationOffice    string       `json:"registration_office"`
+	Registrar             string       `json:"registrar"`
+	OfficeID              *uuid.UUID   `json:"office_id"`
+	RegistrarID           *uuid.UUID   `json:"registrar_id"`
+	RegistryNumber        *string      `json:"registry_number" example:"VIL-2026-000123"`
+	Origin                string       `json:"origin" enums:"domestic,foreign"`
+	ForeignDocumentID     *uuid.UUID   `json:"foreign_document_id"`
+	Spouse1PreviousName   string       `json:"spouse1_previous_name"`
+	Spouse2PreviousName   string       `json:"spouse2_previous_name"`
+	Spouse1PreviousStatus string       `json:"spouse1_previous_status"`
+	Spouse2PreviousStatus string       `json:"spouse2_previous_status"`
+	Spouse1NewName        *string      `json:"spouse1_new_name"`
+	Spouse2NewName        *string      `json:"spouse2_new_name"`
+	Status                string       `json:"status"`
+	EndedOn               *render.Date `json:"ended_on"`
+	CreatedAt             time.Time    `json:"created_at"`
+	UpdatedAt             time.Time    `json:"updated_at"`
+}
+
+func NewMarriageResponse(row repository.Marriage) MarriageResponse {
+	return MarriageResponse{
+		ID:                    row.ID,
+		Spouse1ID:             row.Spouse1ID,
+		Spouse2ID:             row.Spouse2ID,
+		RegisteredOn:          render.Date(row.RegisteredOn.Time),
+		RegistrationOffice:    row.RegistrationOffice,
+		Registrar:             row.Registrar,
+		OfficeID:              render.Nullable(uuid.UUID(row.OfficeID.Bytes), row.OfficeID.Valid),
+		RegistrarID:           render.Nullable(uuid.UUID(row.RegistrarID.Bytes), row.RegistrarID.Valid),
+		RegistryNumber:        render.Nullable(row.RegistryNumber.String, row.RegistryNumber.Valid),
+		Origin:                foreign.Origin(row.ForeignDocumentID),
+		ForeignDocumentID:     render.Nullable(uuid.UUID(row.ForeignDocumentID.Bytes), row.ForeignDocumentID.Valid),
+		Spouse1PreviousName:   row.Spouse1PreviousName,
+		Spouse2PreviousName:   row.Spouse2PreviousName,
+		Spouse1PreviousStatus: row.Spouse1PreviousStatus,
+		Spouse2PreviousStatus: row.Spouse2PreviousStatus,
+		Spouse1NewName:        render.Nullable(row.Spouse1NewName.String, row.Spouse1NewName.Valid),
+		Spouse2NewName:        render.Nullable(row.Spouse2NewName.String, row.Spouse2NewName.Valid),
+		Status:                row.Status,
+		EndedOn:               render.NullableDate(row.EndedOn.Time, row.EndedOn.Valid),
+		CreatedAt:             row.CreatedAt,
+		UpdatedAt:             row.UpdatedAt,
+	}
+}
+
+func NewMarriageResponses(rows []repository.Marriage) []MarriageResponse {
+	items := make([]MarriageResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, NewMarriageResponse(row))
+	}
+	return items
+}
+
+// MarriageRegistrationResponse is a marriage with both spouses as they are
+// after the operation, and the source document of a marriage abroad.
+type MarriageRegistrationResponse struct {
+	Record          MarriageResponse          `json:"record"`
+	Spouse1         person.PersonResponse     `json:"spouse1"`
+	Spouse2         person.PersonResponse     `json:"spouse2"`
+	ForeignDocument *foreign.DocumentResponse `json:"foreign_document"`
+}
+
+func NewMarriageRegistrationResponse(reg MarriageRegistration) MarriageRegistrationResponse {
+	return MarriageRegistrationResponse{
+		Record:          NewMarriageResponse(reg.Record),
+		Spouse1:         person.NewPersonResponse(reg.Spouse1),
+		Spouse2:         person.NewPersonResponse(reg.Spouse2),
+		ForeignDocument: foreign.NewDocumentResponse(reg.Document),
+	}
+}
+
+// MarriageRegistrationEnvelope is the response body for a single marriage.
+type MarriageRegistrationEnvelope struct {
+	Message string                       `json:"message,omitempty"`
+	Data    MarriageRegistrationResponse `json:"data"`
+}
+
+// MarriageListEnvelope is the response body for a list of marriages.
+type MarriageListEnvelope struct {
+	Count int                `json:"count"`
+	Data  []MarriageResponse `json:"data"`
+}
@@ -0,0 +1,27 @@
+package marriage
+
+import (
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func MarriageRouter(db txn.Beginner, queries *repository.Queries, log *zap.SugaredLogger) chi.Router {
+	r := chi.NewRouter()
+
+	service := NewService(db, queries, log)
+	handlers := NewHandlers(service, log)
+
+	r.Post("/", telemetry.InstrumentHandler("marriage", "RegisterMarriage", handlers.RegisterMarriage))
+	r.Post("/foreign", telemetry.InstrumentHandler("marriage", "RegisterForeignMarriage", handlers.RegisterForeignMarriage))
+	r.Get("/", telemetry.InstrumentHandler("marriage", "ListMarriages", handlers.ListMarriages))
+	r.Get("/by-person/{personID}", telemetry.InstrumentHandler("marriage", "ListMarriagesForPerson", handlers.ListMarriagesForPerson))
+	r.Get("/by-number/{number}", telemetry.InstrumentHandler("marriage", "GetMarriageByNumber", handlers.GetMarriageByNumber))
+	r.Get("/{id}", telemetry.InstrumentHandler("marriage", "GetMarriage", handlers.GetMarriage))
+	r.Post("/{id}/divorce", telemetry.InstrumentHandler("marriage", "Divorce", handlers.Divorce))
+	r.Post("/{id}/annul", telemetry.InstrumentHandler("marriage", "Annul", handlers.Annul))
+
+	return r
+}
@@ -0,0 +1,446 @@
+package marriage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/foreign"
+	"github.com/eif-courses/civilregistry/internal/api/office"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const (
+	StatusActive   = "active"
+	StatusDivorced = "divorced"
+	StatusAnnulled = "annulled"
+	StatusWidowed  = "widowed"
+
+	// MinimumAge is the age both spouses must have reached on the day of
+	// the marriage.
+	MinimumAge = 18
+
+	defaultListLimit = 50
+	maxListLimit     = 200
+)
+
+type Service struct {
+	db     txn.Beginner
+	repo   *repository.Queries
+	logger *zap.SugaredLogger
+}
+
+func NewService(db txn.Beginner, repo *repository.Queries, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		db:     db,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// RegisterMarriageParams describes a marriage to register. A NULL new name
+// keeps that spouse's current surname. Document is set for a marriage
+// abroad transcribed from a foreign certificate; RegisteredOn is then the
+// day of the wedding abroad.
+type RegisterMarriageParams struct {
+	Spouse1ID      uuid.UUID
+	Spouse2ID      uuid.UUID
+	RegisteredOn   pgtype.Date
+	Spouse1NewName pgtype.Text
+	Spouse2NewName pgtype.Text
+	Document       *repository.CreateForeignDocumentParams
+}
+
+// MarriageRegistration is a marriage record together with both spouses
+// and, for a marriage abroad, its source document.
+type MarriageRegistration struct {
+	Record   repository.Marriage
+	Spouse1  repository.Person
+	Spouse2  repository.Person
+	Document *repository.ForeignDocument
+}
+
+// RegisterMarriage records the marriage and marks both spouses married,
+// applying any surname change, in one transaction. Both persons are locked
+// first so two concurrent registrations cannot marry the same person twice.
+func (s *Service) RegisterMarriage(ctx context.Context, arg RegisterMarriageParams) (_ *MarriageRegistration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "marriage", "RegisterMarriage")
+	defer func() { op.End(err) }()
+
+	arg.Spouse1NewName = trimName(arg.Spouse1NewName)
+	arg.Spouse2NewName = trimName(arg.Spouse2NewName)
+	if arg.Spouse1ID == arg.Spouse2ID {
+		return nil, apperr.Invalid("a person cannot marry themselves")
+	}
+	if err := validateDate(arg.RegisteredOn, "registered_on"); err != nil {
+		return nil, err
+	}
+	if arg.Document != nil {
+		doc, err := foreign.PrepareDocument(*arg.Document, arg.RegisteredOn)
+		if err != nil {
+			return nil, err
+		}
+		arg.Document = &doc
+	}
+	if _, err := auth.RequireRegistrar(ctx); err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Registering marriage of %s and %s", arg.Spouse1ID, arg.Spouse2ID)
+
+	var reg MarriageRegistration
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		registrar, err := office.Authorize(ctx, q, office.EventMarriage, pgtype.Text{})
+		if err != nil {
+			return err
+		}
+		officeID, registrarID := office.RecordedBy(registrar)
+
+		spouse1, spouse2, err := lockSpouses(ctx, q, arg.Spouse1ID, arg.Spouse2ID)
+		if err != nil {
+			return err
+		}
+		for _, p := range []repository.Person{spouse1, spouse2} {
+			if err := checkCanMarry(ctx, q, p, arg.RegisteredOn.Time); err != nil {
+				return err
+			}
+		}
+
+		if arg.Document != nil {
+			if reg.Document, err = foreign.CreateDocument(ctx, q, *arg.Document); err != nil {
+				return err
+			}
+		}
+
+		number, err := office.NextRegistryNumber(ctx, q, registrar, office.EventMarriage, time.Now().Year())
+		if err != nil {
+			return err
+		}
+
+		reg.Record, err = q.CreateMarriage(ctx, repository.CreateMarriageParams{
+			Spouse1ID:             spouse1.ID,
+			Spouse2ID:             spouse2.ID,
+			RegisteredOn:          arg.RegisteredOn,
+			RegistrationOffice:    registrar.Office.Name,
+			Registrar:             registrar.Registrar.FullName,
+			Spouse1PreviousName:   spouse1.LastName,
+			Spouse2PreviousName:   spouse2.LastName,
+			Spouse1PreviousStatus: spouse1.MaritalStatus,
+			Spouse2PreviousStatus: spouse2.MaritalStatus,
+			Spouse1NewName:        arg.Spouse1NewName,
+			Spouse2NewName:        arg.Spouse2NewName,
+			OfficeID:              officeID,
+			RegistrarID:           registrarID,
+			RegistryNumber:        pgtype.Text{String: number, Valid: true},
+			ForeignDocumentID:     foreign.Ref(reg.Document),
+		})
+		if err != nil {
+			if apperr.IsUniqueViolation(err, "") {
+				return apperr.Conflict("one of the spouses is already married")
+			}
+			return fmt.Errorf("failed CreateMarriage: %w", err)
+		}
+
+		amendment := person.Amendment{
+			LegalBasis:    "marriage registration " + reg.Record.ID.String(),
+			EffectiveDate: arg.RegisteredOn,
+		}
+		if reg.Spouse1, err = setMaritalStatus(ctx, q, spouse1, person.MaritalMarried, newName(spouse1, arg.Spouse1NewName), amendment); err != nil {
+			return err
+		}
+		reg.Spouse2, err = setMaritalStatus(ctx, q, spouse2, person.MaritalMarried, newName(spouse2, arg.Spouse2NewName), amendment)
+		return err
+	})
+	if err != nil {
+		s.logger.Errorf("Failed RegisterMarriage: %v", err)
+		return nil, err
+	}
+
+	s.logger.Infof("RegisterMarriage completed successfully with ID: %s", reg.Record.ID)
+	return &reg, nil
+}
+
+// Divorce closes an active marriage; both spouses become divorced and keep
+// their current surnames.
+func (s *Service) Divorce(ctx context.Context, id uuid.UUID, endedOn pgtype.Date) (_ *MarriageRegistration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "marriage", "Divorce")
+	defer func() { op.End(err) }()
+
+	return s.end(ctx, id, StatusDivorced, endedOn)
+}
+
+// Annul voids an active marriage; both spouses get back the surname and
+// marital status they had before it.
+func (s *Service) Annul(ctx context.Context, id uuid.UUID, endedOn pgtype.Date) (_ *MarriageRegistration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "marriage", "Annul")
+	defer func() { op.End(err) }()
+
+	return s.end(ctx, id, StatusAnnulled, endedOn)
+}
+
+func (s *Service) end(ctx context.Context, id uuid.UUID, outcome string, endedOn pgtype.Date) (*MarriageRegistration, error) {
+	if err := validateDate(endedOn, "ended_on"); err != nil {
+		return nil, err
+	}
+	if _, err := auth.RequireRegistrar(ctx); err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Ending marriage %s as %s", id, outcome)
+
+	var reg MarriageRegistration
+	err := txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		// Ending a marriage takes the same office rights as registering one
+		if _, err := office.Authorize(ctx, q, office.EventMarriage, pgtype.Text{}); err != nil {
+			return err
+		}
+
+		record, err := q.GetMarriageForUpdate(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed GetMarriageForUpdate: %w", err)
+		}
+		if record.Status != StatusActive {
+			return apperr.Conflict("marriage is already %s", record.Status)
+		}
+		if endedOn.Time.Before(record.RegisteredOn.Time) {
+			return apperr.Invalid("ended_on must not be before the marriage was registered")
+		}
+		if reg.Document, err = foreign.LoadDocument(ctx, q, record.ForeignDocumentID); err != nil {
+			return err
+		}
+
+		spouse1, spouse2, err := lockSpouses(ctx, q, record.Spouse1ID, record.Spouse2ID)
+		if err != nil {
+			return err
+		}
+
+		if reg.Record, err = q.EndMarriage(ctx, repository.EndMarriageParams{
+			ID:      id,
+			Status:  outcome,
+			EndedOn: endedOn,
+		}); err != nil {
+			return fmt.Errorf("failed EndMarriage: %w", err)
+		}
+
+		status1, name1 := person.MaritalDivorced, spouse1.LastName
+		status2, name2 := person.MaritalDivorced, spouse2.LastName
+		basis := "divorce"
+		if outcome == StatusAnnulled {
+			status1, name1 = record.Spouse1PreviousStatus, record.Spouse1PreviousName
+			status2, name2 = record.Spouse2PreviousStatus, record.Spouse2PreviousName
+			basis = "annulment"
+		}
+
+		amendment := person.Amendment{
+			LegalBasis:    basis + " of marriage " + id.String(),
+			EffectiveDate: endedOn,
+		}
+		if reg.Spouse1, err = setMaritalStatus(ctx, q, spouse1, status1, name1, amendment); err != nil {
+			return err
+		}
+		reg.Spouse2, err = setMaritalStatus(ctx, q, spouse2, status2, name2, amendment)
+		return err
+	})
+	if err != nil {
+		s.logger.Errorf("Failed to end marriage %s: %v", id, err)
+		return nil, err
+	}
+
+	return &reg, nil
+}
+
+func (s *Service) GetMarriage(ctx context.Context, id uuid.UUID) (_ *MarriageRegistration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "marriage", "GetMarriage")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	record, err := s.repo.GetMarriageByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetMarriageByID: %w", err)
+	}
+	return s.expand(ctx, record)
+}
+
+// GetMarriageByRegistryNumber finds a marriage by its registry number, e.g.
+// VIL-2026-000123, in any letter case.
+func (s *Service) GetMarriageByRegistryNumber(ctx context.Context, number string) (_ *MarriageRegistration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "marriage", "GetMarriageByRegistryNumber")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	if number, err = office.NormalizeRegistryNumber(number); err != nil {
+		return nil, err
+	}
+	record, err := s.repo.GetMarriageByRegistryNumber(ctx, pgtype.Text{String: number, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed GetMarriageByRegistryNumber: %w", err)
+	}
+	return s.expand(ctx, record)
+}
+
+func (s *Service) expand(ctx context.Context, record repository.Marriage) (*MarriageRegistration, error) {
+	var err error
+	reg := MarriageRegistration{Record: record}
+	if reg.Spouse1, err = s.repo.GetPersonByID(ctx, record.Spouse1ID); err != nil {
+		return nil, fmt.Errorf("failed GetPersonByID: %w", err)
+	}
+	if reg.Spouse2, err = s.repo.GetPersonByID(ctx, record.Spouse2ID); err != nil {
+		return nil, fmt.Errorf("failed GetPersonByID: %w", err)
+	}
+	if reg.Document, err = foreign.LoadDocument(ctx, s.repo, record.ForeignDocumentID); err != nil {
+		return nil, err
+	}
+	return &reg, nil
+}
+
+// ListMarriages returns the most recent marriages first, optionally only
+// those in Lithuania or abroad. A zero limit selects the default page size.
+func (s *Service) ListMarriages(ctx context.Context, f foreign.Filter, limit, offset int32) (_ []repository.Marriage, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "marriage", "ListMarriages")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	if limit < 0 || limit > maxListLimit {
+		return nil, apperr.Invalid("limit must be between 1 and %d", maxListLimit)
+	}
+	if limit == 0 {
+		limit = defaultListLimit
+	}
+	if offset < 0 {
+		return nil, apperr.Invalid("offset must not be negative")
+	}
+
+	if f, err = foreign.NormalizeFilter(f); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.ListMarriages(ctx, repository.ListMarriagesParams{
+		Origin:  f.Origin,
+		Country: f.Country,
+		Limit:   limit,
+		Offset:  offset,
+	})
+	if err != nil {
+		s.logger.Errorf("Failed ListMarriages: %v", err)
+		return nil, fmt.Errorf("failed ListMarriages: %w", err)
+	}
+	return result, nil
+}
+
+func (s *Service) ListMarriagesForPerson(ctx context.Context, personID uuid.UUID) (_ []repository.Marriage, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "marriage", "ListMarriagesForPerson")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.ListMarriagesForPerson(ctx, personID)
+	if err != nil {
+		s.logger.Errorf("Failed ListMarriagesForPerson: %v", err)
+		return nil, fmt.Errorf("failed ListMarriagesForPerson: %w", err)
+	}
+	return result, nil
+}
+
+// lockSpouses locks both persons and returns them in argument order.
+func lockSpouses(ctx context.Context, q *repository.Queries, id1, id2 uuid.UUID) (repository.Person, repository.Person, error) {
+	locked, err := person.LockInOrder(ctx, q, id1, id2)
+	if err != nil {
+		return repository.Person{}, repository.Person{}, err
+	}
+	return locked[id1], locked[id2], nil
+}
+
+func checkCanMarry(ctx context.Context, q *repository.Queries, p repository.Person, on time.Time) error {
+	name := p.FirstName + " " + p.LastName
+	if err := person.EnsureAlive(p); err != nil {
+		return err
+	}
+	if ageOn(p.BirthDate.Time, on) < MinimumAge {
+		return apperr.Conflict("%s is under %d on the marriage date", name, MinimumAge)
+	}
+	if p.MaritalStatus == person.MaritalMarried {
+		return apperr.Conflict("%s is already married", name)
+	}
+
+	// marital_status and the marriage table should agree, but check the
+	// records too in case the status was corrected by hand
+	_, err := q.GetActiveMarriageForPerson(ctx, p.ID)
+	if err == nil {
+		return apperr.Conflict("%s is already married", name)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed GetActiveMarriageForPerson: %w", err)
+	}
+	return nil
+}
+
+// setMaritalStatus updates a locked spouse and records the change in their
+// history.
+func setMaritalStatus(ctx context.Context, q *repository.Queries, p repository.Person, status, lastName string, amendment person.Amendment) (repository.Person, error) {
+	updated, err := q.SetPersonMaritalStatus(ctx, repository.SetPersonMaritalStatusParams{
+		ID:            p.ID,
+		MaritalStatus: status,
+		LastName:      lastName,
+	})
+	if err != nil {
+		return repository.Person{}, fmt.Errorf("failed SetPersonMaritalStatus: %w", err)
+	}
+	if err := person.RecordAmendment(ctx, q, p, updated, amendment); err != nil {
+		return repository.Person{}, err
+	}
+	return updated, nil
+}
+
+func newName(p repository.Person, name pgtype.Text) string {
+	if name.Valid {
+		return name.String
+	}
+	return p.LastName
+}
+
+func trimName(name pgtype.Text) pgtype.Text {
+	name.String = strings.TrimSpace(name.String)
+	name.Valid = name.Valid && name.String != ""
+	return name
+}
+
+func validateDate(d pgtype.Date, field string) error {
+	if !d.Valid {
+		return apperr.Invalid("%s is required", field)
+	}
+	if d.Time.After(time.Now()) {
+		return apperr.Invalid("%s must not be in the future", field)
+	}
+	return nil
+}
+
+// ageOn returns the age in completed years of someone born on birth.
+func ageOn(birth, on time.Time) int {
+	age := on.Year() - birth.Year()
+	if on.Month() < birth.Month() || on.Month() == birth.Month() && on.Day() < birth.Day() {
+		age--
+	}
+	return age
+}
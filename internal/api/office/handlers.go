@@ -0,0 +1,521 @@
+package office
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/eif-courses/civilregistry/internal/api/request"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	service *Service
+	logger  *zap.SugaredLogger
+}
+
+func NewHandlers(service *Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type CreateOfficeRequest struct {
+	Code         string `json:"code" example:"VIL"`
+	Name         string `json:"name" example:"Vilniaus miesto civilinės metrikacijos skyrius"`
+	Municipality string `json:"municipality" example:"Vilniaus m. sav."`
+}
+
+// Params converts the request into repository parameters.
+func (req CreateOfficeRequest) Params() repository.CreateOfficeParams {
+	return repository.CreateOfficeParams{
+		Code:         req.Code,
+		Name:         req.Name,
+		Municipality: req.Municipality,
+	}
+}
+
+type SetActiveRequest struct {
+	Active bool `json:"active" example:"false"`
+}
+
+type CreateRegistrarRequest struct {
+	FullName string `json:"full_name" example:"Ona Onaitė"`
+	Username string `json:"username" example:"ona.onaite"`
+	Role     string `json:"role,omitempty" enums:"registrar,supervisor,admin" example:"registrar"`
+}
+
+type AddJurisdictionRequest struct {
+	EventKind string  `json:"event_kind" enums:"birth,marriage,death" example:"birth"`
+	Place     *string `json:"place,omitempty" example:"Vilnius"`
+}
+
+// CreateOffice registers an office
+// @Summary Create office
+// @Description Register a civil registration office. Admins only.
+// @Tags office
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body CreateOfficeRequest true "office data"
+// @Success 201 {object} OfficeEnvelope "Created office"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not an admin"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Office code taken"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/office/ [post]
+func (h *Handlers) CreateOffice(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var req CreateOfficeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.CreateOffice(r.Context(), req.Params())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/office/"+result.ID.String())
+	h.write(w, http.StatusCreated, OfficeEnvelope{
+		Message: "office created successfully",
+		Data:    NewOfficeDetailsResponse(OfficeDetails{Office: *result}),
+	})
+}
+
+// ListOffices lists the offices
+// @Summary List offices
+// @Description List every office, by code
+// @Tags office
+// @Produce json
+// @Success 200 {object} OfficeListEnvelope "Offices"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/office/ [get]
+func (h *Handlers) ListOffices(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	result, err := h.service.ListOffices(r.Context())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, OfficeListEnvelope{
+		Count: len(result),
+		Data:  NewOfficeResponses(result),
+	})
+}
+
+// GetOffice retrieves an office
+// @Summary Get office
+// @Description Get an office with the events it may register
+// @Tags office
+// @Produce json
+// @Param id path string true "office ID"
+// @Success 200 {object} OfficeEnvelope "Office found"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 404 {object} map[string]interface{} "Office not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/office/{id} [get]
+func (h *Handlers) GetOffice(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.GetOffice(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, OfficeEnvelope{Data: NewOfficeDetailsResponse(*result)})
+}
+
+// SetOfficeActive closes or reopens an office
+// @Summary Close or reopen office
+// @Description Registrars of a closed office cannot sign in. Admins only.
+// @Tags office
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "office ID"
+// @Param request body SetActiveRequest true "new state"
+// @Success 200 {object} OfficeEnvelope "Updated office"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not an admin"
+// @Failure 404 {object} map[string]interface{} "Office not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/office/{id}/active [put]
+func (h *Handlers) SetOfficeActive(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	var req SetActiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.SetOfficeActive(r.Context(), id, req.Active)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, OfficeEnvelope{
+		Message: "office updated successfully",
+		Data:    NewOfficeDetailsResponse(OfficeDetails{Office: *result}),
+	})
+}
+
+// CreateRegistrar adds a registrar to an office
+// @Summary Create registrar
+// @Description Add a registrar to the office. The response carries the registrar's API token, which is shown
+// @Description only once. Admins only.
+// @Tags office
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "office ID"
+// @Param request body CreateRegistrarRequest true "registrar data"
+// @Success 201 {object} RegistrarEnvelope "Created registrar with token"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not an admin"
+// @Failure 404 {object} map[string]interface{} "Office not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Username taken"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/office/{id}/registrars [post]
+func (h *Handlers) CreateRegistrar(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	var req CreateRegistrarRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, token, err := h.service.CreateRegistrar(r.Context(), CreateRegistrarParams{
+		OfficeID: id,
+		FullName: req.FullName,
+		Username: req.Username,
+		Role:     req.Role,
+	})
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	resp := NewRegistrarResponse(*result)
+	resp.Token = token
+	w.Header().Set("Cache-Control", "no-store")
+	h.write(w, http.StatusCreated, RegistrarEnvelope{
+		Message: "registrar created successfully; store the token now, it is not shown again",
+		Data:    resp,
+	})
+}
+
+// ListRegistrars lists an office's registrars
+// @Summary List registrars
+// @Description List the registrars of an office. Admins only.
+// @Tags office
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "office ID"
+// @Success 200 {object} RegistrarListEnvelope "Registrars"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not an admin"
+// @Failure 404 {object} map[string]interface{} "Office not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/office/{id}/registrars [get]
+func (h *Handlers) ListRegistrars(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.ListRegistrars(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, RegistrarListEnvelope{
+		Count: len(result),
+		Data:  NewRegistrarResponses(result),
+	})
+}
+
+// SetRegistrarActive suspends or restores a registrar
+// @Summary Suspend or restore registrar
+// @Description A suspended registrar cannot sign in. Admins only.
+// @Tags office
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param registrarID path string true "registrar ID"
+// @Param request body SetActiveRequest true "new state"
+// @Success 200 {object} RegistrarEnvelope "Updated registrar"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not an admin"
+// @Failure 404 {object} map[string]interface{} "Registrar not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/office/registrars/{registrarID}/active [put]
+func (h *Handlers) SetRegistrarActive(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "registrarID")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	var req SetActiveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.SetRegistrarActive(r.Context(), id, req.Active)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, RegistrarEnvelope{
+		Message: "registrar updated successfully",
+		Data:    NewRegistrarResponse(*result),
+	})
+}
+
+// RotateRegistrarToken issues a new token for a registrar
+// @Summary Rotate registrar token
+// @Description Replace the registrar's API token; the old one stops working at once. Admins only.
+// @Tags office
+// @Produce json
+// @Security BearerAuth
+// @Param registrarID path string true "registrar ID"
+// @Success 200 {object} RegistrarEnvelope "Registrar with new token"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not an admin"
+// @Failure 404 {object} map[string]interface{} "Registrar not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/office/registrars/{registrarID}/token [post]
+func (h *Handlers) RotateRegistrarToken(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "registrarID")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, token, err := h.service.RotateRegistrarToken(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	resp := NewRegistrarResponse(*result)
+	resp.Token = token
+	w.Header().Set("Cache-Control", "no-store")
+	h.write(w, http.StatusOK, RegistrarEnvelope{
+		Message: "token rotated successfully; store the token now, it is not shown again",
+		Data:    resp,
+	})
+}
+
+// AddJurisdiction allows an office to register a kind of event
+// @Summary Add jurisdiction rule
+// @Description Allow the office to register births, marriages or deaths, anywhere or only at place (compared
+// @Description without case and diacritics). Admins only.
+// @Tags office
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "office ID"
+// @Param request body AddJurisdictionRequest true "rule"
+// @Success 201 {object} JurisdictionEnvelope "Created rule"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not an admin"
+// @Failure 404 {object} map[string]interface{} "Office not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Rule exists"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/office/{id}/jurisdictions [post]
+func (h *Handlers) AddJurisdiction(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	var req AddJurisdictionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.AddJurisdiction(r.Context(), repository.CreateJurisdictionParams{
+		OfficeID:  id,
+		EventKind: req.EventKind,
+		Place:     request.Text(req.Place),
+	})
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusCreated, JurisdictionEnvelope{
+		Message: "jurisdiction rule added successfully",
+		Data:    NewJurisdictionResponse(*result),
+	})
+}
+
+// RemoveJurisdiction removes a jurisdiction rule
+// @Summary Remove jurisdiction rule
+// @Description Stop the office from registering what the rule allowed. Admins only.
+// @Tags office
+// @Security BearerAuth
+// @Param id path string true "office ID"
+// @Param jurisdictionID path string true "rule ID"
+// @Success 204 "Rule removed"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not an admin"
+// @Failure 404 {object} map[string]interface{} "Rule not found"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/office/{id}/jurisdictions/{jurisdictionID} [delete]
+func (h *Handlers) RemoveJurisdiction(w http.ResponseWriter, r *http.Request) {
+	officeID, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+	id, err := request.UUIDParam(r, "jurisdictionID")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	if err := h.service.RemoveJurisdiction(r.Context(), officeID, id); err != nil {
+		h.serviceError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Me describes the authenticated caller
+// @Summary Current registrar
+// @Description Get the signed-in registrar and their office
+// @Tags office
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} PrincipalEnvelope "Caller"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Router /api/office/me [get]
+func (h *Handlers) Me(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	p, err := h.service.Me(r.Context())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.write(w, http.StatusOK, PrincipalEnvelope{Data: NewPrincipalResponse(p)})
+}
+
+func (h *Handlers) write(w http.ResponseWriter, status int, body any) {
+	if err := render.Write(w, status, render.ContentTypeJSON, body); err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// serviceError maps a service failure onto an HTTP status.
+func (h *Handlers) serviceError(w http.ResponseWriter, err error) {
+	status, msg := apperr.Status(err)
+	if status >= http.StatusInternalServerError {
+		h.logger.Errorf("Service error: %v", err)
+	}
+	if status == http.StatusNotFound && msg == "not found" {
+		msg = "office or registrar not found"
+	}
+	http.Error(w, msg, status)
+}
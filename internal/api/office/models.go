@@ -0,0 +1,163 @@
+package office
+
+import (
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+)
+
+// OfficeResponse is the wire form of repository.Office.
+type OfficeResponse struct {
+	ID           uuid.UUID `json:"id"`
+	Code         string    `json:"code"`
+	Name         string    `json:"name"`
+	Municipality string    `json:"municipality"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func NewOfficeResponse(row repository.Office) OfficeResponse {
+	return OfficeResponse{
+		ID:           row.ID,
+		Code:         row.Code,
+		Name:         row.Name,
+		Municipality: row.Municipality,
+		Active:       row.Active,
+		CreatedAt:    row.CreatedAt,
+	}
+}
+
+func NewOfficeResponses(rows []repository.Office) []OfficeResponse {
+	items := make([]OfficeResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, NewOfficeResponse(row))
+	}
+	return items
+}
+
+// JurisdictionResponse is the wire form of repository.OfficeJurisdiction.
+// A missing place allows the event anywhere.
+type JurisdictionResponse struct {
+	ID        uuid.UUID `json:"id"`
+	EventKind string    `json:"event_kind"`
+	Place     *string   `json:"place"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func NewJurisdictionResponse(row repository.OfficeJurisdiction) JurisdictionResponse {
+	return JurisdictionResponse{
+		ID:        row.ID,
+		EventKind: row.EventKind,
+		Place:     render.Nullable(row.Place.String, row.Place.Valid),
+		CreatedAt: row.CreatedAt,
+	}
+}
+
+// OfficeDetailsResponse is an office with its jurisdiction rules.
+type OfficeDetailsResponse struct {
+	OfficeResponse
+	Jurisdictions []JurisdictionResponse `json:"jurisdictions"`
+}
+
+func NewOfficeDetailsResponse(details OfficeDetails) OfficeDetailsResponse {
+	resp := OfficeDetailsResponse{
+		OfficeResponse: NewOfficeResponse(details.Office),
+		Jurisdictions:  make([]JurisdictionResponse, 0, len(details.Jurisdictions)),
+	}
+	for _, row := range details.Jurisdictions {
+		resp.Jurisdictions = append(resp.Jurisdictions, NewJurisdictionResponse(row))
+	}
+	return resp
+}
+
+// RegistrarResponse is the wire form of repository.Registrar. The token
+// is only present when it was just created.
+type RegistrarResponse struct {
+	ID        uuid.UUID `json:"id"`
+	OfficeID  uuid.UUID `json:"office_id"`
+	FullName  string    `json:"full_name"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	Token     string    `json:"token,omitempty"`
+}
+
+func NewRegistrarResponse(row repository.Registrar) RegistrarResponse {
+	return RegistrarResponse{
+		ID:        row.ID,
+		OfficeID:  row.OfficeID,
+		FullName:  row.FullName,
+		Username:  row.Username,
+		Role:      row.Role,
+		Active:    row.Active,
+		CreatedAt: row.CreatedAt,
+	}
+}
+
+func NewRegistrarResponses(rows []repository.Registrar) []RegistrarResponse {
+	items := make([]RegistrarResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, NewRegistrarResponse(row))
+	}
+	return items
+}
+
+// PrincipalResponse describes the authenticated caller. The bootstrap
+// admin has neither registrar nor office.
+type PrincipalResponse struct {
+	Role      string             `json:"role"`
+	Registrar *RegistrarResponse `json:"registrar,omitempty"`
+	Office    *OfficeResponse    `json:"office,omitempty"`
+}
+
+func NewPrincipalResponse(p *auth.Principal) PrincipalResponse {
+	resp := PrincipalResponse{Role: p.Role}
+	if p.Registrar != nil {
+		registrar := NewRegistrarResponse(*p.Registrar)
+		resp.Registrar = &registrar
+	}
+	if p.Office != nil {
+		o := NewOfficeResponse(*p.Office)
+		resp.Office = &o
+	}
+	return resp
+}
+
+// OfficeEnvelope is the response body for a single office.
+type OfficeEnvelope struct {
+	Message string                `json:"message,omitempty"`
+	Data    OfficeDetailsResponse `json:"data"`
+}
+
+// OfficeListEnvelope is the response body for the list of offices.
+type OfficeListEnvelope struct {
+	Count int              `json:"count"`
+	Data  []OfficeResponse `json:"data"`
+}
+
+// RegistrarEnvelope is the response body for a single registrar.
+type RegistrarEnvelope struct {
+	Message string            `json:"message,omitempty"`
+	Data    RegistrarResponse `json:"data"`
+}
+
+// RegistrarListEnvelope is the response body for an office's registrars.
+type RegistrarListEnvelope struct {
+	Count int                 `json:"count"`
+	Data  []RegistrarResponse `json:"data"`
+}
+
+// JurisdictionEnvelope is the response body for a single jurisdiction rule.
+type JurisdictionEnvelope struct {
+	Message string               `json:"message,omitempty"`
+	Data    JurisdictionResponse `json:"data"`
+}
+
+// PrincipalEnvelope is the response body for the authenticated caller.
+type PrincipalEnvelope struct {
+	Data PrincipalResponse `json:"data"`
+}
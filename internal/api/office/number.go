@@ -0,0 +1,49 @@
+package office
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+)
+
+// maxRegistryNumber is the last number an office can give records of one
+// kind in a year.
+const maxRegistryNumber = 999999
+
+var registryNumberPattern = regexp.MustCompile(`^[A-Z]{3}-[0-9]{4}-[0-9]{6}$`)
+
+// NextRegistryNumber assigns the registry number of a record of the given
+// kind that the registrar's office makes in year, e.g. VIL-2026-000123.
+// Call it inside the transaction that creates the record, just before the
+// insert: the office's counter stays locked until the transaction ends,
+// so concurrent registrations wait their turn and a rolled-back one leaves
+// no gap.
+func NextRegistryNumber(ctx context.Context, q *repository.Queries, p *auth.Principal, kind string, year int) (string, error) {
+	n, err := q.NextRegistryNumber(ctx, repository.NextRegistryNumberParams{
+		OfficeID:   p.Office.ID,
+		RecordKind: kind,
+		Year:       int32(year),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed NextRegistryNumber: %w", err)
+	}
+	if n > maxRegistryNumber {
+		return "", apperr.Conflict("office %s has no %s registry numbers left for %d", p.Office.Code, kind, year)
+	}
+	return fmt.Sprintf("%s-%04d-%06d", p.Office.Code, year, n), nil
+}
+
+// NormalizeRegistryNumber upper-cases a registry number and checks its
+// format.
+func NormalizeRegistryNumber(number string) (string, error) {
+	number = strings.ToUpper(strings.TrimSpace(number))
+	if !registryNumberPattern.MatchString(number) {
+		return "", apperr.Invalid("registry number must look like VIL-2026-000123")
+	}
+	return number, nil
+}
@@ -0,0 +1,29 @@
+package office
+
+import (
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func OfficeRouter(queries *repository.Queries, log *zap.SugaredLogger) chi.Router {
+	r := chi.NewRouter()
+
+	service := NewService(queries, log)
+	handlers := NewHandlers(service, log)
+
+	r.Post("/", telemetry.InstrumentHandler("office", "CreateOffice", handlers.CreateOffice))
+	r.Get("/", telemetry.InstrumentHandler("office", "ListOffices", handlers.ListOffices))
+	r.Get("/me", telemetry.InstrumentHandler("office", "Me", handlers.Me))
+	r.Put("/registrars/{registrarID}/active", telemetry.InstrumentHandler("office", "SetRegistrarActive", handlers.SetRegistrarActive))
+	r.Post("/registrars/{registrarID}/token", telemetry.InstrumentHandler("office", "RotateRegistrarToken", handlers.RotateRegistrarToken))
+	r.Get("/{id}", telemetry.InstrumentHandler("office", "GetOffice", handlers.GetOffice))
+	r.Put("/{id}/active", telemetry.InstrumentHandler("office", "SetOfficeActive", handlers.SetOfficeActive))
+	r.Post("/{id}/registrars", telemetry.InstrumentHandler("office", "CreateRegistrar", handlers.CreateRegistrar))
+	r.Get("/{id}/registrars", telemetry.InstrumentHandler("office", "ListRegistrars", handlers.ListRegistrars))
+	r.Post("/{id}/jurisdictions", telemetry.InstrumentHandler("office", "AddJurisdiction", handlers.AddJurisdiction))
+	r.Delete("/{id}/jurisdictions/{jurisdictionID}", telemetry.InstrumentHandler("office", "RemoveJurisdiction", handlers.RemoveJurisdiction))
+
+	return r
+}
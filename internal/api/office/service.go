@@ -0,0 +1,316 @@
+package office
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Kinds of events an office can be allowed to register.
+const (
+	EventBirth    = "birth"
+	EventMarriage = "marriage"
+	EventDeath    = "death"
+)
+
+var (
+	codePattern     = regexp.MustCompile(`^[A-Z]{3}$`)
+	usernamePattern = regexp.MustCompile(`^[a-z0-9._-]{3,}$`)
+)
+
+type Service struct {
+	repo   *repository.Queries
+	logger *zap.SugaredLogger
+}
+
+func NewService(repo *repository.Queries, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// CreateRegistrarParams describes a registrar account. An empty Role makes
+// a plain registrar.
+type CreateRegistrarParams struct {
+	OfficeID uuid.UUID
+	FullName string
+	Username string
+	Role     string
+}
+
+// OfficeDetails is an office with the events it may register.
+type OfficeDetails struct {
+	Office        repository.Office
+	Jurisdictions []repository.OfficeJurisdiction
+}
+
+// Authorize returns the registrar making a record of the given kind of
+// event, after checking that their office may register it at place. Place
+// is NULL for events that take place at the office.
+func Authorize(ctx context.Context, q *repository.Queries, kind string, place pgtype.Text) (*auth.Principal, error) {
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := q.OfficeHasJurisdiction(ctx, repository.OfficeHasJurisdictionParams{
+		OfficeID:  p.Office.ID,
+		EventKind: kind,
+		Place:     place,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed OfficeHasJurisdiction: %w", err)
+	}
+	if !allowed {
+		if place.Valid {
+			return nil, apperr.Forbidden("office %s may not register %s events in %s", p.Office.Code, kind, place.String)
+		}
+		return nil, apperr.Forbidden("office %s may not register %s events", p.Office.Code, kind)
+	}
+	return p, nil
+}
+
+// RecordedBy converts the principal's office and registrar into the
+// optional references stored on records.
+func RecordedBy(p *auth.Principal) (officeID, registrarID pgtype.UUID) {
+	return pgtype.UUID{Bytes: p.Office.ID, Valid: true}, pgtype.UUID{Bytes: p.Registrar.ID, Valid: true}
+}
+
+func (s *Service) CreateOffice(ctx context.Context, arg repository.CreateOfficeParams) (_ *repository.Office, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "office", "CreateOffice")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	arg.Code = strings.ToUpper(strings.TrimSpace(arg.Code))
+	arg.Name = strings.TrimSpace(arg.Name)
+	arg.Municipality = strings.TrimSpace(arg.Municipality)
+	if !codePattern.MatchString(arg.Code) {
+		return nil, apperr.Invalid("code must be three letters, e.g. VIL")
+	}
+	if arg.Name == "" || arg.Municipality == "" {
+		return nil, apperr.Invalid("name and municipality are required")
+	}
+
+	result, err := s.repo.CreateOffice(ctx, arg)
+	if apperr.IsUniqueViolation(err, "office_code_key") {
+		return nil, apperr.Conflict("office code %s is already taken", arg.Code)
+	}
+	if err != nil {
+		s.logger.Errorf("Failed CreateOffice: %v", err)
+		return nil, fmt.Errorf("failed CreateOffice: %w", err)
+	}
+	return &result, nil
+}
+
+func (s *Service) ListOffices(ctx context.Context) (_ []repository.Office, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "office", "ListOffices")
+	defer func() { op.End(err) }()
+
+	result, err := s.repo.ListOffices(ctx)
+	if err != nil {
+		s.logger.Errorf("Failed ListOffices: %v", err)
+		return nil, fmt.Errorf("failed ListOffices: %w", err)
+	}
+	return result, nil
+}
+
+func (s *Service) GetOffice(ctx context.Context, id uuid.UUID) (_ *OfficeDetails, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "office", "GetOffice")
+	defer func() { op.End(err) }()
+
+	o, err := s.repo.GetOfficeByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetOfficeByID: %w", err)
+	}
+	jurisdictions, err := s.repo.ListJurisdictionsForOffice(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed ListJurisdictionsForOffice: %w", err)
+	}
+	return &OfficeDetails{Office: o, Jurisdictions: jurisdictions}, nil
+}
+
+// SetOfficeActive closes or reopens an office. Registrars of a closed
+// office cannot sign in.
+func (s *Service) SetOfficeActive(ctx context.Context, id uuid.UUID, active bool) (_ *repository.Office, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "office", "SetOfficeActive")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.SetOfficeActive(ctx, repository.SetOfficeActiveParams{ID: id, Active: active})
+	if err != nil {
+		return nil, fmt.Errorf("failed SetOfficeActive: %w", err)
+	}
+	return &result, nil
+}
+
+// CreateRegistrar adds a registrar to an office and returns their API
+// token. Only its hash is stored, so the token cannot be shown again.
+func (s *Service) CreateRegistrar(ctx context.Context, arg CreateRegistrarParams) (_ *repository.Registrar, token string, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "office", "CreateRegistrar")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, "", err
+	}
+	arg.FullName = strings.TrimSpace(arg.FullName)
+	arg.Username = strings.ToLower(strings.TrimSpace(arg.Username))
+	if arg.Role == "" {
+		arg.Role = auth.RoleRegistrar
+	}
+	if arg.FullName == "" {
+		return nil, "", apperr.Invalid("full_name is required")
+	}
+	if !usernamePattern.MatchString(arg.Username) {
+		return nil, "", apperr.Invalid("username must be at least 3 of a-z, 0-9, '.', '_' and '-'")
+	}
+	if arg.Role != auth.RoleRegistrar && arg.Role != auth.RoleSupervisor && arg.Role != auth.RoleAdmin {
+		return nil, "", apperr.Invalid("role must be %q, %q or %q", auth.RoleRegistrar, auth.RoleSupervisor, auth.RoleAdmin)
+	}
+
+	// Resolve the office first so a bad ID is a 404 rather than a foreign key error
+	if _, err := s.repo.GetOfficeByID(ctx, arg.OfficeID); err != nil {
+		return nil, "", fmt.Errorf("failed GetOfficeByID: %w", err)
+	}
+
+	token, err = auth.NewToken()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	result, err := s.repo.CreateRegistrar(ctx, repository.CreateRegistrarParams{
+		OfficeID:  arg.OfficeID,
+		FullName:  arg.FullName,
+		Username:  arg.Username,
+		Role:      arg.Role,
+		TokenHash: auth.HashToken(token),
+	})
+	if apperr.IsUniqueViolation(err, "registrar_username_key") {
+		return nil, "", apperr.Conflict("username %s is already taken", arg.Username)
+	}
+	if err != nil {
+		s.logger.Errorf("Failed CreateRegistrar: %v", err)
+		return nil, "", fmt.Errorf("failed CreateRegistrar: %w", err)
+	}
+
+	s.logger.Infof("CreateRegistrar completed successfully with ID: %s", result.ID)
+	return &result, token, nil
+}
+
+func (s *Service) ListRegistrars(ctx context.Context, officeID uuid.UUID) (_ []repository.Registrar, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "office", "ListRegistrars")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetOfficeByID(ctx, officeID); err != nil {
+		return nil, fmt.Errorf("failed GetOfficeByID: %w", err)
+	}
+	result, err := s.repo.ListRegistrarsForOffice(ctx, officeID)
+	if err != nil {
+		s.logger.Errorf("Failed ListRegistrarsForOffice: %v", err)
+		return nil, fmt.Errorf("failed ListRegistrarsForOffice: %w", err)
+	}
+	return result, nil
+}
+
+// SetRegistrarActive suspends or restores a registrar's access.
+func (s *Service) SetRegistrarActive(ctx context.Context, id uuid.UUID, active bool) (_ *repository.Registrar, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "office", "SetRegistrarActive")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	result, err := s.repo.SetRegistrarActive(ctx, repository.SetRegistrarActiveParams{ID: id, Active: active})
+	if err != nil {
+		return nil, fmt.Errorf("failed SetRegistrarActive: %w", err)
+	}
+	return &result, nil
+}
+
+// RotateRegistrarToken replaces a registrar's token, e.g. after it leaked.
+func (s *Service) RotateRegistrarToken(ctx context.Context, id uuid.UUID) (_ *repository.Registrar, token string, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "office", "RotateRegistrarToken")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, "", err
+	}
+	token, err = auth.NewToken()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	result, err := s.repo.SetRegistrarToken(ctx, repository.SetRegistrarTokenParams{ID: id, TokenHash: auth.HashToken(token)})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed SetRegistrarToken: %w", err)
+	}
+	return &result, token, nil
+}
+
+// AddJurisdiction allows the office to register a kind of event, anywhere
+// when place is NULL.
+func (s *Service) AddJurisdiction(ctx context.Context, arg repository.CreateJurisdictionParams) (_ *repository.OfficeJurisdiction, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "office", "AddJurisdiction")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if arg.EventKind != EventBirth && arg.EventKind != EventMarriage && arg.EventKind != EventDeath {
+		return nil, apperr.Invalid("event_kind must be %q, %q or %q", EventBirth, EventMarriage, EventDeath)
+	}
+	arg.Place.String = strings.TrimSpace(arg.Place.String)
+	arg.Place.Valid = arg.Place.Valid && arg.Place.String != ""
+
+	if _, err := s.repo.GetOfficeByID(ctx, arg.OfficeID); err != nil {
+		return nil, fmt.Errorf("failed GetOfficeByID: %w", err)
+	}
+	result, err := s.repo.CreateJurisdiction(ctx, arg)
+	if apperr.IsUniqueViolation(err, "office_jurisdiction_rule_idx") {
+		return nil, apperr.Conflict("the office already has this rule")
+	}
+	if err != nil {
+		s.logger.Errorf("Failed CreateJurisdiction: %v", err)
+		return nil, fmt.Errorf("failed CreateJurisdiction: %w", err)
+	}
+	return &result, nil
+}
+
+func (s *Service) RemoveJurisdiction(ctx context.Context, officeID, id uuid.UUID) (err error) {
+	ctx, op := telemetry.StartOperation(ctx, "office", "RemoveJurisdiction")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireAdmin(ctx); err != nil {
+		return err
+	}
+	n, err := s.repo.DeleteJurisdiction(ctx, repository.DeleteJurisdictionParams{ID: id, OfficeID: officeID})
+	if err != nil {
+		return fmt.Errorf("failed DeleteJurisdiction: %w", err)
+	}
+	if n == 0 {
+		return apperr.NotFound("jurisdiction rule %s not found", id)
+	}
+	return nil
+}
+
+// Me returns the authenticated caller.
+func (s *Service) Me(ctx context.Context) (*auth.Principal, error) {
+	p := auth.FromContext(ctx)
+	if p == nil {
+		return nil, apperr.Unauthorized("not signed in")
+	}
+	return p, nil
+}
@@ -0,0 +1,244 @@
+package person
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// DefaultDuplicateScore is the lowest score reported when the caller
+	// does not pick one.
+	DefaultDuplicateScore = 60
+
+	defaultDuplicateLimit = 50
+	maxDuplicateLimit     = 200
+
+	// maxCandidatePairs bounds how many pairs the database pre-selects for
+	// scoring in one request.
+	maxCandidatePairs = 1000
+)
+
+// nameFolder mirrors the fold_name SQL function.
+var nameFolder = strings.NewReplacer(
+	"Ą", "a", "Č", "c", "Ę", "e", "Ė", "e", "Į", "i", "Š", "s", "Ų", "u", "Ū", "u", "Ž", "z",
+	"ą", "a", "č", "c", "ę", "e", "ė", "e", "į", "i", "š", "s", "ų", "u", "ū", "u", "ž", "z",
+)
+
+// FoldName lower-cases a name and folds Lithuanian letters to their base
+// letters, the way duplicate detection compares names.
+func FoldName(name string) string {
+	return strings.ToLower(nameFolder.Replace(strings.TrimSpace(name)))
+}
+
+// DuplicateParams selects duplicate candidates. A zero PersonID searches the
+// whole register; zero MinScore and Limit select the defaults.
+type DuplicateParams struct {
+	PersonID uuid.UUID
+	MinScore int
+	Limit    int32
+}
+
+// DuplicateCandidate is a pair of persons that may be the same person.
+// Score runs from 0 to 100; Reasons say what matched or differed.
+type DuplicateCandidate struct {
+	Person    repository.Person
+	Candidate repository.Person
+	Score     int
+	Reasons   []string
+}
+
+// FindDuplicates scores the pairs the database pre-selects (same birth date
+// and a shared name, or both names shared) on names, birth date, birth
+// place, sex, parents and personal code, and returns those reaching
+// MinScore, best first.
+func (s *Service) FindDuplicates(ctx context.Context, arg DuplicateParams) (_ []DuplicateCandidate, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "person", "FindDuplicates")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	if arg.MinScore < 0 || arg.MinScore > 100 {
+		return nil, apperr.Invalid("min_score must be between 0 and 100")
+	}
+	if arg.MinScore == 0 {
+		arg.MinScore = DefaultDuplicateScore
+	}
+	if arg.Limit < 0 || arg.Limit > maxDuplicateLimit {
+		return nil, apperr.Invalid("limit must be between 1 and %d", maxDuplicateLimit)
+	}
+	if arg.Limit == 0 {
+		arg.Limit = defaultDuplicateLimit
+	}
+
+	pairs, err := s.repo.ListDuplicateCandidates(ctx, repository.ListDuplicateCandidatesParams{
+		PersonID: pgtype.UUID{Bytes: arg.PersonID, Valid: arg.PersonID != uuid.Nil},
+		Limit:    maxCandidatePairs,
+	})
+	if err != nil {
+		s.logger.Errorf("Failed ListDuplicateCandidates: %v", err)
+		return nil, fmt.Errorf("failed ListDuplicateCandidates: %w", err)
+	}
+	if len(pairs) == 0 {
+		return nil, nil
+	}
+
+	var ids []uuid.UUID
+	for _, pair := range pairs {
+		ids = append(ids, pair.PersonID, pair.CandidateID)
+	}
+	persons, err := s.repo.ListPersonsByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed ListPersonsByIDs: %w", err)
+	}
+	// Compare legal parents: after an adoption the birth parents are sealed
+	// and must not show through as matching or differing
+	parentage, err := s.repo.ListLegalParentage(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed ListLegalParentage: %w", err)
+	}
+	byID := make(map[uuid.UUID]repository.Person, len(persons))
+	for _, p := range persons {
+		byID[p.ID] = p
+	}
+	parents := make(map[uuid.UUID]repository.LegalParentage, len(parentage))
+	for _, p := range parentage {
+		parents[p.PersonID] = p
+	}
+
+	var result []DuplicateCandidate
+	for _, pair := range pairs {
+		a, b := byID[pair.PersonID], byID[pair.CandidateID]
+		score, reasons := scoreDuplicate(a, b, parents[a.ID], parents[b.ID])
+		if score >= arg.MinScore {
+			result = append(result, DuplicateCandidate{Person: a, Candidate: b, Score: score, Reasons: reasons})
+		}
+	}
+	slices.SortStableFunc(result, func(x, y DuplicateCandidate) int {
+		return cmp.Compare(y.Score, x.Score)
+	})
+	if len(result) > int(arg.Limit) {
+		result = result[:arg.Limit]
+	}
+	return result, nil
+}
+
+// scoreDuplicate rates how likely a and b are the same person. Either
+// parentage may be the zero value when the person has no recorded parents.
+func scoreDuplicate(a, b repository.Person, parentsA, parentsB repository.LegalParentage) (int, []string) {
+	score := 0
+	var reasons []string
+	add := func(points int, reason string) {
+		score += points
+		reasons = append(reasons, reason)
+	}
+
+	for _, name := range []struct{ label, a, b string }{
+		{"first name", a.FirstName, b.FirstName},
+		{"last name", a.LastName, b.LastName},
+	} {
+		switch foldedA, foldedB := FoldName(name.a), FoldName(name.b); {
+		case foldedA == foldedB:
+			add(25, "same "+name.label)
+		case editDistance(foldedA, foldedB) <= 2:
+			add(15, "similar "+name.label)
+		}
+	}
+
+	switch {
+	case a.BirthDate.Time.Equal(b.BirthDate.Time):
+		add(30, "same birth date")
+	case similarDate(a.BirthDate.Time.Format("20060102"), b.BirthDate.Time.Format("20060102")):
+		add(10, "similar birth date")
+	}
+
+	if a.BirthPlace.Valid && b.BirthPlace.Valid && FoldName(a.BirthPlace.String) == FoldName(b.BirthPlace.String) {
+		add(5, "same birth place")
+	}
+	if a.Sex != b.Sex {
+		add(-20, "different sex")
+	}
+
+	for _, parent := range []struct {
+		label string
+		a, b  pgtype.UUID
+	}{
+		{"mother", parentsA.MotherID, parentsB.MotherID},
+		{"father", parentsA.FatherID, parentsB.FatherID},
+	} {
+		if !parent.a.Valid || !parent.b.Valid {
+			continue
+		}
+		if parent.a.Bytes == parent.b.Bytes {
+			add(10, "same "+parent.label)
+		} else {
+			add(-25, "different "+parent.label)
+		}
+	}
+
+	if differingDigits(a.PersonalCode, b.PersonalCode) == 1 {
+		add(10, "personal codes differ in one digit")
+	}
+
+	return min(max(score, 0), 100), reasons
+}
+
+// similarDate reports dates (as YYYYMMDD) one typing slip apart: one
+// differing digit or two adjacent digits swapped.
+func similarDate(a, b string) bool {
+	if differingDigits(a, b) == 1 {
+		return true
+	}
+	for i := 0; i+1 < len(a); i++ {
+		if a[i] != b[i] {
+			return a[i] == b[i+1] && a[i+1] == b[i] && a[i+2:] == b[i+2:]
+		}
+	}
+	return false
+}
+
+func differingDigits(a, b string) int {
+	if len(a) != len(b) {
+		return -1
+	}
+	n := 0
+	for i := range len(a) {
+		if a[i] != b[i] {
+			n++
+		}
+	}
+	return n
+}
+
+// editDistance is the Levenshtein distance between a and b in runes.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,736 @@
+package person
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/request"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	service *Service
+	logger  *zap.SugaredLogger
+}
+
+func NewHandlers(service *Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type CreatePersonRequest struct {
+	PersonalCode string      `json:"personal_code" example:"39001010000"`
+	FirstName    string      `json:"first_name" example:"Jonas"`
+	LastName     string      `json:"last_name" example:"Jonaitis"`
+	BirthDate    render.Date `json:"birth_date" swaggertype:"string" format:"date" example:"1990-01-01"`
+	BirthPlace   *string     `json:"birth_place,omitempty" example:"Vilnius"`
+	Sex          string      `json:"sex" enums:"male,female" example:"male"`
+	Citizenship  string      `json:"citizenship,omitempty" example:"LT"`
+}
+
+// Params converts the request into repository parameters.
+func (req CreatePersonRequest) Params() repository.CreatePersonParams {
+	return repository.CreatePersonParams{
+		PersonalCode: req.PersonalCode,
+		FirstName:    req.FirstName,
+		LastName:     req.LastName,
+		BirthDate:    request.Date(req.BirthDate),
+		BirthPlace:   request.Text(req.BirthPlace),
+		Sex:          req.Sex,
+		Citizenship:  req.Citizenship,
+	}
+}
+
+type UpdatePersonRequest struct {
+	FirstName     string      `json:"first_name" example:"Jonas"`
+	LastName      string      `json:"last_name" example:"Jonaitis"`
+	BirthPlace    *string     `json:"birth_place,omitempty" example:"Vilnius"`
+	Citizenship   string      `json:"citizenship" example:"LT"`
+	LegalBasis    string      `json:"legal_basis" example:"Name change decision No. 12-345"`
+	EffectiveDate render.Date `json:"effective_date,omitempty" swaggertype:"string" format:"date" example:"2024-05-01"`
+}
+
+// Params converts the request into repository parameters and the
+// amendment recorded in the person's history.
+func (req UpdatePersonRequest) Params(id uuid.UUID) (repository.UpdatePersonParams, Amendment) {
+	arg := repository.UpdatePersonParams{
+		ID:          id,
+		FirstName:   req.FirstName,
+		LastName:    req.LastName,
+		BirthPlace:  request.Text(req.BirthPlace),
+		Citizenship: req.Citizenship,
+	}
+	return arg, Amendment{
+		LegalBasis:    req.LegalBasis,
+		EffectiveDate: request.Date(req.EffectiveDate),
+	}
+}
+
+type MergeRequest struct {
+	DuplicateID uuid.UUID `json:"duplicate_id" example:"9b2f6c1e-6d0a-4c55-9a51-3f1d2b7c8e90"`
+	Reason      string    `json:"reason" example:"Same person registered twice after a foreign birth registration"`
+}
+
+// Params converts the request into merge parameters.
+func (req MergeRequest) Params(survivorID uuid.UUID) MergeParams {
+	return MergeParams{
+		SurvivorID:  survivorID,
+		DuplicateID: req.DuplicateID,
+		Reason:      req.Reason,
+	}
+}
+
+type AddAddressRequest struct {
+	Kind       string `json:"kind" enums:"residence,correspondence" example:"residence"`
+	Line       string `json:"line" example:"Gedimino pr. 1-2"`
+	City       string `json:"city" example:"Vilnius"`
+	PostalCode string `json:"postal_code,omitempty" example:"LT-01103"`
+	Country    string `json:"country,omitempty" example:"LT"`
+}
+
+// CreatePerson registers a new person
+// @Summary Create person
+// @Description Register a new person in the civil registry. The personal code must have a valid check digit and
+// @Description encode the given birth_date and sex. The signed-in registrar and their office are recorded.
+// @Tags person
+// @Accept json
+// @Produce json,xml
+// @Security BearerAuth
+// @Param request body CreatePersonRequest true "person data"
+// @Success 201 {object} PersonEnvelope "Created person"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Personal code already registered"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/person/ [post]
+func (h *Handlers) CreatePerson(w http.ResponseWriter, r *http.Request) {
+	format := render.Negotiate(r, render.ContentTypeJSON, render.ContentTypeXML)
+	if format == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var req CreatePersonRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.CreatePerson(r.Context(), req.Params())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/person/"+result.ID.String())
+	err = render.Write(w, http.StatusCreated, format, PersonEnvelope{
+		Message: "person created successfully",
+		Data:    NewPersonResponse(*result),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// GetPersonByID retrieves a person with their addresses
+// @Summary Get person by ID
+// @Description Get a person and their addresses. Supports If-None-Match and If-Modified-Since.
+// @Description With as_of, returns the person's recorded details on that date instead, without addresses.
+// @Tags person
+// @Produce json,xml
+// @Security BearerAuth
+// @Param id path string true "person ID"
+// @Param as_of query string false "Reconstruct the person on this date (YYYY-MM-DD)"
+// @Param If-None-Match header string false "ETag of a cached copy"
+// @Success 200 {object} PersonEnvelope "person found"
+// @Success 304 "Cached copy is still current"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "person not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/person/{id} [get]
+func (h *Handlers) GetPersonByID(w http.ResponseWriter, r *http.Request) {
+	format := render.Negotiate(r, render.ContentTypeJSON, render.ContentTypeXML)
+	if format == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	asOf, err := request.QueryDate(r, "as_of")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+	if asOf.Valid {
+		h.writePersonAsOf(w, r, format, id, asOf.Time)
+		return
+	}
+
+	result, err := h.service.GetPersonByID(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.writePerson(w, r, format, *result)
+}
+
+// GetPersonByPersonalCode retrieves a person by personal code
+// @Summary Get person by personal code
+// @Description Get a person and their addresses by their 11-digit personal code
+// @Tags person
+// @Produce json,xml
+// @Security BearerAuth
+// @Param code path string true "personal code"
+// @Success 200 {object} PersonEnvelope "person found"
+// @Success 304 "Cached copy is still current"
+// @Failure 400 {object} map[string]interface{} "Invalid personal code"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "person not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/person/by-code/{code} [get]
+func (h *Handlers) GetPersonByPersonalCode(w http.ResponseWriter, r *http.Request) {
+	format := render.Negotiate(r, render.ContentTypeJSON, render.ContentTypeXML)
+	if format == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	result, err := h.service.GetPersonByPersonalCode(r.Context(), chi.URLParam(r, "code"))
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.writePerson(w, r, format, *result)
+}
+
+// SearchPersons searches the registry
+// @Summary Search persons
+// @Description Search persons by name, birth date and status. All filters are optional.
+// @Tags person
+// @Produce json,xml,text/csv
+// @Security BearerAuth
+// @Param name query string false "part of the first or last name"
+// @Param birth_date query string false "birth date (YYYY-MM-DD)"
+// @Param status query string false "alive or deceased"
+// @Param limit query int false "page size (default 50, max 200)"
+// @Param offset query int false "rows to skip"
+// @Success 200 {object} PersonListEnvelope "Matching persons"
+// @Failure 400 {object} map[string]interface{} "Invalid filter"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/person/ [get]
+func (h *Handlers) SearchPersons(w http.ResponseWriter, r *http.Request) {
+	format := render.Negotiate(r, render.ContentTypeJSON, render.ContentTypeXML, render.ContentTypeCSV)
+	if format == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	arg := repository.SearchPersonsParams{
+		Name:   request.QueryText(r, "name"),
+		Status: request.QueryText(r, "status"),
+	}
+	birthDate, err := request.QueryDate(r, "birth_date")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+	arg.BirthDate = birthDate
+	if arg.Limit, arg.Offset, err = request.Page(r); err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.SearchPersons(r.Context(), arg)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	if format == render.ContentTypeCSV {
+		err = render.WriteCSV(w, http.StatusOK, personCSVHeader, NewPersonResponses(result))
+	} else {
+		limit := arg.Limit
+		if limit == 0 {
+			limit = defaultSearchLimit
+		}
+		err = render.Write(w, http.StatusOK, format, PersonListEnvelope{
+			Count:  len(result),
+			Limit:  limit,
+			Offset: arg.Offset,
+			Data:   NewPersonResponses(result),
+		})
+	}
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// UpdatePerson updates a person's mutable details
+// @Summary Update person
+// @Description Update names, birth place and citizenship. Every changed attribute is recorded in the person's
+// @Description history with legal_basis and effective_date (default today). Send If-Match with the current ETag
+// @Description to avoid lost updates.
+// @Tags person
+// @Accept json
+// @Produce json,xml
+// @Security BearerAuth
+// @Param id path string true "person ID"
+// @Param If-Match header string false "ETag the update is based on"
+// @Param request body UpdatePersonRequest true "person data"
+// @Success 200 {object} PersonEnvelope "Updated person"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 404 {object} map[string]interface{} "person not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "person is deceased"
+// @Failure 412 {object} map[string]interface{} "person was modified"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/person/{id} [put]
+func (h *Handlers) UpdatePerson(w http.ResponseWriter, r *http.Request) {
+	format := render.Negotiate(r, render.ContentTypeJSON, render.ContentTypeXML)
+	if format == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	var req UpdatePersonRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if r.Header.Get("If-Match") != "" {
+		current, err := h.service.GetPersonByID(r.Context(), id)
+		if err != nil {
+			h.serviceError(w, err)
+			return
+		}
+		if render.PreconditionFailed(w, r, NewPersonResponse(*current).ETag(), format) {
+			return
+		}
+	}
+
+	arg, amendment := req.Params(id)
+	result, err := h.service.UpdatePerson(r.Context(), arg, amendment)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	resp := NewPersonResponse(*result)
+	w.Header().Set("ETag", render.RepresentationETag(resp.ETag(), format))
+	err = render.Write(w, http.StatusOK, format, PersonEnvelope{
+		Message: "person updated successfully",
+		Data:    resp,
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// AddPersonAddress adds an address to a person
+// @Summary Add address
+// @Description Add a residence or correspondence address to a person. The signed-in registrar and their office are
+// @Description recorded.
+// @Tags person
+// @Accept json
+// @Produce json,xml
+// @Security BearerAuth
+// @Param id path string true "person ID"
+// @Param request body AddAddressRequest true "address data"
+// @Success 201 {object} AddressEnvelope "Created address"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 404 {object} map[string]interface{} "person not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/person/{id}/addresses [post]
+func (h *Handlers) AddPersonAddress(w http.ResponseWriter, r *http.Request) {
+	format := render.Negotiate(r, render.ContentTypeJSON, render.ContentTypeXML)
+	if format == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	var req AddAddressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.AddPersonAddress(r.Context(), repository.AddPersonAddressParams{
+		PersonID:   id,
+		Kind:       req.Kind,
+		Line:       req.Line,
+		City:       req.City,
+		PostalCode: req.PostalCode,
+		Country:    req.Country,
+	})
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	err = render.Write(w, http.StatusCreated, format, AddressEnvelope{
+		Message: "address added successfully",
+		Data:    NewAddressResponse(*result),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// ListPersonAddresses lists a person's addresses
+// @Summary List addresses
+// @Description List all addresses recorded for a person
+// @Tags person
+// @Produce json,xml
+// @Security BearerAuth
+// @Param id path string true "person ID"
+// @Success 200 {object} AddressListEnvelope "Addresses"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/person/{id}/addresses [get]
+func (h *Handlers) ListPersonAddresses(w http.ResponseWriter, r *http.Request) {
+	format := render.Negotiate(r, render.ContentTypeJSON, render.ContentTypeXML)
+	if format == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.ListPersonAddresses(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	err = render.Write(w, http.StatusOK, format, AddressListEnvelope{
+		Count: len(result),
+		Data:  NewAddressResponses(result),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// ListPersonHistory lists the recorded changes to a person
+// @Summary Amendment history
+// @Description List every recorded change to a person's details with its legal basis and effective date,
+// @Description oldest first. Marriages, divorces, annulments and deaths are recorded as well as direct updates.
+// @Tags person
+// @Produce json,xml
+// @Security BearerAuth
+// @Param id path string true "person ID"
+// @Success 200 {object} HistoryListEnvelope "History"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "person not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/person/{id}/history [get]
+func (h *Handlers) ListPersonHistory(w http.ResponseWriter, r *http.Request) {
+	format := render.Negotiate(r, render.ContentTypeJSON, render.ContentTypeXML)
+	if format == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.ListPersonHistory(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	err = render.Write(w, http.StatusOK, format, HistoryListEnvelope{
+		Count: len(result),
+		Data:  NewHistoryResponses(result),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// FindDuplicates lists likely duplicate persons
+// @Summary Duplicate candidates
+// @Description List pairs of persons that may be the same person, best match first. Pairs are scored from 0 to
+// @Description 100 on names (compared without case and Lithuanian diacritics, allowing small typos), birth date,
+// @Description birth place, sex, parents and personal code. Pass person_id to look for duplicates of one person.
+// @Tags person
+// @Produce json,xml
+// @Security BearerAuth
+// @Param person_id query string false "only pairs including this person"
+// @Param min_score query int false "lowest score to report (default 60)"
+// @Param limit query int false "maximum pairs (default 50, max 200)"
+// @Success 200 {object} DuplicateListEnvelope "Duplicate candidates"
+// @Failure 400 {object} map[string]interface{} "Invalid filter"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/person/duplicates [get]
+func (h *Handlers) FindDuplicates(w http.ResponseWriter, r *http.Request) {
+	format := render.Negotiate(r, render.ContentTypeJSON, render.ContentTypeXML)
+	if format == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var arg DuplicateParams
+	var err error
+	if r.URL.Query().Get("person_id") != "" {
+		if arg.PersonID, err = request.QueryUUID(r, "person_id"); err != nil {
+			h.serviceError(w, err)
+			return
+		}
+	}
+	minScore, err := request.QueryInt32(r, "min_score")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+	arg.MinScore = int(minScore)
+	if arg.Limit, err = request.QueryInt32(r, "limit"); err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.FindDuplicates(r.Context(), arg)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	err = render.Write(w, http.StatusOK, format, DuplicateListEnvelope{
+		Count: len(result),
+		Data:  NewDuplicateResponses(result),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// MergePersons merges a duplicate person into this one
+// @Summary Merge duplicate
+// @Description Fold duplicate_id into the person in the path in one transaction. Birth, marriage and death
+// @Description records, certificates, addresses, residence declarations and data subject requests naming the
+// @Description duplicate are moved to the survivor, as are applications and appointments made under the duplicate's
+// @Description personal code. The survivor takes over a death, marriage or restriction of processing only the
+// @Description duplicate had. The duplicate is kept, marked merged_into, and the merge is audited with its last
+// @Description state, the number of moved records and the signed-in registrar.
+// @Tags person
+// @Accept json
+// @Produce json,xml
+// @Security BearerAuth
+// @Param id path string true "surviving person ID"
+// @Param request body MergeRequest true "merge data"
+// @Success 200 {object} MergeEnvelope "Merged persons"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Persons cannot be merged"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/person/{id}/merge [post]
+func (h *Handlers) MergePersons(w http.ResponseWriter, r *http.Request) {
+	format := render.Negotiate(r, render.ContentTypeJSON, render.ContentTypeXML)
+	if format == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	var req MergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.MergePersons(r.Context(), req.Params(id))
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	err = render.Write(w, http.StatusOK, format, MergeEnvelope{
+		Message:   "persons merged successfully",
+		Survivor:  NewPersonResponse(result.Survivor),
+		Duplicate: NewPersonResponse(result.Duplicate),
+		Merge:     NewPersonMergeResponse(result.Merge),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// ListPersonMerges lists the merges a person took part in
+// @Summary Merge history
+// @Description List the merges in which the person survived or was merged away, oldest first
+// @Tags person
+// @Produce json,xml
+// @Security BearerAuth
+// @Param id path string true "person ID"
+// @Success 200 {object} PersonMergeListEnvelope "Merges"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "person not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/person/{id}/merges [get]
+func (h *Handlers) ListPersonMerges(w http.ResponseWriter, r *http.Request) {
+	format := render.Negotiate(r, render.ContentTypeJSON, render.ContentTypeXML)
+	if format == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.ListPersonMerges(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	err = render.Write(w, http.StatusOK, format, PersonMergeListEnvelope{
+		Count: len(result),
+		Data:  NewPersonMergeResponses(result),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// HealthCheck checks the health of the person service
+// @Summary Health check
+// @Description Check if the person service can reach the database
+// @Tags person
+// @Produce json
+// @Success 200 {object} map[string]interface{} "Service is healthy"
+// @Failure 503 {object} map[string]interface{} "Service is unhealthy"
+// @Router /api/person/health [get]
+func (h *Handlers) HealthCheck(w http.ResponseWriter, r *http.Request) {
+	if err := h.service.HealthCheck(r.Context()); err != nil {
+		h.logger.Errorf("Health check failed: %v", err)
+		http.Error(w, "Service unhealthy", http.StatusServiceUnavailable)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "healthy",
+		"service": "person-api",
+		"version": "1.0.0",
+	})
+}
+
+// writePerson renders a single person with their addresses, answering
+// conditional requests from the person's version.
+func (h *Handlers) writePerson(w http.ResponseWriter, r *http.Request, format string, row repository.Person) {
+	resp := NewPersonResponse(row)
+	if render.NotModified(w, r, resp.ETag(), format, resp.LastModified()) {
+		return
+	}
+
+	addresses, err := h.service.ListPersonAddresses(r.Context(), row.ID)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+	resp.Addresses = NewAddressResponses(addresses)
+
+	if err := render.Write(w, http.StatusOK, format, PersonEnvelope{Data: resp}); err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// writePersonAsOf renders the person reconstructed on the given date.
+// Past states change when backdated amendments are recorded, so they carry
+// no validators.
+func (h *Handlers) writePersonAsOf(w http.ResponseWriter, r *http.Request, format string, id uuid.UUID, on time.Time) {
+	row, err := h.service.GetPersonAsOf(r.Context(), id, on)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	resp := NewPersonResponse(*row)
+	asOf := render.Date(on)
+	resp.AsOf = &asOf
+	if err := render.Write(w, http.StatusOK, format, PersonEnvelope{Data: resp}); err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// serviceError maps a service failure onto an HTTP status.
+func (h *Handlers) serviceError(w http.ResponseWriter, err error) {
+	status, msg := apperr.Status(err)
+	if status >= http.StatusInternalServerError {
+		h.logger.Errorf("Service error: %v", err)
+	}
+	if status == http.StatusNotFound && msg == "not found" {
+		msg = "person not found"
+	}
+	http.Error(w, msg, status)
+}
@@ -0,0 +1,153 @@
+package person
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Attributes tracked in person_history.
+const (
+	AttrFirstName     = "first_name"
+	AttrLastName      = "last_name"
+	AttrBirthPlace    = "birth_place"
+	AttrCitizenship   = "citizenship"
+	AttrMaritalStatus = "marital_status"
+	AttrStatus        = "status"
+)
+
+// Amendment says why a change to a person's data was made and from which
+// date it applies.
+type Amendment struct {
+	LegalBasis    string
+	EffectiveDate pgtype.Date
+}
+
+// historyField reads and writes one tracked attribute of a person.
+type historyField struct {
+	name string
+	get  func(p repository.Person) pgtype.Text
+	set  func(p *repository.Person, v pgtype.Text)
+}
+
+var historyFields = []historyField{
+	{
+		name: AttrFirstName,
+		get:  func(p repository.Person) pgtype.Text { return text(p.FirstName) },
+		set:  func(p *repository.Person, v pgtype.Text) { p.FirstName = v.String },
+	},
+	{
+		name: AttrLastName,
+		get:  func(p repository.Person) pgtype.Text { return text(p.LastName) },
+		set:  func(p *repository.Person, v pgtype.Text) { p.LastName = v.String },
+	},
+	{
+		name: AttrBirthPlace,
+		get:  func(p repository.Person) pgtype.Text { return p.BirthPlace },
+		set:  func(p *repository.Person, v pgtype.Text) { p.BirthPlace = v },
+	},
+	{
+		name: AttrCitizenship,
+		get:  func(p repository.Person) pgtype.Text { return text(p.Citizenship) },
+		set:  func(p *repository.Person, v pgtype.Text) { p.Citizenship = v.String },
+	},
+	{
+		name: AttrMaritalStatus,
+		get:  func(p repository.Person) pgtype.Text { return text(p.MaritalStatus) },
+		set:  func(p *repository.Person, v pgtype.Text) { p.MaritalStatus = v.String },
+	},
+	{
+		name: AttrStatus,
+		get:  func(p repository.Person) pgtype.Text { return text(p.Status) },
+		set:  func(p *repository.Person, v pgtype.Text) { p.Status = v.String },
+	},
+}
+
+// RecordAmendment writes a history row for every tracked attribute that
+// differs between before and after. Workflows that change persons inside
+// their own transaction (marriages, deaths) call it with the same queries.
+func RecordAmendment(ctx context.Context, q *repository.Queries, before, after repository.Person, a Amendment) error {
+	if strings.TrimSpace(a.LegalBasis) == "" {
+		return apperr.Invalid("legal_basis is required")
+	}
+	if !a.EffectiveDate.Valid {
+		return apperr.Invalid("effective_date is required")
+	}
+
+	for _, f := range historyFields {
+		old, cur := f.get(before), f.get(after)
+		if old == cur {
+			continue
+		}
+		err := q.CreatePersonHistory(ctx, repository.CreatePersonHistoryParams{
+			PersonID:      after.ID,
+			Attribute:     f.name,
+			OldValue:      old,
+			NewValue:      cur,
+			EffectiveDate: a.EffectiveDate,
+			LegalBasis:    strings.TrimSpace(a.LegalBasis),
+			PersonVersion: after.Version,
+		})
+		if err != nil {
+			return fmt.Errorf("failed CreatePersonHistory: %w", err)
+		}
+	}
+	return nil
+}
+
+// AsOf reconstructs p as it stood on the given date from its history,
+// which must be ordered as ListPersonHistory returns it. Attributes without
+// history keep their current value.
+func AsOf(p repository.Person, history []repository.PersonHistory, on time.Time) repository.Person {
+	for _, f := range historyFields {
+		var (
+			value pgtype.Text
+			found bool
+		)
+		for _, h := range history {
+			if h.Attribute != f.name {
+				continue
+			}
+			if h.EffectiveDate.Time.After(on) {
+				if !found {
+					// Every change came later: the value before the first one
+					value, found = h.OldValue, true
+				}
+				break
+			}
+			value, found = h.NewValue, true
+		}
+		if found {
+			f.set(&p, value)
+		}
+	}
+	return p
+}
+
+// validateAmendment checks an amendment made through the person API
+// against the person it changes. A missing effective date means today.
+func validateAmendment(p repository.Person, a Amendment) (Amendment, error) {
+	a.LegalBasis = strings.TrimSpace(a.LegalBasis)
+	if a.LegalBasis == "" {
+		return a, apperr.Invalid("legal_basis is required")
+	}
+	if !a.EffectiveDate.Valid {
+		a.EffectiveDate = pgtype.Date{Time: time.Now().UTC().Truncate(24 * time.Hour), Valid: true}
+	}
+	if a.EffectiveDate.Time.After(time.Now()) {
+		return a, apperr.Invalid("effective_date must not be in the future")
+	}
+	if a.EffectiveDate.Time.Before(p.BirthDate.Time) {
+		return a, apperr.Invalid("effective_date must not be before the birth date")
+	}
+	return a, nil
+}
+
+func text(s string) pgtype.Text {
+	return pgtype.Text{String: s, Valid: true}
+}
@@ -0,0 +1,367 @@
+package person
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/office"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// marriageActive is marriage.StatusActive; the marriage package imports
+// this one.
+const marriageActive = "active"
+
+// MergeParams names the person that stays and the duplicate folded into it.
+type MergeParams struct {
+	SurvivorID  uuid.UUID
+	DuplicateID uuid.UUID
+	Reason      string
+}
+
+// MergeResult is a completed merge with both persons as they are after it.
+type MergeResult struct {
+	Merge     repository.PersonMerge
+	Survivor  repository.Person
+	Duplicate repository.Person
+}
+
+// repoint moves every reference to the duplicate in one column over to the
+// survivor and reports how many rows it changed.
+type repoint struct {
+	column string
+	run    func(ctx context.Context, q *repository.Queries, survivor, duplicate uuid.UUID) (int64, error)
+}
+
+// repoints lists every column that refers to a person, by ID or, for
+// applications and appointments, by personal code. The duplicate's
+// person_history stays with it: replaying it on the survivor would rewrite
+// the survivor's past names.
+var repoints = []repoint{
+	{"birth_record.person_id", func(ctx context.Context, q *repository.Queries, survivor, duplicate uuid.UUID) (int64, error) {
+		return q.RepointBirthRecordPerson(ctx, repository.RepointBirthRecordPersonParams{SurvivorID: survivor, DuplicateID: duplicate})
+	}},
+	{"birth_record.mother_id", func(ctx context.Context, q *repository.Queries, survivor, duplicate uuid.UUID) (int64, error) {
+		return q.RepointBirthRecordMother(ctx, repository.RepointBirthRecordMotherParams{SurvivorID: pgtype.UUID{Bytes: survivor, Valid: true}, DuplicateID: pgtype.UUID{Bytes: duplicate, Valid: true}})
+	}},
+	{"birth_record.father_id", func(ctx context.Context, q *repository.Queries, survivor, duplicate uuid.UUID) (int64, error) {
+		return q.RepointBirthRecordFather(ctx, repository.RepointBirthRecordFatherParams{SurvivorID: pgtype.UUID{Bytes: survivor, Valid: true}, DuplicateID: pgtype.UUID{Bytes: duplicate, Valid: true}})
+	}},
+	{"marriage.spouse1_id", func(ctx context.Context, q *repository.Queries, survivor, duplicate uuid.UUID) (int64, error) {
+		return q.RepointMarriageSpouse1(ctx, repository.RepointMarriageSpouse1Params{SurvivorID: survivor, DuplicateID: duplicate})
+	}},
+	{"marriage.spouse2_id", func(ctx context.Context, q *repository.Queries, survivor, duplicate uuid.UUID) (int64, error) {
+		return q.RepointMarriageSpouse2(ctx, repository.RepointMarriageSpouse2Params{SurvivorID: survivor, DuplicateID: duplicate})
+	}},
+	{"death_record.person_id", func(ctx context.Context, q *repository.Queries, survivor, duplicate uuid.UUID) (int64, error) {
+		return q.RepointDeathRecordPerson(ctx, repository.RepointDeathRecordPersonParams{SurvivorID: survivor, DuplicateID: duplicate})
+	}},
+	{"death_record.informant_person_id", func(ctx context.Context, q *repository.Queries, survivor, duplicate uuid.UUID) (int64, error) {
+		return q.RepointDeathRecordInformant(ctx, repository.RepointDeathRecordInformantParams{SurvivorID: pgtype.UUID{Bytes: survivor, Valid: true}, DuplicateID: pgtype.UUID{Bytes: duplicate, Valid: true}})
+	}},
+	{"certificate.person_id", func(ctx context.Context, q *repository.Queries, survivor, duplicate uuid.UUID) (int64, error) {
+		return q.RepointCertificates(ctx, repository.RepointCertificatesParams{SurvivorID: survivor, DuplicateID: duplicate})
+	}},
+	{"person_address.person_id", func(ctx context.Context, q *repository.Queries, survivor, duplicate uuid.UUID) (int64, error) {
+		return q.RepointPersonAddresses(ctx, repository.RepointPersonAddressesParams{SurvivorID: survivor, DuplicateID: duplicate})
+	}},
+	{"residence_declaration.person_id", func(ctx context.Context, q *repository.Queries, survivor, duplicate uuid.UUID) (int64, error) {
+		return q.RepointResidenceDeclarations(ctx, repository.RepointResidenceDeclarationsParams{SurvivorID: survivor, DuplicateID: duplicate})
+	}},
+	{"adoption.person_id", func(ctx context.Context, q *repository.Queries, survivor, duplicate uuid.UUID) (int64, error) {
+		return q.RepointAdoptionPerson(ctx, repository.RepointAdoptionPersonParams{SurvivorID: survivor, DuplicateID: duplicate})
+	}},
+	{"adoption.adoptive_mother_id", func(ctx context.Context, q *repository.Queries, survivor, duplicate uuid.UUID) (int64, error) {
+		return q.RepointAdoptiveMother(ctx, repository.RepointAdoptiveMotherParams{SurvivorID: pgtype.UUID{Bytes: survivor, Valid: true}, DuplicateID: pgtype.UUID{Bytes: duplicate, Valid: true}})
+	}},
+	{"adoption.adoptive_father_id", func(ctx context.Context, q *repository.Queries, survivor, duplicate uuid.UUID) (int64, error) {
+		return q.RepointAdoptiveFather(ctx, repository.RepointAdoptiveFatherParams{SurvivorID: pgtype.UUID{Bytes: survivor, Valid: true}, DuplicateID: pgtype.UUID{Bytes: duplicate, Valid: true}})
+	}},
+	{"guardianship.ward_id", func(ctx context.Context, q *repository.Queries, survivor, duplicate uuid.UUID) (int64, error) {
+		return q.RepointGuardianshipWard(ctx, repository.RepointGuardianshipWardParams{SurvivorID: survivor, DuplicateID: duplicate})
+	}},
+	{"guardianship.guardian_id", func(ctx context.Context, q *repository.Queries, survivor, duplicate uuid.UUID) (int64, error) {
+		return q.RepointGuardianshipGuardian(ctx, repository.RepointGuardianshipGuardianParams{SurvivorID: survivor, DuplicateID: duplicate})
+	}},
+	{"data_subject_request.person_id", func(ctx context.Context, q *repository.Queries, survivor, duplicate uuid.UUID) (int64, error) {
+		return q.RepointDataSubjectRequests(ctx, repository.RepointDataSubjectRequestsParams{SurvivorID: survivor, DuplicateID: duplicate})
+	}},
+	{"application.personal_code", func(ctx context.Context, q *repository.Queries, survivor, duplicate uuid.UUID) (int64, error) {
+		return q.RekeyApplications(ctx, repository.RekeyApplicationsParams{SurvivorID: survivor, DuplicateID: duplicate})
+	}},
+	{"appointment.personal_code", func(ctx context.Context, q *repository.Queries, survivor, duplicate uuid.UUID) (int64, error) {
+		return q.RekeyAppointments(ctx, repository.RekeyAppointmentsParams{SurvivorID: survivor, DuplicateID: duplicate})
+	}},
+}
+
+// MergePersons folds a duplicate person into the survivor in one
+// transaction: every record naming the duplicate is re-pointed, the
+// survivor takes over a death, marriage or restriction of processing only
+// the duplicate had, and the duplicate is kept, marked as merged, next to a
+// person_merge audit row holding its last state, what was moved and the
+// registrar who merged.
+func (s *Service) MergePersons(ctx context.Context, arg MergeParams) (_ *MergeResult, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "person", "MergePersons")
+	defer func() { op.End(err) }()
+
+	arg.Reason = strings.TrimSpace(arg.Reason)
+	if arg.Reason == "" {
+		return nil, apperr.Invalid("reason is required")
+	}
+	if arg.SurvivorID == arg.DuplicateID {
+		return nil, apperr.Invalid("a person cannot be merged into itself")
+	}
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Merging person %s into %s", arg.DuplicateID, arg.SurvivorID)
+
+	var result MergeResult
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		// Marriage workflows lock the marriage row before the persons; take
+		// the duplicate's marriages first to keep the same order
+		marriages, err := q.ListMarriagesForPerson(ctx, arg.DuplicateID)
+		if err != nil {
+			return fmt.Errorf("failed ListMarriagesForPerson: %w", err)
+		}
+		for _, m := range marriages {
+			if _, err := q.GetMarriageForUpdate(ctx, m.ID); err != nil {
+				return fmt.Errorf("failed GetMarriageForUpdate: %w", err)
+			}
+		}
+
+		locked, err := LockInOrder(ctx, q, arg.SurvivorID, arg.DuplicateID)
+		if err != nil {
+			return err
+		}
+		survivor, duplicate := locked[arg.SurvivorID], locked[arg.DuplicateID]
+		if err := checkMergeable(ctx, q, survivor, duplicate, marriages); err != nil {
+			return err
+		}
+
+		moved := make(map[string]int64, len(repoints))
+		for _, rp := range repoints {
+			n, err := rp.run(ctx, q, survivor.ID, duplicate.ID)
+			if err != nil {
+				return fmt.Errorf("failed to re-point %s: %w", rp.column, err)
+			}
+			if n > 0 {
+				moved[rp.column] = n
+			}
+		}
+
+		if result.Survivor, err = adoptStatus(ctx, q, survivor, duplicate, marriages); err != nil {
+			return err
+		}
+		// A restriction of processing the duplicate asked for binds the
+		// survivor too; its request history moved with the repoints above
+		if duplicate.ProcessingRestrictedAt.Valid && !result.Survivor.ProcessingRestrictedAt.Valid {
+			if result.Survivor, err = q.SetPersonProcessingRestricted(ctx, repository.SetPersonProcessingRestrictedParams{
+				ID:           survivor.ID,
+				RestrictedAt: duplicate.ProcessingRestrictedAt,
+			}); err != nil {
+				return fmt.Errorf("failed SetPersonProcessingRestricted: %w", err)
+			}
+		}
+
+		if result.Duplicate, err = q.MarkPersonMerged(ctx, repository.MarkPersonMergedParams{
+			ID:         duplicate.ID,
+			SurvivorID: pgtype.UUID{Bytes: survivor.ID, Valid: true},
+		}); err != nil {
+			return fmt.Errorf("failed MarkPersonMerged: %w", err)
+		}
+
+		movedJSON, err := json.Marshal(moved)
+		if err != nil {
+			return fmt.Errorf("failed to encode moved records: %w", err)
+		}
+		snapshot, err := json.Marshal(duplicate)
+		if err != nil {
+			return fmt.Errorf("failed to encode duplicate: %w", err)
+		}
+		officeID, registrarID := office.RecordedBy(p)
+		result.Merge, err = q.CreatePersonMerge(ctx, repository.CreatePersonMergeParams{
+			SurvivorID:        survivor.ID,
+			DuplicateID:       duplicate.ID,
+			MergedBy:          p.Name(),
+			Reason:            arg.Reason,
+			Moved:             movedJSON,
+			DuplicateSnapshot: snapshot,
+			OfficeID:          officeID,
+			RegistrarID:       registrarID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed CreatePersonMerge: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorf("Failed MergePersons: %v", err)
+		return nil, err
+	}
+
+	s.logger.Infof("MergePersons completed successfully with ID: %s", result.Merge.ID)
+	return &result, nil
+}
+
+// ListPersonMerges lists the merges a person took part in, oldest first.
+func (s *Service) ListPersonMerges(ctx context.Context, id uuid.UUID) (_ []repository.PersonMerge, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "person", "ListPersonMerges")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetPersonByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed GetPersonByID: %w", err)
+	}
+
+	result, err := s.repo.ListPersonMerges(ctx, id)
+	if err != nil {
+		s.logger.Errorf("Failed ListPersonMerges: %v", err)
+		return nil, fmt.Errorf("failed ListPersonMerges: %w", err)
+	}
+	return result, nil
+}
+
+// checkMergeable rejects merges that would leave the survivor with two
+// records of a kind a person has only once, or that link the two persons
+// to each other.
+func checkMergeable(ctx context.Context, q *repository.Queries, survivor, duplicate repository.Person, duplicateMarriages []repository.Marriage) error {
+	for _, p := range []repository.Person{survivor, duplicate} {
+		if p.MergedInto.Valid {
+			return apperr.Conflict("%s %s was already merged into person %s", p.FirstName, p.LastName, uuid.UUID(p.MergedInto.Bytes))
+		}
+	}
+	if survivor.Sex != duplicate.Sex {
+		return apperr.Conflict("persons of different sex cannot be merged")
+	}
+
+	survivorBirth, err := birthRecord(ctx, q, survivor.ID)
+	if err != nil {
+		return err
+	}
+	duplicateBirth, err := birthRecord(ctx, q, duplicate.ID)
+	if err != nil {
+		return err
+	}
+	if survivorBirth != nil && duplicateBirth != nil {
+		return apperr.Conflict("both persons have a birth record")
+	}
+	if survivorBirth != nil && isPerson(duplicate.ID, survivorBirth.MotherID, survivorBirth.FatherID) ||
+		duplicateBirth != nil && isPerson(survivor.ID, duplicateBirth.MotherID, duplicateBirth.FatherID) {
+		return apperr.Conflict("one person is recorded as the other's parent")
+	}
+	if survivor.Status == StatusDeceased && duplicate.Status == StatusDeceased {
+		return apperr.Conflict("both persons have a death record")
+	}
+
+	// Two residence histories cannot be joined without overlaps
+	survivorDeclared, err := hasResidence(ctx, q, survivor.ID)
+	if err != nil {
+		return err
+	}
+	duplicateDeclared, err := hasResidence(ctx, q, duplicate.ID)
+	if err != nil {
+		return err
+	}
+	if survivorDeclared && duplicateDeclared {
+		return apperr.Conflict("both persons have residence declarations")
+	}
+
+	survivorActive := survivor.MaritalStatus == MaritalMarried
+	for _, m := range duplicateMarriages {
+		if m.Spouse1ID == survivor.ID || m.Spouse2ID == survivor.ID {
+			return apperr.Conflict("the persons are recorded as married to each other")
+		}
+		if m.Status == marriageActive && survivorActive {
+			return apperr.Conflict("both persons are in an active marriage")
+		}
+	}
+	return nil
+}
+
+// adoptStatus carries the duplicate's death and marital status over to the
+// survivor when only the duplicate had them, recording the change in the
+// survivor's history.
+func adoptStatus(ctx context.Context, q *repository.Queries, survivor, duplicate repository.Person, duplicateMarriages []repository.Marriage) (repository.Person, error) {
+	before, after := survivor, survivor
+	var err error
+
+	marital := survivor.MaritalStatus
+	for _, m := range duplicateMarriages {
+		if m.Status == marriageActive {
+			marital = MaritalMarried
+		}
+	}
+	if marital == MaritalSingle {
+		marital = duplicate.MaritalStatus
+	}
+	if marital != survivor.MaritalStatus {
+		if after, err = q.SetPersonMaritalStatus(ctx, repository.SetPersonMaritalStatusParams{
+			ID:            survivor.ID,
+			MaritalStatus: marital,
+			LastName:      survivor.LastName,
+		}); err != nil {
+			return after, fmt.Errorf("failed SetPersonMaritalStatus: %w", err)
+		}
+	}
+
+	if duplicate.Status == StatusDeceased && survivor.Status != StatusDeceased {
+		if after, err = q.SetPersonDeceased(ctx, survivor.ID); err != nil {
+			return after, fmt.Errorf("failed SetPersonDeceased: %w", err)
+		}
+	}
+
+	err = RecordAmendment(ctx, q, before, after, Amendment{
+		LegalBasis:    "merge of duplicate person " + duplicate.PersonalCode,
+		EffectiveDate: pgtype.Date{Time: time.Now(), Valid: true},
+	})
+	return after, err
+}
+
+func birthRecord(ctx context.Context, q *repository.Queries, personID uuid.UUID) (*repository.BirthRecord, error) {
+	r, err := q.GetBirthRecordByPersonID(ctx, personID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed GetBirthRecordByPersonID: %w", err)
+	}
+	return &r, nil
+}
+
+func hasResidence(ctx context.Context, q *repository.Queries, personID uuid.UUID) (bool, error) {
+	_, err := q.GetLatestResidenceDeclaration(ctx, personID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed GetLatestResidenceDeclaration: %w", err)
+	}
+	return true, nil
+}
+
+// isPerson reports whether one of the optional references names id.
+func isPerson(id uuid.UUID, refs ...pgtype.UUID) bool {
+	for _, ref := range refs {
+		if ref.Valid && ref.Bytes == id {
+			return true
+		}
+	}
+	return false
+}
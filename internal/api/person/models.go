@@ -0,0 +1,304 @@
+package person
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+)
+
+// PersonResponse is the wire form of repository.Person, shared by the
+// JSON, XML and CSV encodings. Addresses are only filled in for single
+// person lookups.
+type PersonResponse struct {
+	XMLName       xml.Name    `json:"-" xml:"person"`
+	ID            uuid.UUID   `json:"id" xml:"id"`
+	PersonalCode  string      `json:"personal_code" xml:"personal_code"`
+	FirstName     string      `json:"first_name" xml:"first_name"`
+	LastName      string      `json:"last_name" xml:"last_name"`
+	BirthDate     render.Date `json:"birth_date" xml:"birth_date"`
+	BirthPlace    *string     `json:"birth_place" xml:"birth_place,omitempty"`
+	Sex           string      `json:"sex" xml:"sex"`
+	Citizenship   string      `json:"citizenship" xml:"citizenship"`
+	Status        string      `json:"status" xml:"status"`
+	MaritalStatus string      `json:"marital_status" xml:"marital_status"`
+	Version       int64       `json:"version" xml:"version"`
+	CreatedAt     time.Time   `json:"created_at" xml:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at" xml:"updated_at"`
+	OfficeID      *uuid.UUID  `json:"office_id" xml:"office_id,omitempty"`
+	RegistrarID   *uuid.UUID  `json:"registrar_id" xml:"registrar_id,omitempty"`
+	MergedInto    *uuid.UUID  `json:"merged_into,omitempty" xml:"merged_into,omitempty"`
+	// ProcessingRestrictedAt is set while the person has restricted
+	// processing of their data
+	ProcessingRestrictedAt *time.Time        `json:"processing_restricted_at,omitempty" xml:"processing_restricted_at,omitempty"`
+	Addresses              []AddressResponse `json:"addresses,omitempty" xml:"addresses>address,omitempty"`
+	// AsOf is set when the person was reconstructed from their history
+	AsOf *render.Date `json:"as_of,omitempty" xml:"as_of,attr,omitempty"`
+}
+
+func NewPersonResponse(row repository.Person) PersonResponse {
+	return PersonResponse{
+		ID:                     row.ID,
+		PersonalCode:           row.PersonalCode,
+		FirstName:              row.FirstName,
+		LastName:               row.LastName,
+		BirthDate:              render.Date(row.BirthDate.Time),
+		BirthPlace:             render.Nullable(row.BirthPlace.String, row.BirthPlace.Valid),
+		Sex:                    row.Sex,
+		Citizenship:            row.Citizenship,
+		Status:                 row.Status,
+		MaritalStatus:          row.MaritalStatus,
+		Version:                row.Version,
+		CreatedAt:              row.CreatedAt,
+		UpdatedAt:              row.UpdatedAt,
+		OfficeID:               render.Nullable(uuid.UUID(row.OfficeID.Bytes), row.OfficeID.Valid),
+		RegistrarID:            render.Nullable(uuid.UUID(row.RegistrarID.Bytes), row.RegistrarID.Valid),
+		MergedInto:             render.Nullable(uuid.UUID(row.MergedInto.Bytes), row.MergedInto.Valid),
+		ProcessingRestrictedAt: render.Nullable(row.ProcessingRestrictedAt.Time, row.ProcessingRestrictedAt.Valid),
+	}
+}
+
+func NewPersonResponses(rows []repository.Person) []PersonResponse {
+	items := make([]PersonResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, NewPersonResponse(row))
+	}
+	return items
+}
+
+var personCSVHeader = []string{
+	"id", "personal_code", "first_name", "last_name", "birth_date", "birth_place",
+	"sex", "citizenship", "status", "marital_status", "version", "created_at", "updated_at",
+}
+
+func (m PersonResponse) CSVRecord() []string {
+	return []string{
+		render.CSVValue(m.ID),
+		render.CSVValue(m.PersonalCode),
+		render.CSVValue(m.FirstName),
+		render.CSVValue(m.LastName),
+		render.CSVValue(m.BirthDate),
+		render.CSVValue(m.BirthPlace),
+		render.CSVValue(m.Sex),
+		render.CSVValue(m.Citizenship),
+		render.CSVValue(m.Status),
+		render.CSVValue(m.MaritalStatus),
+		render.CSVValue(m.Version),
+		render.CSVValue(m.CreatedAt),
+		render.CSVValue(m.UpdatedAt),
+	}
+}
+
+// ETag identifies the current version of the person for conditional requests.
+func (m PersonResponse) ETag() string {
+	return render.VersionETag(m.Version)
+}
+
+func (m PersonResponse) LastModified() time.Time {
+	return m.UpdatedAt
+}
+
+// AddressResponse is the wire form of repository.PersonAddress.
+type AddressResponse struct {
+	XMLName     xml.Name   `json:"-" xml:"address"`
+	ID          uuid.UUID  `json:"id" xml:"id"`
+	Kind        string     `json:"kind" xml:"kind"`
+	Line        string     `json:"line" xml:"line"`
+	City        string     `json:"city" xml:"city"`
+	PostalCode  string     `json:"postal_code" xml:"postal_code"`
+	Country     string     `json:"country" xml:"country"`
+	OfficeID    *uuid.UUID `json:"office_id" xml:"office_id,omitempty"`
+	RegistrarID *uuid.UUID `json:"registrar_id" xml:"registrar_id,omitempty"`
+	CreatedAt   time.Time  `json:"created_at" xml:"created_at"`
+}
+
+func NewAddressResponse(row repository.PersonAddress) AddressResponse {
+	return AddressResponse{
+		ID:          row.ID,
+		Kind:        row.Kind,
+		Line:        row.Line,
+		City:        row.City,
+		PostalCode:  row.PostalCode,
+		Country:     row.Country,
+		OfficeID:    render.Nullable(uuid.UUID(row.OfficeID.Bytes), row.OfficeID.Valid),
+		RegistrarID: render.Nullable(uuid.UUID(row.RegistrarID.Bytes), row.RegistrarID.Valid),
+		CreatedAt:   row.CreatedAt,
+	}
+}
+
+func NewAddressResponses(rows []repository.PersonAddress) []AddressResponse {
+	items := make([]AddressResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, NewAddressResponse(row))
+	}
+	return items
+}
+
+// PersonEnvelope is the response body for a single person. XML clients
+// receive the bare <person> element.
+type PersonEnvelope struct {
+	Message string         `json:"message,omitempty"`
+	Data    PersonResponse `json:"data"`
+}
+
+func (e PersonEnvelope) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	return enc.Encode(e.Data)
+}
+
+// PersonListEnvelope is the response body for a page of search results.
+type PersonListEnvelope struct {
+	XMLName xml.Name         `json:"-" xml:"persons"`
+	Count   int              `json:"count" xml:"count,attr"`
+	Limit   int32            `json:"limit" xml:"limit,attr"`
+	Offset  int32            `json:"offset" xml:"offset,attr"`
+	Data    []PersonResponse `json:"data" xml:"person"`
+}
+
+// AddressEnvelope is the response body for a single address.
+type AddressEnvelope struct {
+	Message string          `json:"message,omitempty"`
+	Data    AddressResponse `json:"data"`
+}
+
+func (e AddressEnvelope) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	return enc.Encode(e.Data)
+}
+
+// AddressListEnvelope is the response body for a person's addresses.
+type AddressListEnvelope struct {
+	XMLName xml.Name          `json:"-" xml:"addresses"`
+	Count   int               `json:"count" xml:"count,attr"`
+	Data    []AddressResponse `json:"data" xml:"address"`
+}
+
+// HistoryResponse is the wire form of repository.PersonHistory.
+type HistoryResponse struct {
+	XMLName       xml.Name    `json:"-" xml:"change"`
+	ID            uuid.UUID   `json:"id" xml:"id"`
+	Attribute     string      `json:"attribute" xml:"attribute"`
+	OldValue      *string     `json:"old_value" xml:"old_value,omitempty"`
+	NewValue      *string     `json:"new_value" xml:"new_value,omitempty"`
+	EffectiveDate render.Date `json:"effective_date" xml:"effective_date"`
+	LegalBasis    string      `json:"legal_basis" xml:"legal_basis"`
+	PersonVersion int64       `json:"person_version" xml:"person_version"`
+	RecordedAt    time.Time   `json:"recorded_at" xml:"recorded_at"`
+}
+
+func NewHistoryResponse(row repository.PersonHistory) HistoryResponse {
+	return HistoryResponse{
+		ID:            row.ID,
+		Attribute:     row.Attribute,
+		OldValue:      render.Nullable(row.OldValue.String, row.OldValue.Valid),
+		NewValue:      render.Nullable(row.NewValue.String, row.NewValue.Valid),
+		EffectiveDate: render.Date(row.EffectiveDate.Time),
+		LegalBasis:    row.LegalBasis,
+		PersonVersion: row.PersonVersion,
+		RecordedAt:    row.RecordedAt,
+	}
+}
+
+func NewHistoryResponses(rows []repository.PersonHistory) []HistoryResponse {
+	items := make([]HistoryResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, NewHistoryResponse(row))
+	}
+	return items
+}
+
+// HistoryListEnvelope is the response body for a person's history.
+type HistoryListEnvelope struct {
+	XMLName xml.Name          `json:"-" xml:"history"`
+	Count   int               `json:"count" xml:"count,attr"`
+	Data    []HistoryResponse `json:"data" xml:"change"`
+}
+
+// DuplicateResponse is the wire form of a DuplicateCandidate.
+type DuplicateResponse struct {
+	XMLName   xml.Name       `json:"-" xml:"duplicate"`
+	Person    PersonResponse `json:"person" xml:"person"`
+	Candidate PersonResponse `json:"candidate" xml:"candidate>person"`
+	Score     int            `json:"score" xml:"score,attr"`
+	Reasons   []string       `json:"reasons" xml:"reasons>reason"`
+}
+
+func NewDuplicateResponses(rows []DuplicateCandidate) []DuplicateResponse {
+	items := make([]DuplicateResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, DuplicateResponse{
+			Person:    NewPersonResponse(row.Person),
+			Candidate: NewPersonResponse(row.Candidate),
+			Score:     row.Score,
+			Reasons:   row.Reasons,
+		})
+	}
+	return items
+}
+
+// DuplicateListEnvelope is the response body for duplicate candidates.
+type DuplicateListEnvelope struct {
+	XMLName xml.Name            `json:"-" xml:"duplicates"`
+	Count   int                 `json:"count" xml:"count,attr"`
+	Data    []DuplicateResponse `json:"data" xml:"duplicate"`
+}
+
+// PersonMergeResponse is the wire form of repository.PersonMerge. Moved
+// counts the re-pointed rows per column.
+type PersonMergeResponse struct {
+	XMLName     xml.Name         `json:"-" xml:"merge"`
+	ID          uuid.UUID        `json:"id" xml:"id"`
+	SurvivorID  uuid.UUID        `json:"survivor_id" xml:"survivor_id"`
+	DuplicateID uuid.UUID        `json:"duplicate_id" xml:"duplicate_id"`
+	MergedBy    string           `json:"merged_by" xml:"merged_by"`
+	OfficeID    *uuid.UUID       `json:"office_id" xml:"office_id,omitempty"`
+	RegistrarID *uuid.UUID       `json:"registrar_id" xml:"registrar_id,omitempty"`
+	Reason      string           `json:"reason" xml:"reason"`
+	Moved       map[string]int64 `json:"moved" xml:"-"`
+	// DuplicateSnapshot is the duplicate as it was before the merge
+	DuplicateSnapshot json.RawMessage `json:"duplicate_snapshot" xml:"-" swaggertype:"object"`
+	MergedAt          time.Time       `json:"merged_at" xml:"merged_at"`
+}
+
+func NewPersonMergeResponse(row repository.PersonMerge) PersonMergeResponse {
+	resp := PersonMergeResponse{
+		ID:                row.ID,
+		SurvivorID:        row.SurvivorID,
+		DuplicateID:       row.DuplicateID,
+		MergedBy:          row.MergedBy,
+		OfficeID:          render.Nullable(uuid.UUID(row.OfficeID.Bytes), row.OfficeID.Valid),
+		RegistrarID:       render.Nullable(uuid.UUID(row.RegistrarID.Bytes), row.RegistrarID.Valid),
+		Reason:            row.Reason,
+		DuplicateSnapshot: row.DuplicateSnapshot,
+		MergedAt:          row.MergedAt,
+	}
+	// The column is written by MergePersons; a row it cannot decode still
+	// renders, without the counts
+	_ = json.Unmarshal(row.Moved, &resp.Moved)
+	return resp
+}
+
+func NewPersonMergeResponses(rows []repository.PersonMerge) []PersonMergeResponse {
+	items := make([]PersonMergeResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, NewPersonMergeResponse(row))
+	}
+	return items
+}
+
+// MergeEnvelope is the response body for a completed merge.
+type MergeEnvelope struct {
+	XMLName   xml.Name            `json:"-" xml:"merge_result"`
+	Message   string              `json:"message,omitempty" xml:"-"`
+	Survivor  PersonResponse      `json:"survivor" xml:"survivor>person"`
+	Duplicate PersonResponse      `json:"duplicate" xml:"duplicate>person"`
+	Merge     PersonMergeResponse `json:"merge" xml:"merge"`
+}
+
+// PersonMergeListEnvelope is the response body for a person's merges.
+type PersonMergeListEnvelope struct {
+	XMLName xml.Name              `json:"-" xml:"merges"`
+	Count   int                   `json:"count" xml:"count,attr"`
+	Data    []PersonMergeResponse `json:"data" xml:"merge"`
+}
@@ -0,0 +1,31 @@
+package person
+
+import (
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func PersonRouter(db txn.Beginner, queries *repository.Queries, log *zap.SugaredLogger) chi.Router {
+	r := chi.NewRouter()
+
+	service := NewService(db, queries, log)
+	handlers := NewHandlers(service, log)
+
+	r.Get("/health", telemetry.InstrumentHandler("person", "HealthCheck", handlers.HealthCheck))
+	r.Post("/", telemetry.InstrumentHandler("person", "CreatePerson", handlers.CreatePerson))
+	r.Get("/", telemetry.InstrumentHandler("person", "SearchPersons", handlers.SearchPersons))
+	r.Get("/duplicates", telemetry.InstrumentHandler("person", "FindDuplicates", handlers.FindDuplicates))
+	r.Get("/by-code/{code}", telemetry.InstrumentHandler("person", "GetPersonByPersonalCode", handlers.GetPersonByPersonalCode))
+	r.Get("/{id}", telemetry.InstrumentHandler("person", "GetPersonByID", handlers.GetPersonByID))
+	r.Put("/{id}", telemetry.InstrumentHandler("person", "UpdatePerson", handlers.UpdatePerson))
+	r.Post("/{id}/addresses", telemetry.InstrumentHandler("person", "AddPersonAddress", handlers.AddPersonAddress))
+	r.Get("/{id}/addresses", telemetry.InstrumentHandler("person", "ListPersonAddresses", handlers.ListPersonAddresses))
+	r.Get("/{id}/history", telemetry.InstrumentHandler("person", "ListPersonHistory", handlers.ListPersonHistory))
+	r.Post("/{id}/merge", telemetry.InstrumentHandler("person", "MergePersons", handlers.MergePersons))
+	r.Get("/{id}/merges", telemetry.InstrumentHandler("person", "ListPersonMerges", handlers.ListPersonMerges))
+
+	return r
+}
@@ -0,0 +1,428 @@
+package person
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/office"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/personalcode"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const (
+	StatusAlive    = "alive"
+	StatusDeceased = "deceased"
+
+	MaritalSingle   = "single"
+	MaritalMarried  = "married"
+	MaritalDivorced = "divorced"
+	MaritalWidowed  = "widowed"
+
+	SexMale   = "male"
+	SexFemale = "female"
+
+	AddressResidence      = "residence"
+	AddressCorrespondence = "correspondence"
+
+	defaultSearchLimit = 50
+	maxSearchLimit     = 200
+)
+
+var countryPattern = regexp.MustCompile(`^[A-Z]{2}$`)
+
+type Service struct {
+	db     txn.Beginner
+	repo   *repository.Queries
+	logger *zap.SugaredLogger
+}
+
+func NewService(db txn.Beginner, repo *repository.Queries, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		db:     db,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+func (s *Service) CreatePerson(ctx context.Context, arg repository.CreatePersonParams) (_ *repository.Person, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "person", "CreatePerson")
+	defer func() { op.End(err) }()
+
+	arg, err = PrepareCreate(arg)
+	if err != nil {
+		return nil, err
+	}
+	if arg.PersonalCode == "" {
+		return nil, apperr.Invalid("personal_code is required")
+	}
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	arg.OfficeID, arg.RegistrarID = office.RecordedBy(p)
+
+	s.logger.Infof("Creating person %s %s", arg.FirstName, arg.LastName)
+
+	result, err := s.repo.CreatePerson(ctx, arg)
+	if err != nil {
+		s.logger.Errorf("Failed CreatePerson: %v", err)
+		return nil, CreateError(err, arg.PersonalCode)
+	}
+
+	s.logger.Infof("CreatePerson completed successfully with ID: %s", result.ID)
+	return &result, nil
+}
+
+func (s *Service) GetPersonByID(ctx context.Context, id uuid.UUID) (_ *repository.Person, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "person", "GetPersonByID")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.GetPersonByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetPersonByID: %w", err)
+	}
+	return &result, nil
+}
+
+func (s *Service) GetPersonByPersonalCode(ctx context.Context, code string) (_ *repository.Person, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "person", "GetPersonByPersonalCode")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := personalcode.Validate(code); err != nil {
+		return nil, apperr.Invalid("%s", err)
+	}
+
+	result, err := s.repo.GetPersonByPersonalCode(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetPersonByPersonalCode: %w", err)
+	}
+	return &result, nil
+}
+
+// SearchPersons returns one page of persons matching the optional filters.
+// A zero limit selects the default page size.
+func (s *Service) SearchPersons(ctx context.Context, arg repository.SearchPersonsParams) (_ []repository.Person, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "person", "SearchPersons")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	if arg.Status.Valid && arg.Status.String != StatusAlive && arg.Status.String != StatusDeceased {
+		return nil, apperr.Invalid("status must be %q or %q", StatusAlive, StatusDeceased)
+	}
+	if arg.Limit < 0 || arg.Limit > maxSearchLimit {
+		return nil, apperr.Invalid("limit must be between 1 and %d", maxSearchLimit)
+	}
+	if arg.Limit == 0 {
+		arg.Limit = defaultSearchLimit
+	}
+	if arg.Offset < 0 {
+		return nil, apperr.Invalid("offset must not be negative")
+	}
+	arg.Name.String = strings.TrimSpace(arg.Name.String)
+	arg.Name.Valid = arg.Name.Valid && arg.Name.String != ""
+
+	result, err := s.repo.SearchPersons(ctx, arg)
+	if err != nil {
+		s.logger.Errorf("Failed SearchPersons: %v", err)
+		return nil, fmt.Errorf("failed SearchPersons: %w", err)
+	}
+	return result, nil
+}
+
+// UpdatePerson amends a person's mutable details and records the changed
+// attributes in their history under the given amendment. Only registrars
+// amend persons.
+func (s *Service) UpdatePerson(ctx context.Context, arg repository.UpdatePersonParams, amendment Amendment) (_ *repository.Person, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "person", "UpdatePerson")
+	defer func() { op.End(err) }()
+
+	arg.FirstName = strings.TrimSpace(arg.FirstName)
+	arg.LastName = strings.TrimSpace(arg.LastName)
+	if arg.FirstName == "" || arg.LastName == "" {
+		return nil, apperr.Invalid("first_name and last_name are required")
+	}
+	if !countryPattern.MatchString(arg.Citizenship) {
+		return nil, apperr.Invalid("citizenship must be an ISO 3166-1 alpha-2 code")
+	}
+	if _, err := auth.RequireRegistrar(ctx); err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("UpdatePerson called for ID: %s", arg.ID)
+
+	var result repository.Person
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		current, err := q.GetPersonForUpdate(ctx, arg.ID)
+		if err != nil {
+			return fmt.Errorf("failed GetPersonForUpdate: %w", err)
+		}
+		if err := EnsureAlive(current); err != nil {
+			return err
+		}
+		if amendment, err = validateAmendment(current, amendment); err != nil {
+			return err
+		}
+
+		if result, err = q.UpdatePerson(ctx, arg); err != nil {
+			return fmt.Errorf("failed UpdatePerson: %w", err)
+		}
+		return RecordAmendment(ctx, q, current, result, amendment)
+	})
+	if err != nil {
+		s.logger.Errorf("Failed UpdatePerson: %v", err)
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ListPersonHistory returns every recorded change to the person, oldest
+// effective date first.
+func (s *Service) ListPersonHistory(ctx context.Context, id uuid.UUID) (_ []repository.PersonHistory, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "person", "ListPersonHistory")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	// Resolve the person first so an unknown ID is a 404, not an empty list
+	if _, err := s.repo.GetPersonByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed ListPersonHistory: %w", err)
+	}
+
+	result, err := s.repo.ListPersonHistory(ctx, id)
+	if err != nil {
+		s.logger.Errorf("Failed ListPersonHistory: %v", err)
+		return nil, fmt.Errorf("failed ListPersonHistory: %w", err)
+	}
+	return result, nil
+}
+
+// GetPersonAsOf reconstructs the person as they were recorded on the given
+// date. Dates before the person's birth are not found.
+func (s *Service) GetPersonAsOf(ctx context.Context, id uuid.UUID, on time.Time) (_ *repository.Person, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "person", "GetPersonAsOf")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	current, err := s.repo.GetPersonByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetPersonAsOf: %w", err)
+	}
+	if on.Before(current.BirthDate.Time) {
+		return nil, apperr.NotFound("person was not born on %s", on.Format(time.DateOnly))
+	}
+
+	history, err := s.repo.ListPersonHistory(ctx, id)
+	if err != nil {
+		s.logger.Errorf("Failed ListPersonHistory: %v", err)
+		return nil, fmt.Errorf("failed GetPersonAsOf: %w", err)
+	}
+
+	result := AsOf(current, history, on)
+	return &result, nil
+}
+
+func (s *Service) AddPersonAddress(ctx context.Context, arg repository.AddPersonAddressParams) (_ *repository.PersonAddress, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "person", "AddPersonAddress")
+	defer func() { op.End(err) }()
+
+	if arg.Kind != AddressResidence && arg.Kind != AddressCorrespondence {
+		return nil, apperr.Invalid("kind must be %q or %q", AddressResidence, AddressCorrespondence)
+	}
+	if strings.TrimSpace(arg.Line) == "" || strings.TrimSpace(arg.City) == "" {
+		return nil, apperr.Invalid("line and city are required")
+	}
+	if arg.Country == "" {
+		arg.Country = "LT"
+	}
+	if !countryPattern.MatchString(arg.Country) {
+		return nil, apperr.Invalid("country must be an ISO 3166-1 alpha-2 code")
+	}
+	registrar, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	arg.OfficeID, arg.RegistrarID = office.RecordedBy(registrar)
+
+	// Resolve the person first so a bad ID is a 404 rather than a foreign key error
+	p, err := s.repo.GetPersonByID(ctx, arg.PersonID)
+	if err != nil {
+		return nil, fmt.Errorf("failed AddPersonAddress: %w", err)
+	}
+	if err := EnsureAlive(p); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.AddPersonAddress(ctx, arg)
+	if err != nil {
+		s.logger.Errorf("Failed AddPersonAddress: %v", err)
+		return nil, fmt.Errorf("failed AddPersonAddress: %w", err)
+	}
+	return &result, nil
+}
+
+func (s *Service) ListPersonAddresses(ctx context.Context, personID uuid.UUID) (_ []repository.PersonAddress, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "person", "ListPersonAddresses")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	result, err := s.repo.ListPersonAddresses(ctx, personID)
+	if err != nil {
+		s.logger.Errorf("Failed ListPersonAddresses: %v", err)
+		return nil, fmt.Errorf("failed ListPersonAddresses: %w", err)
+	}
+	return result, nil
+}
+
+func (s *Service) HealthCheck(ctx context.Context) (err error) {
+	ctx, op := telemetry.StartOperation(ctx, "person", "HealthCheck")
+	defer func() { op.End(err) }()
+
+	_, err = s.repo.SearchPersons(ctx, repository.SearchPersonsParams{Limit: 1})
+	return err
+}
+
+// PrepareCreate normalizes and validates a new person. Workflows that create
+// persons inside their own transaction (births, for example) call it before
+// Queries.CreatePerson. A personal code must match the birth date and sex;
+// an empty one is left for the caller to assign with NextPersonalCode.
+func PrepareCreate(arg repository.CreatePersonParams) (repository.CreatePersonParams, error) {
+	arg.PersonalCode = strings.TrimSpace(arg.PersonalCode)
+	arg.FirstName = strings.TrimSpace(arg.FirstName)
+	arg.LastName = strings.TrimSpace(arg.LastName)
+	if arg.Citizenship == "" {
+		arg.Citizenship = "LT"
+	}
+
+	if arg.FirstName == "" || arg.LastName == "" {
+		return arg, apperr.Invalid("first_name and last_name are required")
+	}
+	if err := validateBirthDate(arg.BirthDate); err != nil {
+		return arg, err
+	}
+	if arg.Sex != SexMale && arg.Sex != SexFemale {
+		return arg, apperr.Invalid("sex must be %q or %q", SexMale, SexFemale)
+	}
+	if arg.PersonalCode != "" {
+		if err := personalcode.Check(arg.PersonalCode, arg.BirthDate.Time, arg.Sex); err != nil {
+			return arg, apperr.Invalid("%s", err)
+		}
+	}
+	if !countryPattern.MatchString(arg.Citizenship) {
+		return arg, apperr.Invalid("citizenship must be an ISO 3166-1 alpha-2 code")
+	}
+	return arg, nil
+}
+
+// NextPersonalCode assigns the next free personal code for a person born on
+// birthDate. Serials come from the per-date sequence; codes already taken,
+// for example by persons registered with a code issued elsewhere, are
+// skipped. Call it inside the transaction that creates the person.
+func NextPersonalCode(ctx context.Context, q *repository.Queries, birthDate pgtype.Date, sex string) (string, error) {
+	for {
+		serial, err := q.NextPersonalCodeSerial(ctx, birthDate)
+		if err != nil {
+			return "", fmt.Errorf("failed NextPersonalCodeSerial: %w", err)
+		}
+		if serial > personalcode.MaxSerial {
+			return "", apperr.Conflict("no personal codes left for persons born on %s", birthDate.Time.Format(time.DateOnly))
+		}
+
+		code, err := personalcode.Generate(birthDate.Time, sex, int(serial))
+		if err != nil {
+			return "", apperr.Invalid("%s", err)
+		}
+
+		_, err = q.GetPersonByPersonalCode(ctx, code)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return code, nil
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed GetPersonByPersonalCode: %w", err)
+		}
+	}
+}
+
+// EnsureAlive rejects changes to a deceased person's records, and to a
+// duplicate that was merged into another person.
+func EnsureAlive(p repository.Person) error {
+	if p.MergedInto.Valid {
+		return apperr.Conflict("%s %s was merged into person %s; change that record instead", p.FirstName, p.LastName, uuid.UUID(p.MergedInto.Bytes))
+	}
+	if p.Status == StatusDeceased {
+		return apperr.Conflict("%s %s is deceased; their records can no longer be changed", p.FirstName, p.LastName)
+	}
+	return nil
+}
+
+// LockInOrder locks the given persons with SELECT ... FOR UPDATE in ID
+// order, so transactions locking overlapping sets cannot deadlock. A missing
+// person is reported as invalid input.
+func LockInOrder(ctx context.Context, q *repository.Queries, ids ...uuid.UUID) (map[uuid.UUID]repository.Person, error) {
+	sorted := slices.Clone(ids)
+	slices.SortFunc(sorted, func(a, b uuid.UUID) int { return bytes.Compare(a[:], b[:]) })
+	sorted = slices.Compact(sorted)
+
+	locked := make(map[uuid.UUID]repository.Person, len(sorted))
+	for _, id := range sorted {
+		p, err := q.GetPersonForUpdate(ctx, id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperr.Invalid("person %s not found", id)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed GetPersonForUpdate: %w", err)
+		}
+		locked[id] = p
+	}
+	return locked, nil
+}
+
+// CreateError classifies a failed Queries.CreatePerson call.
+func CreateError(err error, personalCode string) error {
+	if apperr.IsUniqueViolation(err, "person_personal_code_key") {
+		return apperr.Conflict("personal code %s is already registered", personalCode)
+	}
+	return fmt.Errorf("failed CreatePerson: %w", err)
+}
+
+func validateBirthDate(d pgtype.Date) error {
+	if !d.Valid {
+		return apperr.Invalid("birth_date is required")
+	}
+	if d.Time.After(time.Now()) {
+		return apperr.Invalid("birth_date must not be in the future")
+	}
+	return nil
+}
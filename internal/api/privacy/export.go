@@ -0,0 +1,158 @@
+package privacy
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/adoption"
+	"github.com/eif-courses/civilregistry/internal/api/application"
+	"github.com/eif-courses/civilregistry/internal/api/appointment"
+	"github.com/eif-courses/civilregistry/internal/api/attachment"
+	"github.com/eif-courses/civilregistry/internal/api/birth"
+	"github.com/eif-courses/civilregistry/internal/api/certificate"
+	"github.com/eif-courses/civilregistry/internal/api/death"
+	"github.com/eif-courses/civilregistry/internal/api/guardianship"
+	"github.com/eif-courses/civilregistry/internal/api/marriage"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/api/residence"
+	"github.com/google/uuid"
+)
+
+// Names of the files every export contains.
+const (
+	DataFile      = "data.json"
+	HTMLFile      = "index.html"
+	ManifestName  = "manifest.json"
+	SignatureFile = "manifest.sig"
+)
+
+// Export is everything the registry holds about a person, in the wire forms
+// the API uses. A birth record shows the legal parents; after an adoption
+// the birth parents stay sealed and the birth record's attachments are left
+// out.
+type Export struct {
+	PersonID      uuid.UUID                             `json:"person_id"`
+	GeneratedAt   time.Time                             `json:"generated_at"`
+	Person        person.PersonResponse                 `json:"person"`
+	Amendments    []person.HistoryResponse              `json:"amendments"`
+	Addresses     []person.AddressResponse              `json:"addresses"`
+	Birth         *birth.BirthRecordResponse            `json:"birth"`
+	Children      []person.PersonResponse               `json:"children"`
+	Marriages     []marriage.MarriageResponse           `json:"marriages"`
+	Death         *death.DeathRecordResponse            `json:"death"`
+	InformantOf   []death.DeathRecordResponse           `json:"informant_of"`
+	Certificates  []certificate.CertificateResponse     `json:"certificates"`
+	Residence     []residence.DeclarationRecordResponse `json:"residence"`
+	Adoptions     []adoption.AdoptionResponse           `json:"adoptions"`
+	Guardianships []guardianship.GuardianshipResponse   `json:"guardianships"`
+	Applications  []ApplicationExport                   `json:"applications"`
+	Appointments  []appointment.AppointmentResponse     `json:"appointments"`
+	Attachments   []attachment.AttachmentResponse       `json:"attachments"`
+	Merges        []person.PersonMergeResponse          `json:"merges"`
+	SealedAccess  []adoption.SealedRecordAccessResponse `json:"sealed_record_access"`
+	Requests      []RequestResponse                     `json:"requests"`
+	Retention     []Rule                                `json:"retention"`
+}
+
+// ApplicationExport is an application with its status history.
+type ApplicationExport struct {
+	Application application.ApplicationResponse    `json:"application"`
+	History     []application.StatusChangeResponse `json:"history"`
+}
+
+// Manifest lists every other file of an export with its size and SHA-256.
+// Its signature is in manifest.sig.
+type Manifest struct {
+	PersonID    uuid.UUID      `json:"person_id"`
+	GeneratedAt time.Time      `json:"generated_at"`
+	Files       []ManifestFile `json:"files"`
+}
+
+type ManifestFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Signer signs export manifests with HMAC-SHA256. The register keeps the
+// manifest digest and signature of every export, so a copy can later be
+// checked against what was handed out.
+type Signer struct {
+	Key []byte
+}
+
+// Sign returns the base64url encoded signature of a manifest.
+func (s Signer) Sign(manifest []byte) string {
+	mac := hmac.New(sha256.New, s.Key)
+	mac.Write(manifest)
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// archive writes the files of an export into a ZIP, recording each in the
+// manifest. The manifest and its signature go last.
+type archive struct {
+	buf      bytes.Buffer
+	zw       *zip.Writer
+	manifest Manifest
+}
+
+func newArchive(personID uuid.UUID, generatedAt time.Time) *archive {
+	a := &archive{manifest: Manifest{PersonID: personID, GeneratedAt: generatedAt}}
+	a.zw = zip.NewWriter(&a.buf)
+	return a
+}
+
+func (a *archive) add(name string, modified time.Time, r io.Reader) error {
+	w, err := a.zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return fmt.Errorf("failed to add %s to export: %w", name, err)
+	}
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(w, h), r)
+	if err != nil {
+		return fmt.Errorf("failed to write %s to export: %w", name, err)
+	}
+	a.manifest.Files = append(a.manifest.Files, ManifestFile{Name: name, Size: n, SHA256: hex.EncodeToString(h.Sum(nil))})
+	return nil
+}
+
+// close signs the manifest and finishes the ZIP. It returns the archive,
+// the manifest's SHA-256 and its signature.
+func (a *archive) close(signer Signer) (data []byte, digest, signature string, err error) {
+	manifest, err := json.MarshalIndent(a.manifest, "", "  ")
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	sum := sha256.Sum256(manifest)
+	signature = signer.Sign(manifest)
+
+	for _, f := range []struct {
+		name    string
+		content []byte
+	}{{ManifestName, manifest}, {SignatureFile, []byte(signature + "\n")}} {
+		w, err := a.zw.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: a.manifest.GeneratedAt})
+		if err != nil {
+			return nil, "", "", fmt.Errorf("failed to add %s to export: %w", f.name, err)
+		}
+		if _, err := w.Write(f.content); err != nil {
+			return nil, "", "", fmt.Errorf("failed to write %s to export: %w", f.name, err)
+		}
+	}
+	if err := a.zw.Close(); err != nil {
+		return nil, "", "", fmt.Errorf("failed to finish export: %w", err)
+	}
+	return a.buf.Bytes(), hex.EncodeToString(sum[:]), signature, nil
+}
+
+// attachmentPath is where an attachment's content lives inside the export.
+func attachmentPath(id uuid.UUID, filename string) string {
+	return "attachments/" + id.String() + "/" + filename
+}
@@ -0,0 +1,240 @@
+package privacy
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/api/request"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	service *Service
+	logger  *zap.SugaredLogger
+}
+
+func NewHandlers(service *Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type ReasonRequest struct {
+	Reason string `json:"reason" example:"request received by letter 2026-10-01"`
+}
+
+// Export downloads everything held about a person
+// @Summary Export personal data
+// @Description Download a ZIP of everything the registry holds about a person: data.json with their records,
+// @Description amendments, certificates, applications, appointments and audit entries, the same data as index.html,
+// @Description the files attached to their records and applications, and manifest.json with the SHA-256 of every
+// @Description file signed in manifest.sig. The export is recorded as an access request with the manifest digest
+// @Description and signature. After an adoption the birth parents stay sealed.
+// @Tags privacy
+// @Produce application/zip
+// @Security BearerAuth
+// @Param personID path string true "person ID"
+// @Success 200 {file} file "Signed export"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 404 {object} map[string]interface{} "Person not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/privacy/{personID}/export [get]
+func (h *Handlers) Export(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeZIP) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	personID, err := request.UUIDParam(r, "personID")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.Export(r.Context(), personID)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", render.ContentTypeZIP)
+	w.Header().Set("Content-Disposition", `attachment; filename="`+result.Filename+`"`)
+	w.Header().Set("Content-Length", strconv.Itoa(len(result.Archive)))
+	if _, err := w.Write(result.Archive); err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// ListRequests lists a person's requests about their data
+// @Summary List data subject requests
+// @Description List the exports, restrictions and erasures made for a person, oldest first. Exports show the
+// @Description manifest and the digest and signature handed out with it; erasures show what was erased and retained.
+// @Tags privacy
+// @Produce json
+// @Security BearerAuth
+// @Param personID path string true "person ID"
+// @Success 200 {object} RequestListEnvelope "Requests"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 404 {object} map[string]interface{} "Person not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/privacy/{personID}/requests [get]
+func (h *Handlers) ListRequests(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	personID, err := request.UUIDParam(r, "personID")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.ListRequests(r.Context(), personID)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	err = render.Write(w, http.StatusOK, render.ContentTypeJSON, RequestListEnvelope{
+		Count: len(result),
+		Data:  NewRequestResponses(result),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// Restrict restricts processing of a person's data
+// @Summary Restrict processing
+// @Description Restrict processing of a person's data. They are left out of person search and duplicate detection
+// @Description until the restriction is lifted; their civil status is still recorded as the law requires.
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param personID path string true "person ID"
+// @Param request body ReasonRequest true "reason for the restriction"
+// @Success 200 {object} HandledEnvelope "Restriction recorded"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 404 {object} map[string]interface{} "Person not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Already restricted or merged"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/privacy/{personID}/restriction [post]
+func (h *Handlers) Restrict(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, h.service.Restrict, "processing restricted successfully")
+}
+
+// LiftRestriction lifts a restriction of processing
+// @Summary Lift restriction
+// @Description End a restriction of processing. The person is found by search and duplicate detection again.
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param personID path string true "person ID"
+// @Param request body ReasonRequest true "reason for lifting"
+// @Success 200 {object} HandledEnvelope "Restriction lifted"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 404 {object} map[string]interface{} "Person not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Not restricted or merged"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/privacy/{personID}/restriction/lift [post]
+func (h *Handlers) LiftRestriction(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, h.service.LiftRestriction, "restriction lifted successfully")
+}
+
+// Erase erases a person's data the retention rules no longer require
+// @Summary Erase personal data
+// @Description Erase what no retention rule requires any more: contact addresses, contact details of past or cancelled
+// @Description appointments, and applications decided more than five years ago with their attachments. Civil status
+// @Description records, residence declarations and audit entries are kept permanently. The outcome lists what was
+// @Description erased and what was retained with the reason and, where known, the date it may be erased.
+// @Tags privacy
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param personID path string true "person ID"
+// @Param request body ReasonRequest true "reason for the erasure"
+// @Success 200 {object} HandledEnvelope "Erasure recorded"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 404 {object} map[string]interface{} "Person not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Person merged"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/privacy/{personID}/erasure [post]
+func (h *Handlers) Erase(w http.ResponseWriter, r *http.Request) {
+	h.handle(w, r, h.service.Erase, "erasure completed successfully")
+}
+
+// handle decodes a reason and runs one of the request workflows on the
+// person in the path.
+func (h *Handlers) handle(w http.ResponseWriter, r *http.Request, run func(ctx context.Context, personID uuid.UUID, reason string) (*Handled, error), message string) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	personID, err := request.UUIDParam(r, "personID")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	var req ReasonRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := run(r.Context(), personID, req.Reason)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	err = render.Write(w, http.StatusOK, render.ContentTypeJSON, HandledEnvelope{
+		Message: message,
+		Data: HandledResponse{
+			Request: NewRequestResponse(result.Request),
+			Person:  person.NewPersonResponse(result.Person),
+		},
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// serviceError maps a service failure onto an HTTP status.
+func (h *Handlers) serviceError(w http.ResponseWriter, err error) {
+	status, msg := apperr.Status(err)
+	if status >= http.StatusInternalServerError {
+		h.logger.Errorf("Service error: %v", err)
+	}
+	if status == http.StatusNotFound && msg == "not found" {
+		msg = "person not found"
+	}
+	http.Error(w, msg, status)
+}
@@ -0,0 +1,210 @@
+package privacy
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// page is the human-readable copy of an export. Each section lists its
+// entries as label and value pairs taken from the JSON in data.json, so the
+// two always hold the same data.
+type page struct {
+	Name         string
+	PersonalCode string
+	GeneratedAt  string
+	Sections     []pageSection
+}
+
+type pageSection struct {
+	Title   string
+	Entries [][]pageField
+}
+
+type pageField struct {
+	Label string
+	Value string
+}
+
+var pageTemplate = template.Must(template.New("export").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="UTF-8">
+<title>Personal data of {{.Name}}</title>
+<style>
+body { font-family: sans-serif; margin: 2rem auto; max-width: 60rem; color: #1f2937; }
+h1 { font-size: 1.5rem; }
+h2 { font-size: 1.2rem; margin-top: 2rem; border-bottom: 1px solid #d1d5db; }
+table { border-collapse: collapse; width: 100%; margin: 0.75rem 0; }
+th, td { text-align: left; vertical-align: top; padding: 0.25rem 0.5rem; border: 1px solid #e5e7eb; }
+th { width: 30%; background: #f9fafb; font-weight: normal; color: #4b5563; }
+.empty { color: #6b7280; }
+</style>
+</head>
+<body>
+<h1>Personal data held about {{.Name}}</h1>
+<p>Personal code {{.PersonalCode}}. Generated on {{.GeneratedAt}}.</p>
+<p>data.json holds the same data in machine-readable form. manifest.json lists every file of this export with its
+SHA-256 digest and manifest.sig is the registry's signature of it.</p>
+{{range .Sections}}
+<h2>{{.Title}}</h2>
+{{if not .Entries}}<p class="empty">None.</p>{{end}}
+{{range .Entries}}<table>
+{{range .}}<tr><th>{{.Label}}</th><td>{{.Value}}</td></tr>
+{{end}}</table>
+{{end}}{{end}}
+</body>
+</html>
+`))
+
+// writeHTML renders the export as a standalone HTML page.
+func writeHTML(w io.Writer, e Export) error {
+	p := page{
+		Name:         e.Person.FirstName + " " + e.Person.LastName,
+		PersonalCode: e.Person.PersonalCode,
+		GeneratedAt:  e.GeneratedAt.Format(time.RFC1123),
+	}
+	for _, s := range []struct {
+		title string
+		data  any
+	}{
+		{"Person", e.Person},
+		{"Amendments", e.Amendments},
+		{"Contact addresses", e.Addresses},
+		{"Birth record", e.Birth},
+		{"Children", e.Children},
+		{"Marriages", e.Marriages},
+		{"Death record", e.Death},
+		{"Deaths reported as informant", e.InformantOf},
+		{"Certificates", e.Certificates},
+		{"Declared places of residence", e.Residence},
+		{"Adoptions", e.Adoptions},
+		{"Guardianships", e.Guardianships},
+		{"Applications", e.Applications},
+		{"Appointments", e.Appointments},
+		{"Attachments", e.Attachments},
+		{"Merges", e.Merges},
+		{"Openings of the sealed birth record", e.SealedAccess},
+		{"Requests about personal data", e.Requests},
+		{"How long data is kept", e.Retention},
+	} {
+		entries, err := sectionEntries(s.data)
+		if err != nil {
+			return fmt.Errorf("failed to render %s: %w", s.title, err)
+		}
+		p.Sections = append(p.Sections, pageSection{Title: s.title, Entries: entries})
+	}
+	return pageTemplate.Execute(w, p)
+}
+
+// sectionEntries turns a single object into one entry and a list into one
+// entry per element; null and empty lists have none.
+func sectionEntries(v any) ([][]pageField, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	var entries [][]pageField
+	switch tok {
+	case nil:
+	case json.Delim('['):
+		for dec.More() {
+			var fields []pageField
+			if err := flatten(dec, "", &fields); err != nil {
+				return nil, err
+			}
+			entries = append(entries, fields)
+		}
+	case json.Delim('{'):
+		var fields []pageField
+		if err := flattenObject(dec, "", &fields); err != nil {
+			return nil, err
+		}
+		entries = append(entries, fields)
+	default:
+		return nil, fmt.Errorf("unexpected %v", tok)
+	}
+	return entries, nil
+}
+
+// flatten reads one JSON value, adding its leaves to fields in document
+// order. Nested members get the parent's label as a prefix.
+func flatten(dec *json.Decoder, label string, fields *[]pageField) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch tok {
+	case json.Delim('{'):
+		return flattenObject(dec, label, fields)
+	case json.Delim('['):
+		for i := 1; dec.More(); i++ {
+			if err := flatten(dec, join(label, strconv.Itoa(i)), fields); err != nil {
+				return err
+			}
+		}
+		_, err := dec.Token()
+		return err
+	}
+	*fields = append(*fields, pageField{Label: label, Value: value(tok)})
+	return nil
+}
+
+func flattenObject(dec *json.Decoder, label string, fields *[]pageField) error {
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		name, _ := key.(string)
+		if err := flatten(dec, join(label, humanize(name)), fields); err != nil {
+			return err
+		}
+	}
+	_, err := dec.Token()
+	return err
+}
+
+func value(tok json.Token) string {
+	switch v := tok.(type) {
+	case nil:
+		return "—"
+	case bool:
+		if v {
+			return "yes"
+		}
+		return "no"
+	case json.Number:
+		return v.String()
+	case string:
+		return v
+	}
+	return fmt.Sprint(tok)
+}
+
+// humanize turns a JSON member name such as birth_date into "Birth date".
+func humanize(name string) string {
+	name = strings.ReplaceAll(name, "_", " ")
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+func join(prefix, name string) string {
+	if prefix == "" {
+		return name
+	}
+	return prefix + " › " + name
+}
@@ -0,0 +1,72 @@
+package privacy
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+)
+
+// RequestResponse is the wire form of repository.DataSubjectRequest.
+// Outcome is the export's manifest for access requests and the
+// ErasureOutcome for erasures.
+type RequestResponse struct {
+	ID             uuid.UUID       `json:"id"`
+	PersonID       uuid.UUID       `json:"person_id"`
+	Kind           string          `json:"kind" enums:"access,restriction,lift_restriction,erasure"`
+	Reason         string          `json:"reason"`
+	Outcome        json.RawMessage `json:"outcome" swaggertype:"object"`
+	ManifestSHA256 *string         `json:"manifest_sha256"`
+	Signature      *string         `json:"signature"`
+	HandledBy      string          `json:"handled_by"`
+	OfficeID       *uuid.UUID      `json:"office_id"`
+	RegistrarID    *uuid.UUID      `json:"registrar_id"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+func NewRequestResponse(row repository.DataSubjectRequest) RequestResponse {
+	return RequestResponse{
+		ID:             row.ID,
+		PersonID:       row.PersonID,
+		Kind:           row.Kind,
+		Reason:         row.Reason,
+		Outcome:        json.RawMessage(row.Outcome),
+		ManifestSHA256: render.Nullable(row.ManifestSha256.String, row.ManifestSha256.Valid),
+		Signature:      render.Nullable(row.Signature.String, row.Signature.Valid),
+		HandledBy:      row.HandledBy,
+		OfficeID:       render.Nullable(uuid.UUID(row.OfficeID.Bytes), row.OfficeID.Valid),
+		RegistrarID:    render.Nullable(uuid.UUID(row.RegistrarID.Bytes), row.RegistrarID.Valid),
+		CreatedAt:      row.CreatedAt,
+	}
+}
+
+func NewRequestResponses(rows []repository.DataSubjectRequest) []RequestResponse {
+	items := make([]RequestResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, NewRequestResponse(row))
+	}
+	return items
+}
+
+// HandledResponse is a handled request with the person as they are
+// afterwards.
+type HandledResponse struct {
+	Request RequestResponse       `json:"request"`
+	Person  person.PersonResponse `json:"person"`
+}
+
+// HandledEnvelope is the response body for a restriction, its lifting or
+// an erasure.
+type HandledEnvelope struct {
+	Message string          `json:"message,omitempty"`
+	Data    HandledResponse `json:"data"`
+}
+
+// RequestListEnvelope is the response body for a person's requests.
+type RequestListEnvelope struct {
+	Count int               `json:"count"`
+	Data  []RequestResponse `json:"data"`
+}
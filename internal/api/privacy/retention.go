@@ -0,0 +1,93 @@
+// Package privacy handles a person's requests about their own data: a
+// signed copy of everything the registry holds about them, restricting its
+// processing, and erasing what the retention rules no longer require.
+package privacy
+
+import (
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+)
+
+// Categories of personal data, each with its own retention rule.
+const (
+	CategoryCivilRecords = "civil_records"
+	CategoryResidence    = "residence"
+	CategoryAudit        = "audit"
+	CategoryApplications = "applications"
+	CategoryAttachments  = "attachments"
+	CategoryAppointments = "appointments"
+	CategoryContact      = "contact_addresses"
+)
+
+// ApplicationRetentionYears is how long a decided application, its status
+// history and its attachments are kept.
+const ApplicationRetentionYears = 5
+
+// Rule says how long a category of data is kept and why. Erasable data may
+// be erased on request once its period is over.
+type Rule struct {
+	Category string `json:"category"`
+	Data     string `json:"data"`
+	Period   string `json:"period"`
+	Basis    string `json:"basis"`
+	Erasable bool   `json:"erasable"`
+}
+
+// Rules are the retention rules, in the order exports list them.
+var Rules = []Rule{
+	{
+		Category: CategoryCivilRecords,
+		Data:     "person record, amendments, birth, marriage and death records, certificates, adoptions and guardianships",
+		Period:   "permanently",
+		Basis:    "civil status records are kept permanently by law",
+	},
+	{
+		Category: CategoryResidence,
+		Data:     "declared places of residence",
+		Period:   "permanently",
+		Basis:    "the residence register keeps the full history of declarations",
+	},
+	{
+		Category: CategoryAudit,
+		Data:     "merges, openings of sealed records and requests about personal data",
+		Period:   "permanently",
+		Basis:    "kept as evidence of how the register was changed and used",
+	},
+	{
+		Category: CategoryApplications,
+		Data:     "applications, their status history and attachments",
+		Period:   "5 years after the decision",
+		Basis:    "kept while the decision can be appealed or audited",
+		Erasable: true,
+	},
+	{
+		Category: CategoryAppointments,
+		Data:     "appointment bookings",
+		Period:   "until the appointment has taken place or been cancelled",
+		Basis:    "needed to hold the booking",
+		Erasable: true,
+	},
+	{
+		Category: CategoryContact,
+		Data:     "contact addresses",
+		Period:   "until erasure is requested",
+		Basis:    "given voluntarily for correspondence",
+		Erasable: true,
+	},
+}
+
+// applicationRetainedUntil returns when a decided application may be
+// erased. Undecided applications are kept, which ok reports as false.
+func applicationRetainedUntil(a repository.Application) (until time.Time, ok bool) {
+	if !a.DecidedAt.Valid {
+		return time.Time{}, false
+	}
+	return a.DecidedAt.Time.AddDate(ApplicationRetentionYears, 0, 0), true
+}
+
+// appointmentErasable reports whether a booking no longer needs its
+// contact details: it was cancelled or has ended.
+func appointmentErasable(a repository.Appointment, now time.Time) bool {
+	return a.CancelledAt.Valid || !a.EndsAt.After(now)
+}
@@ -0,0 +1,25 @@
+package privacy
+
+import (
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/storage"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func PrivacyRouter(db txn.Beginner, queries *repository.Queries, store storage.BlobStore, opts Options, log *zap.SugaredLogger) chi.Router {
+	r := chi.NewRouter()
+
+	service := NewService(db, queries, store, opts, log)
+	handlers := NewHandlers(service, log)
+
+	r.Get("/{personID}/export", telemetry.InstrumentHandler("privacy", "Export", handlers.Export))
+	r.Get("/{personID}/requests", telemetry.InstrumentHandler("privacy", "ListRequests", handlers.ListRequests))
+	r.Post("/{personID}/restriction", telemetry.InstrumentHandler("privacy", "Restrict", handlers.Restrict))
+	r.Post("/{personID}/restriction/lift", telemetry.InstrumentHandler("privacy", "LiftRestriction", handlers.LiftRestriction))
+	r.Post("/{personID}/erasure", telemetry.InstrumentHandler("privacy", "Erase", handlers.Erase))
+
+	return r
+}
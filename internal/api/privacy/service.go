@@ -0,0 +1,594 @@
+package privacy
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/adoption"
+	"github.com/eif-courses/civilregistry/internal/api/application"
+	"github.com/eif-courses/civilregistry/internal/api/appointment"
+	"github.com/eif-courses/civilregistry/internal/api/attachment"
+	"github.com/eif-courses/civilregistry/internal/api/birth"
+	"github.com/eif-courses/civilregistry/internal/api/certificate"
+	"github.com/eif-courses/civilregistry/internal/api/death"
+	"github.com/eif-courses/civilregistry/internal/api/guardianship"
+	"github.com/eif-courses/civilregistry/internal/api/marriage"
+	"github.com/eif-courses/civilregistry/internal/api/office"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/api/residence"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/eif-courses/civilregistry/internal/storage"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Kinds of requests a person makes about their data.
+const (
+	KindAccess          = "access"
+	KindRestriction     = "restriction"
+	KindLiftRestriction = "lift_restriction"
+	KindErasure         = "erasure"
+)
+
+// Options configures exports. Signer signs their manifests; Verification
+// builds the links of exported certificates.
+type Options struct {
+	Signer       Signer
+	Verification certificate.Verification
+}
+
+type Service struct {
+	db     txn.Beginner
+	repo   *repository.Queries
+	store  storage.BlobStore
+	opts   Options
+	logger *zap.SugaredLogger
+}
+
+func NewService(db txn.Beginner, repo *repository.Queries, store storage.BlobStore, opts Options, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		db:     db,
+		repo:   repo,
+		store:  store,
+		opts:   opts,
+		logger: logger,
+	}
+}
+
+// ExportResult is a finished export and the request that records it.
+type ExportResult struct {
+	Request  repository.DataSubjectRequest
+	Filename string
+	Archive  []byte
+}
+
+// Handled is a restriction, its lifting or an erasure, with the person as
+// they are afterwards.
+type Handled struct {
+	Request repository.DataSubjectRequest
+	Person  repository.Person
+}
+
+// ErasureOutcome is what an erasure removed, counted per category, and
+// what it kept and why.
+type ErasureOutcome struct {
+	Erased   map[string]int64 `json:"erased"`
+	Retained []Retained       `json:"retained"`
+}
+
+// Retained is data an erasure kept. Reference names a single application
+// or appointment; Until is when it may be erased, if that is known yet.
+type Retained struct {
+	Category  string       `json:"category"`
+	Reference string       `json:"reference,omitempty"`
+	Until     *render.Date `json:"until,omitempty"`
+	Basis     string       `json:"basis"`
+}
+
+// Export builds a signed ZIP of everything held about the person: data.json,
+// the same data as index.html, the files attached to their records and
+// applications, and a signed manifest of them all. The export is recorded
+// as an access request with the manifest and its signature.
+func (s *Service) Export(ctx context.Context, personID uuid.UUID) (_ *ExportResult, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "privacy", "Export")
+	defer func() { op.End(err) }()
+
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Exporting personal data of %s", personID)
+
+	e, files, err := s.gather(ctx, personID)
+	if err != nil {
+		s.logger.Errorf("Failed to gather personal data of %s: %v", personID, err)
+		return nil, err
+	}
+
+	a := newArchive(personID, e.GeneratedAt)
+	data, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode export: %w", err)
+	}
+	if err := a.add(DataFile, e.GeneratedAt, bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	var page bytes.Buffer
+	if err := writeHTML(&page, *e); err != nil {
+		return nil, err
+	}
+	if err := a.add(HTMLFile, e.GeneratedAt, &page); err != nil {
+		return nil, err
+	}
+	for _, f := range files {
+		content, err := s.store.Open(ctx, f.Sha256)
+		if err != nil {
+			s.logger.Errorf("Failed to open blob %s of attachment %s: %v", f.Sha256, f.ID, err)
+			return nil, fmt.Errorf("failed to open attachment content: %w", err)
+		}
+		err = a.add(attachmentPath(f.ID, f.Filename), f.UploadedAt, content)
+		content.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	manifest := a.manifest
+	archive, digest, signature, err := a.close(s.opts.Signer)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := record(ctx, s.repo, p, personID, KindAccess, "", manifest,
+		pgtype.Text{String: digest, Valid: true}, pgtype.Text{String: signature, Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Export of %s completed with %d files, manifest %s", personID, len(manifest.Files), digest)
+	return &ExportResult{
+		Request:  req,
+		Filename: "personal-data-" + e.Person.PersonalCode + "-" + e.GeneratedAt.Format("20060102") + ".zip",
+		Archive:  archive,
+	}, nil
+}
+
+// Restrict restricts processing of the person's data: they are left out of
+// searches and duplicate detection until the restriction is lifted. Their
+// civil status is still recorded.
+func (s *Service) Restrict(ctx context.Context, personID uuid.UUID, reason string) (_ *Handled, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "privacy", "Restrict")
+	defer func() { op.End(err) }()
+
+	return s.setRestricted(ctx, personID, reason, true)
+}
+
+// LiftRestriction ends a restriction of processing.
+func (s *Service) LiftRestriction(ctx context.Context, personID uuid.UUID, reason string) (_ *Handled, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "privacy", "LiftRestriction")
+	defer func() { op.End(err) }()
+
+	return s.setRestricted(ctx, personID, reason, false)
+}
+
+func (s *Service) setRestricted(ctx context.Context, personID uuid.UUID, reason string, restrict bool) (*Handled, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, apperr.Invalid("reason is required")
+	}
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var h Handled
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		current, err := lockPerson(ctx, q, personID)
+		if err != nil {
+			return err
+		}
+		kind, at := KindRestriction, pgtype.Timestamptz{Time: time.Now(), Valid: true}
+		switch {
+		case restrict && current.ProcessingRestrictedAt.Valid:
+			return apperr.Conflict("processing of %s %s's data is already restricted", current.FirstName, current.LastName)
+		case !restrict && !current.ProcessingRestrictedAt.Valid:
+			return apperr.Conflict("processing of %s %s's data is not restricted", current.FirstName, current.LastName)
+		case !restrict:
+			kind, at = KindLiftRestriction, pgtype.Timestamptz{}
+		}
+		if h.Person, err = q.SetPersonProcessingRestricted(ctx, repository.SetPersonProcessingRestrictedParams{
+			ID:           personID,
+			RestrictedAt: at,
+		}); err != nil {
+			return fmt.Errorf("failed SetPersonProcessingRestricted: %w", err)
+		}
+		h.Request, err = record(ctx, q, p, personID, kind, reason, struct{}{}, pgtype.Text{}, pgtype.Text{})
+		return err
+	})
+	if err != nil {
+		s.logger.Errorf("Failed to change the restriction of %s: %v", personID, err)
+		return nil, err
+	}
+	return &h, nil
+}
+
+// Erase erases the person's data that no retention rule requires any more:
+// contact addresses, the contact details of past or cancelled appointments,
+// and applications decided more than ApplicationRetentionYears ago with
+// their attachments. Civil status records and everything else still
+// retained are listed in the outcome with the reason.
+func (s *Service) Erase(ctx context.Context, personID uuid.UUID, reason string) (_ *Handled, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "privacy", "Erase")
+	defer func() { op.End(err) }()
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, apperr.Invalid("reason is required")
+	}
+	p, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Erasing personal data of %s", personID)
+
+	var (
+		h      Handled
+		unused []string
+	)
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		var err error
+		if h.Person, err = lockPerson(ctx, q, personID); err != nil {
+			return err
+		}
+		outcome, err := s.erase(ctx, q, h.Person, time.Now(), &unused)
+		if err != nil {
+			return err
+		}
+		h.Request, err = record(ctx, q, p, personID, KindErasure, reason, outcome, pgtype.Text{}, pgtype.Text{})
+		return err
+	})
+	if err != nil {
+		s.logger.Errorf("Failed to erase personal data of %s: %v", personID, err)
+		return nil, err
+	}
+	// The erasure is recorded; a blob left behind here holds no attachment
+	// and is only logged for cleanup.
+	if err := attachment.DeleteBlobs(ctx, s.db, s.repo, s.store, unused...); err != nil {
+		s.logger.Errorf("Failed to delete erased attachment content of %s: %v", personID, err)
+	}
+
+	s.logger.Infof("Erasure of %s completed with request ID: %s", personID, h.Request.ID)
+	return &h, nil
+}
+
+// erase erases what the retention rules allow inside the caller's
+// transaction. The digests of attachment content no longer used are
+// appended to unused for deletion after the transaction commits.
+func (s *Service) erase(ctx context.Context, q *repository.Queries, p repository.Person, now time.Time, unused *[]string) (*ErasureOutcome, error) {
+	outcome := &ErasureOutcome{Erased: map[string]int64{
+		CategoryContact:      0,
+		CategoryAppointments: 0,
+		CategoryApplications: 0,
+		CategoryAttachments:  0,
+	}}
+
+	n, err := q.DeletePersonAddresses(ctx, p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed DeletePersonAddresses: %w", err)
+	}
+	outcome.Erased[CategoryContact] = n
+
+	appointments, err := q.ListAppointmentsByPersonalCode(ctx, pgtype.Text{String: p.PersonalCode, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed ListAppointmentsByPersonalCode: %w", err)
+	}
+	for _, a := range appointments {
+		if !appointmentErasable(a, now) {
+			outcome.Retained = append(outcome.Retained, Retained{
+				Category:  CategoryAppointments,
+				Reference: a.Reference,
+				Until:     render.NullableDate(a.EndsAt, true),
+				Basis:     "the appointment is still ahead; cancel it first",
+			})
+			continue
+		}
+		if err := q.EraseAppointment(ctx, a.ID); err != nil {
+			return nil, fmt.Errorf("failed EraseAppointment: %w", err)
+		}
+		outcome.Erased[CategoryAppointments]++
+	}
+
+	applications, err := q.ListApplicationsByPersonalCode(ctx, p.PersonalCode)
+	if err != nil {
+		return nil, fmt.Errorf("failed ListApplicationsByPersonalCode: %w", err)
+	}
+	for _, a := range applications {
+		until, decided := applicationRetainedUntil(a)
+		if !decided || until.After(now) {
+			r := Retained{Category: CategoryApplications, Reference: a.Number, Basis: "the application is still open"}
+			if decided {
+				r.Until = render.NullableDate(until, true)
+				r.Basis = fmt.Sprintf("applications are kept %d years after the decision", ApplicationRetentionYears)
+			}
+			outcome.Retained = append(outcome.Retained, r)
+			continue
+		}
+		files, err := q.ListAttachmentsByOwner(ctx, repository.ListAttachmentsByOwnerParams{
+			OwnerKind: attachment.OwnerApplication,
+			OwnerID:   a.ID,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed ListAttachmentsByOwner: %w", err)
+		}
+		for _, f := range files {
+			digest, err := attachment.Remove(ctx, q, f)
+			if err != nil {
+				return nil, err
+			}
+			if digest != "" {
+				*unused = append(*unused, digest)
+			}
+			outcome.Erased[CategoryAttachments]++
+		}
+		if err := q.EraseApplication(ctx, a.ID); err != nil {
+			return nil, fmt.Errorf("failed EraseApplication: %w", err)
+		}
+		if err := q.EraseApplicationStatusNotes(ctx, a.ID); err != nil {
+			return nil, fmt.Errorf("failed EraseApplicationStatusNotes: %w", err)
+		}
+		outcome.Erased[CategoryApplications]++
+	}
+
+	for _, rule := range Rules {
+		if !rule.Erasable {
+			outcome.Retained = append(outcome.Retained, Retained{Category: rule.Category, Basis: rule.Basis})
+		}
+	}
+	return outcome, nil
+}
+
+// ListRequests returns the person's requests about their data, oldest
+// first.
+func (s *Service) ListRequests(ctx context.Context, personID uuid.UUID) (_ []repository.DataSubjectRequest, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "privacy", "ListRequests")
+	defer func() { op.End(err) }()
+
+	if _, err := auth.RequireRegistrar(ctx); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetPersonByID(ctx, personID); err != nil {
+		return nil, fmt.Errorf("failed GetPersonByID: %w", err)
+	}
+	result, err := s.repo.ListDataSubjectRequests(ctx, personID)
+	if err != nil {
+		return nil, fmt.Errorf("failed ListDataSubjectRequests: %w", err)
+	}
+	return result, nil
+}
+
+// gather collects the export and the attachments whose content goes with
+// it.
+func (s *Service) gather(ctx context.Context, personID uuid.UUID) (*Export, []repository.Attachment, error) {
+	p, err := s.repo.GetPersonByID(ctx, personID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed GetPersonByID: %w", err)
+	}
+	e := &Export{
+		PersonID:    personID,
+		GeneratedAt: time.Now().UTC().Truncate(time.Second),
+		Person:      person.NewPersonResponse(p),
+		Retention:   Rules,
+	}
+	var files []repository.Attachment
+	attach := func(kind string, id uuid.UUID) error {
+		rows, err := s.repo.ListAttachmentsByOwner(ctx, repository.ListAttachmentsByOwnerParams{OwnerKind: kind, OwnerID: id})
+		if err != nil {
+			return fmt.Errorf("failed ListAttachmentsByOwner: %w", err)
+		}
+		files = append(files, rows...)
+		return nil
+	}
+
+	history, err := s.repo.ListPersonHistory(ctx, personID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed ListPersonHistory: %w", err)
+	}
+	e.Amendments = person.NewHistoryResponses(history)
+	addresses, err := s.repo.ListPersonAddresses(ctx, personID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed ListPersonAddresses: %w", err)
+	}
+	e.Addresses = person.NewAddressResponses(addresses)
+
+	record, err := s.repo.GetBirthRecordByPersonID(ctx, personID)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return nil, nil, fmt.Errorf("failed GetBirthRecordByPersonID: %w", err)
+	default:
+		parents, err := s.repo.GetLegalParentage(ctx, personID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed GetLegalParentage: %w", err)
+		}
+		// An adoption seals the birth parents, and with them the documents
+		// filed with the birth record
+		if parents.AdoptionID.Valid {
+			record.MotherID, record.FatherID = parents.MotherID, parents.FatherID
+		} else if err := attach(attachment.OwnerBirth, record.ID); err != nil {
+			return nil, nil, err
+		}
+		resp := birth.NewBirthRecordResponse(record)
+		e.Birth = &resp
+	}
+
+	children, err := s.repo.ListLegalChildren(ctx, personID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed ListLegalChildren: %w", err)
+	}
+	e.Children = person.NewPersonResponses(children)
+
+	marriages, err := s.repo.ListMarriagesForPerson(ctx, personID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed ListMarriagesForPerson: %w", err)
+	}
+	e.Marriages = marriage.NewMarriageResponses(marriages)
+	for _, m := range marriages {
+		if err := attach(attachment.OwnerMarriage, m.ID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	deathRecord, err := s.repo.GetDeathRecordByPersonID(ctx, personID)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+	case err != nil:
+		return nil, nil, fmt.Errorf("failed GetDeathRecordByPersonID: %w", err)
+	default:
+		resp := death.NewDeathRecordResponse(deathRecord)
+		e.Death = &resp
+		if err := attach(attachment.OwnerDeath, deathRecord.ID); err != nil {
+			return nil, nil, err
+		}
+	}
+	informed, err := s.repo.ListDeathRecordsByInformant(ctx, pgtype.UUID{Bytes: personID, Valid: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed ListDeathRecordsByInformant: %w", err)
+	}
+	e.InformantOf = death.NewDeathRecordResponses(informed)
+
+	certificates, err := s.repo.ListCertificatesForPerson(ctx, personID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed ListCertificatesForPerson: %w", err)
+	}
+	if e.Certificates, err = certificate.NewCertificateResponses(certificates, s.opts.Verification); err != nil {
+		return nil, nil, err
+	}
+
+	declarations, err := s.repo.ListResidenceDeclarationsForPerson(ctx, personID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed ListResidenceDeclarationsForPerson: %w", err)
+	}
+	e.Residence = make([]residence.DeclarationRecordResponse, 0, len(declarations))
+	for _, d := range declarations {
+		e.Residence = append(e.Residence, residence.NewDeclarationRecordResponse(d))
+	}
+
+	adoptions, err := s.repo.ListAdoptionsForPerson(ctx, personID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed ListAdoptionsForPerson: %w", err)
+	}
+	e.Adoptions = adoption.NewAdoptionResponses(adoptions)
+	e.SealedAccess = []adoption.SealedRecordAccessResponse{}
+	for _, a := range adoptions {
+		if a.PersonID != personID {
+			continue
+		}
+		access, err := s.repo.ListSealedRecordAccess(ctx, a.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed ListSealedRecordAccess: %w", err)
+		}
+		e.SealedAccess = append(e.SealedAccess, adoption.NewSealedRecordAccessResponses(access)...)
+	}
+
+	guardianships, err := s.repo.ListGuardianshipsForPerson(ctx, personID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed ListGuardianshipsForPerson: %w", err)
+	}
+	e.Guardianships = guardianship.NewGuardianshipResponses(guardianships)
+
+	applications, err := s.repo.ListApplicationsByPersonalCode(ctx, p.PersonalCode)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed ListApplicationsByPersonalCode: %w", err)
+	}
+	e.Applications = make([]ApplicationExport, 0, len(applications))
+	for _, a := range applications {
+		resp, err := application.NewApplicationResponse(a)
+		if err != nil {
+			return nil, nil, err
+		}
+		changes, err := s.repo.ListApplicationStatusHistory(ctx, a.ID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed ListApplicationStatusHistory: %w", err)
+		}
+		e.Applications = append(e.Applications, ApplicationExport{
+			Application: resp,
+			History:     application.NewStatusChangeResponses(changes),
+		})
+		if err := attach(attachment.OwnerApplication, a.ID); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	appointments, err := s.repo.ListAppointmentsByPersonalCode(ctx, pgtype.Text{String: p.PersonalCode, Valid: true})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed ListAppointmentsByPersonalCode: %w", err)
+	}
+	e.Appointments = appointment.NewAppointmentResponses(appointments)
+
+	e.Attachments = make([]attachment.AttachmentResponse, 0, len(files))
+	for _, f := range files {
+		e.Attachments = append(e.Attachments, attachment.NewAttachmentResponse(f, attachmentPath(f.ID, f.Filename)))
+	}
+
+	merges, err := s.repo.ListPersonMerges(ctx, personID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed ListPersonMerges: %w", err)
+	}
+	e.Merges = person.NewPersonMergeResponses(merges)
+
+	requests, err := s.repo.ListDataSubjectRequests(ctx, personID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed ListDataSubjectRequests: %w", err)
+	}
+	e.Requests = NewRequestResponses(requests)
+	return e, files, nil
+}
+
+// lockPerson locks a person who can still make requests about their data;
+// a merged duplicate's data now belongs to the survivor.
+func lockPerson(ctx context.Context, q *repository.Queries, id uuid.UUID) (repository.Person, error) {
+	p, err := q.GetPersonForUpdate(ctx, id)
+	if err != nil {
+		return repository.Person{}, fmt.Errorf("failed GetPersonForUpdate: %w", err)
+	}
+	if p.MergedInto.Valid {
+		return repository.Person{}, apperr.Conflict("%s %s was merged into person %s; make the request for that person", p.FirstName, p.LastName, uuid.UUID(p.MergedInto.Bytes))
+	}
+	return p, nil
+}
+
+func record(ctx context.Context, q *repository.Queries, p *auth.Principal, personID uuid.UUID, kind, reason string, outcome any, digest, signature pgtype.Text) (repository.DataSubjectRequest, error) {
+	data, err := json.Marshal(outcome)
+	if err != nil {
+		return repository.DataSubjectRequest{}, fmt.Errorf("failed to encode outcome: %w", err)
+	}
+	officeID, registrarID := office.RecordedBy(p)
+	req, err := q.CreateDataSubjectRequest(ctx, repository.CreateDataSubjectRequestParams{
+		PersonID:       personID,
+		Kind:           kind,
+		Reason:         reason,
+		Outcome:        data,
+		ManifestSha256: digest,
+		Signature:      signature,
+		HandledBy:      p.Name(),
+		OfficeID:       officeID,
+		RegistrarID:    registrarID,
+	})
+	if err != nil {
+		return repository.DataSubjectRequest{}, fmt.Errorf("failed CreateDataSubjectRequest: %w", err)
+	}
+	return req, nil
+}
@@ -0,0 +1,113 @@
+package report
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/request"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/go-chi/chi/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	service *Service
+	logger  *zap.SugaredLogger
+}
+
+func NewHandlers(service *Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetReport counts births, marriages or deaths
+// @Summary Statistical report
+// @Description Count births (by date of birth), marriages (by registration date, annulled ones left out) or deaths
+// @Description (by date of death) per month, quarter or year, optionally broken down by office, municipality, sex or
+// @Description age group. Births are broken down by the child's sex and the mother's age group; marriages by sex
+// @Description and age group count spouses rather than marriages. The range defaults to the last 12 months and
+// @Description covers at most 120 periods.
+// @Tags report
+// @Produce json,text/csv
+// @Security BearerAuth
+// @Param event path string true "births, marriages or deaths"
+// @Param period query string false "month (default), quarter or year"
+// @Param from query string false "first day (YYYY-MM-DD)"
+// @Param to query string false "last day (YYYY-MM-DD, default today)"
+// @Param by query string false "office, municipality, sex or age_group"
+// @Param office_id query string false "only events registered by this office"
+// @Param municipality query string false "only events registered by offices of this municipality"
+// @Success 200 {object} ReportEnvelope "Report"
+// @Failure 400 {object} map[string]interface{} "Invalid filter"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "Unknown report"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/reports/{event} [get]
+func (h *Handlers) GetReport(w http.ResponseWriter, r *http.Request) {
+	format := render.Negotiate(r, render.ContentTypeJSON, render.ContentTypeCSV)
+	if format == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	f, err := ParseFilter(r, chi.URLParam(r, "event"))
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.Report(r.Context(), f)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	if format == render.ContentTypeCSV {
+		err = render.WriteCSV(w, http.StatusOK, ReportCSVHeader, NewReportRows(*result))
+	} else {
+		err = render.Write(w, http.StatusOK, format, ReportEnvelope{Data: NewReportResponse(*result)})
+	}
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// ParseFilter reads a report filter from the query string; the service
+// fills in the defaults.
+func ParseFilter(r *http.Request, event string) (Filter, error) {
+	f := Filter{
+		Event:        event,
+		Period:       r.URL.Query().Get("period"),
+		By:           r.URL.Query().Get("by"),
+		Municipality: request.QueryText(r, "municipality"),
+	}
+	for name, dst := range map[string]*time.Time{"from": &f.From, "to": &f.To} {
+		d, err := request.QueryDate(r, name)
+		if err != nil {
+			return f, err
+		}
+		*dst = d.Time
+	}
+	if r.URL.Query().Get("office_id") != "" {
+		id, err := request.QueryUUID(r, "office_id")
+		if err != nil {
+			return f, err
+		}
+		f.OfficeID = pgtype.UUID{Bytes: id, Valid: true}
+	}
+	return f, nil
+}
+
+// serviceError maps a service failure onto an HTTP status.
+func (h *Handlers) serviceError(w http.ResponseWriter, err error) {
+	status, msg := apperr.Status(err)
+	if status >= http.StatusInternalServerError {
+		h.logger.Errorf("Service error: %v", err)
+	}
+	http.Error(w, msg, status)
+}
@@ -0,0 +1,117 @@
+package report
+
+import (
+	"github.com/eif-courses/civilregistry/internal/render"
+)
+
+// PeriodCountResponse is the number of events in one period. Label names
+// the period, e.g. 2026-03, 2026-Q1 or 2026.
+type PeriodCountResponse struct {
+	Period render.Date `json:"period" swaggertype:"string" format:"date"`
+	Label  string      `json:"label" example:"2026-03"`
+	Count  int64       `json:"count" example:"42"`
+}
+
+// GroupCountResponse is the number of events in one group over the whole
+// range.
+type GroupCountResponse struct {
+	Group string `json:"group" example:"female"`
+	Count int64  `json:"count" example:"21"`
+}
+
+// CellResponse is the number of events of one group in one period.
+type CellResponse struct {
+	Period render.Date `json:"period" swaggertype:"string" format:"date"`
+	Label  string      `json:"label" example:"2026-03"`
+	Group  string      `json:"group" example:"female"`
+	Count  int64       `json:"count" example:"11"`
+}
+
+// ReportResponse is the wire form of a Report. Groups and cells are only
+// present when the report is broken down by a dimension.
+type ReportResponse struct {
+	Event   string                `json:"event" enums:"births,marriages,deaths"`
+	Period  string                `json:"period" enums:"month,quarter,year"`
+	By      string                `json:"by,omitempty" enums:"office,municipality,sex,age_group"`
+	From    render.Date           `json:"from" swaggertype:"string" format:"date"`
+	To      render.Date           `json:"to" swaggertype:"string" format:"date"`
+	Total   int64                 `json:"total" example:"480"`
+	Periods []PeriodCountResponse `json:"periods"`
+	Groups  []GroupCountResponse  `json:"groups,omitempty"`
+	Cells   []CellResponse        `json:"cells,omitempty"`
+}
+
+func NewReportResponse(rep Report) ReportResponse {
+	resp := ReportResponse{
+		Event:   rep.Event,
+		Period:  rep.Period,
+		By:      rep.By,
+		From:    render.Date(rep.From),
+		To:      render.Date(rep.To),
+		Total:   rep.Total,
+		Periods: make([]PeriodCountResponse, 0, len(rep.Periods)),
+	}
+	for _, p := range rep.Periods {
+		resp.Periods = append(resp.Periods, PeriodCountResponse{
+			Period: render.Date(p.Period),
+			Label:  PeriodLabel(p.Period, rep.Period),
+			Count:  p.Count,
+		})
+	}
+	for _, g := range rep.Groups {
+		resp.Groups = append(resp.Groups, GroupCountResponse{Group: g.Group, Count: g.Count})
+	}
+	for _, c := range rep.Cells {
+		resp.Cells = append(resp.Cells, CellResponse{
+			Period: render.Date(c.Period),
+			Label:  PeriodLabel(c.Period, rep.Period),
+			Group:  c.Group,
+			Count:  c.Count,
+		})
+	}
+	return resp
+}
+
+// ReportEnvelope is the response body for a report.
+type ReportEnvelope struct {
+	Data ReportResponse `json:"data"`
+}
+
+// ReportCSVHeader heads the CSV form of a report.
+var ReportCSVHeader = []string{"event", "period", "period_start", "group", "count"}
+
+// ReportRow is one CSV row: a period's total, or one group's count in a
+// period when the report is broken down by a dimension.
+type ReportRow struct {
+	Event  string
+	Period string
+	Start  render.Date
+	Group  string
+	Count  int64
+}
+
+// NewReportRows lists a report as CSV rows. Totals get the group "total";
+// empty periods are kept, empty groups are not.
+func NewReportRows(rep Report) []ReportRow {
+	var rows []ReportRow
+	if rep.By == "" {
+		for _, p := range rep.Periods {
+			rows = append(rows, ReportRow{rep.Event, PeriodLabel(p.Period, rep.Period), render.Date(p.Period), "total", p.Count})
+		}
+		return rows
+	}
+	for _, c := range rep.Cells {
+		rows = append(rows, ReportRow{rep.Event, PeriodLabel(c.Period, rep.Period), render.Date(c.Period), c.Group, c.Count})
+	}
+	return rows
+}
+
+func (m ReportRow) CSVRecord() []string {
+	return []string{
+		render.CSVValue(m.Event),
+		render.CSVValue(m.Period),
+		render.CSVValue(m.Start),
+		render.CSVValue(m.Group),
+		render.CSVValue(m.Count),
+	}
+}
@@ -0,0 +1,19 @@
+package report
+
+import (
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func ReportRouter(queries *repository.Queries, log *zap.SugaredLogger) chi.Router {
+	r := chi.NewRouter()
+
+	service := NewService(queries, log)
+	handlers := NewHandlers(service, log)
+
+	r.Get("/{event}", telemetry.InstrumentHandler("report", "GetReport", handlers.GetReport))
+
+	return r
+}
@@ -0,0 +1,359 @@
+package report
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// Events reported on.
+const (
+	EventBirths    = "births"
+	EventMarriages = "marriages"
+	EventDeaths    = "deaths"
+)
+
+// Periods events are counted by.
+const (
+	PeriodMonth   = "month"
+	PeriodQuarter = "quarter"
+	PeriodYear    = "year"
+)
+
+// Dimensions a report can break its counts down by. Births are broken
+// down by the child's sex and the mother's age group, marriages by the
+// spouses' sex and age group, deaths by the deceased's.
+const (
+	ByOffice       = "office"
+	ByMunicipality = "municipality"
+	BySex          = "sex"
+	ByAgeGroup     = "age_group"
+)
+
+const (
+	// Unknown labels a group without a value, such as the municipality of
+	// records made before offices were tracked.
+	Unknown = "unknown"
+
+	// maxPeriods bounds a report's range, e.g. ten years by month.
+	maxPeriods = 120
+	// defaultPeriods is how many periods up to today a report covers when
+	// no start date is given.
+	defaultPeriods = 12
+)
+
+var (
+	Events     = []string{EventBirths, EventMarriages, EventDeaths}
+	Periods    = []string{PeriodMonth, PeriodQuarter, PeriodYear}
+	Dimensions = []string{ByOffice, ByMunicipality, BySex, ByAgeGroup}
+	// AgeGroups are the bands of the age_group SQL function, youngest first.
+	AgeGroups = []string{"0-19", "20-24", "25-29", "30-34", "35-39", "40-49", "50-64", "65-79", "80+", Unknown}
+	// Sexes orders the sex groups.
+	Sexes = []string{"female", "male"}
+)
+
+type Service struct {
+	repo   *repository.Queries
+	logger *zap.SugaredLogger
+}
+
+func NewService(repo *repository.Queries, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// Filter selects a report. From and To are inclusive dates; By is empty
+// for totals only.
+type Filter struct {
+	Event        string
+	Period       string
+	By           string
+	From         time.Time
+	To           time.Time
+	OfficeID     pgtype.UUID
+	Municipality pgtype.Text
+}
+
+// PeriodCount is the number of events in the period starting on Period.
+type PeriodCount struct {
+	Period time.Time
+	Count  int64
+}
+
+// GroupCount is the number of events in a group of the report's dimension.
+type GroupCount struct {
+	Group string
+	Count int64
+}
+
+// Cell is the number of events of a group in a period.
+type Cell struct {
+	Period time.Time
+	Group  string
+	Count  int64
+}
+
+// Report counts events of one kind. Periods lists every period of the
+// range, empty ones included; Groups and Cells are set when the report is
+// broken down by a dimension, and Cells leaves out empty combinations.
+// Marriages are counted once each, except by sex and age group, where
+// every spouse counts.
+type Report struct {
+	Filter
+	Total   int64
+	Periods []PeriodCount
+	Groups  []GroupCount
+	Cells   []Cell
+}
+
+// fact is a count at the grain of the report queries.
+type fact struct {
+	period       time.Time
+	office       string
+	municipality string
+	sex          string
+	ageGroup     string
+	events       int64
+}
+
+// Report counts the events selected by f. Any signed-in caller may read
+// reports; they only hold counts.
+func (s *Service) Report(ctx context.Context, f Filter) (_ *Report, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "report", "Report")
+	defer func() { op.End(err) }()
+
+	if auth.FromContext(ctx) == nil {
+		return nil, apperr.Unauthorized("sign in to see reports")
+	}
+	if f, err = Normalize(f, time.Now()); err != nil {
+		return nil, err
+	}
+
+	facts, err := s.facts(ctx, f)
+	if err != nil {
+		s.logger.Errorf("Failed to load %s report: %v", f.Event, err)
+		return nil, err
+	}
+	return rollUp(f, facts), nil
+}
+
+// Normalize fills in the defaults of f, a monthly report of the last
+// twelve months up to today, and validates it.
+func Normalize(f Filter, now time.Time) (Filter, error) {
+	if !slices.Contains(Events, f.Event) {
+		return f, apperr.NotFound("no report on %q", f.Event)
+	}
+	if f.Period == "" {
+		f.Period = PeriodMonth
+	}
+	if !slices.Contains(Periods, f.Period) {
+		return f, apperr.Invalid("period must be month, quarter or year")
+	}
+	if f.By != "" && !slices.Contains(Dimensions, f.By) {
+		return f, apperr.Invalid("by must be office, municipality, sex or age_group")
+	}
+	if f.To.IsZero() {
+		y, m, d := now.Date()
+		f.To = time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	if f.From.IsZero() {
+		f.From = step(PeriodStart(f.To, f.Period), f.Period, 1-defaultPeriods)
+	}
+	if f.From.After(f.To) {
+		return f, apperr.Invalid("from must not be after to")
+	}
+	if len(periodStarts(f)) > maxPeriods {
+		return f, apperr.Invalid("a report covers at most %d periods; shorten the range or count by a longer period", maxPeriods)
+	}
+	return f, nil
+}
+
+func (s *Service) facts(ctx context.Context, f Filter) ([]fact, error) {
+	from := pgtype.Date{Time: f.From, Valid: true}
+	to := pgtype.Date{Time: f.To, Valid: true}
+
+	var facts []fact
+	switch f.Event {
+	case EventBirths:
+		rows, err := s.repo.BirthReportRows(ctx, repository.BirthReportRowsParams{
+			Period: f.Period, FromDate: from, ToDate: to, OfficeID: f.OfficeID, Municipality: f.Municipality,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed BirthReportRows: %w", err)
+		}
+		for _, r := range rows {
+			facts = append(facts, fact{r.Period.Time, r.Office, r.Municipality, r.Sex, r.AgeGroup, r.Events})
+		}
+	case EventMarriages:
+		rows, err := s.repo.MarriageReportRows(ctx, repository.MarriageReportRowsParams{
+			Period: f.Period, FromDate: from, ToDate: to, OfficeID: f.OfficeID, Municipality: f.Municipality,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed MarriageReportRows: %w", err)
+		}
+		for _, r := range rows {
+			facts = append(facts, fact{r.Period.Time, r.Office, r.Municipality, r.Sex, r.AgeGroup, r.Events})
+		}
+	case EventDeaths:
+		rows, err := s.repo.DeathReportRows(ctx, repository.DeathReportRowsParams{
+			Period: f.Period, FromDate: from, ToDate: to, OfficeID: f.OfficeID, Municipality: f.Municipality,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed DeathReportRows: %w", err)
+		}
+		for _, r := range rows {
+			facts = append(facts, fact{r.Period.Time, r.Office, r.Municipality, r.Sex, r.AgeGroup, r.Events})
+		}
+	}
+	return facts, nil
+}
+
+// rollUp sums the facts by period and by the report's dimension.
+func rollUp(f Filter, facts []fact) *Report {
+	// The marriage queries count spouses; two make a marriage unless the
+	// report is about the spouses themselves
+	per := int64(1)
+	if f.Event == EventMarriages && f.By != BySex && f.By != ByAgeGroup {
+		per = 2
+	}
+
+	periods := map[time.Time]int64{}
+	groups := map[string]int64{}
+	cells := map[Cell]int64{}
+	for _, x := range facts {
+		periods[x.period] += x.events
+		if f.By == "" {
+			continue
+		}
+		g := x.group(f.By)
+		groups[g] += x.events
+		cells[Cell{Period: x.period, Group: g}] += x.events
+	}
+
+	rep := &Report{Filter: f}
+	for _, start := range periodStarts(f) {
+		n := periods[start] / per
+		rep.Periods = append(rep.Periods, PeriodCount{Period: start, Count: n})
+		rep.Total += n
+	}
+	for g, n := range groups {
+		rep.Groups = append(rep.Groups, GroupCount{Group: g, Count: n / per})
+	}
+	slices.SortFunc(rep.Groups, func(a, b GroupCount) int {
+		return compareGroups(f.By, a, b)
+	})
+	order := make(map[string]int, len(rep.Groups))
+	for i, g := range rep.Groups {
+		order[g.Group] = i
+	}
+	for c, n := range cells {
+		c.Count = n / per
+		rep.Cells = append(rep.Cells, c)
+	}
+	slices.SortFunc(rep.Cells, func(a, b Cell) int {
+		return cmp.Or(a.Period.Compare(b.Period), cmp.Compare(order[a.Group], order[b.Group]))
+	})
+	return rep
+}
+
+func (x fact) group(by string) string {
+	var g string
+	switch by {
+	case ByOffice:
+		g = x.office
+	case ByMunicipality:
+		g = x.municipality
+	case BySex:
+		g = x.sex
+	case ByAgeGroup:
+		g = x.ageGroup
+	}
+	if g == "" {
+		return Unknown
+	}
+	return g
+}
+
+// compareGroups keeps sexes and age groups in their natural order and
+// lists offices and municipalities largest first.
+func compareGroups(by string, a, b GroupCount) int {
+	switch by {
+	case BySex:
+		return cmp.Compare(rank(Sexes, a.Group), rank(Sexes, b.Group))
+	case ByAgeGroup:
+		return cmp.Compare(rank(AgeGroups, a.Group), rank(AgeGroups, b.Group))
+	}
+	switch {
+	case a.Group == Unknown && b.Group != Unknown:
+		return 1
+	case b.Group == Unknown && a.Group != Unknown:
+		return -1
+	}
+	return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Group, b.Group))
+}
+
+func rank(order []string, s string) int {
+	if i := slices.Index(order, s); i >= 0 {
+		return i
+	}
+	return len(order)
+}
+
+// PeriodStart returns the first day of the period containing t.
+func PeriodStart(t time.Time, period string) time.Time {
+	y, m, _ := t.Date()
+	switch period {
+	case PeriodQuarter:
+		m = (m-1)/3*3 + 1
+	case PeriodYear:
+		m = time.January
+	}
+	return time.Date(y, m, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// PeriodLabel names the period starting on start, e.g. 2026-03, 2026-Q1
+// or 2026.
+func PeriodLabel(start time.Time, period string) string {
+	switch period {
+	case PeriodQuarter:
+		return start.Format("2006") + "-Q" + strconv.Itoa(int(start.Month()-1)/3+1)
+	case PeriodYear:
+		return start.Format("2006")
+	}
+	return start.Format("2006-01")
+}
+
+// step moves a period start n periods forward, or back for negative n.
+func step(start time.Time, period string, n int) time.Time {
+	switch period {
+	case PeriodQuarter:
+		return start.AddDate(0, 3*n, 0)
+	case PeriodYear:
+		return start.AddDate(n, 0, 0)
+	}
+	return start.AddDate(0, n, 0)
+}
+
+// periodStarts lists the periods that overlap f's range.
+func periodStarts(f Filter) []time.Time {
+	var starts []time.Time
+	for start := PeriodStart(f.From, f.Period); !start.After(f.To); start = step(start, f.Period, 1) {
+		starts = append(starts, start)
+		if len(starts) > maxPeriods {
+			break
+		}
+	}
+	return starts
+}
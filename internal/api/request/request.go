@@ -0,0 +1,98 @@
+// Package request parses path and query parameters for the hand-written
+// API handlers. Parse failures are apperr.Invalid errors, so handlers can
+// pass them straight to their serviceError.
+package request
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// UUIDParam parses the named chi URL parameter as a UUID.
+func UUIDParam(r *http.Request, name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(chi.URLParam(r, name))
+	if err != nil {
+		return uuid.Nil, apperr.Invalid("invalid %s format", name)
+	}
+	return id, nil
+}
+
+// QueryUUID parses a required UUID query parameter.
+func QueryUUID(r *http.Request, name string) (uuid.UUID, error) {
+	id, err := uuid.Parse(r.URL.Query().Get(name))
+	if err != nil {
+		return uuid.Nil, apperr.Invalid("%s must be a UUID", name)
+	}
+	return id, nil
+}
+
+// QueryInt32 parses an optional integer query parameter; missing is zero.
+func QueryInt32(r *http.Request, name string) (int32, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.ParseInt(value, 10, 32)
+	if err != nil {
+		return 0, apperr.Invalid("%s must be an integer", name)
+	}
+	return int32(n), nil
+}
+
+// Page reads the limit and offset query parameters.
+func Page(r *http.Request) (limit, offset int32, err error) {
+	if limit, err = QueryInt32(r, "limit"); err != nil {
+		return 0, 0, err
+	}
+	if offset, err = QueryInt32(r, "offset"); err != nil {
+		return 0, 0, err
+	}
+	return limit, offset, nil
+}
+
+// QueryText reads an optional text query parameter; empty is NULL.
+func QueryText(r *http.Request, name string) pgtype.Text {
+	value := r.URL.Query().Get(name)
+	return pgtype.Text{String: value, Valid: value != ""}
+}
+
+// QueryDate parses an optional YYYY-MM-DD query parameter; missing is NULL.
+func QueryDate(r *http.Request, name string) (pgtype.Date, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return pgtype.Date{}, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return pgtype.Date{}, apperr.Invalid("%s must be YYYY-MM-DD", name)
+	}
+	return pgtype.Date{Time: t, Valid: true}, nil
+}
+
+// Date converts a decoded request date; the zero date is NULL.
+func Date(d render.Date) pgtype.Date {
+	return pgtype.Date{Time: time.Time(d), Valid: !d.IsZero()}
+}
+
+// Text converts an optional request string; nil is NULL.
+func Text(s *string) pgtype.Text {
+	if s == nil {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{String: *s, Valid: true}
+}
+
+// UUID converts an optional request UUID; nil is NULL.
+func UUID(id *uuid.UUID) pgtype.UUID {
+	if id == nil {
+		return pgtype.UUID{}
+	}
+	return pgtype.UUID{Bytes: *id, Valid: true}
+}
@@ -0,0 +1,433 @@
+package residence
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/request"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	service *Service
+	logger  *zap.SugaredLogger
+}
+
+func NewHandlers(service *Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		service: service,
+		logger:  logger,
+	}
+}
+
+type CreateAddressRequest struct {
+	Municipality string `json:"municipality" example:"Vilniaus m. sav."`
+	Street       string `json:"street,omitempty" example:"Gedimino pr."`
+	House        string `json:"house" example:"1"`
+	Flat         string `json:"flat,omitempty" example:"2"`
+	PostalCode   string `json:"postal_code" example:"LT-01103"`
+}
+
+// Params converts the request into repository parameters.
+func (req CreateAddressRequest) Params() repository.CreateAddressParams {
+	return repository.CreateAddressParams{
+		Municipality: req.Municipality,
+		Street:       req.Street,
+		House:        req.House,
+		Flat:         req.Flat,
+		PostalCode:   req.PostalCode,
+	}
+}
+
+type DeclareResidenceRequest struct {
+	PersonID  uuid.UUID   `json:"person_id"`
+	AddressID uuid.UUID   `json:"address_id"`
+	StartDate render.Date `json:"start_date" swaggertype:"string" format:"date" example:"2026-09-01"`
+}
+
+// Params converts the request into service parameters.
+func (req DeclareResidenceRequest) Params() DeclareParams {
+	return DeclareParams{
+		PersonID:  req.PersonID,
+		AddressID: req.AddressID,
+		StartDate: request.Date(req.StartDate),
+	}
+}
+
+type EndResidenceRequest struct {
+	EndDate render.Date `json:"end_date" swaggertype:"string" format:"date" example:"2026-09-30"`
+}
+
+// CreateAddress registers an address
+// @Summary Create address
+// @Description Add an address to the register. Street is empty for villages without streets, flat for houses.
+// @Description Postal codes are stored as LT-NNNNN.
+// @Tags residence
+// @Accept json
+// @Produce json
+// @Param request body CreateAddressRequest true "address data"
+// @Success 201 {object} AddressEnvelope "Created address"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Address already registered"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/residence/addresses [post]
+func (h *Handlers) CreateAddress(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var req CreateAddressRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.CreateAddress(r.Context(), req.Params())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/residence/addresses/"+result.ID.String())
+	err = render.Write(w, http.StatusCreated, render.ContentTypeJSON, AddressEnvelope{
+		Message: "address created successfully",
+		Data:    NewAddressResponse(*result),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// GetAddressByID retrieves an address
+// @Summary Get address
+// @Description Get an address from the register
+// @Tags residence
+// @Produce json
+// @Param id path string true "address ID"
+// @Success 200 {object} AddressEnvelope "Address found"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 404 {object} map[string]interface{} "Address not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/residence/addresses/{id} [get]
+func (h *Handlers) GetAddressByID(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.GetAddressByID(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	if err := render.Write(w, http.StatusOK, render.ContentTypeJSON, AddressEnvelope{Data: NewAddressResponse(*result)}); err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// SearchAddresses searches the address register
+// @Summary Search addresses
+// @Description Filter addresses by municipality (exact, ignoring case and diacritics), street prefix and postal code
+// @Tags residence
+// @Produce json
+// @Param municipality query string false "municipality"
+// @Param street query string false "street prefix"
+// @Param postal_code query string false "postal code"
+// @Param limit query int false "page size (default 50, max 200)"
+// @Param offset query int false "rows to skip"
+// @Success 200 {object} AddressListEnvelope "Matching addresses"
+// @Failure 400 {object} map[string]interface{} "Invalid filter"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/residence/addresses [get]
+func (h *Handlers) SearchAddresses(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	arg := repository.SearchAddressesParams{
+		Municipality: request.QueryText(r, "municipality"),
+		Street:       request.QueryText(r, "street"),
+		PostalCode:   request.QueryText(r, "postal_code"),
+	}
+	var err error
+	if arg.Limit, arg.Offset, err = request.Page(r); err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.SearchAddresses(r.Context(), arg)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	limit := arg.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	err = render.Write(w, http.StatusOK, render.ContentTypeJSON, AddressListEnvelope{
+		Count:  len(result),
+		Limit:  limit,
+		Offset: arg.Offset,
+		Data:   NewAddressResponses(result),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// ListResidents lists the residents of an address
+// @Summary Residents of an address
+// @Description List the persons whose declared residence was the address on the given day (default today)
+// @Tags residence
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "address ID"
+// @Param on query string false "day as YYYY-MM-DD"
+// @Success 200 {object} ResidentListEnvelope "Residents"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "Address not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/residence/addresses/{id}/residents [get]
+func (h *Handlers) ListResidents(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+	day, err := request.QueryDate(r, "on")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+	if !day.Valid {
+		day = pgtype.Date{Time: time.Now(), Valid: true}
+	}
+
+	result, err := h.service.ListResidents(r.Context(), id, day)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	err = render.Write(w, http.StatusOK, render.ContentTypeJSON, ResidentListEnvelope{
+		Count: len(result),
+		On:    render.Date(day.Time),
+		Data:  NewResidentResponses(result),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// DeclareResidence declares a person's place of residence
+// @Summary Declare residence
+// @Description Record that the person lives at the address from start_date. The person's current declaration,
+// @Description if any, ends on that date; a person has one current declaration at a time. The signed-in registrar
+// @Description and their office are recorded.
+// @Tags residence
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body DeclareResidenceRequest true "declaration data"
+// @Success 201 {object} DeclarationEnvelope "Created declaration"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Declaration overlaps the residence history"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/residence/declarations [post]
+func (h *Handlers) DeclareResidence(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	var req DeclareResidenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.DeclareResidence(r.Context(), req.Params())
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", "/api/residence/declarations/"+result.Record.ID.String())
+	h.writeDeclaration(w, http.StatusCreated, "residence declared successfully", *result)
+}
+
+// GetDeclaration retrieves a residence declaration
+// @Summary Get declaration
+// @Description Get a residence declaration with its address
+// @Tags residence
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "declaration ID"
+// @Success 200 {object} DeclarationEnvelope "Declaration found"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "Declaration not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/residence/declarations/{id} [get]
+func (h *Handlers) GetDeclaration(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.GetDeclaration(r.Context(), id)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.writeDeclaration(w, http.StatusOK, "", *result)
+}
+
+// EndResidence ends a current residence declaration
+// @Summary End residence
+// @Description End the current declaration without declaring a new one, e.g. when the person leaves the country.
+// @Description The residence covers the days before end_date.
+// @Tags residence
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "declaration ID"
+// @Param request body EndResidenceRequest true "end date"
+// @Success 200 {object} DeclarationEnvelope "Ended declaration"
+// @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 403 {object} map[string]interface{} "Not a registrar"
+// @Failure 404 {object} map[string]interface{} "Declaration not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 409 {object} map[string]interface{} "Declaration already ended"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/residence/declarations/{id}/end [post]
+func (h *Handlers) EndResidence(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	id, err := request.UUIDParam(r, "id")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	var req EndResidenceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.logger.Errorf("Failed to decode request: %v", err)
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.EndResidence(r.Context(), id, request.Date(req.EndDate))
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	h.writeDeclaration(w, http.StatusOK, "residence ended successfully", *result)
+}
+
+// ListPersonDeclarations lists a person's residence history
+// @Summary Residence history
+// @Description List a person's residence declarations, oldest first. The one without end_date is current.
+// @Tags residence
+// @Produce json
+// @Security BearerAuth
+// @Param personID path string true "person ID"
+// @Success 200 {object} DeclarationListEnvelope "Declarations"
+// @Failure 400 {object} map[string]interface{} "Invalid ID"
+// @Failure 401 {object} map[string]interface{} "Not signed in"
+// @Failure 404 {object} map[string]interface{} "Person not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
+// @Failure 500 {object} map[string]interface{} "Internal server error"
+// @Router /api/residence/by-person/{personID} [get]
+func (h *Handlers) ListPersonDeclarations(w http.ResponseWriter, r *http.Request) {
+	if render.Negotiate(r, render.ContentTypeJSON) == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	personID, err := request.UUIDParam(r, "personID")
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	result, err := h.service.ListPersonDeclarations(r.Context(), personID)
+	if err != nil {
+		h.serviceError(w, err)
+		return
+	}
+
+	err = render.Write(w, http.StatusOK, render.ContentTypeJSON, DeclarationListEnvelope{
+		Count: len(result),
+		Data:  NewDeclarationResponses(result),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+func (h *Handlers) writeDeclaration(w http.ResponseWriter, status int, message string, decl Declaration) {
+	err := render.Write(w, status, render.ContentTypeJSON, DeclarationEnvelope{
+		Message: message,
+		Data:    NewDeclarationResponse(decl),
+	})
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// serviceError maps a service failure onto an HTTP status.
+func (h *Handlers) serviceError(w http.ResponseWriter, err error) {
+	status, msg := apperr.Status(err)
+	if status >= http.StatusInternalServerError {
+		h.logger.Errorf("Service error: %v", err)
+	}
+	if status == http.StatusNotFound && msg == "not found" {
+		msg = "record not found"
+	}
+	http.Error(w, msg, status)
+}
@@ -0,0 +1,151 @@
+package residence
+
+import (
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/google/uuid"
+)
+
+// AddressResponse is the wire form of repository.Address.
+type AddressResponse struct {
+	ID           uuid.UUID `json:"id"`
+	Municipality string    `json:"municipality"`
+	Street       string    `json:"street"`
+	House        string    `json:"house"`
+	Flat         string    `json:"flat"`
+	PostalCode   string    `json:"postal_code"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func NewAddressResponse(row repository.Address) AddressResponse {
+	return AddressResponse{
+		ID:           row.ID,
+		Municipality: row.Municipality,
+		Street:       row.Street,
+		House:        row.House,
+		Flat:         row.Flat,
+		PostalCode:   row.PostalCode,
+		CreatedAt:    row.CreatedAt,
+	}
+}
+
+func NewAddressResponses(rows []repository.Address) []AddressResponse {
+	items := make([]AddressResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, NewAddressResponse(row))
+	}
+	return items
+}
+
+// DeclarationRecordResponse is the wire form of
+// repository.ResidenceDeclaration. A missing end_date marks the current
+// residence.
+type DeclarationRecordResponse struct {
+	ID          uuid.UUID    `json:"id"`
+	PersonID    uuid.UUID    `json:"person_id"`
+	AddressID   uuid.UUID    `json:"address_id"`
+	StartDate   render.Date  `json:"start_date"`
+	EndDate     *render.Date `json:"end_date"`
+	DeclaredBy  string       `json:"declared_by"`
+	OfficeID    *uuid.UUID   `json:"office_id"`
+	RegistrarID *uuid.UUID   `json:"registrar_id"`
+	DeclaredAt  time.Time    `json:"declared_at"`
+}
+
+func NewDeclarationRecordResponse(row repository.ResidenceDeclaration) DeclarationRecordResponse {
+	return DeclarationRecordResponse{
+		ID:          row.ID,
+		PersonID:    row.PersonID,
+		AddressID:   row.AddressID,
+		StartDate:   render.Date(row.StartDate.Time),
+		EndDate:     render.NullableDate(row.EndDate.Time, row.EndDate.Valid),
+		DeclaredBy:  row.DeclaredBy,
+		OfficeID:    render.Nullable(uuid.UUID(row.OfficeID.Bytes), row.OfficeID.Valid),
+		RegistrarID: render.Nullable(uuid.UUID(row.RegistrarID.Bytes), row.RegistrarID.Valid),
+		DeclaredAt:  row.DeclaredAt,
+	}
+}
+
+// DeclarationResponse is a declaration with its address. Previous is only
+// present when declaring ended the person's earlier residence.
+type DeclarationResponse struct {
+	Record   DeclarationRecordResponse  `json:"record"`
+	Address  AddressResponse            `json:"address"`
+	Previous *DeclarationRecordResponse `json:"previous,omitempty"`
+}
+
+func NewDeclarationResponse(decl Declaration) DeclarationResponse {
+	resp := DeclarationResponse{
+		Record:  NewDeclarationRecordResponse(decl.Record),
+		Address: NewAddressResponse(decl.Address),
+	}
+	if decl.Previous != nil {
+		previous := NewDeclarationRecordResponse(*decl.Previous)
+		resp.Previous = &previous
+	}
+	return resp
+}
+
+func NewDeclarationResponses(decls []Declaration) []DeclarationResponse {
+	items := make([]DeclarationResponse, 0, len(decls))
+	for _, decl := range decls {
+		items = append(items, NewDeclarationResponse(decl))
+	}
+	return items
+}
+
+// ResidentResponse is a person with the declaration placing them at the
+// address.
+type ResidentResponse struct {
+	Person      person.PersonResponse     `json:"person"`
+	Declaration DeclarationRecordResponse `json:"declaration"`
+}
+
+func NewResidentResponses(rows []Resident) []ResidentResponse {
+	items := make([]ResidentResponse, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, ResidentResponse{
+			Person:      person.NewPersonResponse(row.Person),
+			Declaration: NewDeclarationRecordResponse(row.Declaration),
+		})
+	}
+	return items
+}
+
+// AddressEnvelope is the response body for a single address.
+type AddressEnvelope struct {
+	Message string          `json:"message,omitempty"`
+	Data    AddressResponse `json:"data"`
+}
+
+// AddressListEnvelope is the response body for a page of addresses.
+type AddressListEnvelope struct {
+	Count  int               `json:"count"`
+	Limit  int32             `json:"limit"`
+	Offset int32             `json:"offset"`
+	Data   []AddressResponse `json:"data"`
+}
+
+// DeclarationEnvelope is the response body for a single declaration.
+type DeclarationEnvelope struct {
+	Message string              `json:"message,omitempty"`
+	Data    DeclarationResponse `json:"data"`
+}
+
+// DeclarationListEnvelope is the response body for a person's residence
+// history.
+type DeclarationListEnvelope struct {
+	Count int                   `json:"count"`
+	Data  []DeclarationResponse `json:"data"`
+}
+
+// ResidentListEnvelope is the response body for the residents of an
+// address on a day.
+type ResidentListEnvelope struct {
+	Count int                `json:"count"`
+	On    render.Date        `json:"on"`
+	Data  []ResidentResponse `json:"data"`
+}
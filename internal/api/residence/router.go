@@ -0,0 +1,27 @@
+package residence
+
+import (
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func ResidenceRouter(db txn.Beginner, queries *repository.Queries, log *zap.SugaredLogger) chi.Router {
+	r := chi.NewRouter()
+
+	service := NewService(db, queries, log)
+	handlers := NewHandlers(service, log)
+
+	r.Post("/addresses", telemetry.InstrumentHandler("residence", "CreateAddress", handlers.CreateAddress))
+	r.Get("/addresses", telemetry.InstrumentHandler("residence", "SearchAddresses", handlers.SearchAddresses))
+	r.Get("/addresses/{id}", telemetry.InstrumentHandler("residence", "GetAddressByID", handlers.GetAddressByID))
+	r.Get("/addresses/{id}/residents", telemetry.InstrumentHandler("residence", "ListResidents", handlers.ListResidents))
+	r.Post("/declarations", telemetry.InstrumentHandler("residence", "DeclareResidence", handlers.DeclareResidence))
+	r.Get("/declarations/{id}", telemetry.InstrumentHandler("residence", "GetDeclaration", handlers.GetDeclaration))
+	r.Post("/declarations/{id}/end", telemetry.InstrumentHandler("residence", "EndResidence", handlers.EndResidence))
+	r.Get("/by-person/{personID}", telemetry.InstrumentHandler("residence", "ListPersonDeclarations", handlers.ListPersonDeclarations))
+
+	return r
+}
@@ -0,0 +1,381 @@
+package residence
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/eif-courses/civilregistry/internal/api/office"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/telemetry"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 200
+)
+
+// postalCodePattern accepts Lithuanian postal codes with or without the
+// LT- prefix; they are stored with it.
+var postalCodePattern = regexp.MustCompile(`^(?:LT-?)?([0-9]{5})$`)
+
+type Service struct {
+	db     txn.Beginner
+	repo   *repository.Queries
+	logger *zap.SugaredLogger
+}
+
+func NewService(db txn.Beginner, repo *repository.Queries, logger *zap.SugaredLogger) *Service {
+	return &Service{
+		db:     db,
+		repo:   repo,
+		logger: logger,
+	}
+}
+
+// DeclareParams describes a residence declaration: the person has lived at
+// the address since StartDate.
+type DeclareParams struct {
+	PersonID  uuid.UUID
+	AddressID uuid.UUID
+	StartDate pgtype.Date
+}
+
+// Declaration is a residence declaration with its address. Previous is the
+// declaration it ended, if any.
+type Declaration struct {
+	Record   repository.ResidenceDeclaration
+	Address  repository.Address
+	Previous *repository.ResidenceDeclaration
+}
+
+// Resident is a person whose declaration covers the queried day.
+type Resident struct {
+	Declaration repository.ResidenceDeclaration
+	Person      repository.Person
+}
+
+// CreateAddress registers an address. The same municipality, street, house
+// and flat, compared without case and diacritics, are registered once.
+func (s *Service) CreateAddress(ctx context.Context, arg repository.CreateAddressParams) (_ *repository.Address, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "residence", "CreateAddress")
+	defer func() { op.End(err) }()
+
+	arg.Municipality = strings.TrimSpace(arg.Municipality)
+	arg.Street = strings.TrimSpace(arg.Street)
+	arg.House = strings.TrimSpace(arg.House)
+	arg.Flat = strings.TrimSpace(arg.Flat)
+	if arg.Municipality == "" || arg.House == "" {
+		return nil, apperr.Invalid("municipality and house are required")
+	}
+	postalCode, ok := NormalizePostalCode(arg.PostalCode)
+	if !ok {
+		return nil, apperr.Invalid("postal_code must be a Lithuanian postal code such as LT-01103")
+	}
+	arg.PostalCode = postalCode
+
+	result, err := s.repo.CreateAddress(ctx, arg)
+	if apperr.IsUniqueViolation(err, "address_key_idx") {
+		return nil, apperr.Conflict("address is already registered")
+	}
+	if err != nil {
+		s.logger.Errorf("Failed CreateAddress: %v", err)
+		return nil, fmt.Errorf("failed CreateAddress: %w", err)
+	}
+	return &result, nil
+}
+
+func (s *Service) GetAddressByID(ctx context.Context, id uuid.UUID) (_ *repository.Address, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "residence", "GetAddressByID")
+	defer func() { op.End(err) }()
+
+	result, err := s.repo.GetAddressByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetAddressByID: %w", err)
+	}
+	return &result, nil
+}
+
+// SearchAddresses filters the register by municipality, street prefix and
+// postal code. A zero limit selects the default page size.
+func (s *Service) SearchAddresses(ctx context.Context, arg repository.SearchAddressesParams) (_ []repository.Address, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "residence", "SearchAddresses")
+	defer func() { op.End(err) }()
+
+	if arg.Limit < 0 || arg.Limit > maxSearchLimit {
+		return nil, apperr.Invalid("limit must be between 1 and %d", maxSearchLimit)
+	}
+	if arg.Limit == 0 {
+		arg.Limit = defaultSearchLimit
+	}
+	if arg.Offset < 0 {
+		return nil, apperr.Invalid("offset must not be negative")
+	}
+	if arg.PostalCode.Valid {
+		postalCode, ok := NormalizePostalCode(arg.PostalCode.String)
+		if !ok {
+			return nil, apperr.Invalid("postal_code must be a Lithuanian postal code such as LT-01103")
+		}
+		arg.PostalCode.String = postalCode
+	}
+
+	result, err := s.repo.SearchAddresses(ctx, arg)
+	if err != nil {
+		s.logger.Errorf("Failed SearchAddresses: %v", err)
+		return nil, fmt.Errorf("failed SearchAddresses: %w", err)
+	}
+	return result, nil
+}
+
+// DeclareResidence records that the person lives at the address from
+// StartDate, taken by the signed-in registrar. A current declaration
+// elsewhere ends the day the new one starts; the new one must start after
+// every earlier declaration.
+func (s *Service) DeclareResidence(ctx context.Context, arg DeclareParams) (_ *Declaration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "residence", "DeclareResidence")
+	defer func() { op.End(err) }()
+
+	if !arg.StartDate.Valid {
+		return nil, apperr.Invalid("start_date is required")
+	}
+	if arg.StartDate.Time.After(time.Now()) {
+		return nil, apperr.Invalid("start_date must not be in the future")
+	}
+	registrar, err := auth.RequireRegistrar(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Infof("Declaring residence of %s at %s", arg.PersonID, arg.AddressID)
+
+	var decl Declaration
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		// The person lock serializes declarations, so the latest one read
+		// below stays the latest until we commit
+		locked, err := person.LockInOrder(ctx, q, arg.PersonID)
+		if err != nil {
+			return err
+		}
+		p := locked[arg.PersonID]
+		if err := person.EnsureAlive(p); err != nil {
+			return err
+		}
+		if arg.StartDate.Time.Before(p.BirthDate.Time) {
+			return apperr.Invalid("start_date must not be before the birth date")
+		}
+
+		decl.Address, err = q.GetAddressByID(ctx, arg.AddressID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperr.Invalid("address %s not found", arg.AddressID)
+		}
+		if err != nil {
+			return fmt.Errorf("failed GetAddressByID: %w", err)
+		}
+
+		latest, err := q.GetLatestResidenceDeclaration(ctx, arg.PersonID)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+		case err != nil:
+			return fmt.Errorf("failed GetLatestResidenceDeclaration: %w", err)
+		case !latest.EndDate.Valid:
+			if latest.AddressID == arg.AddressID {
+				return apperr.Conflict("the person already resides at this address")
+			}
+			if !arg.StartDate.Time.After(latest.StartDate.Time) {
+				return apperr.Conflict("start_date must be after %s, when the current residence started", latest.StartDate.Time.Format(time.DateOnly))
+			}
+			ended, err := q.EndResidenceDeclaration(ctx, repository.EndResidenceDeclarationParams{
+				ID:      latest.ID,
+				EndDate: arg.StartDate,
+			})
+			if err != nil {
+				return fmt.Errorf("failed EndResidenceDeclaration: %w", err)
+			}
+			decl.Previous = &ended
+		case arg.StartDate.Time.Before(latest.EndDate.Time):
+			return apperr.Conflict("start_date must not be before %s, when the previous residence ended", latest.EndDate.Time.Format(time.DateOnly))
+		}
+
+		officeID, registrarID := office.RecordedBy(registrar)
+		decl.Record, err = q.CreateResidenceDeclaration(ctx, repository.CreateResidenceDeclarationParams{
+			PersonID:    arg.PersonID,
+			AddressID:   arg.AddressID,
+			StartDate:   arg.StartDate,
+			DeclaredBy:  registrar.Name(),
+			OfficeID:    officeID,
+			RegistrarID: registrarID,
+		})
+		if err != nil {
+			return fmt.Errorf("failed CreateResidenceDeclaration: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorf("Failed DeclareResidence: %v", err)
+		return nil, err
+	}
+
+	s.logger.Infof("DeclareResidence completed successfully with ID: %s", decl.Record.ID)
+	return &decl, nil
+}
+
+// EndResidence ends a current declaration without a new one, e.g. when the
+// person leaves the country. The residence covers days before endDate.
+func (s *Service) EndResidence(ctx context.Context, id uuid.UUID, endDate pgtype.Date) (_ *Declaration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "residence", "EndResidence")
+	defer func() { op.End(err) }()
+
+	if !endDate.Valid {
+		return nil, apperr.Invalid("end_date is required")
+	}
+	if endDate.Time.After(time.Now()) {
+		return nil, apperr.Invalid("end_date must not be in the future")
+	}
+	if _, err := auth.RequireRegistrar(ctx); err != nil {
+		return nil, err
+	}
+
+	var decl Declaration
+	err = txn.Run(ctx, s.db, s.repo, func(q *repository.Queries) error {
+		current, err := q.GetResidenceDeclarationByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed GetResidenceDeclarationByID: %w", err)
+		}
+		if _, err := person.LockInOrder(ctx, q, current.PersonID); err != nil {
+			return err
+		}
+		// Re-read under the lock; a declaration made meanwhile may have
+		// ended it already
+		if current, err = q.GetResidenceDeclarationByID(ctx, id); err != nil {
+			return fmt.Errorf("failed GetResidenceDeclarationByID: %w", err)
+		}
+		if current.EndDate.Valid {
+			return apperr.Conflict("the declaration already ended on %s", current.EndDate.Time.Format(time.DateOnly))
+		}
+		if !endDate.Time.After(current.StartDate.Time) {
+			return apperr.Invalid("end_date must be after the start_date %s", current.StartDate.Time.Format(time.DateOnly))
+		}
+
+		if decl.Record, err = q.EndResidenceDeclaration(ctx, repository.EndResidenceDeclarationParams{
+			ID:      id,
+			EndDate: endDate,
+		}); err != nil {
+			return fmt.Errorf("failed EndResidenceDeclaration: %w", err)
+		}
+		if decl.Address, err = q.GetAddressByID(ctx, current.AddressID); err != nil {
+			return fmt.Errorf("failed GetAddressByID: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		s.logger.Errorf("Failed EndResidence: %v", err)
+		return nil, err
+	}
+	return &decl, nil
+}
+
+func (s *Service) GetDeclaration(ctx context.Context, id uuid.UUID) (_ *Declaration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "residence", "GetDeclaration")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	record, err := s.repo.GetResidenceDeclarationByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetResidenceDeclarationByID: %w", err)
+	}
+	address, err := s.repo.GetAddressByID(ctx, record.AddressID)
+	if err != nil {
+		return nil, fmt.Errorf("failed GetAddressByID: %w", err)
+	}
+	return &Declaration{Record: record, Address: address}, nil
+}
+
+// ListPersonDeclarations returns the person's residence history, oldest
+// first.
+func (s *Service) ListPersonDeclarations(ctx context.Context, personID uuid.UUID) (_ []Declaration, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "residence", "ListPersonDeclarations")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	// Resolve the person first so an unknown ID is a 404, not an empty list
+	if _, err := s.repo.GetPersonByID(ctx, personID); err != nil {
+		return nil, fmt.Errorf("failed GetPersonByID: %w", err)
+	}
+
+	records, err := s.repo.ListResidenceDeclarationsForPerson(ctx, personID)
+	if err != nil {
+		s.logger.Errorf("Failed ListResidenceDeclarationsForPerson: %v", err)
+		return nil, fmt.Errorf("failed ListResidenceDeclarationsForPerson: %w", err)
+	}
+
+	addresses := make(map[uuid.UUID]repository.Address)
+	result := make([]Declaration, 0, len(records))
+	for _, record := range records {
+		address, ok := addresses[record.AddressID]
+		if !ok {
+			if address, err = s.repo.GetAddressByID(ctx, record.AddressID); err != nil {
+				return nil, fmt.Errorf("failed GetAddressByID: %w", err)
+			}
+			addresses[record.AddressID] = address
+		}
+		result = append(result, Declaration{Record: record, Address: address})
+	}
+	return result, nil
+}
+
+// ListResidents returns the persons declared at the address on the given
+// day.
+func (s *Service) ListResidents(ctx context.Context, addressID uuid.UUID, day pgtype.Date) (_ []Resident, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "residence", "ListResidents")
+	defer func() { op.End(err) }()
+
+	if err := auth.RequireSignedIn(ctx); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetAddressByID(ctx, addressID); err != nil {
+		return nil, fmt.Errorf("failed GetAddressByID: %w", err)
+	}
+
+	rows, err := s.repo.ListResidentsAtAddress(ctx, repository.ListResidentsAtAddressParams{
+		AddressID: addressID,
+		Day:       day,
+	})
+	if err != nil {
+		s.logger.Errorf("Failed ListResidentsAtAddress: %v", err)
+		return nil, fmt.Errorf("failed ListResidentsAtAddress: %w", err)
+	}
+
+	result := make([]Resident, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, Resident{Declaration: row.ResidenceDeclaration, Person: row.Person})
+	}
+	return result, nil
+}
+
+// NormalizePostalCode returns code in the stored LT-NNNNN form, or false
+// when it is not a Lithuanian postal code.
+func NormalizePostalCode(code string) (string, bool) {
+	m := postalCodePattern.FindStringSubmatch(strings.ToUpper(strings.TrimSpace(code)))
+	if m == nil {
+		return "", false
+	}
+	return "LT-" + m[1], true
+}
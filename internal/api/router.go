@@ -4,39 +4,123 @@ import (
 	"net/http"
 	"path/filepath"
 
+	"github.com/eif-courses/civilregistry/internal/api/adoption"
+	"github.com/eif-courses/civilregistry/internal/api/application"
+	"github.com/eif-courses/civilregistry/internal/api/appointment"
+	"github.com/eif-courses/civilregistry/internal/api/attachment"
+	"github.com/eif-courses/civilregistry/internal/api/birth"
+	"github.com/eif-courses/civilregistry/internal/api/certificate"
+	"github.com/eif-courses/civilregistry/internal/api/death"
+	"github.com/eif-courses/civilregistry/internal/api/guardianship"
+	"github.com/eif-courses/civilregistry/internal/api/kinship"
+	"github.com/eif-courses/civilregistry/internal/api/marriage"
+	"github.com/eif-courses/civilregistry/internal/api/office"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/api/privacy"
+	"github.com/eif-courses/civilregistry/internal/api/report"
+	"github.com/eif-courses/civilregistry/internal/api/residence"
+	"github.com/eif-courses/civilregistry/internal/config"
 	"github.com/eif-courses/civilregistry/internal/generated/api/post"
 	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/storage"
+	frontendapplication "github.com/eif-courses/civilregistry/internal/web/application"
+	frontendappointment "github.com/eif-courses/civilregistry/internal/web/appointment"
+	frontendbirth "github.com/eif-courses/civilregistry/internal/web/birth"
+	frontendcertificate "github.com/eif-courses/civilregistry/internal/web/certificate"
+	frontenddeath "github.com/eif-courses/civilregistry/internal/web/death"
+	frontendkinship "github.com/eif-courses/civilregistry/internal/web/kinship"
+	frontendmarriage "github.com/eif-courses/civilregistry/internal/web/marriage"
+	webmiddleware "github.com/eif-courses/civilregistry/internal/web/middleware"
+	frontendoffice "github.com/eif-courses/civilregistry/internal/web/office"
+	frontendperson "github.com/eif-courses/civilregistry/internal/web/person"
 	frontendpost "github.com/eif-courses/civilregistry/internal/web/post"
+	frontendreport "github.com/eif-courses/civilregistry/internal/web/report"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	httpSwagger "github.com/swaggo/http-swagger/v2"
 	"go.uber.org/zap"
 )
 
-func NewRouter(queries *repository.Queries, log *zap.SugaredLogger) http.Handler {
+// NewRouter wires every feature. Features that need transactions get db;
+// queries is the same pool wrapped by repository.New. store keeps
+// attachment contents, which personal data exports include.
+func NewRouter(cfg *config.Config, db *pgxpool.Pool, queries *repository.Queries, store storage.BlobStore, log *zap.SugaredLogger) http.Handler {
 	r := chi.NewRouter()
 
+	verification := certificate.Verification{
+		Key:     []byte(cfg.CertificateSigningKey),
+		BaseURL: cfg.BaseURL,
+	}
+	attachments := attachment.Options{
+		MaxSize: cfg.AttachmentMaxBytes,
+		Links: attachment.Links{
+			Key:     []byte(cfg.DownloadSigningKey),
+			BaseURL: cfg.BaseURL,
+			TTL:     cfg.AttachmentLinkTTL,
+		},
+	}
+	exports := privacy.Options{
+		Signer:       privacy.Signer{Key: []byte(cfg.ExportSigningKey)},
+		Verification: verification,
+	}
+
 	// Add middleware
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
+	// Registrar tokens from the Authorization header or the sign-in cookie
+	r.Use(webmiddleware.Authenticate(queries, cfg.AdminToken, log))
 
 	// Swagger documentation route
 	r.Get("/swagger/*", httpSwagger.Handler(
 		httpSwagger.URL("http://localhost:8080/swagger/doc.json"),
 	))
 
+	// Prometheus metrics recorded by the generated handlers and services
+	r.Handle("/metrics", promhttp.Handler())
+
 	// API routes
 	r.Route("/api", func(r chi.Router) {
 		r.Mount("/post", post.PostRouter(queries, log))
+		r.Mount("/person", person.PersonRouter(db, queries, log))
+		r.Mount("/birth", birth.BirthRouter(db, queries, log))
+		r.Mount("/marriage", marriage.MarriageRouter(db, queries, log))
+		r.Mount("/death", death.DeathRouter(db, queries, log))
+		r.Mount("/adoption", adoption.AdoptionRouter(db, queries, log))
+		r.Mount("/guardianship", guardianship.GuardianshipRouter(db, queries, log))
+		r.Mount("/kinship", kinship.KinshipRouter(queries, log))
+		r.Mount("/certificate", certificate.CertificateRouter(queries, verification, log))
+		r.Mount("/residence", residence.ResidenceRouter(db, queries, log))
+		r.Mount("/office", office.OfficeRouter(queries, log))
+		r.Mount("/appointments", appointment.AppointmentRouter(db, queries, log))
+		r.Mount("/applications", application.ApplicationRouter(db, queries, log))
+		r.Mount("/attachments", attachment.AttachmentRouter(db, queries, store, attachments, log))
+		r.Mount("/reports", report.ReportRouter(queries, log))
+		r.Mount("/privacy", privacy.PrivacyRouter(db, queries, store, exports, log))
 
 		// FORCE REFERENCE: This ensures Swagger sees the handlers
 		_ = post.NewHandlers
 	})
 
+	// gRPC / Connect services share the HTTP port; paths look like
+	// /civilregistry.post.v1.PostService/GetPostByID
+	r.Mount(post.PostConnectHandler(queries, log))
+
 	// Web routes
 	frontendpost.SetupRoutes(r, queries, log)
+	frontendbirth.SetupRoutes(r, db, queries, log)
+	frontendmarriage.SetupRoutes(r, db, queries, log)
+	frontenddeath.SetupRoutes(r, db, queries, log)
+	frontendperson.SetupRoutes(r, db, queries, log)
+	frontendcertificate.SetupRoutes(r, queries, verification, log)
+	frontendkinship.SetupRoutes(r, db, queries, log)
+	frontendoffice.SetupRoutes(r, queries, cfg.AdminToken, log)
+	frontendappointment.SetupRoutes(r, db, queries, log)
+	frontendapplication.SetupRoutes(r, db, queries, store, attachments, log)
+	frontendreport.SetupRoutes(r, queries, log)
 
 	// Serve assets
 	workDir, _ := filepath.Abs(".")
@@ -0,0 +1,100 @@
+// Package apperr classifies service errors so handlers can map them onto
+// HTTP statuses without knowing where they came from.
+package apperr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+var (
+	ErrInvalid      = errors.New("invalid input")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrForbidden    = errors.New("forbidden")
+	ErrUnauthorized = errors.New("unauthorized")
+)
+
+// Invalid reports input the service rejected; the message is shown to the client.
+func Invalid(format string, args ...any) error {
+	return &Error{kind: ErrInvalid, msg: fmt.Sprintf(format, args...)}
+}
+
+// NotFound reports a missing record.
+func NotFound(format string, args ...any) error {
+	return &Error{kind: ErrNotFound, msg: fmt.Sprintf(format, args...)}
+}
+
+// Conflict reports a request that clashes with the current state, such as a
+// duplicate key or a forbidden state transition.
+func Conflict(format string, args ...any) error {
+	return &Error{kind: ErrConflict, msg: fmt.Sprintf(format, args...)}
+}
+
+// Forbidden reports an operation the caller is not allowed to perform.
+func Forbidden(format string, args ...any) error {
+	return &Error{kind: ErrForbidden, msg: fmt.Sprintf(format, args...)}
+}
+
+// Unauthorized reports an operation that needs an authenticated caller.
+func Unauthorized(format string, args ...any) error {
+	return &Error{kind: ErrUnauthorized, msg: fmt.Sprintf(format, args...)}
+}
+
+// Error is a classified error whose message is safe to return to clients.
+type Error struct {
+	kind error
+	msg  string
+}
+
+func (e *Error) Error() string { return e.msg }
+
+func (e *Error) Unwrap() error { return e.kind }
+
+// IsUniqueViolation reports whether err is a Postgres unique constraint
+// violation, optionally on the named constraint.
+func IsUniqueViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23505" {
+		return false
+	}
+	return constraint == "" || pgErr.ConstraintName == constraint
+}
+
+// IsExclusionViolation reports whether err is a Postgres exclusion
+// constraint violation, optionally on the named constraint.
+func IsExclusionViolation(err error, constraint string) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != "23P01" {
+		return false
+	}
+	return constraint == "" || pgErr.ConstraintName == constraint
+}
+
+// Status maps err onto an HTTP status and a message that is safe to show.
+// Unclassified errors become a 500 with a generic message.
+func Status(err error) (int, string) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		switch appErr.kind {
+		case ErrInvalid:
+			return http.StatusBadRequest, appErr.msg
+		case ErrNotFound:
+			return http.StatusNotFound, appErr.msg
+		case ErrConflict:
+			return http.StatusConflict, appErr.msg
+		case ErrForbidden:
+			return http.StatusForbidden, appErr.msg
+		case ErrUnauthorized:
+			return http.StatusUnauthorized, appErr.msg
+		}
+	}
+	if errors.Is(err, pgx.ErrNoRows) {
+		return http.StatusNotFound, "not found"
+	}
+	return http.StatusInternalServerError, "Internal server error"
+}
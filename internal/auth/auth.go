@@ -0,0 +1,118 @@
+// Package auth carries the authenticated caller through request contexts.
+// Registrars authenticate with an API token, sent as a bearer token or,
+// from the web pages, in a cookie; services read the caller, and with it
+// their office, from the context.
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+)
+
+const (
+	RoleRegistrar = "registrar"
+	// RoleSupervisor is a registrar who may also open sealed records.
+	RoleSupervisor = "supervisor"
+	RoleAdmin      = "admin"
+
+	// CookieName holds the token for the web pages.
+	CookieName = "registrar_token"
+)
+
+// Principal is an authenticated caller. The bootstrap admin configured
+// with ADMIN_TOKEN has no registrar record and no office.
+type Principal struct {
+	Registrar *repository.Registrar
+	Office    *repository.Office
+	Role      string
+}
+
+// Name identifies the caller in records and logs.
+func (p *Principal) Name() string {
+	if p.Registrar == nil {
+		return "admin"
+	}
+	return p.Registrar.FullName
+}
+
+type contextKey struct{}
+
+// WithPrincipal returns a context carrying p.
+func WithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the authenticated caller, or nil for anonymous
+// requests.
+func FromContext(ctx context.Context) *Principal {
+	p, _ := ctx.Value(contextKey{}).(*Principal)
+	return p
+}
+
+// RequireSignedIn rejects anonymous callers. Personal data, such as persons,
+// their records and relatives, is only shown to signed-in staff.
+func RequireSignedIn(ctx context.Context) error {
+	if FromContext(ctx) == nil {
+		return apperr.Unauthorized("sign in to see personal data")
+	}
+	return nil
+}
+
+// RequireRegistrar returns the caller when they are a registrar attached to
+// an office, the only callers who may make registry records or manage an
+// office's calendar.
+func RequireRegistrar(ctx context.Context) (*Principal, error) {
+	p := FromContext(ctx)
+	if p == nil {
+		return nil, apperr.Unauthorized("sign in as a registrar")
+	}
+	if p.Registrar == nil || p.Office == nil {
+		return nil, apperr.Forbidden("only registrars attached to an office can do this")
+	}
+	return p, nil
+}
+
+// RequireAdmin rejects callers who are not admins.
+func RequireAdmin(ctx context.Context) error {
+	p := FromContext(ctx)
+	if p == nil {
+		return apperr.Unauthorized("sign in as an admin")
+	}
+	if p.Role != RoleAdmin {
+		return apperr.Forbidden("only admins can manage offices and registrars")
+	}
+	return nil
+}
+
+// RequireSealedAccess returns the caller when they may open sealed records,
+// such as the birth parents behind an adoption: supervisors and admins.
+func RequireSealedAccess(ctx context.Context) (*Principal, error) {
+	p := FromContext(ctx)
+	if p == nil {
+		return nil, apperr.Unauthorized("sign in as a supervisor")
+	}
+	if p.Role != RoleSupervisor && p.Role != RoleAdmin {
+		return nil, apperr.Forbidden("only supervisors and admins can open sealed records")
+	}
+	return p, nil
+}
+
+// NewToken returns a random API token, shown to the registrar once.
+func NewToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// HashToken is the form in which tokens are stored and looked up.
+func HashToken(token string) []byte {
+	sum := sha256.Sum256([]byte(token))
+	return sum[:]
+}
@@ -3,10 +3,14 @@ package post
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/render"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"go.uber.org/zap"
 )
 
@@ -27,14 +31,21 @@ func NewHandlers(service *Service, logger *zap.SugaredLogger) *Handlers {
 // @Description Create a new post record
 // @Tags post
 // @Accept json
-// @Produce json
+// @Produce json,xml
 // @Param request body CreatePostRequest true "post data"
 // @Success 201 {object} map[string]interface{} "Created post"
 // @Failure 400 {object} map[string]interface{} "Invalid request"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/post/ [post]
 func (h *Handlers) CreatePost(w http.ResponseWriter, r *http.Request) {
 
+	format := render.Negotiate(r, render.ContentTypeJSON, render.ContentTypeXML)
+	if format == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
 	var req CreatePostRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		h.logger.Errorf("Failed to decode request: %v", err)
@@ -45,19 +56,18 @@ func (h *Handlers) CreatePost(w http.ResponseWriter, r *http.Request) {
 	result, err := h.service.CreatePost(r.Context(), req.Title, req.Body)
 
 	if err != nil {
-		h.logger.Errorf("Service error: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.serviceError(w, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-
-	w.WriteHeader(http.StatusCreated)
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"message": "post created successfully",
-		"data":    result,
+	err = render.Write(w, http.StatusCreated, format, PostEnvelope{
+		Message: "post created successfully",
+		Data:    NewPostResponse(*result),
 	})
 
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
 }
 
 type CreatePostRequest struct {
@@ -67,18 +77,27 @@ type CreatePostRequest struct {
 
 // GetPostByID retrieves a post by ID
 // @Summary Get post by ID
-// @Description Get a specific post by its ID
+// @Description Get a specific post by its ID. Supports If-None-Match and If-Modified-Since.
 // @Tags post
 // @Accept json
-// @Produce json
+// @Produce json,xml
 // @Param id path string true "post ID"
+// @Param If-None-Match header string false "ETag of a cached copy"
 // @Success 200 {object} map[string]interface{} "post found"
+// @Success 304 "Cached copy is still current"
 // @Failure 400 {object} map[string]interface{} "Invalid ID"
 // @Failure 404 {object} map[string]interface{} "post not found"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/post/{id} [get]
 func (h *Handlers) GetPostByID(w http.ResponseWriter, r *http.Request) {
 
+	format := render.Negotiate(r, render.ContentTypeJSON, render.ContentTypeXML)
+	if format == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
 	idParam := chi.URLParam(r, "id")
 
 	id, err := uuid.Parse(idParam)
@@ -91,17 +110,21 @@ func (h *Handlers) GetPostByID(w http.ResponseWriter, r *http.Request) {
 	result, err := h.service.GetPostByID(r.Context(), id)
 
 	if err != nil {
-		h.logger.Errorf("Service error: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.serviceError(w, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"data": result,
+	resp := NewPostResponse(*result)
+	if render.NotModified(w, r, resp.ETag(), format, resp.LastModified()) {
+		return
+	}
+	err = render.Write(w, http.StatusOK, format, PostEnvelope{
+		Data: resp,
 	})
 
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
 }
 
 // GetPublicPosts retrieves all posts
@@ -109,27 +132,65 @@ func (h *Handlers) GetPostByID(w http.ResponseWriter, r *http.Request) {
 // @Description Retrieve all post records
 // @Tags post
 // @Accept json
-// @Produce json
+// @Produce json,xml,text/csv,application/x-ndjson
 // @Success 200 {object} map[string]interface{} "List of posts"
+// @Failure 406 {object} map[string]interface{} "Unsupported Accept type"
 // @Failure 500 {object} map[string]interface{} "Internal server error"
 // @Router /api/post/ [get]
 func (h *Handlers) GetPublicPosts(w http.ResponseWriter, r *http.Request) {
 
+	format := render.Negotiate(r, render.ContentTypeJSON, render.ContentTypeXML, render.ContentTypeCSV, render.ContentTypeNDJSON)
+	if format == "" {
+		http.Error(w, "Not acceptable", http.StatusNotAcceptable)
+		return
+	}
+
+	switch format {
+	case render.ContentTypeNDJSON:
+		stream := render.NewNDJSONStream(w)
+		err := h.service.StreamGetPublicPosts(r.Context(), func(row repository.Post) error {
+			return stream.Write(NewPostResponse(row))
+		})
+		if err := render.FinishStream(w, stream, err); err != nil {
+			h.logger.Errorf("Stream error: %v", err)
+		}
+		return
+	case render.ContentTypeCSV:
+		stream := render.NewCSVStream(w, postCSVHeader)
+		err := h.service.StreamGetPublicPosts(r.Context(), func(row repository.Post) error {
+			return stream.Write(NewPostResponse(row).CSVRecord())
+		})
+		if err := render.FinishStream(w, stream, err); err != nil {
+			h.logger.Errorf("Stream error: %v", err)
+		}
+		return
+	}
+
 	result, err := h.service.GetPublicPosts(r.Context())
 
 	if err != nil {
-		h.logger.Errorf("Service error: %v", err)
-		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		h.serviceError(w, err)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-
-	json.NewEncoder(w).Encode(map[string]interface{}{
-		"data":  result,
-		"count": len(result),
+	err = render.Write(w, http.StatusOK, format, PostListEnvelope{
+		Count: len(result),
+		Data:  NewPostResponses(result),
 	})
 
+	if err != nil {
+		h.logger.Errorf("Failed to write response: %v", err)
+	}
+}
+
+// serviceError maps a service failure onto an HTTP status.
+func (h *Handlers) serviceError(w http.ResponseWriter, err error) {
+	h.logger.Errorf("Service error: %v", err)
+	if errors.Is(err, pgx.ErrNoRows) {
+		http.Error(w, "post not found", http.StatusNotFound)
+		return
+	}
+	http.Error(w, "Internal server error", http.StatusInternalServerError)
 }
 
 // HealthCheck checks the health of the post service
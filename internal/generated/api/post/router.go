@@ -2,7 +2,12 @@
 package post
 
 import (
+	"net/http"
+
+	"connectrpc.com/connect"
+	"github.com/eif-courses/civilregistry/internal/generated/proto/civilregistry/post/v1/postv1connect"
 	"github.com/eif-courses/civilregistry/internal/generated/repository" // ✅ Correct path
+	"github.com/eif-courses/civilregistry/internal/telemetry"
 	"github.com/go-chi/chi/v5"
 	"go.uber.org/zap"
 )
@@ -14,10 +19,21 @@ func PostRouter(queries *repository.Queries, log *zap.SugaredLogger) chi.Router
 	service := NewService(queries, log)
 	handlers := NewHandlers(service, log)
 
-	r.Get("/health", handlers.HealthCheck)
-	r.Post("/", handlers.CreatePost)
-	r.Get("/{id}", handlers.GetPostByID)
-	r.Get("/", handlers.GetPublicPosts)
+	r.Get("/health", telemetry.InstrumentHandler("post", "HealthCheck", handlers.HealthCheck))
+	r.Post("/", telemetry.InstrumentHandler("post", "CreatePost", handlers.CreatePost))
+	r.Get("/{id}", telemetry.InstrumentHandler("post", "GetPostByID", handlers.GetPostByID))
+	r.Get("/", telemetry.InstrumentHandler("post", "GetPublicPosts", handlers.GetPublicPosts))
 
 	return r
 }
+
+// PostConnectHandler serves the same service over gRPC and Connect.
+// Mount the handler at the returned path on the main router. Every RPC is
+// instrumented like the matching REST route.
+func PostConnectHandler(queries *repository.Queries, log *zap.SugaredLogger) (string, http.Handler) {
+	service := NewService(queries, log)
+	return postv1connect.NewPostServiceHandler(
+		NewConnectServer(service, log),
+		connect.WithInterceptors(telemetry.ConnectInterceptor("post")),
+	)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	"github.com/eif-courses/civilregistry/internal/generated/repository" // ✅ Correct path
+	"github.com/eif-courses/civilregistry/internal/telemetry"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
@@ -22,7 +23,10 @@ func NewService(repo *repository.Queries, logger *zap.SugaredLogger) *Service {
 	}
 }
 
-func (s *Service) CreatePost(ctx context.Context, title, body string) (*repository.Post, error) {
+func (s *Service) CreatePost(ctx context.Context, title, body string) (_ *repository.Post, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "post", "CreatePost")
+	defer func() { op.End(err) }()
+
 	s.logger.Infof("Creating new CreatePost: %s", title)
 
 	if title == "" || body == "" {
@@ -42,7 +46,10 @@ func (s *Service) CreatePost(ctx context.Context, title, body string) (*reposito
 	return &result, nil
 }
 
-func (s *Service) GetPostByID(ctx context.Context, id uuid.UUID) (*repository.Post, error) {
+func (s *Service) GetPostByID(ctx context.Context, id uuid.UUID) (_ *repository.Post, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "post", "GetPostByID")
+	defer func() { op.End(err) }()
+
 	s.logger.Infof("GetPostByID called for ID: %s", id)
 
 	result, err := s.repo.GetPostByID(ctx, id)
@@ -55,7 +62,10 @@ func (s *Service) GetPostByID(ctx context.Context, id uuid.UUID) (*repository.Po
 	return &result, nil
 }
 
-func (s *Service) GetPublicPosts(ctx context.Context) ([]repository.Post, error) {
+func (s *Service) GetPublicPosts(ctx context.Context) (_ []repository.Post, err error) {
+	ctx, op := telemetry.StartOperation(ctx, "post", "GetPublicPosts")
+	defer func() { op.End(err) }()
+
 	s.logger.Info("GetPublicPosts called")
 
 	result, err := s.repo.GetPublicPosts(ctx)
@@ -68,7 +78,24 @@ func (s *Service) GetPublicPosts(ctx context.Context) ([]repository.Post, error)
 	return result, nil
 }
 
-func (s *Service) HealthCheck(ctx context.Context) error {
+func (s *Service) StreamGetPublicPosts(ctx context.Context, fn func(repository.Post) error) (err error) {
+	ctx, op := telemetry.StartOperation(ctx, "post", "StreamGetPublicPosts")
+	defer func() { op.End(err) }()
+
+	s.logger.Info("StreamGetPublicPosts called")
+
+	if err := s.repo.StreamGetPublicPosts(ctx, fn); err != nil {
+		s.logger.Errorf("Failed StreamGetPublicPosts: %v", err)
+		return fmt.Errorf("failed StreamGetPublicPosts: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Service) HealthCheck(ctx context.Context) (err error) {
+	_, op := telemetry.StartOperation(ctx, "post", "HealthCheck")
+	defer func() { op.End(err) }()
+
 	s.logger.Info("Performing health check")
 	return nil
 }
@@ -0,0 +1,439 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: civilregistry/post/v1/post.proto
+
+package postv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Post struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Body          string                 `protobuf:"bytes,3,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Post) Reset() {
+	*x = Post{}
+	mi := &file_civilregistry_post_v1_post_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Post) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Post) ProtoMessage() {}
+
+func (x *Post) ProtoReflect() protoreflect.Message {
+	mi := &file_civilregistry_post_v1_post_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Post.ProtoReflect.Descriptor instead.
+func (*Post) Descriptor() ([]byte, []int) {
+	return file_civilregistry_post_v1_post_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Post) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Post) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *Post) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+type CreatePostRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Body          string                 `protobuf:"bytes,2,opt,name=body,proto3" json:"body,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePostRequest) Reset() {
+	*x = CreatePostRequest{}
+	mi := &file_civilregistry_post_v1_post_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePostRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePostRequest) ProtoMessage() {}
+
+func (x *CreatePostRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_civilregistry_post_v1_post_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePostRequest.ProtoReflect.Descriptor instead.
+func (*CreatePostRequest) Descriptor() ([]byte, []int) {
+	return file_civilregistry_post_v1_post_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *CreatePostRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *CreatePostRequest) GetBody() string {
+	if x != nil {
+		return x.Body
+	}
+	return ""
+}
+
+type CreatePostResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Post          *Post                  `protobuf:"bytes,1,opt,name=post,proto3" json:"post,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreatePostResponse) Reset() {
+	*x = CreatePostResponse{}
+	mi := &file_civilregistry_post_v1_post_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePostResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePostResponse) ProtoMessage() {}
+
+func (x *CreatePostResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_civilregistry_post_v1_post_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePostResponse.ProtoReflect.Descriptor instead.
+func (*CreatePostResponse) Descriptor() ([]byte, []int) {
+	return file_civilregistry_post_v1_post_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *CreatePostResponse) GetPost() *Post {
+	if x != nil {
+		return x.Post
+	}
+	return nil
+}
+
+type GetPostByIDRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPostByIDRequest) Reset() {
+	*x = GetPostByIDRequest{}
+	mi := &file_civilregistry_post_v1_post_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPostByIDRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPostByIDRequest) ProtoMessage() {}
+
+func (x *GetPostByIDRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_civilregistry_post_v1_post_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPostByIDRequest.ProtoReflect.Descriptor instead.
+func (*GetPostByIDRequest) Descriptor() ([]byte, []int) {
+	return file_civilregistry_post_v1_post_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *GetPostByIDRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetPostByIDResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Post          *Post                  `protobuf:"bytes,1,opt,name=post,proto3" json:"post,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPostByIDResponse) Reset() {
+	*x = GetPostByIDResponse{}
+	mi := &file_civilregistry_post_v1_post_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPostByIDResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPostByIDResponse) ProtoMessage() {}
+
+func (x *GetPostByIDResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_civilregistry_post_v1_post_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPostByIDResponse.ProtoReflect.Descriptor instead.
+func (*GetPostByIDResponse) Descriptor() ([]byte, []int) {
+	return file_civilregistry_post_v1_post_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetPostByIDResponse) GetPost() *Post {
+	if x != nil {
+		return x.Post
+	}
+	return nil
+}
+
+type GetPublicPostsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPublicPostsRequest) Reset() {
+	*x = GetPublicPostsRequest{}
+	mi := &file_civilregistry_post_v1_post_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPublicPostsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPublicPostsRequest) ProtoMessage() {}
+
+func (x *GetPublicPostsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_civilregistry_post_v1_post_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPublicPostsRequest.ProtoReflect.Descriptor instead.
+func (*GetPublicPostsRequest) Descriptor() ([]byte, []int) {
+	return file_civilregistry_post_v1_post_proto_rawDescGZIP(), []int{5}
+}
+
+type GetPublicPostsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Posts         []*Post                `protobuf:"bytes,1,rep,name=posts,proto3" json:"posts,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPublicPostsResponse) Reset() {
+	*x = GetPublicPostsResponse{}
+	mi := &file_civilregistry_post_v1_post_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPublicPostsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPublicPostsResponse) ProtoMessage() {}
+
+func (x *GetPublicPostsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_civilregistry_post_v1_post_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPublicPostsResponse.ProtoReflect.Descriptor instead.
+func (*GetPublicPostsResponse) Descriptor() ([]byte, []int) {
+	return file_civilregistry_post_v1_post_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetPublicPostsResponse) GetPosts() []*Post {
+	if x != nil {
+		return x.Posts
+	}
+	return nil
+}
+
+var File_civilregistry_post_v1_post_proto protoreflect.FileDescriptor
+
+const file_civilregistry_post_v1_post_proto_rawDesc = "" +
+	"\n" +
+	" civilregistry/post/v1/post.proto\x12\x15civilregistry.post.v1\"@\n" +
+	"\x04Post\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x03 \x01(\tR\x04body\"=\n" +
+	"\x11CreatePostRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x12\n" +
+	"\x04body\x18\x02 \x01(\tR\x04body\"E\n" +
+	"\x12CreatePostResponse\x12/\n" +
+	"\x04post\x18\x01 \x01(\v2\x1b.civilregistry.post.v1.PostR\x04post\"$\n" +
+	"\x12GetPostByIDRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"F\n" +
+	"\x13GetPostByIDResponse\x12/\n" +
+	"\x04post\x18\x01 \x01(\v2\x1b.civilregistry.post.v1.PostR\x04post\"\x17\n" +
+	"\x15GetPublicPostsRequest\"K\n" +
+	"\x16GetPublicPostsResponse\x121\n" +
+	"\x05posts\x18\x01 \x03(\v2\x1b.civilregistry.post.v1.PostR\x05posts2\xaa\x03\n" +
+	"\vPostService\x12a\n" +
+	"\n" +
+	"CreatePost\x12(.civilregistry.post.v1.CreatePostRequest\x1a).civilregistry.post.v1.CreatePostResponse\x12d\n" +
+	"\vGetPostByID\x12).civilregistry.post.v1.GetPostByIDRequest\x1a*.civilregistry.post.v1.GetPostByIDResponse\x12m\n" +
+	"\x0eGetPublicPosts\x12,.civilregistry.post.v1.GetPublicPostsRequest\x1a-.civilregistry.post.v1.GetPublicPostsResponse\x12c\n" +
+	"\x14StreamGetPublicPosts\x12,.civilregistry.post.v1.GetPublicPostsRequest\x1a\x1b.civilregistry.post.v1.Post0\x01B\\ZZgithub.com/eif-courses/civilregistry/internal/generated/proto/civilregistry/post/v1;postv1b\x06proto3"
+
+var (
+	file_civilregistry_post_v1_post_proto_rawDescOnce sync.Once
+	file_civilregistry_post_v1_post_proto_rawDescData []byte
+)
+
+func file_civilregistry_post_v1_post_proto_rawDescGZIP() []byte {
+	file_civilregistry_post_v1_post_proto_rawDescOnce.Do(func() {
+		file_civilregistry_post_v1_post_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_civilregistry_post_v1_post_proto_rawDesc), len(file_civilregistry_post_v1_post_proto_rawDesc)))
+	})
+	return file_civilregistry_post_v1_post_proto_rawDescData
+}
+
+var file_civilregistry_post_v1_post_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_civilregistry_post_v1_post_proto_goTypes = []any{
+	(*Post)(nil),                   // 0: civilregistry.post.v1.Post
+	(*CreatePostRequest)(nil),      // 1: civilregistry.post.v1.CreatePostRequest
+	(*CreatePostResponse)(nil),     // 2: civilregistry.post.v1.CreatePostResponse
+	(*GetPostByIDRequest)(nil),     // 3: civilregistry.post.v1.GetPostByIDRequest
+	(*GetPostByIDResponse)(nil),    // 4: civilregistry.post.v1.GetPostByIDResponse
+	(*GetPublicPostsRequest)(nil),  // 5: civilregistry.post.v1.GetPublicPostsRequest
+	(*GetPublicPostsResponse)(nil), // 6: civilregistry.post.v1.GetPublicPostsResponse
+}
+var file_civilregistry_post_v1_post_proto_depIdxs = []int32{
+	0, // 0: civilregistry.post.v1.CreatePostResponse.post:type_name -> civilregistry.post.v1.Post
+	0, // 1: civilregistry.post.v1.GetPostByIDResponse.post:type_name -> civilregistry.post.v1.Post
+	0, // 2: civilregistry.post.v1.GetPublicPostsResponse.posts:type_name -> civilregistry.post.v1.Post
+	1, // 3: civilregistry.post.v1.PostService.CreatePost:input_type -> civilregistry.post.v1.CreatePostRequest
+	3, // 4: civilregistry.post.v1.PostService.GetPostByID:input_type -> civilregistry.post.v1.GetPostByIDRequest
+	5, // 5: civilregistry.post.v1.PostService.GetPublicPosts:input_type -> civilregistry.post.v1.GetPublicPostsRequest
+	5, // 6: civilregistry.post.v1.PostService.StreamGetPublicPosts:input_type -> civilregistry.post.v1.GetPublicPostsRequest
+	2, // 7: civilregistry.post.v1.PostService.CreatePost:output_type -> civilregistry.post.v1.CreatePostResponse
+	4, // 8: civilregistry.post.v1.PostService.GetPostByID:output_type -> civilregistry.post.v1.GetPostByIDResponse
+	6, // 9: civilregistry.post.v1.PostService.GetPublicPosts:output_type -> civilregistry.post.v1.GetPublicPostsResponse
+	0, // 10: civilregistry.post.v1.PostService.StreamGetPublicPosts:output_type -> civilregistry.post.v1.Post
+	7, // [7:11] is the sub-list for method output_type
+	3, // [3:7] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_civilregistry_post_v1_post_proto_init() }
+func file_civilregistry_post_v1_post_proto_init() {
+	if File_civilregistry_post_v1_post_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_civilregistry_post_v1_post_proto_rawDesc), len(file_civilregistry_post_v1_post_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_civilregistry_post_v1_post_proto_goTypes,
+		DependencyIndexes: file_civilregistry_post_v1_post_proto_depIdxs,
+		MessageInfos:      file_civilregistry_post_v1_post_proto_msgTypes,
+	}.Build()
+	File_civilregistry_post_v1_post_proto = out.File
+	file_civilregistry_post_v1_post_proto_goTypes = nil
+	file_civilregistry_post_v1_post_proto_depIdxs = nil
+}
@@ -0,0 +1,194 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: civilregistry/post/v1/post.proto
+
+package postv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/eif-courses/civilregistry/internal/generated/proto/civilregistry/post/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// PostServiceName is the fully-qualified name of the PostService service.
+	PostServiceName = "civilregistry.post.v1.PostService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// PostServiceCreatePostProcedure is the fully-qualified name of the PostService's CreatePost RPC.
+	PostServiceCreatePostProcedure = "/civilregistry.post.v1.PostService/CreatePost"
+	// PostServiceGetPostByIDProcedure is the fully-qualified name of the PostService's GetPostByID RPC.
+	PostServiceGetPostByIDProcedure = "/civilregistry.post.v1.PostService/GetPostByID"
+	// PostServiceGetPublicPostsProcedure is the fully-qualified name of the PostService's
+	// GetPublicPosts RPC.
+	PostServiceGetPublicPostsProcedure = "/civilregistry.post.v1.PostService/GetPublicPosts"
+	// PostServiceStreamGetPublicPostsProcedure is the fully-qualified name of the PostService's
+	// StreamGetPublicPosts RPC.
+	PostServiceStreamGetPublicPostsProcedure = "/civilregistry.post.v1.PostService/StreamGetPublicPosts"
+)
+
+// PostServiceClient is a client for the civilregistry.post.v1.PostService service.
+type PostServiceClient interface {
+	CreatePost(context.Context, *connect.Request[v1.CreatePostRequest]) (*connect.Response[v1.CreatePostResponse], error)
+	GetPostByID(context.Context, *connect.Request[v1.GetPostByIDRequest]) (*connect.Response[v1.GetPostByIDResponse], error)
+	GetPublicPosts(context.Context, *connect.Request[v1.GetPublicPostsRequest]) (*connect.Response[v1.GetPublicPostsResponse], error)
+	StreamGetPublicPosts(context.Context, *connect.Request[v1.GetPublicPostsRequest]) (*connect.ServerStreamForClient[v1.Post], error)
+}
+
+// NewPostServiceClient constructs a client for the civilregistry.post.v1.PostService service. By
+// default, it uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses,
+// and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the
+// connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewPostServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) PostServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	postServiceMethods := v1.File_civilregistry_post_v1_post_proto.Services().ByName("PostService").Methods()
+	return &postServiceClient{
+		createPost: connect.NewClient[v1.CreatePostRequest, v1.CreatePostResponse](
+			httpClient,
+			baseURL+PostServiceCreatePostProcedure,
+			connect.WithSchema(postServiceMethods.ByName("CreatePost")),
+			connect.WithClientOptions(opts...),
+		),
+		getPostByID: connect.NewClient[v1.GetPostByIDRequest, v1.GetPostByIDResponse](
+			httpClient,
+			baseURL+PostServiceGetPostByIDProcedure,
+			connect.WithSchema(postServiceMethods.ByName("GetPostByID")),
+			connect.WithClientOptions(opts...),
+		),
+		getPublicPosts: connect.NewClient[v1.GetPublicPostsRequest, v1.GetPublicPostsResponse](
+			httpClient,
+			baseURL+PostServiceGetPublicPostsProcedure,
+			connect.WithSchema(postServiceMethods.ByName("GetPublicPosts")),
+			connect.WithClientOptions(opts...),
+		),
+		streamGetPublicPosts: connect.NewClient[v1.GetPublicPostsRequest, v1.Post](
+			httpClient,
+			baseURL+PostServiceStreamGetPublicPostsProcedure,
+			connect.WithSchema(postServiceMethods.ByName("StreamGetPublicPosts")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// postServiceClient implements PostServiceClient.
+type postServiceClient struct {
+	createPost           *connect.Client[v1.CreatePostRequest, v1.CreatePostResponse]
+	getPostByID          *connect.Client[v1.GetPostByIDRequest, v1.GetPostByIDResponse]
+	getPublicPosts       *connect.Client[v1.GetPublicPostsRequest, v1.GetPublicPostsResponse]
+	streamGetPublicPosts *connect.Client[v1.GetPublicPostsRequest, v1.Post]
+}
+
+// CreatePost calls civilregistry.post.v1.PostService.CreatePost.
+func (c *postServiceClient) CreatePost(ctx context.Context, req *connect.Request[v1.CreatePostRequest]) (*connect.Response[v1.CreatePostResponse], error) {
+	return c.createPost.CallUnary(ctx, req)
+}
+
+// GetPostByID calls civilregistry.post.v1.PostService.GetPostByID.
+func (c *postServiceClient) GetPostByID(ctx context.Context, req *connect.Request[v1.GetPostByIDRequest]) (*connect.Response[v1.GetPostByIDResponse], error) {
+	return c.getPostByID.CallUnary(ctx, req)
+}
+
+// GetPublicPosts calls civilregistry.post.v1.PostService.GetPublicPosts.
+func (c *postServiceClient) GetPublicPosts(ctx context.Context, req *connect.Request[v1.GetPublicPostsRequest]) (*connect.Response[v1.GetPublicPostsResponse], error) {
+	return c.getPublicPosts.CallUnary(ctx, req)
+}
+
+// StreamGetPublicPosts calls civilregistry.post.v1.PostService.StreamGetPublicPosts.
+func (c *postServiceClient) StreamGetPublicPosts(ctx context.Context, req *connect.Request[v1.GetPublicPostsRequest]) (*connect.ServerStreamForClient[v1.Post], error) {
+	return c.streamGetPublicPosts.CallServerStream(ctx, req)
+}
+
+// PostServiceHandler is an implementation of the civilregistry.post.v1.PostService service.
+type PostServiceHandler interface {
+	CreatePost(context.Context, *connect.Request[v1.CreatePostRequest]) (*connect.Response[v1.CreatePostResponse], error)
+	GetPostByID(context.Context, *connect.Request[v1.GetPostByIDRequest]) (*connect.Response[v1.GetPostByIDResponse], error)
+	GetPublicPosts(context.Context, *connect.Request[v1.GetPublicPostsRequest]) (*connect.Response[v1.GetPublicPostsResponse], error)
+	StreamGetPublicPosts(context.Context, *connect.Request[v1.GetPublicPostsRequest], *connect.ServerStream[v1.Post]) error
+}
+
+// NewPostServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewPostServiceHandler(svc PostServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	postServiceMethods := v1.File_civilregistry_post_v1_post_proto.Services().ByName("PostService").Methods()
+	postServiceCreatePostHandler := connect.NewUnaryHandler(
+		PostServiceCreatePostProcedure,
+		svc.CreatePost,
+		connect.WithSchema(postServiceMethods.ByName("CreatePost")),
+		connect.WithHandlerOptions(opts...),
+	)
+	postServiceGetPostByIDHandler := connect.NewUnaryHandler(
+		PostServiceGetPostByIDProcedure,
+		svc.GetPostByID,
+		connect.WithSchema(postServiceMethods.ByName("GetPostByID")),
+		connect.WithHandlerOptions(opts...),
+	)
+	postServiceGetPublicPostsHandler := connect.NewUnaryHandler(
+		PostServiceGetPublicPostsProcedure,
+		svc.GetPublicPosts,
+		connect.WithSchema(postServiceMethods.ByName("GetPublicPosts")),
+		connect.WithHandlerOptions(opts...),
+	)
+	postServiceStreamGetPublicPostsHandler := connect.NewServerStreamHandler(
+		PostServiceStreamGetPublicPostsProcedure,
+		svc.StreamGetPublicPosts,
+		connect.WithSchema(postServiceMethods.ByName("StreamGetPublicPosts")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/civilregistry.post.v1.PostService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case PostServiceCreatePostProcedure:
+			postServiceCreatePostHandler.ServeHTTP(w, r)
+		case PostServiceGetPostByIDProcedure:
+			postServiceGetPostByIDHandler.ServeHTTP(w, r)
+		case PostServiceGetPublicPostsProcedure:
+			postServiceGetPublicPostsHandler.ServeHTTP(w, r)
+		case PostServiceStreamGetPublicPostsProcedure:
+			postServiceStreamGetPublicPostsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedPostServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedPostServiceHandler struct{}
+
+func (UnimplementedPostServiceHandler) CreatePost(context.Context, *connect.Request[v1.CreatePostRequest]) (*connect.Response[v1.CreatePostResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("civilregistry.post.v1.PostService.CreatePost is not implemented"))
+}
+
+func (UnimplementedPostServiceHandler) GetPostByID(context.Context, *connect.Request[v1.GetPostByIDRequest]) (*connect.Response[v1.GetPostByIDResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("civilregistry.post.v1.PostService.GetPostByID is not implemented"))
+}
+
+func (UnimplementedPostServiceHandler) GetPublicPosts(context.Context, *connect.Request[v1.GetPublicPostsRequest]) (*connect.Response[v1.GetPublicPostsResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("civilregistry.post.v1.PostService.GetPublicPosts is not implemented"))
+}
+
+func (UnimplementedPostServiceHandler) StreamGetPublicPosts(context.Context, *connect.Request[v1.GetPublicPostsRequest], *connect.ServerStream[v1.Post]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("civilregistry.post.v1.PostService.StreamGetPublicPosts is not implemented"))
+}
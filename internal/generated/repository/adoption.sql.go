@@ -0,0 +1,496 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: adoption.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countOverlappingGuardianships = `-- name: CountOverlappingGuardianships :one
+SELECT count(*) FROM guardianship
+WHERE ward_id = $1
+  AND guardian_id = $2
+  AND (end_date IS NULL OR end_date > $3)
+`
+
+type CountOverlappingGuardianshipsParams struct {
+	WardID     uuid.UUID   `json:"ward_id"`
+	GuardianID uuid.UUID   `json:"guardian_id"`
+	StartDate  pgtype.Date `json:"start_date"`
+}
+
+// Open or later-ending guardianships of the same guardian over the ward
+// that overlap a new one starting on start_date.
+func (q *Queries) CountOverlappingGuardianships(ctx context.Context, arg CountOverlappingGuardianshipsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countOverlappingGuardianships, arg.WardID, arg.GuardianID, arg.StartDate)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAdoption = `-- name: CreateAdoption :one
+INSERT INTO adoption (person_id, adoptive_mother_id, adoptive_father_id, court, decision_number, decided_on,
+                      registration_office, registrar, office_id, registrar_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, person_id, adoptive_mother_id, adoptive_father_id, court, decision_number, decided_on, status, revoked_on, registration_office, registrar, office_id, registrar_id, created_at
+`
+
+type CreateAdoptionParams struct {
+	PersonID           uuid.UUID   `json:"person_id"`
+	AdoptiveMotherID   pgtype.UUID `json:"adoptive_mother_id"`
+	AdoptiveFatherID   pgtype.UUID `json:"adoptive_father_id"`
+	Court              string      `json:"court"`
+	DecisionNumber     string      `json:"decision_number"`
+	DecidedOn          pgtype.Date `json:"decided_on"`
+	RegistrationOffice string      `json:"registration_office"`
+	Registrar          string      `json:"registrar"`
+	OfficeID           pgtype.UUID `json:"office_id"`
+	RegistrarID        pgtype.UUID `json:"registrar_id"`
+}
+
+func (q *Queries) CreateAdoption(ctx context.Context, arg CreateAdoptionParams) (Adoption, error) {
+	row := q.db.QueryRow(ctx, createAdoption,
+		arg.PersonID,
+		arg.AdoptiveMotherID,
+		arg.AdoptiveFatherID,
+		arg.Court,
+		arg.DecisionNumber,
+		arg.DecidedOn,
+		arg.RegistrationOffice,
+		arg.Registrar,
+		arg.OfficeID,
+		arg.RegistrarID,
+	)
+	var i Adoption
+	err := row.Scan(
+		&i.ID,
+		&i.PersonID,
+		&i.AdoptiveMotherID,
+		&i.AdoptiveFatherID,
+		&i.Court,
+		&i.DecisionNumber,
+		&i.DecidedOn,
+		&i.Status,
+		&i.RevokedOn,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createGuardianship = `-- name: CreateGuardianship :one
+INSERT INTO guardianship (ward_id, guardian_id, kind, court, decision_number, decided_on, start_date,
+                          registration_office, registrar, office_id, registrar_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING id, ward_id, guardian_id, kind, court, decision_number, decided_on, start_date, end_date, registration_office, registrar, office_id, registrar_id, created_at
+`
+
+type CreateGuardianshipParams struct {
+	WardID             uuid.UUID   `json:"ward_id"`
+	GuardianID         uuid.UUID   `json:"guardian_id"`
+	Kind               string      `json:"kind"`
+	Court              string      `json:"court"`
+	DecisionNumber     string      `json:"decision_number"`
+	DecidedOn          pgtype.Date `json:"decided_on"`
+	StartDate          pgtype.Date `json:"start_date"`
+	RegistrationOffice string      `json:"registration_office"`
+	Registrar          string      `json:"registrar"`
+	OfficeID           pgtype.UUID `json:"office_id"`
+	RegistrarID        pgtype.UUID `json:"registrar_id"`
+}
+
+func (q *Queries) CreateGuardianship(ctx context.Context, arg CreateGuardianshipParams) (Guardianship, error) {
+	row := q.db.QueryRow(ctx, createGuardianship,
+		arg.WardID,
+		arg.GuardianID,
+		arg.Kind,
+		arg.Court,
+		arg.DecisionNumber,
+		arg.DecidedOn,
+		arg.StartDate,
+		arg.RegistrationOffice,
+		arg.Registrar,
+		arg.OfficeID,
+		arg.RegistrarID,
+	)
+	var i Guardianship
+	err := row.Scan(
+		&i.ID,
+		&i.WardID,
+		&i.GuardianID,
+		&i.Kind,
+		&i.Court,
+		&i.DecisionNumber,
+		&i.DecidedOn,
+		&i.StartDate,
+		&i.EndDate,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createSealedRecordAccess = `-- name: CreateSealedRecordAccess :one
+INSERT INTO sealed_record_access (adoption_id, registrar_id, accessed_by, reason)
+VALUES ($1, $2, $3, $4)
+RETURNING id, adoption_id, registrar_id, accessed_by, reason, accessed_at
+`
+
+type CreateSealedRecordAccessParams struct {
+	AdoptionID  uuid.UUID   `json:"adoption_id"`
+	RegistrarID pgtype.UUID `json:"registrar_id"`
+	AccessedBy  string      `json:"accessed_by"`
+	Reason      string      `json:"reason"`
+}
+
+func (q *Queries) CreateSealedRecordAccess(ctx context.Context, arg CreateSealedRecordAccessParams) (SealedRecordAccess, error) {
+	row := q.db.QueryRow(ctx, createSealedRecordAccess,
+		arg.AdoptionID,
+		arg.RegistrarID,
+		arg.AccessedBy,
+		arg.Reason,
+	)
+	var i SealedRecordAccess
+	err := row.Scan(
+		&i.ID,
+		&i.AdoptionID,
+		&i.RegistrarID,
+		&i.AccessedBy,
+		&i.Reason,
+		&i.AccessedAt,
+	)
+	return i, err
+}
+
+const endGuardianship = `-- name: EndGuardianship :one
+UPDATE guardianship
+SET end_date = $2
+WHERE id = $1
+  AND end_date IS NULL
+RETURNING id, ward_id, guardian_id, kind, court, decision_number, decided_on, start_date, end_date, registration_office, registrar, office_id, registrar_id, created_at
+`
+
+type EndGuardianshipParams struct {
+	ID      uuid.UUID   `json:"id"`
+	EndDate pgtype.Date `json:"end_date"`
+}
+
+func (q *Queries) EndGuardianship(ctx context.Context, arg EndGuardianshipParams) (Guardianship, error) {
+	row := q.db.QueryRow(ctx, endGuardianship, arg.ID, arg.EndDate)
+	var i Guardianship
+	err := row.Scan(
+		&i.ID,
+		&i.WardID,
+		&i.GuardianID,
+		&i.Kind,
+		&i.Court,
+		&i.DecisionNumber,
+		&i.DecidedOn,
+		&i.StartDate,
+		&i.EndDate,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAdoptionByID = `-- name: GetAdoptionByID :one
+SELECT id, person_id, adoptive_mother_id, adoptive_father_id, court, decision_number, decided_on, status, revoked_on, registration_office, registrar, office_id, registrar_id, created_at FROM adoption
+WHERE id = $1
+`
+
+func (q *Queries) GetAdoptionByID(ctx context.Context, id uuid.UUID) (Adoption, error) {
+	row := q.db.QueryRow(ctx, getAdoptionByID, id)
+	var i Adoption
+	err := row.Scan(
+		&i.ID,
+		&i.PersonID,
+		&i.AdoptiveMotherID,
+		&i.AdoptiveFatherID,
+		&i.Court,
+		&i.DecisionNumber,
+		&i.DecidedOn,
+		&i.Status,
+		&i.RevokedOn,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAdoptionForUpdate = `-- name: GetAdoptionForUpdate :one
+SELECT id, person_id, adoptive_mother_id, adoptive_father_id, court, decision_number, decided_on, status, revoked_on, registration_office, registrar, office_id, registrar_id, created_at FROM adoption
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetAdoptionForUpdate(ctx context.Context, id uuid.UUID) (Adoption, error) {
+	row := q.db.QueryRow(ctx, getAdoptionForUpdate, id)
+	var i Adoption
+	err := row.Scan(
+		&i.ID,
+		&i.PersonID,
+		&i.AdoptiveMotherID,
+		&i.AdoptiveFatherID,
+		&i.Court,
+		&i.DecisionNumber,
+		&i.DecidedOn,
+		&i.Status,
+		&i.RevokedOn,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getGuardianshipForUpdate = `-- name: GetGuardianshipForUpdate :one
+SELECT id, ward_id, guardian_id, kind, court, decision_number, decided_on, start_date, end_date, registration_office, registrar, office_id, registrar_id, created_at FROM guardianship
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetGuardianshipForUpdate(ctx context.Context, id uuid.UUID) (Guardianship, error) {
+	row := q.db.QueryRow(ctx, getGuardianshipForUpdate, id)
+	var i Guardianship
+	err := row.Scan(
+		&i.ID,
+		&i.WardID,
+		&i.GuardianID,
+		&i.Kind,
+		&i.Court,
+		&i.DecisionNumber,
+		&i.DecidedOn,
+		&i.StartDate,
+		&i.EndDate,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLegalParentage = `-- name: GetLegalParentage :one
+SELECT person_id, mother_id, father_id, adoption_id FROM legal_parentage
+WHERE person_id = $1
+`
+
+func (q *Queries) GetLegalParentage(ctx context.Context, personID uuid.UUID) (LegalParentage, error) {
+	row := q.db.QueryRow(ctx, getLegalParentage, personID)
+	var i LegalParentage
+	err := row.Scan(
+		&i.PersonID,
+		&i.MotherID,
+		&i.FatherID,
+		&i.AdoptionID,
+	)
+	return i, err
+}
+
+const listAdoptionsForPerson = `-- name: ListAdoptionsForPerson :many
+SELECT id, person_id, adoptive_mother_id, adoptive_father_id, court, decision_number, decided_on, status, revoked_on, registration_office, registrar, office_id, registrar_id, created_at FROM adoption
+WHERE person_id = $1
+   OR adoptive_mother_id = $1
+   OR adoptive_father_id = $1
+ORDER BY decided_on DESC, id
+`
+
+// Adoptions of the person, and those that made them an adoptive parent.
+func (q *Queries) ListAdoptionsForPerson(ctx context.Context, personID uuid.UUID) ([]Adoption, error) {
+	rows, err := q.db.Query(ctx, listAdoptionsForPerson, personID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Adoption
+	for rows.Next() {
+		var i Adoption
+		if err := rows.Scan(
+			&i.ID,
+			&i.PersonID,
+			&i.AdoptiveMotherID,
+			&i.AdoptiveFatherID,
+			&i.Court,
+			&i.DecisionNumber,
+			&i.DecidedOn,
+			&i.Status,
+			&i.RevokedOn,
+			&i.RegistrationOffice,
+			&i.Registrar,
+			&i.OfficeID,
+			&i.RegistrarID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGuardianshipsForPerson = `-- name: ListGuardianshipsForPerson :many
+SELECT id, ward_id, guardian_id, kind, court, decision_number, decided_on, start_date, end_date, registration_office, registrar, office_id, registrar_id, created_at FROM guardianship
+WHERE ward_id = $1
+   OR guardian_id = $1
+ORDER BY start_date DESC, id
+`
+
+// Guardianships over the person, and those in which they are the guardian.
+func (q *Queries) ListGuardianshipsForPerson(ctx context.Context, personID uuid.UUID) ([]Guardianship, error) {
+	rows, err := q.db.Query(ctx, listGuardianshipsForPerson, personID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Guardianship
+	for rows.Next() {
+		var i Guardianship
+		if err := rows.Scan(
+			&i.ID,
+			&i.WardID,
+			&i.GuardianID,
+			&i.Kind,
+			&i.Court,
+			&i.DecisionNumber,
+			&i.DecidedOn,
+			&i.StartDate,
+			&i.EndDate,
+			&i.RegistrationOffice,
+			&i.Registrar,
+			&i.OfficeID,
+			&i.RegistrarID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLegalParentage = `-- name: ListLegalParentage :many
+SELECT person_id, mother_id, father_id, adoption_id FROM legal_parentage
+WHERE person_id = ANY ($1::uuid[])
+`
+
+func (q *Queries) ListLegalParentage(ctx context.Context, personIds []uuid.UUID) ([]LegalParentage, error) {
+	rows, err := q.db.Query(ctx, listLegalParentage, personIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []LegalParentage
+	for rows.Next() {
+		var i LegalParentage
+		if err := rows.Scan(
+			&i.PersonID,
+			&i.MotherID,
+			&i.FatherID,
+			&i.AdoptionID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSealedRecordAccess = `-- name: ListSealedRecordAccess :many
+SELECT id, adoption_id, registrar_id, accessed_by, reason, accessed_at FROM sealed_record_access
+WHERE adoption_id = $1
+ORDER BY accessed_at DESC, id
+`
+
+func (q *Queries) ListSealedRecordAccess(ctx context.Context, adoptionID uuid.UUID) ([]SealedRecordAccess, error) {
+	rows, err := q.db.Query(ctx, listSealedRecordAccess, adoptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SealedRecordAccess
+	for rows.Next() {
+		var i SealedRecordAccess
+		if err := rows.Scan(
+			&i.ID,
+			&i.AdoptionID,
+			&i.RegistrarID,
+			&i.AccessedBy,
+			&i.Reason,
+			&i.AccessedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeAdoption = `-- name: RevokeAdoption :one
+UPDATE adoption
+SET status     = 'revoked',
+    revoked_on = $2
+WHERE id = $1
+  AND status = 'active'
+RETURNING id, person_id, adoptive_mother_id, adoptive_father_id, court, decision_number, decided_on, status, revoked_on, registration_office, registrar, office_id, registrar_id, created_at
+`
+
+type RevokeAdoptionParams struct {
+	ID        uuid.UUID   `json:"id"`
+	RevokedOn pgtype.Date `json:"revoked_on"`
+}
+
+func (q *Queries) RevokeAdoption(ctx context.Context, arg RevokeAdoptionParams) (Adoption, error) {
+	row := q.db.QueryRow(ctx, revokeAdoption, arg.ID, arg.RevokedOn)
+	var i Adoption
+	err := row.Scan(
+		&i.ID,
+		&i.PersonID,
+		&i.AdoptiveMotherID,
+		&i.AdoptiveFatherID,
+		&i.Court,
+		&i.DecisionNumber,
+		&i.DecidedOn,
+		&i.Status,
+		&i.RevokedOn,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,347 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: application.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const countApplicationsByStatus = `-- name: CountApplicationsByStatus :many
+SELECT status, count(*) AS count FROM application
+WHERE office_id = $1
+GROUP BY status
+`
+
+type CountApplicationsByStatusRow struct {
+	Status string `json:"status"`
+	Count  int64  `json:"count"`
+}
+
+func (q *Queries) CountApplicationsByStatus(ctx context.Context, officeID uuid.UUID) ([]CountApplicationsByStatusRow, error) {
+	rows, err := q.db.Query(ctx, countApplicationsByStatus, officeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CountApplicationsByStatusRow
+	for rows.Next() {
+		var i CountApplicationsByStatusRow
+		if err := rows.Scan(&i.Status, &i.Count); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const createApplication = `-- name: CreateApplication :one
+INSERT INTO application (number, kind, office_id, applicant_name, applicant_email, applicant_phone, personal_code, details)
+VALUES ('APP-' || to_char(now(), 'YYYY') || '-' || lpad(nextval('application_number_seq')::text, 6, '0'),
+        $1, $2, $3, $4, $5, $6, $7)
+RETURNING id, number, kind, status, office_id, applicant_name, applicant_email, applicant_phone, personal_code, details, assigned_to, submitted_at, updated_at, decided_at
+`
+
+type CreateApplicationParams struct {
+	Kind           string    `json:"kind"`
+	OfficeID       uuid.UUID `json:"office_id"`
+	ApplicantName  string    `json:"applicant_name"`
+	ApplicantEmail string    `json:"applicant_email"`
+	ApplicantPhone string    `json:"applicant_phone"`
+	PersonalCode   string    `json:"personal_code"`
+	Details        []byte    `json:"details"`
+}
+
+// Numbers look like APP-2026-000123; the sequence runs across years.
+func (q *Queries) CreateApplication(ctx context.Context, arg CreateApplicationParams) (Application, error) {
+	row := q.db.QueryRow(ctx, createApplication,
+		arg.Kind,
+		arg.OfficeID,
+		arg.ApplicantName,
+		arg.ApplicantEmail,
+		arg.ApplicantPhone,
+		arg.PersonalCode,
+		arg.Details,
+	)
+	var i Application
+	err := row.Scan(
+		&i.ID,
+		&i.Number,
+		&i.Kind,
+		&i.Status,
+		&i.OfficeID,
+		&i.ApplicantName,
+		&i.ApplicantEmail,
+		&i.ApplicantPhone,
+		&i.PersonalCode,
+		&i.Details,
+		&i.AssignedTo,
+		&i.SubmittedAt,
+		&i.UpdatedAt,
+		&i.DecidedAt,
+	)
+	return i, err
+}
+
+const createApplicationStatusChange = `-- name: CreateApplicationStatusChange :one
+INSERT INTO application_status_history (application_id, from_status, to_status, note, changed_by)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, application_id, from_status, to_status, note, changed_by, changed_at
+`
+
+type CreateApplicationStatusChangeParams struct {
+	ApplicationID uuid.UUID   `json:"application_id"`
+	FromStatus    pgtype.Text `json:"from_status"`
+	ToStatus      string      `json:"to_status"`
+	Note          string      `json:"note"`
+	ChangedBy     string      `json:"changed_by"`
+}
+
+func (q *Queries) CreateApplicationStatusChange(ctx context.Context, arg CreateApplicationStatusChangeParams) (ApplicationStatusHistory, error) {
+	row := q.db.QueryRow(ctx, createApplicationStatusChange,
+		arg.ApplicationID,
+		arg.FromStatus,
+		arg.ToStatus,
+		arg.Note,
+		arg.ChangedBy,
+	)
+	var i ApplicationStatusHistory
+	err := row.Scan(
+		&i.ID,
+		&i.ApplicationID,
+		&i.FromStatus,
+		&i.ToStatus,
+		&i.Note,
+		&i.ChangedBy,
+		&i.ChangedAt,
+	)
+	return i, err
+}
+
+const getApplicationByID = `-- name: GetApplicationByID :one
+SELECT id, number, kind, status, office_id, applicant_name, applicant_email, applicant_phone, personal_code, details, assigned_to, submitted_at, updated_at, decided_at FROM application
+WHERE id = $1
+`
+
+func (q *Queries) GetApplicationByID(ctx context.Context, id uuid.UUID) (Application, error) {
+	row := q.db.QueryRow(ctx, getApplicationByID, id)
+	var i Application
+	err := row.Scan(
+		&i.ID,
+		&i.Number,
+		&i.Kind,
+		&i.Status,
+		&i.OfficeID,
+		&i.ApplicantName,
+		&i.ApplicantEmail,
+		&i.ApplicantPhone,
+		&i.PersonalCode,
+		&i.Details,
+		&i.AssignedTo,
+		&i.SubmittedAt,
+		&i.UpdatedAt,
+		&i.DecidedAt,
+	)
+	return i, err
+}
+
+const getApplicationByNumber = `-- name: GetApplicationByNumber :one
+SELECT id, number, kind, status, office_id, applicant_name, applicant_email, applicant_phone, personal_code, details, assigned_to, submitted_at, updated_at, decided_at FROM application
+WHERE number = $1
+`
+
+func (q *Queries) GetApplicationByNumber(ctx context.Context, number string) (Application, error) {
+	row := q.db.QueryRow(ctx, getApplicationByNumber, number)
+	var i Application
+	err := row.Scan(
+		&i.ID,
+		&i.Number,
+		&i.Kind,
+		&i.Status,
+		&i.OfficeID,
+		&i.ApplicantName,
+		&i.ApplicantEmail,
+		&i.ApplicantPhone,
+		&i.PersonalCode,
+		&i.Details,
+		&i.AssignedTo,
+		&i.SubmittedAt,
+		&i.UpdatedAt,
+		&i.DecidedAt,
+	)
+	return i, err
+}
+
+const getApplicationForUpdate = `-- name: GetApplicationForUpdate :one
+SELECT id, number, kind, status, office_id, applicant_name, applicant_email, applicant_phone, personal_code, details, assigned_to, submitted_at, updated_at, decided_at FROM application
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetApplicationForUpdate(ctx context.Context, id uuid.UUID) (Application, error) {
+	row := q.db.QueryRow(ctx, getApplicationForUpdate, id)
+	var i Application
+	err := row.Scan(
+		&i.ID,
+		&i.Number,
+		&i.Kind,
+		&i.Status,
+		&i.OfficeID,
+		&i.ApplicantName,
+		&i.ApplicantEmail,
+		&i.ApplicantPhone,
+		&i.PersonalCode,
+		&i.Details,
+		&i.AssignedTo,
+		&i.SubmittedAt,
+		&i.UpdatedAt,
+		&i.DecidedAt,
+	)
+	return i, err
+}
+
+const listApplicationQueue = `-- name: ListApplicationQueue :many
+SELECT id, number, kind, status, office_id, applicant_name, applicant_email, applicant_phone, personal_code, details, assigned_to, submitted_at, updated_at, decided_at FROM application
+WHERE office_id = $1
+  AND ($2::text IS NULL OR status = $2)
+  AND ($3::text IS NULL OR kind = $3)
+  AND ($4::uuid IS NULL OR assigned_to = $4)
+  AND (NOT $5::boolean OR assigned_to IS NULL)
+ORDER BY submitted_at, number
+LIMIT $7 OFFSET $6
+`
+
+type ListApplicationQueueParams struct {
+	OfficeID   uuid.UUID   `json:"office_id"`
+	Status     pgtype.Text `json:"status"`
+	Kind       pgtype.Text `json:"kind"`
+	AssignedTo pgtype.UUID `json:"assigned_to"`
+	Unassigned bool        `json:"unassigned"`
+	RowOffset  int32       `json:"row_offset"`
+	RowLimit   int32       `json:"row_limit"`
+}
+
+// An office's applications, oldest first. assigned_to filters on the
+// registrar; unassigned keeps applications nobody has picked up.
+func (q *Queries) ListApplicationQueue(ctx context.Context, arg ListApplicationQueueParams) ([]Application, error) {
+	rows, err := q.db.Query(ctx, listApplicationQueue,
+		arg.OfficeID,
+		arg.Status,
+		arg.Kind,
+		arg.AssignedTo,
+		arg.Unassigned,
+		arg.RowOffset,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Application
+	for rows.Next() {
+		var i Application
+		if err := rows.Scan(
+			&i.ID,
+			&i.Number,
+			&i.Kind,
+			&i.Status,
+			&i.OfficeID,
+			&i.ApplicantName,
+			&i.ApplicantEmail,
+			&i.ApplicantPhone,
+			&i.PersonalCode,
+			&i.Details,
+			&i.AssignedTo,
+			&i.SubmittedAt,
+			&i.UpdatedAt,
+			&i.DecidedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listApplicationStatusHistory = `-- name: ListApplicationStatusHistory :many
+SELECT id, application_id, from_status, to_status, note, changed_by, changed_at FROM application_status_history
+WHERE application_id = $1
+ORDER BY changed_at, id
+`
+
+func (q *Queries) ListApplicationStatusHistory(ctx context.Context, applicationID uuid.UUID) ([]ApplicationStatusHistory, error) {
+	rows, err := q.db.Query(ctx, listApplicationStatusHistory, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ApplicationStatusHistory
+	for rows.Next() {
+		var i ApplicationStatusHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.ApplicationID,
+			&i.FromStatus,
+			&i.ToStatus,
+			&i.Note,
+			&i.ChangedBy,
+			&i.ChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setApplicationStatus = `-- name: SetApplicationStatus :one
+UPDATE application
+SET status      = $1,
+    assigned_to = coalesce($2, assigned_to),
+    decided_at  = CASE WHEN $1::text IN ('approved', 'rejected') THEN now() END,
+    updated_at  = now()
+WHERE id = $3
+RETURNING id, number, kind, status, office_id, applicant_name, applicant_email, applicant_phone, personal_code, details, assigned_to, submitted_at, updated_at, decided_at
+`
+
+type SetApplicationStatusParams struct {
+	Status     string      `json:"status"`
+	AssignedTo pgtype.UUID `json:"assigned_to"`
+	ID         uuid.UUID   `json:"id"`
+}
+
+func (q *Queries) SetApplicationStatus(ctx context.Context, arg SetApplicationStatusParams) (Application, error) {
+	row := q.db.QueryRow(ctx, setApplicationStatus, arg.Status, arg.AssignedTo, arg.ID)
+	var i Application
+	err := row.Scan(
+		&i.ID,
+		&i.Number,
+		&i.Kind,
+		&i.Status,
+		&i.OfficeID,
+		&i.ApplicantName,
+		&i.ApplicantEmail,
+		&i.ApplicantPhone,
+		&i.PersonalCode,
+		&i.Details,
+		&i.AssignedTo,
+		&i.SubmittedAt,
+		&i.UpdatedAt,
+		&i.DecidedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,558 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: appointment.sql
+
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const cancelAppointment = `-- name: CancelAppointment :one
+UPDATE appointment
+SET status       = 'cancelled',
+    cancelled_at = now(),
+    cancelled_by = $1
+WHERE id = $2
+RETURNING id, slot_id, reference, full_name, email, phone, personal_code, notes, status, starts_at, ends_at, booked_at, cancelled_at, cancelled_by
+`
+
+type CancelAppointmentParams struct {
+	CancelledBy pgtype.Text `json:"cancelled_by"`
+	ID          uuid.UUID   `json:"id"`
+}
+
+func (q *Queries) CancelAppointment(ctx context.Context, arg CancelAppointmentParams) (Appointment, error) {
+	row := q.db.QueryRow(ctx, cancelAppointment, arg.CancelledBy, arg.ID)
+	var i Appointment
+	err := row.Scan(
+		&i.ID,
+		&i.SlotID,
+		&i.Reference,
+		&i.FullName,
+		&i.Email,
+		&i.Phone,
+		&i.PersonalCode,
+		&i.Notes,
+		&i.Status,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.BookedAt,
+		&i.CancelledAt,
+		&i.CancelledBy,
+	)
+	return i, err
+}
+
+const createAppointment = `-- name: CreateAppointment :one
+INSERT INTO appointment (slot_id, reference, full_name, email, phone, personal_code, notes, starts_at, ends_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, slot_id, reference, full_name, email, phone, personal_code, notes, status, starts_at, ends_at, booked_at, cancelled_at, cancelled_by
+`
+
+type CreateAppointmentParams struct {
+	SlotID       uuid.UUID   `json:"slot_id"`
+	Reference    string      `json:"reference"`
+	FullName     string      `json:"full_name"`
+	Email        string      `json:"email"`
+	Phone        string      `json:"phone"`
+	PersonalCode pgtype.Text `json:"personal_code"`
+	Notes        string      `json:"notes"`
+	StartsAt     time.Time   `json:"starts_at"`
+	EndsAt       time.Time   `json:"ends_at"`
+}
+
+func (q *Queries) CreateAppointment(ctx context.Context, arg CreateAppointmentParams) (Appointment, error) {
+	row := q.db.QueryRow(ctx, createAppointment,
+		arg.SlotID,
+		arg.Reference,
+		arg.FullName,
+		arg.Email,
+		arg.Phone,
+		arg.PersonalCode,
+		arg.Notes,
+		arg.StartsAt,
+		arg.EndsAt,
+	)
+	var i Appointment
+	err := row.Scan(
+		&i.ID,
+		&i.SlotID,
+		&i.Reference,
+		&i.FullName,
+		&i.Email,
+		&i.Phone,
+		&i.PersonalCode,
+		&i.Notes,
+		&i.Status,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.BookedAt,
+		&i.CancelledAt,
+		&i.CancelledBy,
+	)
+	return i, err
+}
+
+const createAppointmentSlot = `-- name: CreateAppointmentSlot :one
+INSERT INTO appointment_slot (office_id, kind, starts_at, ends_at, capacity, created_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, office_id, kind, starts_at, ends_at, capacity, booked, created_by, created_at
+`
+
+type CreateAppointmentSlotParams struct {
+	OfficeID  uuid.UUID `json:"office_id"`
+	Kind      string    `json:"kind"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Capacity  int32     `json:"capacity"`
+	CreatedBy uuid.UUID `json:"created_by"`
+}
+
+func (q *Queries) CreateAppointmentSlot(ctx context.Context, arg CreateAppointmentSlotParams) (AppointmentSlot, error) {
+	row := q.db.QueryRow(ctx, createAppointmentSlot,
+		arg.OfficeID,
+		arg.Kind,
+		arg.StartsAt,
+		arg.EndsAt,
+		arg.Capacity,
+		arg.CreatedBy,
+	)
+	var i AppointmentSlot
+	err := row.Scan(
+		&i.ID,
+		&i.OfficeID,
+		&i.Kind,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Capacity,
+		&i.Booked,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createDueAppointmentReminders = `-- name: CreateDueAppointmentReminders :many
+INSERT INTO appointment_reminder (appointment_id)
+SELECT a.id FROM appointment a
+WHERE a.status = 'booked'
+  AND a.starts_at > now()
+  AND a.starts_at <= $1
+ON CONFLICT (appointment_id) DO NOTHING
+RETURNING id, appointment_id, created_at, sent_at
+`
+
+// Adds a reminder for every booked appointment starting before until that
+// has none yet, so running it again is harmless.
+func (q *Queries) CreateDueAppointmentReminders(ctx context.Context, until time.Time) ([]AppointmentReminder, error) {
+	rows, err := q.db.Query(ctx, createDueAppointmentReminders, until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AppointmentReminder
+	for rows.Next() {
+		var i AppointmentReminder
+		if err := rows.Scan(
+			&i.ID,
+			&i.AppointmentID,
+			&i.CreatedAt,
+			&i.SentAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deleteUnusedAppointmentSlot = `-- name: DeleteUnusedAppointmentSlot :execrows
+DELETE FROM appointment_slot s
+WHERE s.id = $1
+  AND NOT EXISTS (SELECT 1 FROM appointment a WHERE a.slot_id = s.id)
+`
+
+// Removes a slot nobody has booked, not even with a cancelled booking.
+func (q *Queries) DeleteUnusedAppointmentSlot(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUnusedAppointmentSlot, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getAppointmentByReference = `-- name: GetAppointmentByReference :one
+SELECT id, slot_id, reference, full_name, email, phone, personal_code, notes, status, starts_at, ends_at, booked_at, cancelled_at, cancelled_by FROM appointment
+WHERE reference = $1
+`
+
+func (q *Queries) GetAppointmentByReference(ctx context.Context, reference string) (Appointment, error) {
+	row := q.db.QueryRow(ctx, getAppointmentByReference, reference)
+	var i Appointment
+	err := row.Scan(
+		&i.ID,
+		&i.SlotID,
+		&i.Reference,
+		&i.FullName,
+		&i.Email,
+		&i.Phone,
+		&i.PersonalCode,
+		&i.Notes,
+		&i.Status,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.BookedAt,
+		&i.CancelledAt,
+		&i.CancelledBy,
+	)
+	return i, err
+}
+
+const getAppointmentForUpdate = `-- name: GetAppointmentForUpdate :one
+SELECT id, slot_id, reference, full_name, email, phone, personal_code, notes, status, starts_at, ends_at, booked_at, cancelled_at, cancelled_by FROM appointment
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetAppointmentForUpdate(ctx context.Context, id uuid.UUID) (Appointment, error) {
+	row := q.db.QueryRow(ctx, getAppointmentForUpdate, id)
+	var i Appointment
+	err := row.Scan(
+		&i.ID,
+		&i.SlotID,
+		&i.Reference,
+		&i.FullName,
+		&i.Email,
+		&i.Phone,
+		&i.PersonalCode,
+		&i.Notes,
+		&i.Status,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.BookedAt,
+		&i.CancelledAt,
+		&i.CancelledBy,
+	)
+	return i, err
+}
+
+const getAppointmentSlotByID = `-- name: GetAppointmentSlotByID :one
+SELECT id, office_id, kind, starts_at, ends_at, capacity, booked, created_by, created_at FROM appointment_slot
+WHERE id = $1
+`
+
+func (q *Queries) GetAppointmentSlotByID(ctx context.Context, id uuid.UUID) (AppointmentSlot, error) {
+	row := q.db.QueryRow(ctx, getAppointmentSlotByID, id)
+	var i AppointmentSlot
+	err := row.Scan(
+		&i.ID,
+		&i.OfficeID,
+		&i.Kind,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Capacity,
+		&i.Booked,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getAppointmentSlotForUpdate = `-- name: GetAppointmentSlotForUpdate :one
+SELECT id, office_id, kind, starts_at, ends_at, capacity, booked, created_by, created_at FROM appointment_slot
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetAppointmentSlotForUpdate(ctx context.Context, id uuid.UUID) (AppointmentSlot, error) {
+	row := q.db.QueryRow(ctx, getAppointmentSlotForUpdate, id)
+	var i AppointmentSlot
+	err := row.Scan(
+		&i.ID,
+		&i.OfficeID,
+		&i.Kind,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Capacity,
+		&i.Booked,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listAppointmentSlotsForOffice = `-- name: ListAppointmentSlotsForOffice :many
+SELECT id, office_id, kind, starts_at, ends_at, capacity, booked, created_by, created_at FROM appointment_slot
+WHERE office_id = $1
+  AND starts_at >= $2
+  AND starts_at < $3
+ORDER BY starts_at, kind
+`
+
+type ListAppointmentSlotsForOfficeParams struct {
+	OfficeID uuid.UUID `json:"office_id"`
+	FromTime time.Time `json:"from_time"`
+	ToTime   time.Time `json:"to_time"`
+}
+
+func (q *Queries) ListAppointmentSlotsForOffice(ctx context.Context, arg ListAppointmentSlotsForOfficeParams) ([]AppointmentSlot, error) {
+	rows, err := q.db.Query(ctx, listAppointmentSlotsForOffice, arg.OfficeID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AppointmentSlot
+	for rows.Next() {
+		var i AppointmentSlot
+		if err := rows.Scan(
+			&i.ID,
+			&i.OfficeID,
+			&i.Kind,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.Capacity,
+			&i.Booked,
+			&i.CreatedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAvailableAppointmentSlots = `-- name: ListAvailableAppointmentSlots :many
+SELECT s.id, s.office_id, s.kind, s.starts_at, s.ends_at, s.capacity, s.booked, s.created_by, s.created_at FROM appointment_slot s
+JOIN office o ON o.id = s.office_id
+WHERE o.active
+  AND s.booked < s.capacity
+  AND s.starts_at > now()
+  AND s.starts_at >= $1
+  AND s.starts_at < $2
+  AND ($3::uuid IS NULL OR s.office_id = $3)
+  AND ($4::text IS NULL OR s.kind = $4)
+ORDER BY s.starts_at, o.code
+LIMIT $5
+`
+
+type ListAvailableAppointmentSlotsParams struct {
+	FromTime time.Time   `json:"from_time"`
+	ToTime   time.Time   `json:"to_time"`
+	OfficeID pgtype.UUID `json:"office_id"`
+	Kind     pgtype.Text `json:"kind"`
+	RowLimit int32       `json:"row_limit"`
+}
+
+// Future slots with free places in active offices.
+func (q *Queries) ListAvailableAppointmentSlots(ctx context.Context, arg ListAvailableAppointmentSlotsParams) ([]AppointmentSlot, error) {
+	rows, err := q.db.Query(ctx, listAvailableAppointmentSlots,
+		arg.FromTime,
+		arg.ToTime,
+		arg.OfficeID,
+		arg.Kind,
+		arg.RowLimit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []AppointmentSlot
+	for rows.Next() {
+		var i AppointmentSlot
+		if err := rows.Scan(
+			&i.ID,
+			&i.OfficeID,
+			&i.Kind,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.Capacity,
+			&i.Booked,
+			&i.CreatedBy,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listBookedAppointmentsForOffice = `-- name: ListBookedAppointmentsForOffice :many
+SELECT a.id, a.slot_id, a.reference, a.full_name, a.email, a.phone, a.personal_code, a.notes, a.status, a.starts_at, a.ends_at, a.booked_at, a.cancelled_at, a.cancelled_by FROM appointment a
+JOIN appointment_slot s ON s.id = a.slot_id
+WHERE s.office_id = $1
+  AND a.status = 'booked'
+  AND a.starts_at >= $2
+  AND a.starts_at < $3
+ORDER BY a.starts_at, a.booked_at
+`
+
+type ListBookedAppointmentsForOfficeParams struct {
+	OfficeID uuid.UUID `json:"office_id"`
+	FromTime time.Time `json:"from_time"`
+	ToTime   time.Time `json:"to_time"`
+}
+
+func (q *Queries) ListBookedAppointmentsForOffice(ctx context.Context, arg ListBookedAppointmentsForOfficeParams) ([]Appointment, error) {
+	rows, err := q.db.Query(ctx, listBookedAppointmentsForOffice, arg.OfficeID, arg.FromTime, arg.ToTime)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Appointment
+	for rows.Next() {
+		var i Appointment
+		if err := rows.Scan(
+			&i.ID,
+			&i.SlotID,
+			&i.Reference,
+			&i.FullName,
+			&i.Email,
+			&i.Phone,
+			&i.PersonalCode,
+			&i.Notes,
+			&i.Status,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.BookedAt,
+			&i.CancelledAt,
+			&i.CancelledBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPendingAppointmentReminders = `-- name: ListPendingAppointmentReminders :many
+SELECT r.id, r.appointment_id, r.created_at, r.sent_at, a.id, a.slot_id, a.reference, a.full_name, a.email, a.phone, a.personal_code, a.notes, a.status, a.starts_at, a.ends_at, a.booked_at, a.cancelled_at, a.cancelled_by FROM appointment_reminder r
+JOIN appointment a ON a.id = r.appointment_id
+JOIN appointment_slot s ON s.id = a.slot_id
+WHERE s.office_id = $1
+  AND r.sent_at IS NULL
+  AND a.status = 'booked'
+  AND a.starts_at > now()
+ORDER BY a.starts_at
+`
+
+type ListPendingAppointmentRemindersRow struct {
+	AppointmentReminder AppointmentReminder `json:"appointment_reminder"`
+	Appointment         Appointment         `json:"appointment"`
+}
+
+func (q *Queries) ListPendingAppointmentReminders(ctx context.Context, officeID uuid.UUID) ([]ListPendingAppointmentRemindersRow, error) {
+	rows, err := q.db.Query(ctx, listPendingAppointmentReminders, officeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPendingAppointmentRemindersRow
+	for rows.Next() {
+		var i ListPendingAppointmentRemindersRow
+		if err := rows.Scan(
+			&i.AppointmentReminder.ID,
+			&i.AppointmentReminder.AppointmentID,
+			&i.AppointmentReminder.CreatedAt,
+			&i.AppointmentReminder.SentAt,
+			&i.Appointment.ID,
+			&i.Appointment.SlotID,
+			&i.Appointment.Reference,
+			&i.Appointment.FullName,
+			&i.Appointment.Email,
+			&i.Appointment.Phone,
+			&i.Appointment.PersonalCode,
+			&i.Appointment.Notes,
+			&i.Appointment.Status,
+			&i.Appointment.StartsAt,
+			&i.Appointment.EndsAt,
+			&i.Appointment.BookedAt,
+			&i.Appointment.CancelledAt,
+			&i.Appointment.CancelledBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markAppointmentReminderSent = `-- name: MarkAppointmentReminderSent :one
+UPDATE appointment_reminder r
+SET sent_at = now()
+FROM appointment a, appointment_slot s
+WHERE r.id = $1
+  AND r.sent_at IS NULL
+  AND a.id = r.appointment_id
+  AND s.id = a.slot_id
+  AND s.office_id = $2
+RETURNING r.id, r.appointment_id, r.created_at, r.sent_at
+`
+
+type MarkAppointmentReminderSentParams struct {
+	ID       uuid.UUID `json:"id"`
+	OfficeID uuid.UUID `json:"office_id"`
+}
+
+func (q *Queries) MarkAppointmentReminderSent(ctx context.Context, arg MarkAppointmentReminderSentParams) (AppointmentReminder, error) {
+	row := q.db.QueryRow(ctx, markAppointmentReminderSent, arg.ID, arg.OfficeID)
+	var i AppointmentReminder
+	err := row.Scan(
+		&i.ID,
+		&i.AppointmentID,
+		&i.CreatedAt,
+		&i.SentAt,
+	)
+	return i, err
+}
+
+const setAppointmentSlotBooked = `-- name: SetAppointmentSlotBooked :one
+UPDATE appointment_slot
+SET booked = $1
+WHERE id = $2
+RETURNING id, office_id, kind, starts_at, ends_at, capacity, booked, created_by, created_at
+`
+
+type SetAppointmentSlotBookedParams struct {
+	Booked int32     `json:"booked"`
+	ID     uuid.UUID `json:"id"`
+}
+
+func (q *Queries) SetAppointmentSlotBooked(ctx context.Context, arg SetAppointmentSlotBookedParams) (AppointmentSlot, error) {
+	row := q.db.QueryRow(ctx, setAppointmentSlotBooked, arg.Booked, arg.ID)
+	var i AppointmentSlot
+	err := row.Scan(
+		&i.ID,
+		&i.OfficeID,
+		&i.Kind,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.Capacity,
+		&i.Booked,
+		&i.CreatedBy,
+		&i.CreatedAt,
+	)
+	return i, err
+}
@@ -0,0 +1,203 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: attachment.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const countAttachmentsByOwner = `-- name: CountAttachmentsByOwner :one
+SELECT count(*) FROM attachment
+WHERE owner_kind = $1
+  AND owner_id = $2
+`
+
+type CountAttachmentsByOwnerParams struct {
+	OwnerKind string    `json:"owner_kind"`
+	OwnerID   uuid.UUID `json:"owner_id"`
+}
+
+func (q *Queries) CountAttachmentsByOwner(ctx context.Context, arg CountAttachmentsByOwnerParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countAttachmentsByOwner, arg.OwnerKind, arg.OwnerID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countAttachmentsBySHA256 = `-- name: CountAttachmentsBySHA256 :one
+SELECT count(*) FROM attachment
+WHERE sha256 = $1
+`
+
+func (q *Queries) CountAttachmentsBySHA256(ctx context.Context, sha256 string) (int64, error) {
+	row := q.db.QueryRow(ctx, countAttachmentsBySHA256, sha256)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createAttachment = `-- name: CreateAttachment :one
+INSERT INTO attachment (owner_kind, owner_id, office_id, filename, content_type, size_bytes, sha256, uploaded_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, owner_kind, owner_id, office_id, filename, content_type, size_bytes, sha256, uploaded_by, uploaded_at
+`
+
+type CreateAttachmentParams struct {
+	OwnerKind   string    `json:"owner_kind"`
+	OwnerID     uuid.UUID `json:"owner_id"`
+	OfficeID    uuid.UUID `json:"office_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	Sha256      string    `json:"sha256"`
+	UploadedBy  string    `json:"uploaded_by"`
+}
+
+func (q *Queries) CreateAttachment(ctx context.Context, arg CreateAttachmentParams) (Attachment, error) {
+	row := q.db.QueryRow(ctx, createAttachment,
+		arg.OwnerKind,
+		arg.OwnerID,
+		arg.OfficeID,
+		arg.Filename,
+		arg.ContentType,
+		arg.SizeBytes,
+		arg.Sha256,
+		arg.UploadedBy,
+	)
+	var i Attachment
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerKind,
+		&i.OwnerID,
+		&i.OfficeID,
+		&i.Filename,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.Sha256,
+		&i.UploadedBy,
+		&i.UploadedAt,
+	)
+	return i, err
+}
+
+const deleteAttachment = `-- name: DeleteAttachment :one
+DELETE FROM attachment
+WHERE id = $1
+RETURNING id, owner_kind, owner_id, office_id, filename, content_type, size_bytes, sha256, uploaded_by, uploaded_at
+`
+
+func (q *Queries) DeleteAttachment(ctx context.Context, id uuid.UUID) (Attachment, error) {
+	row := q.db.QueryRow(ctx, deleteAttachment, id)
+	var i Attachment
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerKind,
+		&i.OwnerID,
+		&i.OfficeID,
+		&i.Filename,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.Sha256,
+		&i.UploadedBy,
+		&i.UploadedAt,
+	)
+	return i, err
+}
+
+const getAttachmentByID = `-- name: GetAttachmentByID :one
+SELECT id, owner_kind, owner_id, office_id, filename, content_type, size_bytes, sha256, uploaded_by, uploaded_at FROM attachment
+WHERE id = $1
+`
+
+func (q *Queries) GetAttachmentByID(ctx context.Context, id uuid.UUID) (Attachment, error) {
+	row := q.db.QueryRow(ctx, getAttachmentByID, id)
+	var i Attachment
+	err := row.Scan(
+		&i.ID,
+		&i.OwnerKind,
+		&i.OwnerID,
+		&i.OfficeID,
+		&i.Filename,
+		&i.ContentType,
+		&i.SizeBytes,
+		&i.Sha256,
+		&i.UploadedBy,
+		&i.UploadedAt,
+	)
+	return i, err
+}
+
+const listAttachmentsByOwner = `-- name: ListAttachmentsByOwner :many
+SELECT id, owner_kind, owner_id, office_id, filename, content_type, size_bytes, sha256, uploaded_by, uploaded_at FROM attachment
+WHERE owner_kind = $1
+  AND owner_id = $2
+ORDER BY uploaded_at, id
+`
+
+type ListAttachmentsByOwnerParams struct {
+	OwnerKind string    `json:"owner_kind"`
+	OwnerID   uuid.UUID `json:"owner_id"`
+}
+
+func (q *Queries) ListAttachmentsByOwner(ctx context.Context, arg ListAttachmentsByOwnerParams) ([]Attachment, error) {
+	rows, err := q.db.Query(ctx, listAttachmentsByOwner, arg.OwnerKind, arg.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Attachment
+	for rows.Next() {
+		var i Attachment
+		if err := rows.Scan(
+			&i.ID,
+			&i.OwnerKind,
+			&i.OwnerID,
+			&i.OfficeID,
+			&i.Filename,
+			&i.ContentType,
+			&i.SizeBytes,
+			&i.Sha256,
+			&i.UploadedBy,
+			&i.UploadedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockAttachmentBlob = `-- name: LockAttachmentBlob :exec
+SELECT pg_advisory_xact_lock(hashtextextended($1::text, 0))
+`
+
+// Serializes uploads and deletions of the same content so a blob is never
+// removed while a new attachment starts using it.
+func (q *Queries) LockAttachmentBlob(ctx context.Context, sha256 string) error {
+	_, err := q.db.Exec(ctx, lockAttachmentBlob, sha256)
+	return err
+}
+
+const lockAttachmentOwner = `-- name: LockAttachmentOwner :exec
+SELECT pg_advisory_xact_lock(hashtextextended($1::text || '/' || $2::uuid::text, 1))
+`
+
+type LockAttachmentOwnerParams struct {
+	OwnerKind string    `json:"owner_kind"`
+	OwnerID   uuid.UUID `json:"owner_id"`
+}
+
+// Serializes uploads to the same application or record so concurrent ones
+// cannot together go past the per-owner limit.
+func (q *Queries) LockAttachmentOwner(ctx context.Context, arg LockAttachmentOwnerParams) error {
+	_, err := q.db.Exec(ctx, lockAttachmentOwner, arg.OwnerKind, arg.OwnerID)
+	return err
+}
@@ -0,0 +1,196 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: birth.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createBirthRecord = `-- name: CreateBirthRecord :one
+INSERT INTO birth_record (person_id, mother_id, father_id, birth_place, registration_office, registrar,
+                          office_id, registrar_id, registry_number, foreign_document_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING id, person_id, mother_id, father_id, birth_place, registration_office, registrar, registered_at, office_id, registrar_id, registry_number, foreign_document_id
+`
+
+type CreateBirthRecordParams struct {
+	PersonID           uuid.UUID   `json:"person_id"`
+	MotherID           pgtype.UUID `json:"mother_id"`
+	FatherID           pgtype.UUID `json:"father_id"`
+	BirthPlace         string      `json:"birth_place"`
+	RegistrationOffice string      `json:"registration_office"`
+	Registrar          string      `json:"registrar"`
+	OfficeID           pgtype.UUID `json:"office_id"`
+	RegistrarID        pgtype.UUID `json:"registrar_id"`
+	RegistryNumber     pgtype.Text `json:"registry_number"`
+	ForeignDocumentID  pgtype.UUID `json:"foreign_document_id"`
+}
+
+func (q *Queries) CreateBirthRecord(ctx context.Context, arg CreateBirthRecordParams) (BirthRecord, error) {
+	row := q.db.QueryRow(ctx, createBirthRecord,
+		arg.PersonID,
+		arg.MotherID,
+		arg.FatherID,
+		arg.BirthPlace,
+		arg.RegistrationOffice,
+		arg.Registrar,
+		arg.OfficeID,
+		arg.RegistrarID,
+		arg.RegistryNumber,
+		arg.ForeignDocumentID,
+	)
+	var i BirthRecord
+	err := row.Scan(
+		&i.ID,
+		&i.PersonID,
+		&i.MotherID,
+		&i.FatherID,
+		&i.BirthPlace,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.RegisteredAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.RegistryNumber,
+		&i.ForeignDocumentID,
+	)
+	return i, err
+}
+
+const getBirthRecordByID = `-- name: GetBirthRecordByID :one
+SELECT id, person_id, mother_id, father_id, birth_place, registration_office, registrar, registered_at, office_id, registrar_id, registry_number, foreign_document_id FROM birth_record
+WHERE id = $1
+`
+
+func (q *Queries) GetBirthRecordByID(ctx context.Context, id uuid.UUID) (BirthRecord, error) {
+	row := q.db.QueryRow(ctx, getBirthRecordByID, id)
+	var i BirthRecord
+	err := row.Scan(
+		&i.ID,
+		&i.PersonID,
+		&i.MotherID,
+		&i.FatherID,
+		&i.BirthPlace,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.RegisteredAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.RegistryNumber,
+		&i.ForeignDocumentID,
+	)
+	return i, err
+}
+
+const getBirthRecordByPersonID = `-- name: GetBirthRecordByPersonID :one
+SELECT id, person_id, mother_id, father_id, birth_place, registration_office, registrar, registered_at, office_id, registrar_id, registry_number, foreign_document_id FROM birth_record
+WHERE person_id = $1
+`
+
+func (q *Queries) GetBirthRecordByPersonID(ctx context.Context, personID uuid.UUID) (BirthRecord, error) {
+	row := q.db.QueryRow(ctx, getBirthRecordByPersonID, personID)
+	var i BirthRecord
+	err := row.Scan(
+		&i.ID,
+		&i.PersonID,
+		&i.MotherID,
+		&i.FatherID,
+		&i.BirthPlace,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.RegisteredAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.RegistryNumber,
+		&i.ForeignDocumentID,
+	)
+	return i, err
+}
+
+const getBirthRecordByRegistryNumber = `-- name: GetBirthRecordByRegistryNumber :one
+SELECT id, person_id, mother_id, father_id, birth_place, registration_office, registrar, registered_at, office_id, registrar_id, registry_number, foreign_document_id FROM birth_record
+WHERE registry_number = $1
+`
+
+func (q *Queries) GetBirthRecordByRegistryNumber(ctx context.Context, registryNumber pgtype.Text) (BirthRecord, error) {
+	row := q.db.QueryRow(ctx, getBirthRecordByRegistryNumber, registryNumber)
+	var i BirthRecord
+	err := row.Scan(
+		&i.ID,
+		&i.PersonID,
+		&i.MotherID,
+		&i.FatherID,
+		&i.BirthPlace,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.RegisteredAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.RegistryNumber,
+		&i.ForeignDocumentID,
+	)
+	return i, err
+}
+
+const listBirthRecords = `-- name: ListBirthRecords :many
+SELECT id, person_id, mother_id, father_id, birth_place, registration_office, registrar, registered_at, office_id, registrar_id, registry_number, foreign_document_id FROM birth_record
+WHERE ($1::text IS NULL
+    OR ($1 = 'foreign') = (foreign_document_id IS NOT NULL))
+  AND ($2::text IS NULL
+    OR foreign_document_id IN (SELECT id FROM foreign_document WHERE country = $2))
+ORDER BY registered_at DESC, id
+LIMIT $4 OFFSET $3
+`
+
+type ListBirthRecordsParams struct {
+	Origin  pgtype.Text `json:"origin"`
+	Country pgtype.Text `json:"country"`
+	Offset  int32       `json:"offset"`
+	Limit   int32       `json:"limit"`
+}
+
+// origin is domestic or foreign; country keeps births abroad in that
+// country.
+func (q *Queries) ListBirthRecords(ctx context.Context, arg ListBirthRecordsParams) ([]BirthRecord, error) {
+	rows, err := q.db.Query(ctx, listBirthRecords,
+		arg.Origin,
+		arg.Country,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BirthRecord
+	for rows.Next() {
+		var i BirthRecord
+		if err := rows.Scan(
+			&i.ID,
+			&i.PersonID,
+			&i.MotherID,
+			&i.FatherID,
+			&i.BirthPlace,
+			&i.RegistrationOffice,
+			&i.Registrar,
+			&i.RegisteredAt,
+			&i.OfficeID,
+			&i.RegistrarID,
+			&i.RegistryNumber,
+			&i.ForeignDocumentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,177 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: certificate.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createCertificate = `-- name: CreateCertificate :one
+INSERT INTO certificate (kind, record_id, person_id, content, issued_by, office_id, registrar_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING id, serial_number, kind, record_id, person_id, content, issued_on, issued_by, created_at, revoked_at, revoked_by, revocation_reason, revoking_registrar_id, office_id, registrar_id
+`
+
+type CreateCertificateParams struct {
+	Kind        string      `json:"kind"`
+	RecordID    uuid.UUID   `json:"record_id"`
+	PersonID    uuid.UUID   `json:"person_id"`
+	Content     []byte      `json:"content"`
+	IssuedBy    string      `json:"issued_by"`
+	OfficeID    pgtype.UUID `json:"office_id"`
+	RegistrarID pgtype.UUID `json:"registrar_id"`
+}
+
+func (q *Queries) CreateCertificate(ctx context.Context, arg CreateCertificateParams) (Certificate, error) {
+	row := q.db.QueryRow(ctx, createCertificate,
+		arg.Kind,
+		arg.RecordID,
+		arg.PersonID,
+		arg.Content,
+		arg.IssuedBy,
+		arg.OfficeID,
+		arg.RegistrarID,
+	)
+	var i Certificate
+	err := row.Scan(
+		&i.ID,
+		&i.SerialNumber,
+		&i.Kind,
+		&i.RecordID,
+		&i.PersonID,
+		&i.Content,
+		&i.IssuedOn,
+		&i.IssuedBy,
+		&i.CreatedAt,
+		&i.RevokedAt,
+		&i.RevokedBy,
+		&i.RevocationReason,
+		&i.RevokingRegistrarID,
+		&i.OfficeID,
+		&i.RegistrarID,
+	)
+	return i, err
+}
+
+const getCertificateByID = `-- name: GetCertificateByID :one
+SELECT id, serial_number, kind, record_id, person_id, content, issued_on, issued_by, created_at, revoked_at, revoked_by, revocation_reason, revoking_registrar_id, office_id, registrar_id FROM certificate
+WHERE id = $1
+`
+
+func (q *Queries) GetCertificateByID(ctx context.Context, id uuid.UUID) (Certificate, error) {
+	row := q.db.QueryRow(ctx, getCertificateByID, id)
+	var i Certificate
+	err := row.Scan(
+		&i.ID,
+		&i.SerialNumber,
+		&i.Kind,
+		&i.RecordID,
+		&i.PersonID,
+		&i.Content,
+		&i.IssuedOn,
+		&i.IssuedBy,
+		&i.CreatedAt,
+		&i.RevokedAt,
+		&i.RevokedBy,
+		&i.RevocationReason,
+		&i.RevokingRegistrarID,
+		&i.OfficeID,
+		&i.RegistrarID,
+	)
+	return i, err
+}
+
+const listCertificatesForPerson = `-- name: ListCertificatesForPerson :many
+SELECT id, serial_number, kind, record_id, person_id, content, issued_on, issued_by, created_at, revoked_at, revoked_by, revocation_reason, revoking_registrar_id, office_id, registrar_id FROM certificate
+WHERE person_id = $1
+ORDER BY created_at DESC
+`
+
+func (q *Queries) ListCertificatesForPerson(ctx context.Context, personID uuid.UUID) ([]Certificate, error) {
+	rows, err := q.db.Query(ctx, listCertificatesForPerson, personID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Certificate
+	for rows.Next() {
+		var i Certificate
+		if err := rows.Scan(
+			&i.ID,
+			&i.SerialNumber,
+			&i.Kind,
+			&i.RecordID,
+			&i.PersonID,
+			&i.Content,
+			&i.IssuedOn,
+			&i.IssuedBy,
+			&i.CreatedAt,
+			&i.RevokedAt,
+			&i.RevokedBy,
+			&i.RevocationReason,
+			&i.RevokingRegistrarID,
+			&i.OfficeID,
+			&i.RegistrarID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const revokeCertificate = `-- name: RevokeCertificate :one
+UPDATE certificate
+SET revoked_at            = now(),
+    revoked_by            = $2,
+    revocation_reason     = $3,
+    revoking_registrar_id = $4
+WHERE id = $1
+  AND revoked_at IS NULL
+RETURNING id, serial_number, kind, record_id, person_id, content, issued_on, issued_by, created_at, revoked_at, revoked_by, revocation_reason, revoking_registrar_id, office_id, registrar_id
+`
+
+type RevokeCertificateParams struct {
+	ID                  uuid.UUID   `json:"id"`
+	RevokedBy           pgtype.Text `json:"revoked_by"`
+	RevocationReason    pgtype.Text `json:"revocation_reason"`
+	RevokingRegistrarID pgtype.UUID `json:"revoking_registrar_id"`
+}
+
+// Revoked certificates stay revoked; no row is returned for them.
+func (q *Queries) RevokeCertificate(ctx context.Context, arg RevokeCertificateParams) (Certificate, error) {
+	row := q.db.QueryRow(ctx, revokeCertificate,
+		arg.ID,
+		arg.RevokedBy,
+		arg.RevocationReason,
+		arg.RevokingRegistrarID,
+	)
+	var i Certificate
+	err := row.Scan(
+		&i.ID,
+		&i.SerialNumber,
+		&i.Kind,
+		&i.RecordID,
+		&i.PersonID,
+		&i.Content,
+		&i.IssuedOn,
+		&i.IssuedBy,
+		&i.CreatedAt,
+		&i.RevokedAt,
+		&i.RevokedBy,
+		&i.RevocationReason,
+		&i.RevokingRegistrarID,
+		&i.OfficeID,
+		&i.RegistrarID,
+	)
+	return i, err
+}
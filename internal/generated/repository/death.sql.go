@@ -0,0 +1,211 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: death.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createDeathRecord = `-- name: CreateDeathRecord :one
+INSERT INTO death_record (person_id, date_of_death, place_of_death, cause_code,
+                          informant_name, informant_person_id, registration_office, registrar,
+                          office_id, registrar_id, registry_number, foreign_document_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING id, person_id, date_of_death, place_of_death, cause_code, informant_name, informant_person_id, registration_office, registrar, registered_at, office_id, registrar_id, registry_number, foreign_document_id
+`
+
+type CreateDeathRecordParams struct {
+	PersonID           uuid.UUID   `json:"person_id"`
+	DateOfDeath        pgtype.Date `json:"date_of_death"`
+	PlaceOfDeath       string      `json:"place_of_death"`
+	CauseCode          string      `json:"cause_code"`
+	InformantName      string      `json:"informant_name"`
+	InformantPersonID  pgtype.UUID `json:"informant_person_id"`
+	RegistrationOffice string      `json:"registration_office"`
+	Registrar          string      `json:"registrar"`
+	OfficeID           pgtype.UUID `json:"office_id"`
+	RegistrarID        pgtype.UUID `json:"registrar_id"`
+	RegistryNumber     pgtype.Text `json:"registry_number"`
+	ForeignDocumentID  pgtype.UUID `json:"foreign_document_id"`
+}
+
+func (q *Queries) CreateDeathRecord(ctx context.Context, arg CreateDeathRecordParams) (DeathRecord, error) {
+	row := q.db.QueryRow(ctx, createDeathRecord,
+		arg.PersonID,
+		arg.DateOfDeath,
+		arg.PlaceOfDeath,
+		arg.CauseCode,
+		arg.InformantName,
+		arg.InformantPersonID,
+		arg.RegistrationOffice,
+		arg.Registrar,
+		arg.OfficeID,
+		arg.RegistrarID,
+		arg.RegistryNumber,
+		arg.ForeignDocumentID,
+	)
+	var i DeathRecord
+	err := row.Scan(
+		&i.ID,
+		&i.PersonID,
+		&i.DateOfDeath,
+		&i.PlaceOfDeath,
+		&i.CauseCode,
+		&i.InformantName,
+		&i.InformantPersonID,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.RegisteredAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.RegistryNumber,
+		&i.ForeignDocumentID,
+	)
+	return i, err
+}
+
+const getDeathRecordByID = `-- name: GetDeathRecordByID :one
+SELECT id, person_id, date_of_death, place_of_death, cause_code, informant_name, informant_person_id, registration_office, registrar, registered_at, office_id, registrar_id, registry_number, foreign_document_id FROM death_record
+WHERE id = $1
+`
+
+func (q *Queries) GetDeathRecordByID(ctx context.Context, id uuid.UUID) (DeathRecord, error) {
+	row := q.db.QueryRow(ctx, getDeathRecordByID, id)
+	var i DeathRecord
+	err := row.Scan(
+		&i.ID,
+		&i.PersonID,
+		&i.DateOfDeath,
+		&i.PlaceOfDeath,
+		&i.CauseCode,
+		&i.InformantName,
+		&i.InformantPersonID,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.RegisteredAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.RegistryNumber,
+		&i.ForeignDocumentID,
+	)
+	return i, err
+}
+
+const getDeathRecordByPersonID = `-- name: GetDeathRecordByPersonID :one
+SELECT id, person_id, date_of_death, place_of_death, cause_code, informant_name, informant_person_id, registration_office, registrar, registered_at, office_id, registrar_id, registry_number, foreign_document_id FROM death_record
+WHERE person_id = $1
+`
+
+func (q *Queries) GetDeathRecordByPersonID(ctx context.Context, personID uuid.UUID) (DeathRecord, error) {
+	row := q.db.QueryRow(ctx, getDeathRecordByPersonID, personID)
+	var i DeathRecord
+	err := row.Scan(
+		&i.ID,
+		&i.PersonID,
+		&i.DateOfDeath,
+		&i.PlaceOfDeath,
+		&i.CauseCode,
+		&i.InformantName,
+		&i.InformantPersonID,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.RegisteredAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.RegistryNumber,
+		&i.ForeignDocumentID,
+	)
+	return i, err
+}
+
+const getDeathRecordByRegistryNumber = `-- name: GetDeathRecordByRegistryNumber :one
+SELECT id, person_id, date_of_death, place_of_death, cause_code, informant_name, informant_person_id, registration_office, registrar, registered_at, office_id, registrar_id, registry_number, foreign_document_id FROM death_record
+WHERE registry_number = $1
+`
+
+func (q *Queries) GetDeathRecordByRegistryNumber(ctx context.Context, registryNumber pgtype.Text) (DeathRecord, error) {
+	row := q.db.QueryRow(ctx, getDeathRecordByRegistryNumber, registryNumber)
+	var i DeathRecord
+	err := row.Scan(
+		&i.ID,
+		&i.PersonID,
+		&i.DateOfDeath,
+		&i.PlaceOfDeath,
+		&i.CauseCode,
+		&i.InformantName,
+		&i.InformantPersonID,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.RegisteredAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.RegistryNumber,
+		&i.ForeignDocumentID,
+	)
+	return i, err
+}
+
+const listDeathRecords = `-- name: ListDeathRecords :many
+SELECT id, person_id, date_of_death, place_of_death, cause_code, informant_name, informant_person_id, registration_office, registrar, registered_at, office_id, registrar_id, registry_number, foreign_document_id FROM death_record
+WHERE ($1::text IS NULL
+    OR ($1 = 'foreign') = (foreign_document_id IS NOT NULL))
+  AND ($2::text IS NULL
+    OR foreign_document_id IN (SELECT id FROM foreign_document WHERE country = $2))
+ORDER BY registered_at DESC, id
+LIMIT $4 OFFSET $3
+`
+
+type ListDeathRecordsParams struct {
+	Origin  pgtype.Text `json:"origin"`
+	Country pgtype.Text `json:"country"`
+	Offset  int32       `json:"offset"`
+	Limit   int32       `json:"limit"`
+}
+
+// origin is domestic or foreign; country keeps deaths abroad in that
+// country.
+func (q *Queries) ListDeathRecords(ctx context.Context, arg ListDeathRecordsParams) ([]DeathRecord, error) {
+	rows, err := q.db.Query(ctx, listDeathRecords,
+		arg.Origin,
+		arg.Country,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DeathRecord
+	for rows.Next() {
+		var i DeathRecord
+		if err := rows.Scan(
+			&i.ID,
+			&i.PersonID,
+			&i.DateOfDeath,
+			&i.PlaceOfDeath,
+			&i.CauseCode,
+			&i.InformantName,
+			&i.InformantPersonID,
+			&i.RegistrationOffice,
+			&i.Registrar,
+			&i.RegisteredAt,
+			&i.OfficeID,
+			&i.RegistrarID,
+			&i.RegistryNumber,
+			&i.ForeignDocumentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
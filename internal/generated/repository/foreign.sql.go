@@ -0,0 +1,94 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: foreign.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createForeignDocument = `-- name: CreateForeignDocument :one
+INSERT INTO foreign_document (country, issuing_authority, document_number, issued_on, document_language,
+                              legalisation, legalisation_authority, legalisation_number, legalised_on,
+                              translator, translated_on)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING id, country, issuing_authority, document_number, issued_on, document_language, legalisation, legalisation_authority, legalisation_number, legalised_on, translator, translated_on, created_at
+`
+
+type CreateForeignDocumentParams struct {
+	Country               string      `json:"country"`
+	IssuingAuthority      string      `json:"issuing_authority"`
+	DocumentNumber        string      `json:"document_number"`
+	IssuedOn              pgtype.Date `json:"issued_on"`
+	DocumentLanguage      string      `json:"document_language"`
+	Legalisation          string      `json:"legalisation"`
+	LegalisationAuthority pgtype.Text `json:"legalisation_authority"`
+	LegalisationNumber    pgtype.Text `json:"legalisation_number"`
+	LegalisedOn           pgtype.Date `json:"legalised_on"`
+	Translator            pgtype.Text `json:"translator"`
+	TranslatedOn          pgtype.Date `json:"translated_on"`
+}
+
+func (q *Queries) CreateForeignDocument(ctx context.Context, arg CreateForeignDocumentParams) (ForeignDocument, error) {
+	row := q.db.QueryRow(ctx, createForeignDocument,
+		arg.Country,
+		arg.IssuingAuthority,
+		arg.DocumentNumber,
+		arg.IssuedOn,
+		arg.DocumentLanguage,
+		arg.Legalisation,
+		arg.LegalisationAuthority,
+		arg.LegalisationNumber,
+		arg.LegalisedOn,
+		arg.Translator,
+		arg.TranslatedOn,
+	)
+	var i ForeignDocument
+	err := row.Scan(
+		&i.ID,
+		&i.Country,
+		&i.IssuingAuthority,
+		&i.DocumentNumber,
+		&i.IssuedOn,
+		&i.DocumentLanguage,
+		&i.Legalisation,
+		&i.LegalisationAuthority,
+		&i.LegalisationNumber,
+		&i.LegalisedOn,
+		&i.Translator,
+		&i.TranslatedOn,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getForeignDocumentByID = `-- name: GetForeignDocumentByID :one
+SELECT id, country, issuing_authority, document_number, issued_on, document_language, legalisation, legalisation_authority, legalisation_number, legalised_on, translator, translated_on, created_at FROM foreign_document
+WHERE id = $1
+`
+
+func (q *Queries) GetForeignDocumentByID(ctx context.Context, id uuid.UUID) (ForeignDocument, error) {
+	row := q.db.QueryRow(ctx, getForeignDocumentByID, id)
+	var i ForeignDocument
+	err := row.Scan(
+		&i.ID,
+		&i.Country,
+		&i.IssuingAuthority,
+		&i.DocumentNumber,
+		&i.IssuedOn,
+		&i.DocumentLanguage,
+		&i.Legalisation,
+		&i.LegalisationAuthority,
+		&i.LegalisationNumber,
+		&i.LegalisedOn,
+		&i.Translator,
+		&i.TranslatedOn,
+		&i.CreatedAt,
+	)
+	return i, err
+}
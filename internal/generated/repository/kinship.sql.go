@@ -0,0 +1,222 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: kinship.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const listCommonAncestors = `-- name: ListCommonAncestors :many
+WITH RECURSIVE person_line (person_id, depth) AS (
+    SELECT $1::uuid, 0
+    UNION
+    SELECT parent.id, pl.depth + 1
+    FROM person_line pl
+             JOIN legal_parentage b ON b.person_id = pl.person_id
+             JOIN person parent ON parent.id IN (b.mother_id, b.father_id)
+    WHERE pl.depth < $2::int
+),
+relative_line (person_id, depth) AS (
+    SELECT $3::uuid, 0
+    UNION
+    SELECT parent.id, rl.depth + 1
+    FROM relative_line rl
+             JOIN legal_parentage b ON b.person_id = rl.person_id
+             JOIN person parent ON parent.id IN (b.mother_id, b.father_id)
+    WHERE rl.depth < $2::int
+)
+SELECT pl.person_id AS ancestor_id,
+       min(pl.depth)::int AS person_depth,
+       min(rl.depth)::int AS relative_depth
+FROM person_line pl
+         JOIN relative_line rl ON rl.person_id = pl.person_id
+GROUP BY pl.person_id
+ORDER BY min(pl.depth) + min(rl.depth), pl.person_id
+`
+
+type ListCommonAncestorsParams struct {
+	PersonID   uuid.UUID `json:"person_id"`
+	MaxDepth   int32     `json:"max_depth"`
+	RelativeID uuid.UUID `json:"relative_id"`
+}
+
+type ListCommonAncestorsRow struct {
+	AncestorID    uuid.UUID `json:"ancestor_id"`
+	PersonDepth   int32     `json:"person_depth"`
+	RelativeDepth int32     `json:"relative_depth"`
+}
+
+// Walks the legal parent links up from both persons and returns every
+// ancestor they share, with the number of generations from each side. A
+// person counts as their own ancestor at depth 0, so direct lines show up too.
+func (q *Queries) ListCommonAncestors(ctx context.Context, arg ListCommonAncestorsParams) ([]ListCommonAncestorsRow, error) {
+	rows, err := q.db.Query(ctx, listCommonAncestors, arg.PersonID, arg.MaxDepth, arg.RelativeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListCommonAncestorsRow
+	for rows.Next() {
+		var i ListCommonAncestorsRow
+		if err := rows.Scan(&i.AncestorID, &i.PersonDepth, &i.RelativeDepth); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFamilyTree = `-- name: ListFamilyTree :many
+WITH RECURSIVE ancestors (person_id, generation) AS (
+    SELECT $1::uuid, 0
+    UNION
+    SELECT parent.id, a.generation + 1
+    FROM ancestors a
+             JOIN legal_parentage b ON b.person_id = a.person_id
+             JOIN person parent ON parent.id IN (b.mother_id, b.father_id)
+    WHERE a.generation < $2::int
+),
+descendants (person_id, generation) AS (
+    SELECT $1::uuid, 0
+    UNION
+    SELECT b.person_id, d.generation - 1
+    FROM descendants d
+             JOIN legal_parentage b ON d.person_id IN (b.mother_id, b.father_id)
+    WHERE d.generation > -$2::int
+),
+blood AS (
+    SELECT person_id, generation FROM ancestors
+    UNION
+    SELECT person_id, generation FROM descendants
+),
+members AS (
+    SELECT person_id, generation FROM blood
+    UNION
+    SELECT CASE WHEN m.spouse1_id = bl.person_id THEN m.spouse2_id ELSE m.spouse1_id END,
+           bl.generation
+    FROM blood bl
+             JOIN marriage m ON bl.person_id IN (m.spouse1_id, m.spouse2_id)
+)
+SELECT DISTINCT ON (p.id) p.id, p.personal_code, p.first_name, p.last_name, p.birth_date, p.birth_place, p.sex, p.citizenship, p.status, p.version, p.created_at, p.updated_at, p.marital_status, p.merged_into, p.processing_restricted_at, p.office_id, p.registrar_id,
+       mb.generation::int AS generation,
+       b.mother_id,
+       b.father_id
+FROM members mb
+         JOIN person p ON p.id = mb.person_id
+         LEFT JOIN legal_parentage b ON b.person_id = p.id
+ORDER BY p.id, abs(mb.generation)
+`
+
+type ListFamilyTreeParams struct {
+	PersonID    uuid.UUID `json:"person_id"`
+	Generations int32     `json:"generations"`
+}
+
+type ListFamilyTreeRow struct {
+	Person     Person      `json:"person"`
+	Generation int32       `json:"generation"`
+	MotherID   pgtype.UUID `json:"mother_id"`
+	FatherID   pgtype.UUID `json:"father_id"`
+}
+
+// Collects a person's ancestors and descendants up to the given number of
+// generations, plus everyone married to one of them. generation is positive
+// for ancestors, negative for descendants and shared with the partner for
+// spouses. mother_id and father_id are the member's legal parents.
+func (q *Queries) ListFamilyTree(ctx context.Context, arg ListFamilyTreeParams) ([]ListFamilyTreeRow, error) {
+	rows, err := q.db.Query(ctx, listFamilyTree, arg.PersonID, arg.Generations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListFamilyTreeRow
+	for rows.Next() {
+		var i ListFamilyTreeRow
+		if err := rows.Scan(
+			&i.Person.ID,
+			&i.Person.PersonalCode,
+			&i.Person.FirstName,
+			&i.Person.LastName,
+			&i.Person.BirthDate,
+			&i.Person.BirthPlace,
+			&i.Person.Sex,
+			&i.Person.Citizenship,
+			&i.Person.Status,
+			&i.Person.Version,
+			&i.Person.CreatedAt,
+			&i.Person.UpdatedAt,
+			&i.Person.MaritalStatus,
+			&i.Person.MergedInto,
+			&i.Person.ProcessingRestrictedAt,
+			&i.Person.OfficeID,
+			&i.Person.RegistrarID,
+			&i.Generation,
+			&i.MotherID,
+			&i.FatherID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMarriagesAmongPersons = `-- name: ListMarriagesAmongPersons :many
+SELECT id, spouse1_id, spouse2_id, registered_on, registration_office, registrar, spouse1_previous_name, spouse2_previous_name, spouse1_previous_status, spouse2_previous_status, spouse1_new_name, spouse2_new_name, status, ended_on, created_at, updated_at, office_id, registrar_id, registry_number, foreign_document_id FROM marriage
+WHERE spouse1_id = ANY ($1::uuid[])
+  AND spouse2_id = ANY ($1::uuid[])
+ORDER BY registered_on, id
+`
+
+func (q *Queries) ListMarriagesAmongPersons(ctx context.Context, personIds []uuid.UUID) ([]Marriage, error) {
+	rows, err := q.db.Query(ctx, listMarriagesAmongPersons, personIds)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Marriage
+	for rows.Next() {
+		var i Marriage
+		if err := rows.Scan(
+			&i.ID,
+			&i.Spouse1ID,
+			&i.Spouse2ID,
+			&i.RegisteredOn,
+			&i.RegistrationOffice,
+			&i.Registrar,
+			&i.Spouse1PreviousName,
+			&i.Spouse2PreviousName,
+			&i.Spouse1PreviousStatus,
+			&i.Spouse2PreviousStatus,
+			&i.Spouse1NewName,
+			&i.Spouse2NewName,
+			&i.Status,
+			&i.EndedOn,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OfficeID,
+			&i.RegistrarID,
+			&i.RegistryNumber,
+			&i.ForeignDocumentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
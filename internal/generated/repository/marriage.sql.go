@@ -0,0 +1,375 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: marriage.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createMarriage = `-- name: CreateMarriage :one
+INSERT INTO marriage (spouse1_id, spouse2_id, registered_on, registration_office, registrar,
+                      spouse1_previous_name, spouse2_previous_name,
+                      spouse1_previous_status, spouse2_previous_status,
+                      spouse1_new_name, spouse2_new_name, office_id, registrar_id, registry_number,
+                      foreign_document_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+RETURNING id, spouse1_id, spouse2_id, registered_on, registration_office, registrar, spouse1_previous_name, spouse2_previous_name, spouse1_previous_status, spouse2_previous_status, spouse1_new_name, spouse2_new_name, status, ended_on, created_at, updated_at, office_id, registrar_id, registry_number, foreign_document_id
+`
+
+type CreateMarriageParams struct {
+	Spouse1ID             uuid.UUID   `json:"spouse1_id"`
+	Spouse2ID             uuid.UUID   `json:"spouse2_id"`
+	RegisteredOn          pgtype.Date `json:"registered_on"`
+	RegistrationOffice    string      `json:"registration_office"`
+	Registrar             string      `json:"registrar"`
+	Spouse1PreviousName   string      `json:"spouse1_previous_name"`
+	Spouse2PreviousName   string      `json:"spouse2_previous_name"`
+	Spouse1PreviousStatus string      `json:"spouse1_previous_status"`
+	Spouse2PreviousStatus string      `json:"spouse2_previous_status"`
+	Spouse1NewName        pgtype.Text `json:"spouse1_new_name"`
+	Spouse2NewName        pgtype.Text `json:"spouse2_new_name"`
+	OfficeID              pgtype.UUID `json:"office_id"`
+	RegistrarID           pgtype.UUID `json:"registrar_id"`
+	RegistryNumber        pgtype.Text `json:"registry_number"`
+	ForeignDocumentID     pgtype.UUID `json:"foreign_document_id"`
+}
+
+func (q *Queries) CreateMarriage(ctx context.Context, arg CreateMarriageParams) (Marriage, error) {
+	row := q.db.QueryRow(ctx, createMarriage,
+		arg.Spouse1ID,
+		arg.Spouse2ID,
+		arg.RegisteredOn,
+		arg.RegistrationOffice,
+		arg.Registrar,
+		arg.Spouse1PreviousName,
+		arg.Spouse2PreviousName,
+		arg.Spouse1PreviousStatus,
+		arg.Spouse2PreviousStatus,
+		arg.Spouse1NewName,
+		arg.Spouse2NewName,
+		arg.OfficeID,
+		arg.RegistrarID,
+		arg.RegistryNumber,
+		arg.ForeignDocumentID,
+	)
+	var i Marriage
+	err := row.Scan(
+		&i.ID,
+		&i.Spouse1ID,
+		&i.Spouse2ID,
+		&i.RegisteredOn,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.Spouse1PreviousName,
+		&i.Spouse2PreviousName,
+		&i.Spouse1PreviousStatus,
+		&i.Spouse2PreviousStatus,
+		&i.Spouse1NewName,
+		&i.Spouse2NewName,
+		&i.Status,
+		&i.EndedOn,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.RegistryNumber,
+		&i.ForeignDocumentID,
+	)
+	return i, err
+}
+
+const endMarriage = `-- name: EndMarriage :one
+UPDATE marriage
+SET status     = $2,
+    ended_on   = $3,
+    updated_at = now()
+WHERE id = $1
+  AND status = 'active'
+RETURNING id, spouse1_id, spouse2_id, registered_on, registration_office, registrar, spouse1_previous_name, spouse2_previous_name, spouse1_previous_status, spouse2_previous_status, spouse1_new_name, spouse2_new_name, status, ended_on, created_at, updated_at, office_id, registrar_id, registry_number, foreign_document_id
+`
+
+type EndMarriageParams struct {
+	ID      uuid.UUID   `json:"id"`
+	Status  string      `json:"status"`
+	EndedOn pgtype.Date `json:"ended_on"`
+}
+
+func (q *Queries) EndMarriage(ctx context.Context, arg EndMarriageParams) (Marriage, error) {
+	row := q.db.QueryRow(ctx, endMarriage, arg.ID, arg.Status, arg.EndedOn)
+	var i Marriage
+	err := row.Scan(
+		&i.ID,
+		&i.Spouse1ID,
+		&i.Spouse2ID,
+		&i.RegisteredOn,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.Spouse1PreviousName,
+		&i.Spouse2PreviousName,
+		&i.Spouse1PreviousStatus,
+		&i.Spouse2PreviousStatus,
+		&i.Spouse1NewName,
+		&i.Spouse2NewName,
+		&i.Status,
+		&i.EndedOn,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.RegistryNumber,
+		&i.ForeignDocumentID,
+	)
+	return i, err
+}
+
+const getActiveMarriageForPerson = `-- name: GetActiveMarriageForPerson :one
+SELECT id, spouse1_id, spouse2_id, registered_on, registration_office, registrar, spouse1_previous_name, spouse2_previous_name, spouse1_previous_status, spouse2_previous_status, spouse1_new_name, spouse2_new_name, status, ended_on, created_at, updated_at, office_id, registrar_id, registry_number, foreign_document_id FROM marriage
+WHERE status = 'active'
+  AND (spouse1_id = $1 OR spouse2_id = $1)
+`
+
+func (q *Queries) GetActiveMarriageForPerson(ctx context.Context, spouse1ID uuid.UUID) (Marriage, error) {
+	row := q.db.QueryRow(ctx, getActiveMarriageForPerson, spouse1ID)
+	var i Marriage
+	err := row.Scan(
+		&i.ID,
+		&i.Spouse1ID,
+		&i.Spouse2ID,
+		&i.RegisteredOn,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.Spouse1PreviousName,
+		&i.Spouse2PreviousName,
+		&i.Spouse1PreviousStatus,
+		&i.Spouse2PreviousStatus,
+		&i.Spouse1NewName,
+		&i.Spouse2NewName,
+		&i.Status,
+		&i.EndedOn,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.RegistryNumber,
+		&i.ForeignDocumentID,
+	)
+	return i, err
+}
+
+const getMarriageByID = `-- name: GetMarriageByID :one
+SELECT id, spouse1_id, spouse2_id, registered_on, registration_office, registrar, spouse1_previous_name, spouse2_previous_name, spouse1_previous_status, spouse2_previous_status, spouse1_new_name, spouse2_new_name, status, ended_on, created_at, updated_at, office_id, registrar_id, registry_number, foreign_document_id FROM marriage
+WHERE id = $1
+`
+
+func (q *Queries) GetMarriageByID(ctx context.Context, id uuid.UUID) (Marriage, error) {
+	row := q.db.QueryRow(ctx, getMarriageByID, id)
+	var i Marriage
+	err := row.Scan(
+		&i.ID,
+		&i.Spouse1ID,
+		&i.Spouse2ID,
+		&i.RegisteredOn,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.Spouse1PreviousName,
+		&i.Spouse2PreviousName,
+		&i.Spouse1PreviousStatus,
+		&i.Spouse2PreviousStatus,
+		&i.Spouse1NewName,
+		&i.Spouse2NewName,
+		&i.Status,
+		&i.EndedOn,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.RegistryNumber,
+		&i.ForeignDocumentID,
+	)
+	return i, err
+}
+
+const getMarriageByRegistryNumber = `-- name: GetMarriageByRegistryNumber :one
+SELECT id, spouse1_id, spouse2_id, registered_on, registration_office, registrar, spouse1_previous_name, spouse2_previous_name, spouse1_previous_status, spouse2_previous_status, spouse1_new_name, spouse2_new_name, status, ended_on, created_at, updated_at, office_id, registrar_id, registry_number, foreign_document_id FROM marriage
+WHERE registry_number = $1
+`
+
+func (q *Queries) GetMarriageByRegistryNumber(ctx context.Context, registryNumber pgtype.Text) (Marriage, error) {
+	row := q.db.QueryRow(ctx, getMarriageByRegistryNumber, registryNumber)
+	var i Marriage
+	err := row.Scan(
+		&i.ID,
+		&i.Spouse1ID,
+		&i.Spouse2ID,
+		&i.RegisteredOn,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.Spouse1PreviousName,
+		&i.Spouse2PreviousName,
+		&i.Spouse1PreviousStatus,
+		&i.Spouse2PreviousStatus,
+		&i.Spouse1NewName,
+		&i.Spouse2NewName,
+		&i.Status,
+		&i.EndedOn,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.RegistryNumber,
+		&i.ForeignDocumentID,
+	)
+	return i, err
+}
+
+const getMarriageForUpdate = `-- name: GetMarriageForUpdate :one
+SELECT id, spouse1_id, spouse2_id, registered_on, registration_office, registrar, spouse1_previous_name, spouse2_previous_name, spouse1_previous_status, spouse2_previous_status, spouse1_new_name, spouse2_new_name, status, ended_on, created_at, updated_at, office_id, registrar_id, registry_number, foreign_document_id FROM marriage
+WHERE id = $1
+FOR UPDATE
+`
+
+func (q *Queries) GetMarriageForUpdate(ctx context.Context, id uuid.UUID) (Marriage, error) {
+	row := q.db.QueryRow(ctx, getMarriageForUpdate, id)
+	var i Marriage
+	err := row.Scan(
+		&i.ID,
+		&i.Spouse1ID,
+		&i.Spouse2ID,
+		&i.RegisteredOn,
+		&i.RegistrationOffice,
+		&i.Registrar,
+		&i.Spouse1PreviousName,
+		&i.Spouse2PreviousName,
+		&i.Spouse1PreviousStatus,
+		&i.Spouse2PreviousStatus,
+		&i.Spouse1NewName,
+		&i.Spouse2NewName,
+		&i.Status,
+		&i.EndedOn,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.RegistryNumber,
+		&i.ForeignDocumentID,
+	)
+	return i, err
+}
+
+const listMarriages = `-- name: ListMarriages :many
+SELECT id, spouse1_id, spouse2_id, registered_on, registration_office, registrar, spouse1_previous_name, spouse2_previous_name, spouse1_previous_status, spouse2_previous_status, spouse1_new_name, spouse2_new_name, status, ended_on, created_at, updated_at, office_id, registrar_id, registry_number, foreign_document_id FROM marriage
+WHERE ($1::text IS NULL
+    OR ($1 = 'foreign') = (foreign_document_id IS NOT NULL))
+  AND ($2::text IS NULL
+    OR foreign_document_id IN (SELECT id FROM foreign_document WHERE country = $2))
+ORDER BY registered_on DESC, id
+LIMIT $4 OFFSET $3
+`
+
+type ListMarriagesParams struct {
+	Origin  pgtype.Text `json:"origin"`
+	Country pgtype.Text `json:"country"`
+	Offset  int32       `json:"offset"`
+	Limit   int32       `json:"limit"`
+}
+
+// origin is domestic or foreign; country keeps marriages abroad in that
+// country.
+func (q *Queries) ListMarriages(ctx context.Context, arg ListMarriagesParams) ([]Marriage, error) {
+	rows, err := q.db.Query(ctx, listMarriages,
+		arg.Origin,
+		arg.Country,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Marriage
+	for rows.Next() {
+		var i Marriage
+		if err := rows.Scan(
+			&i.ID,
+			&i.Spouse1ID,
+			&i.Spouse2ID,
+			&i.RegisteredOn,
+			&i.RegistrationOffice,
+			&i.Registrar,
+			&i.Spouse1PreviousName,
+			&i.Spouse2PreviousName,
+			&i.Spouse1PreviousStatus,
+			&i.Spouse2PreviousStatus,
+			&i.Spouse1NewName,
+			&i.Spouse2NewName,
+			&i.Status,
+			&i.EndedOn,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OfficeID,
+			&i.RegistrarID,
+			&i.RegistryNumber,
+			&i.ForeignDocumentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMarriagesForPerson = `-- name: ListMarriagesForPerson :many
+SELECT id, spouse1_id, spouse2_id, registered_on, registration_office, registrar, spouse1_previous_name, spouse2_previous_name, spouse1_previous_status, spouse2_previous_status, spouse1_new_name, spouse2_new_name, status, ended_on, created_at, updated_at, office_id, registrar_id, registry_number, foreign_document_id FROM marriage
+WHERE spouse1_id = $1 OR spouse2_id = $1
+ORDER BY registered_on DESC, id
+`
+
+func (q *Queries) ListMarriagesForPerson(ctx context.Context, spouse1ID uuid.UUID) ([]Marriage, error) {
+	rows, err := q.db.Query(ctx, listMarriagesForPerson, spouse1ID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Marriage
+	for rows.Next() {
+		var i Marriage
+		if err := rows.Scan(
+			&i.ID,
+			&i.Spouse1ID,
+			&i.Spouse2ID,
+			&i.RegisteredOn,
+			&i.RegistrationOffice,
+			&i.Registrar,
+			&i.Spouse1PreviousName,
+			&i.Spouse2PreviousName,
+			&i.Spouse1PreviousStatus,
+			&i.Spouse2PreviousStatus,
+			&i.Spouse1NewName,
+			&i.Spouse2NewName,
+			&i.Status,
+			&i.EndedOn,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.OfficeID,
+			&i.RegistrarID,
+			&i.RegistryNumber,
+			&i.ForeignDocumentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
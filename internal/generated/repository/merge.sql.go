@@ -0,0 +1,555 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: merge.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createPersonMerge = `-- name: CreatePersonMerge :one
+INSERT INTO person_merge (survivor_id, duplicate_id, merged_by, reason, moved, duplicate_snapshot, office_id,
+                          registrar_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, survivor_id, duplicate_id, merged_by, reason, moved, duplicate_snapshot, merged_at, office_id, registrar_id
+`
+
+type CreatePersonMergeParams struct {
+	SurvivorID        uuid.UUID   `json:"survivor_id"`
+	DuplicateID       uuid.UUID   `json:"duplicate_id"`
+	MergedBy          string      `json:"merged_by"`
+	Reason            string      `json:"reason"`
+	Moved             []byte      `json:"moved"`
+	DuplicateSnapshot []byte      `json:"duplicate_snapshot"`
+	OfficeID          pgtype.UUID `json:"office_id"`
+	RegistrarID       pgtype.UUID `json:"registrar_id"`
+}
+
+func (q *Queries) CreatePersonMerge(ctx context.Context, arg CreatePersonMergeParams) (PersonMerge, error) {
+	row := q.db.QueryRow(ctx, createPersonMerge,
+		arg.SurvivorID,
+		arg.DuplicateID,
+		arg.MergedBy,
+		arg.Reason,
+		arg.Moved,
+		arg.DuplicateSnapshot,
+		arg.OfficeID,
+		arg.RegistrarID,
+	)
+	var i PersonMerge
+	err := row.Scan(
+		&i.ID,
+		&i.SurvivorID,
+		&i.DuplicateID,
+		&i.MergedBy,
+		&i.Reason,
+		&i.Moved,
+		&i.DuplicateSnapshot,
+		&i.MergedAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+	)
+	return i, err
+}
+
+const listDuplicateCandidates = `-- name: ListDuplicateCandidates :many
+WITH pairs AS (
+    SELECT a.id AS person_id, b.id AS candidate_id
+    FROM person a
+             JOIN person b ON b.birth_date = a.birth_date AND b.id <> a.id
+    WHERE fold_name(a.first_name) = fold_name(b.first_name)
+       OR fold_name(a.last_name) = fold_name(b.last_name)
+    UNION
+    SELECT a.id, b.id
+    FROM person a
+             JOIN person b ON fold_name(b.last_name) = fold_name(a.last_name)
+        AND fold_name(b.first_name) = fold_name(a.first_name)
+        AND b.id <> a.id
+)
+SELECT pairs.person_id, pairs.candidate_id
+FROM pairs
+         JOIN person a ON a.id = pairs.person_id
+         JOIN person b ON b.id = pairs.candidate_id
+WHERE a.merged_into IS NULL
+  AND b.merged_into IS NULL
+  AND a.processing_restricted_at IS NULL
+  AND b.processing_restricted_at IS NULL
+  AND CASE
+          WHEN $1::uuid IS NULL THEN pairs.person_id < pairs.candidate_id
+          ELSE pairs.person_id = $1::uuid
+    END
+ORDER BY pairs.person_id, pairs.candidate_id
+LIMIT $2
+`
+
+type ListDuplicateCandidatesParams struct {
+	PersonID pgtype.UUID `json:"person_id"`
+	Limit    int32       `json:"limit"`
+}
+
+type ListDuplicateCandidatesRow struct {
+	PersonID    uuid.UUID `json:"person_id"`
+	CandidateID uuid.UUID `json:"candidate_id"`
+}
+
+// Pairs of unmerged persons that share a birth date and a first or last name,
+// or share both names, with diacritics folded. Without person_id each pair
+// comes once; with it, only that person's pairs. Persons who restricted
+// processing of their data are left out.
+func (q *Queries) ListDuplicateCandidates(ctx context.Context, arg ListDuplicateCandidatesParams) ([]ListDuplicateCandidatesRow, error) {
+	rows, err := q.db.Query(ctx, listDuplicateCandidates, arg.PersonID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDuplicateCandidatesRow
+	for rows.Next() {
+		var i ListDuplicateCandidatesRow
+		if err := rows.Scan(&i.PersonID, &i.CandidateID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPersonMerges = `-- name: ListPersonMerges :many
+SELECT id, survivor_id, duplicate_id, merged_by, reason, moved, duplicate_snapshot, merged_at, office_id, registrar_id FROM person_merge
+WHERE survivor_id = $1 OR duplicate_id = $1
+ORDER BY merged_at, id
+`
+
+// Merges the person took part in, as survivor or as duplicate.
+func (q *Queries) ListPersonMerges(ctx context.Context, survivorID uuid.UUID) ([]PersonMerge, error) {
+	rows, err := q.db.Query(ctx, listPersonMerges, survivorID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PersonMerge
+	for rows.Next() {
+		var i PersonMerge
+		if err := rows.Scan(
+			&i.ID,
+			&i.SurvivorID,
+			&i.DuplicateID,
+			&i.MergedBy,
+			&i.Reason,
+			&i.Moved,
+			&i.DuplicateSnapshot,
+			&i.MergedAt,
+			&i.OfficeID,
+			&i.RegistrarID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPersonsByIDs = `-- name: ListPersonsByIDs :many
+SELECT id, personal_code, first_name, last_name, birth_date, birth_place, sex, citizenship, status, version, created_at, updated_at, marital_status, merged_into, processing_restricted_at, office_id, registrar_id FROM person
+WHERE id = ANY ($1::uuid[])
+`
+
+func (q *Queries) ListPersonsByIDs(ctx context.Context, ids []uuid.UUID) ([]Person, error) {
+	rows, err := q.db.Query(ctx, listPersonsByIDs, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Person
+	for rows.Next() {
+		var i Person
+		if err := rows.Scan(
+			&i.ID,
+			&i.PersonalCode,
+			&i.FirstName,
+			&i.LastName,
+			&i.BirthDate,
+			&i.BirthPlace,
+			&i.Sex,
+			&i.Citizenship,
+			&i.Status,
+			&i.Version,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.MaritalStatus,
+			&i.MergedInto,
+			&i.ProcessingRestrictedAt,
+			&i.OfficeID,
+			&i.RegistrarID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markPersonMerged = `-- name: MarkPersonMerged :one
+UPDATE person
+SET merged_into = $1,
+    version     = version + 1,
+    updated_at  = now()
+WHERE id = $2
+RETURNING id, personal_code, first_name, last_name, birth_date, birth_place, sex, citizenship, status, version, created_at, updated_at, marital_status, merged_into, processing_restricted_at, office_id, registrar_id
+`
+
+type MarkPersonMergedParams struct {
+	SurvivorID pgtype.UUID `json:"survivor_id"`
+	ID         uuid.UUID   `json:"id"`
+}
+
+func (q *Queries) MarkPersonMerged(ctx context.Context, arg MarkPersonMergedParams) (Person, error) {
+	row := q.db.QueryRow(ctx, markPersonMerged, arg.SurvivorID, arg.ID)
+	var i Person
+	err := row.Scan(
+		&i.ID,
+		&i.PersonalCode,
+		&i.FirstName,
+		&i.LastName,
+		&i.BirthDate,
+		&i.BirthPlace,
+		&i.Sex,
+		&i.Citizenship,
+		&i.Status,
+		&i.Version,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MaritalStatus,
+		&i.MergedInto,
+		&i.ProcessingRestrictedAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+	)
+	return i, err
+}
+
+const rekeyApplications = `-- name: RekeyApplications :execrows
+UPDATE application
+SET personal_code = (SELECT p.personal_code FROM person p WHERE p.id = $1::uuid),
+    updated_at    = now()
+WHERE personal_code = (SELECT p.personal_code FROM person p WHERE p.id = $2::uuid)
+`
+
+type RekeyApplicationsParams struct {
+	SurvivorID  uuid.UUID `json:"survivor_id"`
+	DuplicateID uuid.UUID `json:"duplicate_id"`
+}
+
+// Applications name the person by personal code rather than by ID.
+func (q *Queries) RekeyApplications(ctx context.Context, arg RekeyApplicationsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, rekeyApplications, arg.SurvivorID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const rekeyAppointments = `-- name: RekeyAppointments :execrows
+UPDATE appointment
+SET personal_code = (SELECT p.personal_code FROM person p WHERE p.id = $1::uuid)
+WHERE personal_code = (SELECT p.personal_code FROM person p WHERE p.id = $2::uuid)
+`
+
+type RekeyAppointmentsParams struct {
+	SurvivorID  uuid.UUID `json:"survivor_id"`
+	DuplicateID uuid.UUID `json:"duplicate_id"`
+}
+
+// Appointments name the person by personal code, when one was given.
+func (q *Queries) RekeyAppointments(ctx context.Context, arg RekeyAppointmentsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, rekeyAppointments, arg.SurvivorID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const repointAdoptionPerson = `-- name: RepointAdoptionPerson :execrows
+UPDATE adoption SET person_id = $1 WHERE person_id = $2
+`
+
+type RepointAdoptionPersonParams struct {
+	SurvivorID  uuid.UUID `json:"survivor_id"`
+	DuplicateID uuid.UUID `json:"duplicate_id"`
+}
+
+func (q *Queries) RepointAdoptionPerson(ctx context.Context, arg RepointAdoptionPersonParams) (int64, error) {
+	result, err := q.db.Exec(ctx, repointAdoptionPerson, arg.SurvivorID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const repointAdoptiveFather = `-- name: RepointAdoptiveFather :execrows
+UPDATE adoption SET adoptive_father_id = $1 WHERE adoptive_father_id = $2
+`
+
+type RepointAdoptiveFatherParams struct {
+	SurvivorID  pgtype.UUID `json:"survivor_id"`
+	DuplicateID pgtype.UUID `json:"duplicate_id"`
+}
+
+func (q *Queries) RepointAdoptiveFather(ctx context.Context, arg RepointAdoptiveFatherParams) (int64, error) {
+	result, err := q.db.Exec(ctx, repointAdoptiveFather, arg.SurvivorID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const repointAdoptiveMother = `-- name: RepointAdoptiveMother :execrows
+UPDATE adoption SET adoptive_mother_id = $1 WHERE adoptive_mother_id = $2
+`
+
+type RepointAdoptiveMotherParams struct {
+	SurvivorID  pgtype.UUID `json:"survivor_id"`
+	DuplicateID pgtype.UUID `json:"duplicate_id"`
+}
+
+func (q *Queries) RepointAdoptiveMother(ctx context.Context, arg RepointAdoptiveMotherParams) (int64, error) {
+	result, err := q.db.Exec(ctx, repointAdoptiveMother, arg.SurvivorID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const repointBirthRecordFather = `-- name: RepointBirthRecordFather :execrows
+UPDATE birth_record SET father_id = $1 WHERE father_id = $2
+`
+
+type RepointBirthRecordFatherParams struct {
+	SurvivorID  pgtype.UUID `json:"survivor_id"`
+	DuplicateID pgtype.UUID `json:"duplicate_id"`
+}
+
+func (q *Queries) RepointBirthRecordFather(ctx context.Context, arg RepointBirthRecordFatherParams) (int64, error) {
+	result, err := q.db.Exec(ctx, repointBirthRecordFather, arg.SurvivorID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const repointBirthRecordMother = `-- name: RepointBirthRecordMother :execrows
+UPDATE birth_record SET mother_id = $1 WHERE mother_id = $2
+`
+
+type RepointBirthRecordMotherParams struct {
+	SurvivorID  pgtype.UUID `json:"survivor_id"`
+	DuplicateID pgtype.UUID `json:"duplicate_id"`
+}
+
+func (q *Queries) RepointBirthRecordMother(ctx context.Context, arg RepointBirthRecordMotherParams) (int64, error) {
+	result, err := q.db.Exec(ctx, repointBirthRecordMother, arg.SurvivorID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const repointBirthRecordPerson = `-- name: RepointBirthRecordPerson :execrows
+UPDATE birth_record SET person_id = $1 WHERE person_id = $2
+`
+
+type RepointBirthRecordPersonParams struct {
+	SurvivorID  uuid.UUID `json:"survivor_id"`
+	DuplicateID uuid.UUID `json:"duplicate_id"`
+}
+
+func (q *Queries) RepointBirthRecordPerson(ctx context.Context, arg RepointBirthRecordPersonParams) (int64, error) {
+	result, err := q.db.Exec(ctx, repointBirthRecordPerson, arg.SurvivorID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const repointCertificates = `-- name: RepointCertificates :execrows
+UPDATE certificate SET person_id = $1 WHERE person_id = $2
+`
+
+type RepointCertificatesParams struct {
+	SurvivorID  uuid.UUID `json:"survivor_id"`
+	DuplicateID uuid.UUID `json:"duplicate_id"`
+}
+
+func (q *Queries) RepointCertificates(ctx context.Context, arg RepointCertificatesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, repointCertificates, arg.SurvivorID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const repointDataSubjectRequests = `-- name: RepointDataSubjectRequests :execrows
+UPDATE data_subject_request SET person_id = $1 WHERE person_id = $2
+`
+
+type RepointDataSubjectRequestsParams struct {
+	SurvivorID  uuid.UUID `json:"survivor_id"`
+	DuplicateID uuid.UUID `json:"duplicate_id"`
+}
+
+func (q *Queries) RepointDataSubjectRequests(ctx context.Context, arg RepointDataSubjectRequestsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, repointDataSubjectRequests, arg.SurvivorID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const repointDeathRecordInformant = `-- name: RepointDeathRecordInformant :execrows
+UPDATE death_record SET informant_person_id = $1 WHERE informant_person_id = $2
+`
+
+type RepointDeathRecordInformantParams struct {
+	SurvivorID  pgtype.UUID `json:"survivor_id"`
+	DuplicateID pgtype.UUID `json:"duplicate_id"`
+}
+
+func (q *Queries) RepointDeathRecordInformant(ctx context.Context, arg RepointDeathRecordInformantParams) (int64, error) {
+	result, err := q.db.Exec(ctx, repointDeathRecordInformant, arg.SurvivorID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const repointDeathRecordPerson = `-- name: RepointDeathRecordPerson :execrows
+UPDATE death_record SET person_id = $1 WHERE person_id = $2
+`
+
+type RepointDeathRecordPersonParams struct {
+	SurvivorID  uuid.UUID `json:"survivor_id"`
+	DuplicateID uuid.UUID `json:"duplicate_id"`
+}
+
+func (q *Queries) RepointDeathRecordPerson(ctx context.Context, arg RepointDeathRecordPersonParams) (int64, error) {
+	result, err := q.db.Exec(ctx, repointDeathRecordPerson, arg.SurvivorID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const repointGuardianshipGuardian = `-- name: RepointGuardianshipGuardian :execrows
+UPDATE guardianship SET guardian_id = $1 WHERE guardian_id = $2
+`
+
+type RepointGuardianshipGuardianParams struct {
+	SurvivorID  uuid.UUID `json:"survivor_id"`
+	DuplicateID uuid.UUID `json:"duplicate_id"`
+}
+
+func (q *Queries) RepointGuardianshipGuardian(ctx context.Context, arg RepointGuardianshipGuardianParams) (int64, error) {
+	result, err := q.db.Exec(ctx, repointGuardianshipGuardian, arg.SurvivorID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const repointGuardianshipWard = `-- name: RepointGuardianshipWard :execrows
+UPDATE guardianship SET ward_id = $1 WHERE ward_id = $2
+`
+
+type RepointGuardianshipWardParams struct {
+	SurvivorID  uuid.UUID `json:"survivor_id"`
+	DuplicateID uuid.UUID `json:"duplicate_id"`
+}
+
+func (q *Queries) RepointGuardianshipWard(ctx context.Context, arg RepointGuardianshipWardParams) (int64, error) {
+	result, err := q.db.Exec(ctx, repointGuardianshipWard, arg.SurvivorID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const repointMarriageSpouse1 = `-- name: RepointMarriageSpouse1 :execrows
+UPDATE marriage SET spouse1_id = $1, updated_at = now() WHERE spouse1_id = $2
+`
+
+type RepointMarriageSpouse1Params struct {
+	SurvivorID  uuid.UUID `json:"survivor_id"`
+	DuplicateID uuid.UUID `json:"duplicate_id"`
+}
+
+func (q *Queries) RepointMarriageSpouse1(ctx context.Context, arg RepointMarriageSpouse1Params) (int64, error) {
+	result, err := q.db.Exec(ctx, repointMarriageSpouse1, arg.SurvivorID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const repointMarriageSpouse2 = `-- name: RepointMarriageSpouse2 :execrows
+UPDATE marriage SET spouse2_id = $1, updated_at = now() WHERE spouse2_id = $2
+`
+
+type RepointMarriageSpouse2Params struct {
+	SurvivorID  uuid.UUID `json:"survivor_id"`
+	DuplicateID uuid.UUID `json:"duplicate_id"`
+}
+
+func (q *Queries) RepointMarriageSpouse2(ctx context.Context, arg RepointMarriageSpouse2Params) (int64, error) {
+	result, err := q.db.Exec(ctx, repointMarriageSpouse2, arg.SurvivorID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const repointPersonAddresses = `-- name: RepointPersonAddresses :execrows
+UPDATE person_address SET person_id = $1 WHERE person_id = $2
+`
+
+type RepointPersonAddressesParams struct {
+	SurvivorID  uuid.UUID `json:"survivor_id"`
+	DuplicateID uuid.UUID `json:"duplicate_id"`
+}
+
+func (q *Queries) RepointPersonAddresses(ctx context.Context, arg RepointPersonAddressesParams) (int64, error) {
+	result, err := q.db.Exec(ctx, repointPersonAddresses, arg.SurvivorID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const repointResidenceDeclarations = `-- name: RepointResidenceDeclarations :execrows
+UPDATE residence_declaration SET person_id = $1 WHERE person_id = $2
+`
+
+type RepointResidenceDeclarationsParams struct {
+	SurvivorID  uuid.UUID `json:"survivor_id"`
+	DuplicateID uuid.UUID `json:"duplicate_id"`
+}
+
+func (q *Queries) RepointResidenceDeclarations(ctx context.Context, arg RepointResidenceDeclarationsParams) (int64, error) {
+	result, err := q.db.Exec(ctx, repointResidenceDeclarations, arg.SurvivorID, arg.DuplicateID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -5,11 +5,363 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type Address struct {
+	ID           uuid.UUID `json:"id"`
+	Municipality string    `json:"municipality"`
+	Street       string    `json:"street"`
+	House        string    `json:"house"`
+	Flat         string    `json:"flat"`
+	PostalCode   string    `json:"postal_code"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type Adoption struct {
+	ID                 uuid.UUID   `json:"id"`
+	PersonID           uuid.UUID   `json:"person_id"`
+	AdoptiveMotherID   pgtype.UUID `json:"adoptive_mother_id"`
+	AdoptiveFatherID   pgtype.UUID `json:"adoptive_father_id"`
+	Court              string      `json:"court"`
+	DecisionNumber     string      `json:"decision_number"`
+	DecidedOn          pgtype.Date `json:"decided_on"`
+	Status             string      `json:"status"`
+	RevokedOn          pgtype.Date `json:"revoked_on"`
+	RegistrationOffice string      `json:"registration_office"`
+	Registrar          string      `json:"registrar"`
+	OfficeID           pgtype.UUID `json:"office_id"`
+	RegistrarID        pgtype.UUID `json:"registrar_id"`
+	CreatedAt          time.Time   `json:"created_at"`
+}
+
+type Application struct {
+	ID             uuid.UUID          `json:"id"`
+	Number         string             `json:"number"`
+	Kind           string             `json:"kind"`
+	Status         string             `json:"status"`
+	OfficeID       uuid.UUID          `json:"office_id"`
+	ApplicantName  string             `json:"applicant_name"`
+	ApplicantEmail string             `json:"applicant_email"`
+	ApplicantPhone string             `json:"applicant_phone"`
+	PersonalCode   string             `json:"personal_code"`
+	Details        []byte             `json:"details"`
+	AssignedTo     pgtype.UUID        `json:"assigned_to"`
+	SubmittedAt    time.Time          `json:"submitted_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+	DecidedAt      pgtype.Timestamptz `json:"decided_at"`
+}
+
+type ApplicationStatusHistory struct {
+	ID            uuid.UUID   `json:"id"`
+	ApplicationID uuid.UUID   `json:"application_id"`
+	FromStatus    pgtype.Text `json:"from_status"`
+	ToStatus      string      `json:"to_status"`
+	Note          string      `json:"note"`
+	ChangedBy     string      `json:"changed_by"`
+	ChangedAt     time.Time   `json:"changed_at"`
+}
+
+type Appointment struct {
+	ID           uuid.UUID          `json:"id"`
+	SlotID       uuid.UUID          `json:"slot_id"`
+	Reference    string             `json:"reference"`
+	FullName     string             `json:"full_name"`
+	Email        string             `json:"email"`
+	Phone        string             `json:"phone"`
+	PersonalCode pgtype.Text        `json:"personal_code"`
+	Notes        string             `json:"notes"`
+	Status       string             `json:"status"`
+	StartsAt     time.Time          `json:"starts_at"`
+	EndsAt       time.Time          `json:"ends_at"`
+	BookedAt     time.Time          `json:"booked_at"`
+	CancelledAt  pgtype.Timestamptz `json:"cancelled_at"`
+	CancelledBy  pgtype.Text        `json:"cancelled_by"`
+}
+
+type AppointmentReminder struct {
+	ID            uuid.UUID          `json:"id"`
+	AppointmentID uuid.UUID          `json:"appointment_id"`
+	CreatedAt     time.Time          `json:"created_at"`
+	SentAt        pgtype.Timestamptz `json:"sent_at"`
+}
+
+type AppointmentSlot struct {
+	ID        uuid.UUID `json:"id"`
+	OfficeID  uuid.UUID `json:"office_id"`
+	Kind      string    `json:"kind"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Capacity  int32     `json:"capacity"`
+	Booked    int32     `json:"booked"`
+	CreatedBy uuid.UUID `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Attachment struct {
+	ID          uuid.UUID `json:"id"`
+	OwnerKind   string    `json:"owner_kind"`
+	OwnerID     uuid.UUID `json:"owner_id"`
+	OfficeID    uuid.UUID `json:"office_id"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"content_type"`
+	SizeBytes   int64     `json:"size_bytes"`
+	Sha256      string    `json:"sha256"`
+	UploadedBy  string    `json:"uploaded_by"`
+	UploadedAt  time.Time `json:"uploaded_at"`
+}
+
+type BirthRecord struct {
+	ID                 uuid.UUID   `json:"id"`
+	PersonID           uuid.UUID   `json:"person_id"`
+	MotherID           pgtype.UUID `json:"mother_id"`
+	FatherID           pgtype.UUID `json:"father_id"`
+	BirthPlace         string      `json:"birth_place"`
+	RegistrationOffice string      `json:"registration_office"`
+	Registrar          string      `json:"registrar"`
+	RegisteredAt       time.Time   `json:"registered_at"`
+	OfficeID           pgtype.UUID `json:"office_id"`
+	RegistrarID        pgtype.UUID `json:"registrar_id"`
+	RegistryNumber     pgtype.Text `json:"registry_number"`
+	ForeignDocumentID  pgtype.UUID `json:"foreign_document_id"`
+}
+
+type Certificate struct {
+	ID                  uuid.UUID          `json:"id"`
+	SerialNumber        string             `json:"serial_number"`
+	Kind                string             `json:"kind"`
+	RecordID            uuid.UUID          `json:"record_id"`
+	PersonID            uuid.UUID          `json:"person_id"`
+	Content             []byte             `json:"content"`
+	IssuedOn            pgtype.Date        `json:"issued_on"`
+	IssuedBy            string             `json:"issued_by"`
+	CreatedAt           time.Time          `json:"created_at"`
+	RevokedAt           pgtype.Timestamptz `json:"revoked_at"`
+	RevokedBy           pgtype.Text        `json:"revoked_by"`
+	RevocationReason    pgtype.Text        `json:"revocation_reason"`
+	RevokingRegistrarID pgtype.UUID        `json:"revoking_registrar_id"`
+	OfficeID            pgtype.UUID        `json:"office_id"`
+	RegistrarID         pgtype.UUID        `json:"registrar_id"`
+}
+
+type DataSubjectRequest struct {
+	ID             uuid.UUID   `json:"id"`
+	PersonID       uuid.UUID   `json:"person_id"`
+	Kind           string      `json:"kind"`
+	Reason         string      `json:"reason"`
+	Outcome        []byte      `json:"outcome"`
+	ManifestSha256 pgtype.Text `json:"manifest_sha256"`
+	Signature      pgtype.Text `json:"signature"`
+	HandledBy      string      `json:"handled_by"`
+	OfficeID       pgtype.UUID `json:"office_id"`
+	RegistrarID    pgtype.UUID `json:"registrar_id"`
+	CreatedAt      time.Time   `json:"created_at"`
+}
+
+type DeathRecord struct {
+	ID                 uuid.UUID   `json:"id"`
+	PersonID           uuid.UUID   `json:"person_id"`
+	DateOfDeath        pgtype.Date `json:"date_of_death"`
+	PlaceOfDeath       string      `json:"place_of_death"`
+	CauseCode          string      `json:"cause_code"`
+	InformantName      string      `json:"informant_name"`
+	InformantPersonID  pgtype.UUID `json:"informant_person_id"`
+	RegistrationOffice string      `json:"registration_office"`
+	Registrar          string      `json:"registrar"`
+	RegisteredAt       time.Time   `json:"registered_at"`
+	OfficeID           pgtype.UUID `json:"office_id"`
+	RegistrarID        pgtype.UUID `json:"registrar_id"`
+	RegistryNumber     pgtype.Text `json:"registry_number"`
+	ForeignDocumentID  pgtype.UUID `json:"foreign_document_id"`
+}
+
+type ForeignDocument struct {
+	ID                    uuid.UUID   `json:"id"`
+	Country               string      `json:"country"`
+	IssuingAuthority      string      `json:"issuing_authority"`
+	DocumentNumber        string      `json:"document_number"`
+	IssuedOn              pgtype.Date `json:"issued_on"`
+	DocumentLanguage      string      `json:"document_language"`
+	Legalisation          string      `json:"legalisation"`
+	LegalisationAuthority pgtype.Text `json:"legalisation_authority"`
+	LegalisationNumber    pgtype.Text `json:"legalisation_number"`
+	LegalisedOn           pgtype.Date `json:"legalised_on"`
+	Translator            pgtype.Text `json:"translator"`
+	TranslatedOn          pgtype.Date `json:"translated_on"`
+	CreatedAt             time.Time   `json:"created_at"`
+}
+
+type Guardianship struct {
+	ID                 uuid.UUID   `json:"id"`
+	WardID             uuid.UUID   `json:"ward_id"`
+	GuardianID         uuid.UUID   `json:"guardian_id"`
+	Kind               string      `json:"kind"`
+	Court              string      `json:"court"`
+	DecisionNumber     string      `json:"decision_number"`
+	DecidedOn          pgtype.Date `json:"decided_on"`
+	StartDate          pgtype.Date `json:"start_date"`
+	EndDate            pgtype.Date `json:"end_date"`
+	RegistrationOffice string      `json:"registration_office"`
+	Registrar          string      `json:"registrar"`
+	OfficeID           pgtype.UUID `json:"office_id"`
+	RegistrarID        pgtype.UUID `json:"registrar_id"`
+	CreatedAt          time.Time   `json:"created_at"`
+}
+
+type LegalParentage struct {
+	PersonID   uuid.UUID   `json:"person_id"`
+	MotherID   pgtype.UUID `json:"mother_id"`
+	FatherID   pgtype.UUID `json:"father_id"`
+	AdoptionID pgtype.UUID `json:"adoption_id"`
+}
+
+type Marriage struct {
+	ID                    uuid.UUID   `json:"id"`
+	Spouse1ID             uuid.UUID   `json:"spouse1_id"`
+	Spouse2ID             uuid.UUID   `json:"spouse2_id"`
+	RegisteredOn          pgtype.Date `json:"registered_on"`
+	RegistrationOffice    string      `json:"registration_office"`
+	Registrar             string      `json:"registrar"`
+	Spouse1PreviousName   string      `json:"spouse1_previous_name"`
+	Spouse2PreviousName   string      `json:"spouse2_previous_name"`
+	Spouse1PreviousStatus string      `json:"spouse1_previous_status"`
+	Spouse2PreviousStatus string      `json:"spouse2_previous_status"`
+	Spouse1NewName        pgtype.Text `json:"spouse1_new_name"`
+	Spouse2NewName        pgtype.Text `json:"spouse2_new_name"`
+	Status                string      `json:"status"`
+	EndedOn               pgtype.Date `json:"ended_on"`
+	CreatedAt             time.Time   `json:"created_at"`
+	UpdatedAt             time.Time   `json:"updated_at"`
+	OfficeID              pgtype.UUID `json:"office_id"`
+	RegistrarID           pgtype.UUID `json:"registrar_id"`
+	RegistryNumber        pgtype.Text `json:"registry_number"`
+	ForeignDocumentID     pgtype.UUID `json:"foreign_document_id"`
+}
+
+type Office struct {
+	ID           uuid.UUID `json:"id"`
+	Code         string    `json:"code"`
+	Name         string    `json:"name"`
+	Municipality string    `json:"municipality"`
+	Active       bool      `json:"active"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+type OfficeJurisdiction struct {
+	ID        uuid.UUID   `json:"id"`
+	OfficeID  uuid.UUID   `json:"office_id"`
+	EventKind string      `json:"event_kind"`
+	Place     pgtype.Text `json:"place"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+type Person struct {
+	ID                     uuid.UUID          `json:"id"`
+	PersonalCode           string             `json:"personal_code"`
+	FirstName              string             `json:"first_name"`
+	LastName               string             `json:"last_name"`
+	BirthDate              pgtype.Date        `json:"birth_date"`
+	BirthPlace             pgtype.Text        `json:"birth_place"`
+	Sex                    string             `json:"sex"`
+	Citizenship            string             `json:"citizenship"`
+	Status                 string             `json:"status"`
+	Version                int64              `json:"version"`
+	CreatedAt              time.Time          `json:"created_at"`
+	UpdatedAt              time.Time          `json:"updated_at"`
+	MaritalStatus          string             `json:"marital_status"`
+	MergedInto             pgtype.UUID        `json:"merged_into"`
+	ProcessingRestrictedAt pgtype.Timestamptz `json:"processing_restricted_at"`
+	OfficeID               pgtype.UUID        `json:"office_id"`
+	RegistrarID            pgtype.UUID        `json:"registrar_id"`
+}
+
+type PersonAddress struct {
+	ID          uuid.UUID   `json:"id"`
+	PersonID    uuid.UUID   `json:"person_id"`
+	Kind        string      `json:"kind"`
+	Line        string      `json:"line"`
+	City        string      `json:"city"`
+	PostalCode  string      `json:"postal_code"`
+	Country     string      `json:"country"`
+	CreatedAt   time.Time   `json:"created_at"`
+	OfficeID    pgtype.UUID `json:"office_id"`
+	RegistrarID pgtype.UUID `json:"registrar_id"`
+}
+
+type PersonHistory struct {
+	ID            uuid.UUID   `json:"id"`
+	PersonID      uuid.UUID   `json:"person_id"`
+	Attribute     string      `json:"attribute"`
+	OldValue      pgtype.Text `json:"old_value"`
+	NewValue      pgtype.Text `json:"new_value"`
+	EffectiveDate pgtype.Date `json:"effective_date"`
+	LegalBasis    string      `json:"legal_basis"`
+	PersonVersion int64       `json:"person_version"`
+	RecordedAt    time.Time   `json:"recorded_at"`
+}
+
+type PersonMerge struct {
+	ID                uuid.UUID   `json:"id"`
+	SurvivorID        uuid.UUID   `json:"survivor_id"`
+	DuplicateID       uuid.UUID   `json:"duplicate_id"`
+	MergedBy          string      `json:"merged_by"`
+	Reason            string      `json:"reason"`
+	Moved             []byte      `json:"moved"`
+	DuplicateSnapshot []byte      `json:"duplicate_snapshot"`
+	MergedAt          time.Time   `json:"merged_at"`
+	OfficeID          pgtype.UUID `json:"office_id"`
+	RegistrarID       pgtype.UUID `json:"registrar_id"`
+}
+
+type PersonalCodeSequence struct {
+	BirthDate  pgtype.Date `json:"birth_date"`
+	LastSerial int32       `json:"last_serial"`
+}
+
 type Post struct {
 	ID    uuid.UUID `json:"id"`
 	Title string    `json:"title"`
 	Body  string    `json:"body"`
 }
+
+type Registrar struct {
+	ID        uuid.UUID `json:"id"`
+	OfficeID  uuid.UUID `json:"office_id"`
+	FullName  string    `json:"full_name"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	TokenHash []byte    `json:"token_hash"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type RegistryNumberSequence struct {
+	OfficeID   uuid.UUID `json:"office_id"`
+	RecordKind string    `json:"record_kind"`
+	Year       int32     `json:"year"`
+	LastNumber int32     `json:"last_number"`
+}
+
+type ResidenceDeclaration struct {
+	ID          uuid.UUID   `json:"id"`
+	PersonID    uuid.UUID   `json:"person_id"`
+	AddressID   uuid.UUID   `json:"address_id"`
+	StartDate   pgtype.Date `json:"start_date"`
+	EndDate     pgtype.Date `json:"end_date"`
+	DeclaredBy  string      `json:"declared_by"`
+	DeclaredAt  time.Time   `json:"declared_at"`
+	OfficeID    pgtype.UUID `json:"office_id"`
+	RegistrarID pgtype.UUID `json:"registrar_id"`
+}
+
+type SealedRecordAccess struct {
+	ID          uuid.UUID   `json:"id"`
+	AdoptionID  uuid.UUID   `json:"adoption_id"`
+	RegistrarID pgtype.UUID `json:"registrar_id"`
+	AccessedBy  string      `json:"accessed_by"`
+	Reason      string      `json:"reason"`
+	AccessedAt  time.Time   `json:"accessed_at"`
+}
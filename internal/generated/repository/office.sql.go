@@ -0,0 +1,426 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: office.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createJurisdiction = `-- name: CreateJurisdiction :one
+INSERT INTO office_jurisdiction (office_id, event_kind, place)
+VALUES ($1, $2, $3)
+RETURNING id, office_id, event_kind, place, created_at
+`
+
+type CreateJurisdictionParams struct {
+	OfficeID  uuid.UUID   `json:"office_id"`
+	EventKind string      `json:"event_kind"`
+	Place     pgtype.Text `json:"place"`
+}
+
+func (q *Queries) CreateJurisdiction(ctx context.Context, arg CreateJurisdictionParams) (OfficeJurisdiction, error) {
+	row := q.db.QueryRow(ctx, createJurisdiction, arg.OfficeID, arg.EventKind, arg.Place)
+	var i OfficeJurisdiction
+	err := row.Scan(
+		&i.ID,
+		&i.OfficeID,
+		&i.EventKind,
+		&i.Place,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createOffice = `-- name: CreateOffice :one
+INSERT INTO office (code, name, municipality)
+VALUES ($1, $2, $3)
+RETURNING id, code, name, municipality, active, created_at
+`
+
+type CreateOfficeParams struct {
+	Code         string `json:"code"`
+	Name         string `json:"name"`
+	Municipality string `json:"municipality"`
+}
+
+func (q *Queries) CreateOffice(ctx context.Context, arg CreateOfficeParams) (Office, error) {
+	row := q.db.QueryRow(ctx, createOffice, arg.Code, arg.Name, arg.Municipality)
+	var i Office
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.Municipality,
+		&i.Active,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createRegistrar = `-- name: CreateRegistrar :one
+INSERT INTO registrar (office_id, full_name, username, role, token_hash)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, office_id, full_name, username, role, token_hash, active, created_at
+`
+
+type CreateRegistrarParams struct {
+	OfficeID  uuid.UUID `json:"office_id"`
+	FullName  string    `json:"full_name"`
+	Username  string    `json:"username"`
+	Role      string    `json:"role"`
+	TokenHash []byte    `json:"token_hash"`
+}
+
+func (q *Queries) CreateRegistrar(ctx context.Context, arg CreateRegistrarParams) (Registrar, error) {
+	row := q.db.QueryRow(ctx, createRegistrar,
+		arg.OfficeID,
+		arg.FullName,
+		arg.Username,
+		arg.Role,
+		arg.TokenHash,
+	)
+	var i Registrar
+	err := row.Scan(
+		&i.ID,
+		&i.OfficeID,
+		&i.FullName,
+		&i.Username,
+		&i.Role,
+		&i.TokenHash,
+		&i.Active,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteJurisdiction = `-- name: DeleteJurisdiction :execrows
+DELETE FROM office_jurisdiction
+WHERE id = $1
+  AND office_id = $2
+`
+
+type DeleteJurisdictionParams struct {
+	ID       uuid.UUID `json:"id"`
+	OfficeID uuid.UUID `json:"office_id"`
+}
+
+func (q *Queries) DeleteJurisdiction(ctx context.Context, arg DeleteJurisdictionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteJurisdiction, arg.ID, arg.OfficeID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const getActiveRegistrarByTokenHash = `-- name: GetActiveRegistrarByTokenHash :one
+SELECT r.id, r.office_id, r.full_name, r.username, r.role, r.token_hash, r.active, r.created_at, o.id, o.code, o.name, o.municipality, o.active, o.created_at
+FROM registrar r
+         JOIN office o ON o.id = r.office_id
+WHERE r.token_hash = $1
+  AND r.active
+  AND o.active
+`
+
+type GetActiveRegistrarByTokenHashRow struct {
+	Registrar Registrar `json:"registrar"`
+	Office    Office    `json:"office"`
+}
+
+// The registrar signing in with the token, with their office. Inactive
+// registrars and registrars of closed offices cannot sign in.
+func (q *Queries) GetActiveRegistrarByTokenHash(ctx context.Context, tokenHash []byte) (GetActiveRegistrarByTokenHashRow, error) {
+	row := q.db.QueryRow(ctx, getActiveRegistrarByTokenHash, tokenHash)
+	var i GetActiveRegistrarByTokenHashRow
+	err := row.Scan(
+		&i.Registrar.ID,
+		&i.Registrar.OfficeID,
+		&i.Registrar.FullName,
+		&i.Registrar.Username,
+		&i.Registrar.Role,
+		&i.Registrar.TokenHash,
+		&i.Registrar.Active,
+		&i.Registrar.CreatedAt,
+		&i.Office.ID,
+		&i.Office.Code,
+		&i.Office.Name,
+		&i.Office.Municipality,
+		&i.Office.Active,
+		&i.Office.CreatedAt,
+	)
+	return i, err
+}
+
+const getOfficeByID = `-- name: GetOfficeByID :one
+SELECT id, code, name, municipality, active, created_at FROM office
+WHERE id = $1
+`
+
+func (q *Queries) GetOfficeByID(ctx context.Context, id uuid.UUID) (Office, error) {
+	row := q.db.QueryRow(ctx, getOfficeByID, id)
+	var i Office
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.Municipality,
+		&i.Active,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getRegistrarByID = `-- name: GetRegistrarByID :one
+SELECT id, office_id, full_name, username, role, token_hash, active, created_at FROM registrar
+WHERE id = $1
+`
+
+func (q *Queries) GetRegistrarByID(ctx context.Context, id uuid.UUID) (Registrar, error) {
+	row := q.db.QueryRow(ctx, getRegistrarByID, id)
+	var i Registrar
+	err := row.Scan(
+		&i.ID,
+		&i.OfficeID,
+		&i.FullName,
+		&i.Username,
+		&i.Role,
+		&i.TokenHash,
+		&i.Active,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const listJurisdictionsForOffice = `-- name: ListJurisdictionsForOffice :many
+SELECT id, office_id, event_kind, place, created_at FROM office_jurisdiction
+WHERE office_id = $1
+ORDER BY event_kind, place NULLS FIRST
+`
+
+func (q *Queries) ListJurisdictionsForOffice(ctx context.Context, officeID uuid.UUID) ([]OfficeJurisdiction, error) {
+	rows, err := q.db.Query(ctx, listJurisdictionsForOffice, officeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []OfficeJurisdiction
+	for rows.Next() {
+		var i OfficeJurisdiction
+		if err := rows.Scan(
+			&i.ID,
+			&i.OfficeID,
+			&i.EventKind,
+			&i.Place,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOffices = `-- name: ListOffices :many
+SELECT id, code, name, municipality, active, created_at FROM office
+ORDER BY code
+`
+
+func (q *Queries) ListOffices(ctx context.Context) ([]Office, error) {
+	rows, err := q.db.Query(ctx, listOffices)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Office
+	for rows.Next() {
+		var i Office
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Name,
+			&i.Municipality,
+			&i.Active,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRegistrarsForOffice = `-- name: ListRegistrarsForOffice :many
+SELECT id, office_id, full_name, username, role, token_hash, active, created_at FROM registrar
+WHERE office_id = $1
+ORDER BY full_name, id
+`
+
+func (q *Queries) ListRegistrarsForOffice(ctx context.Context, officeID uuid.UUID) ([]Registrar, error) {
+	rows, err := q.db.Query(ctx, listRegistrarsForOffice, officeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Registrar
+	for rows.Next() {
+		var i Registrar
+		if err := rows.Scan(
+			&i.ID,
+			&i.OfficeID,
+			&i.FullName,
+			&i.Username,
+			&i.Role,
+			&i.TokenHash,
+			&i.Active,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const nextRegistryNumber = `-- name: NextRegistryNumber :one
+INSERT INTO registry_number_sequence (office_id, record_kind, year, last_number)
+VALUES ($1, $2, $3, 1)
+ON CONFLICT (office_id, record_kind, year) DO UPDATE
+    SET last_number = registry_number_sequence.last_number + 1
+RETURNING last_number
+`
+
+type NextRegistryNumberParams struct {
+	OfficeID   uuid.UUID `json:"office_id"`
+	RecordKind string    `json:"record_kind"`
+	Year       int32     `json:"year"`
+}
+
+// Numbers start at 1 each year. The caller rejects numbers past 999999,
+// which rolls the bump back.
+func (q *Queries) NextRegistryNumber(ctx context.Context, arg NextRegistryNumberParams) (int32, error) {
+	row := q.db.QueryRow(ctx, nextRegistryNumber, arg.OfficeID, arg.RecordKind, arg.Year)
+	var last_number int32
+	err := row.Scan(&last_number)
+	return last_number, err
+}
+
+const officeHasJurisdiction = `-- name: OfficeHasJurisdiction :one
+SELECT EXISTS (SELECT 1
+               FROM office_jurisdiction
+               WHERE office_id = $1
+                 AND event_kind = $2
+                 AND (place IS NULL
+                   OR $3::text IS NULL
+                   OR fold_name(place) = fold_name($3))) AS allowed
+`
+
+type OfficeHasJurisdictionParams struct {
+	OfficeID  uuid.UUID   `json:"office_id"`
+	EventKind string      `json:"event_kind"`
+	Place     pgtype.Text `json:"place"`
+}
+
+// Whether the office may register the kind of event at the place. A NULL
+// place (marriages, which take place at the office) matches any rule.
+func (q *Queries) OfficeHasJurisdiction(ctx context.Context, arg OfficeHasJurisdictionParams) (bool, error) {
+	row := q.db.QueryRow(ctx, officeHasJurisdiction, arg.OfficeID, arg.EventKind, arg.Place)
+	var allowed bool
+	err := row.Scan(&allowed)
+	return allowed, err
+}
+
+const setOfficeActive = `-- name: SetOfficeActive :one
+UPDATE office
+SET active = $1
+WHERE id = $2
+RETURNING id, code, name, municipality, active, created_at
+`
+
+type SetOfficeActiveParams struct {
+	Active bool      `json:"active"`
+	ID     uuid.UUID `json:"id"`
+}
+
+func (q *Queries) SetOfficeActive(ctx context.Context, arg SetOfficeActiveParams) (Office, error) {
+	row := q.db.QueryRow(ctx, setOfficeActive, arg.Active, arg.ID)
+	var i Office
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.Municipality,
+		&i.Active,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const setRegistrarActive = `-- name: SetRegistrarActive :one
+UPDATE registrar
+SET active = $1
+WHERE id = $2
+RETURNING id, office_id, full_name, username, role, token_hash, active, created_at
+`
+
+type SetRegistrarActiveParams struct {
+	Active bool      `json:"active"`
+	ID     uuid.UUID `json:"id"`
+}
+
+func (q *Queries) SetRegistrarActive(ctx context.Context, arg SetRegistrarActiveParams) (Registrar, error) {
+	row := q.db.QueryRow(ctx, setRegistrarActive, arg.Active, arg.ID)
+	var i Registrar
+	err := row.Scan(
+		&i.ID,
+		&i.OfficeID,
+		&i.FullName,
+		&i.Username,
+		&i.Role,
+		&i.TokenHash,
+		&i.Active,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const setRegistrarToken = `-- name: SetRegistrarToken :one
+UPDATE registrar
+SET token_hash = $1
+WHERE id = $2
+RETURNING id, office_id, full_name, username, role, token_hash, active, created_at
+`
+
+type SetRegistrarTokenParams struct {
+	TokenHash []byte    `json:"token_hash"`
+	ID        uuid.UUID `json:"id"`
+}
+
+func (q *Queries) SetRegistrarToken(ctx context.Context, arg SetRegistrarTokenParams) (Registrar, error) {
+	row := q.db.QueryRow(ctx, setRegistrarToken, arg.TokenHash, arg.ID)
+	var i Registrar
+	err := row.Scan(
+		&i.ID,
+		&i.OfficeID,
+		&i.FullName,
+		&i.Username,
+		&i.Role,
+		&i.TokenHash,
+		&i.Active,
+		&i.CreatedAt,
+	)
+	return i, err
+}
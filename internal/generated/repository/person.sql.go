@@ -0,0 +1,517 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: person.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const addPersonAddress = `-- name: AddPersonAddress :one
+INSERT INTO person_address (person_id, kind, line, city, postal_code, country, office_id, registrar_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING id, person_id, kind, line, city, postal_code, country, created_at, office_id, registrar_id
+`
+
+type AddPersonAddressParams struct {
+	PersonID    uuid.UUID   `json:"person_id"`
+	Kind        string      `json:"kind"`
+	Line        string      `json:"line"`
+	City        string      `json:"city"`
+	PostalCode  string      `json:"postal_code"`
+	Country     string      `json:"country"`
+	OfficeID    pgtype.UUID `json:"office_id"`
+	RegistrarID pgtype.UUID `json:"registrar_id"`
+}
+
+func (q *Queries) AddPersonAddress(ctx context.Context, arg AddPersonAddressParams) (PersonAddress, error) {
+	row := q.db.QueryRow(ctx, addPersonAddress,
+		arg.PersonID,
+		arg.Kind,
+		arg.Line,
+		arg.City,
+		arg.PostalCode,
+		arg.Country,
+		arg.OfficeID,
+		arg.RegistrarID,
+	)
+	var i PersonAddress
+	err := row.Scan(
+		&i.ID,
+		&i.PersonID,
+		&i.Kind,
+		&i.Line,
+		&i.City,
+		&i.PostalCode,
+		&i.Country,
+		&i.CreatedAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+	)
+	return i, err
+}
+
+const createPerson = `-- name: CreatePerson :one
+INSERT INTO person (personal_code, first_name, last_name, birth_date, birth_place, sex, citizenship, office_id,
+                    registrar_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, personal_code, first_name, last_name, birth_date, birth_place, sex, citizenship, status, version, created_at, updated_at, marital_status, merged_into, processing_restricted_at, office_id, registrar_id
+`
+
+type CreatePersonParams struct {
+	PersonalCode string      `json:"personal_code"`
+	FirstName    string      `json:"first_name"`
+	LastName     string      `json:"last_name"`
+	BirthDate    pgtype.Date `json:"birth_date"`
+	BirthPlace   pgtype.Text `json:"birth_place"`
+	Sex          string      `json:"sex"`
+	Citizenship  string      `json:"citizenship"`
+	OfficeID     pgtype.UUID `json:"office_id"`
+	RegistrarID  pgtype.UUID `json:"registrar_id"`
+}
+
+func (q *Queries) CreatePerson(ctx context.Context, arg CreatePersonParams) (Person, error) {
+	row := q.db.QueryRow(ctx, createPerson,
+		arg.PersonalCode,
+		arg.FirstName,
+		arg.LastName,
+		arg.BirthDate,
+		arg.BirthPlace,
+		arg.Sex,
+		arg.Citizenship,
+		arg.OfficeID,
+		arg.RegistrarID,
+	)
+	var i Person
+	err := row.Scan(
+		&i.ID,
+		&i.PersonalCode,
+		&i.FirstName,
+		&i.LastName,
+		&i.BirthDate,
+		&i.BirthPlace,
+		&i.Sex,
+		&i.Citizenship,
+		&i.Status,
+		&i.Version,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MaritalStatus,
+		&i.MergedInto,
+		&i.ProcessingRestrictedAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+	)
+	return i, err
+}
+
+const createPersonHistory = `-- name: CreatePersonHistory :exec
+INSERT INTO person_history (person_id, attribute, old_value, new_value, effective_date, legal_basis, person_version)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+
+type CreatePersonHistoryParams struct {
+	PersonID      uuid.UUID   `json:"person_id"`
+	Attribute     string      `json:"attribute"`
+	OldValue      pgtype.Text `json:"old_value"`
+	NewValue      pgtype.Text `json:"new_value"`
+	EffectiveDate pgtype.Date `json:"effective_date"`
+	LegalBasis    string      `json:"legal_basis"`
+	PersonVersion int64       `json:"person_version"`
+}
+
+func (q *Queries) CreatePersonHistory(ctx context.Context, arg CreatePersonHistoryParams) error {
+	_, err := q.db.Exec(ctx, createPersonHistory,
+		arg.PersonID,
+		arg.Attribute,
+		arg.OldValue,
+		arg.NewValue,
+		arg.EffectiveDate,
+		arg.LegalBasis,
+		arg.PersonVersion,
+	)
+	return err
+}
+
+const getPersonByID = `-- name: GetPersonByID :one
+SELECT id, personal_code, first_name, last_name, birth_date, birth_place, sex, citizenship, status, version, created_at, updated_at, marital_status, merged_into, processing_restricted_at, office_id, registrar_id FROM person
+WHERE id = $1
+`
+
+func (q *Queries) GetPersonByID(ctx context.Context, id uuid.UUID) (Person, error) {
+	row := q.db.QueryRow(ctx, getPersonByID, id)
+	var i Person
+	err := row.Scan(
+		&i.ID,
+		&i.PersonalCode,
+		&i.FirstName,
+		&i.LastName,
+		&i.BirthDate,
+		&i.BirthPlace,
+		&i.Sex,
+		&i.Citizenship,
+		&i.Status,
+		&i.Version,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MaritalStatus,
+		&i.MergedInto,
+		&i.ProcessingRestrictedAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+	)
+	return i, err
+}
+
+const getPersonByPersonalCode = `-- name: GetPersonByPersonalCode :one
+SELECT id, personal_code, first_name, last_name, birth_date, birth_place, sex, citizenship, status, version, created_at, updated_at, marital_status, merged_into, processing_restricted_at, office_id, registrar_id FROM person
+WHERE personal_code = $1
+`
+
+func (q *Queries) GetPersonByPersonalCode(ctx context.Context, personalCode string) (Person, error) {
+	row := q.db.QueryRow(ctx, getPersonByPersonalCode, personalCode)
+	var i Person
+	err := row.Scan(
+		&i.ID,
+		&i.PersonalCode,
+		&i.FirstName,
+		&i.LastName,
+		&i.BirthDate,
+		&i.BirthPlace,
+		&i.Sex,
+		&i.Citizenship,
+		&i.Status,
+		&i.Version,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MaritalStatus,
+		&i.MergedInto,
+		&i.ProcessingRestrictedAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+	)
+	return i, err
+}
+
+const getPersonForUpdate = `-- name: GetPersonForUpdate :one
+SELECT id, personal_code, first_name, last_name, birth_date, birth_place, sex, citizenship, status, version, created_at, updated_at, marital_status, merged_into, processing_restricted_at, office_id, registrar_id FROM person
+WHERE id = $1
+FOR UPDATE
+`
+
+// Locks the person row for the rest of the transaction.
+func (q *Queries) GetPersonForUpdate(ctx context.Context, id uuid.UUID) (Person, error) {
+	row := q.db.QueryRow(ctx, getPersonForUpdate, id)
+	var i Person
+	err := row.Scan(
+		&i.ID,
+		&i.PersonalCode,
+		&i.FirstName,
+		&i.LastName,
+		&i.BirthDate,
+		&i.BirthPlace,
+		&i.Sex,
+		&i.Citizenship,
+		&i.Status,
+		&i.Version,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MaritalStatus,
+		&i.MergedInto,
+		&i.ProcessingRestrictedAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+	)
+	return i, err
+}
+
+const listPersonAddresses = `-- name: ListPersonAddresses :many
+SELECT id, person_id, kind, line, city, postal_code, country, created_at, office_id, registrar_id FROM person_address
+WHERE person_id = $1
+ORDER BY created_at
+`
+
+func (q *Queries) ListPersonAddresses(ctx context.Context, personID uuid.UUID) ([]PersonAddress, error) {
+	rows, err := q.db.Query(ctx, listPersonAddresses, personID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PersonAddress
+	for rows.Next() {
+		var i PersonAddress
+		if err := rows.Scan(
+			&i.ID,
+			&i.PersonID,
+			&i.Kind,
+			&i.Line,
+			&i.City,
+			&i.PostalCode,
+			&i.Country,
+			&i.CreatedAt,
+			&i.OfficeID,
+			&i.RegistrarID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPersonHistory = `-- name: ListPersonHistory :many
+SELECT id, person_id, attribute, old_value, new_value, effective_date, legal_basis, person_version, recorded_at FROM person_history
+WHERE person_id = $1
+ORDER BY effective_date, recorded_at, id
+`
+
+// Oldest first; AsOf in the person service relies on this order.
+func (q *Queries) ListPersonHistory(ctx context.Context, personID uuid.UUID) ([]PersonHistory, error) {
+	rows, err := q.db.Query(ctx, listPersonHistory, personID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PersonHistory
+	for rows.Next() {
+		var i PersonHistory
+		if err := rows.Scan(
+			&i.ID,
+			&i.PersonID,
+			&i.Attribute,
+			&i.OldValue,
+			&i.NewValue,
+			&i.EffectiveDate,
+			&i.LegalBasis,
+			&i.PersonVersion,
+			&i.RecordedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const nextPersonalCodeSerial = `-- name: NextPersonalCodeSerial :one
+INSERT INTO personal_code_sequence (birth_date, last_serial)
+VALUES ($1, 1)
+ON CONFLICT (birth_date) DO UPDATE
+    SET last_serial = personal_code_sequence.last_serial + 1
+RETURNING last_serial
+`
+
+// Serials start at 1. The caller rejects serials past 999, which rolls the bump back.
+func (q *Queries) NextPersonalCodeSerial(ctx context.Context, birthDate pgtype.Date) (int32, error) {
+	row := q.db.QueryRow(ctx, nextPersonalCodeSerial, birthDate)
+	var last_serial int32
+	err := row.Scan(&last_serial)
+	return last_serial, err
+}
+
+const searchPersons = `-- name: SearchPersons :many
+SELECT id, personal_code, first_name, last_name, birth_date, birth_place, sex, citizenship, status, version, created_at, updated_at, marital_status, merged_into, processing_restricted_at, office_id, registrar_id FROM person
+WHERE ($1::text IS NULL
+        OR first_name ILIKE '%' || $1 || '%'
+        OR last_name ILIKE '%' || $1 || '%')
+  AND ($2::date IS NULL OR birth_date = $2)
+  AND ($3::text IS NULL OR status = $3)
+  AND merged_into IS NULL
+  AND processing_restricted_at IS NULL
+ORDER BY last_name, first_name, id
+LIMIT $5 OFFSET $4
+`
+
+type SearchPersonsParams struct {
+	Name      pgtype.Text `json:"name"`
+	BirthDate pgtype.Date `json:"birth_date"`
+	Status    pgtype.Text `json:"status"`
+	Offset    int32       `json:"offset"`
+	Limit     int32       `json:"limit"`
+}
+
+// Every filter is optional; name matches first or last name case-insensitively.
+// Persons merged into another record, or who restricted processing of their
+// data, are left out.
+func (q *Queries) SearchPersons(ctx context.Context, arg SearchPersonsParams) ([]Person, error) {
+	rows, err := q.db.Query(ctx, searchPersons,
+		arg.Name,
+		arg.BirthDate,
+		arg.Status,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Person
+	for rows.Next() {
+		var i Person
+		if err := rows.Scan(
+			&i.ID,
+			&i.PersonalCode,
+			&i.FirstName,
+			&i.LastName,
+			&i.BirthDate,
+			&i.BirthPlace,
+			&i.Sex,
+			&i.Citizenship,
+			&i.Status,
+			&i.Version,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.MaritalStatus,
+			&i.MergedInto,
+			&i.ProcessingRestrictedAt,
+			&i.OfficeID,
+			&i.RegistrarID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setPersonDeceased = `-- name: SetPersonDeceased :one
+UPDATE person
+SET status     = 'deceased',
+    version    = version + 1,
+    updated_at = now()
+WHERE id = $1
+RETURNING id, personal_code, first_name, last_name, birth_date, birth_place, sex, citizenship, status, version, created_at, updated_at, marital_status, merged_into, processing_restricted_at, office_id, registrar_id
+`
+
+func (q *Queries) SetPersonDeceased(ctx context.Context, id uuid.UUID) (Person, error) {
+	row := q.db.QueryRow(ctx, setPersonDeceased, id)
+	var i Person
+	err := row.Scan(
+		&i.ID,
+		&i.PersonalCode,
+		&i.FirstName,
+		&i.LastName,
+		&i.BirthDate,
+		&i.BirthPlace,
+		&i.Sex,
+		&i.Citizenship,
+		&i.Status,
+		&i.Version,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MaritalStatus,
+		&i.MergedInto,
+		&i.ProcessingRestrictedAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+	)
+	return i, err
+}
+
+const setPersonMaritalStatus = `-- name: SetPersonMaritalStatus :one
+UPDATE person
+SET marital_status = $2,
+    last_name      = $3,
+    version        = version + 1,
+    updated_at     = now()
+WHERE id = $1
+RETURNING id, personal_code, first_name, last_name, birth_date, birth_place, sex, citizenship, status, version, created_at, updated_at, marital_status, merged_into, processing_restricted_at, office_id, registrar_id
+`
+
+type SetPersonMaritalStatusParams struct {
+	ID            uuid.UUID `json:"id"`
+	MaritalStatus string    `json:"marital_status"`
+	LastName      string    `json:"last_name"`
+}
+
+func (q *Queries) SetPersonMaritalStatus(ctx context.Context, arg SetPersonMaritalStatusParams) (Person, error) {
+	row := q.db.QueryRow(ctx, setPersonMaritalStatus, arg.ID, arg.MaritalStatus, arg.LastName)
+	var i Person
+	err := row.Scan(
+		&i.ID,
+		&i.PersonalCode,
+		&i.FirstName,
+		&i.LastName,
+		&i.BirthDate,
+		&i.BirthPlace,
+		&i.Sex,
+		&i.Citizenship,
+		&i.Status,
+		&i.Version,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MaritalStatus,
+		&i.MergedInto,
+		&i.ProcessingRestrictedAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+	)
+	return i, err
+}
+
+const updatePerson = `-- name: UpdatePerson :one
+UPDATE person
+SET first_name  = $2,
+    last_name   = $3,
+    birth_place = $4,
+    citizenship = $5,
+    version     = version + 1,
+    updated_at  = now()
+WHERE id = $1
+  AND status = 'alive'
+RETURNING id, personal_code, first_name, last_name, birth_date, birth_place, sex, citizenship, status, version, created_at, updated_at, marital_status, merged_into, processing_restricted_at, office_id, registrar_id
+`
+
+type UpdatePersonParams struct {
+	ID          uuid.UUID   `json:"id"`
+	FirstName   string      `json:"first_name"`
+	LastName    string      `json:"last_name"`
+	BirthPlace  pgtype.Text `json:"birth_place"`
+	Citizenship string      `json:"citizenship"`
+}
+
+// Deceased persons are frozen; no row is returned for them.
+func (q *Queries) UpdatePerson(ctx context.Context, arg UpdatePersonParams) (Person, error) {
+	row := q.db.QueryRow(ctx, updatePerson,
+		arg.ID,
+		arg.FirstName,
+		arg.LastName,
+		arg.BirthPlace,
+		arg.Citizenship,
+	)
+	var i Person
+	err := row.Scan(
+		&i.ID,
+		&i.PersonalCode,
+		&i.FirstName,
+		&i.LastName,
+		&i.BirthDate,
+		&i.BirthPlace,
+		&i.Sex,
+		&i.Citizenship,
+		&i.Status,
+		&i.Version,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MaritalStatus,
+		&i.MergedInto,
+		&i.ProcessingRestrictedAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+	)
+	return i, err
+}
@@ -45,6 +45,7 @@ const getPublicPosts = `-- name: GetPublicPosts :many
 SELECT id, title, body from post
 `
 
+// genapi:stream
 func (q *Queries) GetPublicPosts(ctx context.Context) ([]Post, error) {
 	rows, err := q.db.Query(ctx, getPublicPosts)
 	if err != nil {
@@ -0,0 +1,369 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: privacy.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createDataSubjectRequest = `-- name: CreateDataSubjectRequest :one
+INSERT INTO data_subject_request (person_id, kind, reason, outcome, manifest_sha256, signature, handled_by, office_id,
+                                  registrar_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, person_id, kind, reason, outcome, manifest_sha256, signature, handled_by, office_id, registrar_id, created_at
+`
+
+type CreateDataSubjectRequestParams struct {
+	PersonID       uuid.UUID   `json:"person_id"`
+	Kind           string      `json:"kind"`
+	Reason         string      `json:"reason"`
+	Outcome        []byte      `json:"outcome"`
+	ManifestSha256 pgtype.Text `json:"manifest_sha256"`
+	Signature      pgtype.Text `json:"signature"`
+	HandledBy      string      `json:"handled_by"`
+	OfficeID       pgtype.UUID `json:"office_id"`
+	RegistrarID    pgtype.UUID `json:"registrar_id"`
+}
+
+func (q *Queries) CreateDataSubjectRequest(ctx context.Context, arg CreateDataSubjectRequestParams) (DataSubjectRequest, error) {
+	row := q.db.QueryRow(ctx, createDataSubjectRequest,
+		arg.PersonID,
+		arg.Kind,
+		arg.Reason,
+		arg.Outcome,
+		arg.ManifestSha256,
+		arg.Signature,
+		arg.HandledBy,
+		arg.OfficeID,
+		arg.RegistrarID,
+	)
+	var i DataSubjectRequest
+	err := row.Scan(
+		&i.ID,
+		&i.PersonID,
+		&i.Kind,
+		&i.Reason,
+		&i.Outcome,
+		&i.ManifestSha256,
+		&i.Signature,
+		&i.HandledBy,
+		&i.OfficeID,
+		&i.RegistrarID,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deletePersonAddresses = `-- name: DeletePersonAddresses :execrows
+DELETE FROM person_address
+WHERE person_id = $1
+`
+
+func (q *Queries) DeletePersonAddresses(ctx context.Context, personID uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePersonAddresses, personID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const eraseApplication = `-- name: EraseApplication :exec
+UPDATE application
+SET applicant_name  = '[erased]',
+    applicant_email = '[erased]',
+    applicant_phone = '',
+    personal_code   = '00000000000',
+    details         = '{}',
+    updated_at      = now()
+WHERE id = $1
+`
+
+// Keeps the application's number, kind, office and status history dates for
+// the statistics; everything identifying the applicant goes. The personal
+// code is required, so a code of zeros stands in for it.
+func (q *Queries) EraseApplication(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, eraseApplication, id)
+	return err
+}
+
+const eraseApplicationStatusNotes = `-- name: EraseApplicationStatusNotes :exec
+UPDATE application_status_history
+SET note = ''
+WHERE application_id = $1
+`
+
+func (q *Queries) EraseApplicationStatusNotes(ctx context.Context, applicationID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, eraseApplicationStatusNotes, applicationID)
+	return err
+}
+
+const eraseAppointment = `-- name: EraseAppointment :exec
+UPDATE appointment
+SET full_name     = '[erased]',
+    email         = 'erased-' || id || '@invalid',
+    phone         = '',
+    personal_code = NULL,
+    notes         = ''
+WHERE id = $1
+`
+
+// The email stays unique per appointment so that erased bookings never
+// trip the overlap constraint on email.
+func (q *Queries) EraseAppointment(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, eraseAppointment, id)
+	return err
+}
+
+const listApplicationsByPersonalCode = `-- name: ListApplicationsByPersonalCode :many
+SELECT id, number, kind, status, office_id, applicant_name, applicant_email, applicant_phone, personal_code, details, assigned_to, submitted_at, updated_at, decided_at FROM application
+WHERE personal_code = $1
+ORDER BY submitted_at, id
+`
+
+func (q *Queries) ListApplicationsByPersonalCode(ctx context.Context, personalCode string) ([]Application, error) {
+	rows, err := q.db.Query(ctx, listApplicationsByPersonalCode, personalCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Application
+	for rows.Next() {
+		var i Application
+		if err := rows.Scan(
+			&i.ID,
+			&i.Number,
+			&i.Kind,
+			&i.Status,
+			&i.OfficeID,
+			&i.ApplicantName,
+			&i.ApplicantEmail,
+			&i.ApplicantPhone,
+			&i.PersonalCode,
+			&i.Details,
+			&i.AssignedTo,
+			&i.SubmittedAt,
+			&i.UpdatedAt,
+			&i.DecidedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAppointmentsByPersonalCode = `-- name: ListAppointmentsByPersonalCode :many
+SELECT id, slot_id, reference, full_name, email, phone, personal_code, notes, status, starts_at, ends_at, booked_at, cancelled_at, cancelled_by FROM appointment
+WHERE personal_code = $1
+ORDER BY starts_at, id
+`
+
+func (q *Queries) ListAppointmentsByPersonalCode(ctx context.Context, personalCode pgtype.Text) ([]Appointment, error) {
+	rows, err := q.db.Query(ctx, listAppointmentsByPersonalCode, personalCode)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Appointment
+	for rows.Next() {
+		var i Appointment
+		if err := rows.Scan(
+			&i.ID,
+			&i.SlotID,
+			&i.Reference,
+			&i.FullName,
+			&i.Email,
+			&i.Phone,
+			&i.PersonalCode,
+			&i.Notes,
+			&i.Status,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.BookedAt,
+			&i.CancelledAt,
+			&i.CancelledBy,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDataSubjectRequests = `-- name: ListDataSubjectRequests :many
+SELECT id, person_id, kind, reason, outcome, manifest_sha256, signature, handled_by, office_id, registrar_id, created_at FROM data_subject_request
+WHERE person_id = $1
+ORDER BY created_at, id
+`
+
+func (q *Queries) ListDataSubjectRequests(ctx context.Context, personID uuid.UUID) ([]DataSubjectRequest, error) {
+	rows, err := q.db.Query(ctx, listDataSubjectRequests, personID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DataSubjectRequest
+	for rows.Next() {
+		var i DataSubjectRequest
+		if err := rows.Scan(
+			&i.ID,
+			&i.PersonID,
+			&i.Kind,
+			&i.Reason,
+			&i.Outcome,
+			&i.ManifestSha256,
+			&i.Signature,
+			&i.HandledBy,
+			&i.OfficeID,
+			&i.RegistrarID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDeathRecordsByInformant = `-- name: ListDeathRecordsByInformant :many
+SELECT id, person_id, date_of_death, place_of_death, cause_code, informant_name, informant_person_id, registration_office, registrar, registered_at, office_id, registrar_id, registry_number, foreign_document_id FROM death_record
+WHERE informant_person_id = $1
+ORDER BY registered_at, id
+`
+
+func (q *Queries) ListDeathRecordsByInformant(ctx context.Context, informantPersonID pgtype.UUID) ([]DeathRecord, error) {
+	rows, err := q.db.Query(ctx, listDeathRecordsByInformant, informantPersonID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DeathRecord
+	for rows.Next() {
+		var i DeathRecord
+		if err := rows.Scan(
+			&i.ID,
+			&i.PersonID,
+			&i.DateOfDeath,
+			&i.PlaceOfDeath,
+			&i.CauseCode,
+			&i.InformantName,
+			&i.InformantPersonID,
+			&i.RegistrationOffice,
+			&i.Registrar,
+			&i.RegisteredAt,
+			&i.OfficeID,
+			&i.RegistrarID,
+			&i.RegistryNumber,
+			&i.ForeignDocumentID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLegalChildren = `-- name: ListLegalChildren :many
+SELECT p.id, p.personal_code, p.first_name, p.last_name, p.birth_date, p.birth_place, p.sex, p.citizenship, p.status, p.version, p.created_at, p.updated_at, p.marital_status, p.merged_into, p.processing_restricted_at, p.office_id, p.registrar_id
+FROM legal_parentage lp
+         JOIN person p ON p.id = lp.person_id
+WHERE lp.mother_id = $1::uuid
+   OR lp.father_id = $1::uuid
+ORDER BY p.birth_date, p.id
+`
+
+// Persons whose legal parent the person is, adoptions included.
+func (q *Queries) ListLegalChildren(ctx context.Context, personID uuid.UUID) ([]Person, error) {
+	rows, err := q.db.Query(ctx, listLegalChildren, personID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Person
+	for rows.Next() {
+		var i Person
+		if err := rows.Scan(
+			&i.ID,
+			&i.PersonalCode,
+			&i.FirstName,
+			&i.LastName,
+			&i.BirthDate,
+			&i.BirthPlace,
+			&i.Sex,
+			&i.Citizenship,
+			&i.Status,
+			&i.Version,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.MaritalStatus,
+			&i.MergedInto,
+			&i.ProcessingRestrictedAt,
+			&i.OfficeID,
+			&i.RegistrarID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setPersonProcessingRestricted = `-- name: SetPersonProcessingRestricted :one
+UPDATE person
+SET processing_restricted_at = $1,
+    updated_at               = now()
+WHERE id = $2
+RETURNING id, personal_code, first_name, last_name, birth_date, birth_place, sex, citizenship, status, version, created_at, updated_at, marital_status, merged_into, processing_restricted_at, office_id, registrar_id
+`
+
+type SetPersonProcessingRestrictedParams struct {
+	RestrictedAt pgtype.Timestamptz `json:"restricted_at"`
+	ID           uuid.UUID          `json:"id"`
+}
+
+// A NULL restricted_at lifts the restriction.
+func (q *Queries) SetPersonProcessingRestricted(ctx context.Context, arg SetPersonProcessingRestrictedParams) (Person, error) {
+	row := q.db.QueryRow(ctx, setPersonProcessingRestricted, arg.RestrictedAt, arg.ID)
+	var i Person
+	err := row.Scan(
+		&i.ID,
+		&i.PersonalCode,
+		&i.FirstName,
+		&i.LastName,
+		&i.BirthDate,
+		&i.BirthPlace,
+		&i.Sex,
+		&i.Citizenship,
+		&i.Status,
+		&i.Version,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.MaritalStatus,
+		&i.MergedInto,
+		&i.ProcessingRestrictedAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+	)
+	return i, err
+}
@@ -0,0 +1,223 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: report.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const birthReportRows = `-- name: BirthReportRows :many
+SELECT date_trunc($1::text, c.birth_date::timestamp)::date AS period,
+       coalesce(o.name, b.registration_office)::text                     AS office,
+       coalesce(o.municipality, '')::text                                AS municipality,
+       c.sex,
+       age_group(m.birth_date, c.birth_date)::text                       AS age_group,
+       count(*)                                                          AS events
+FROM birth_record b
+         JOIN person c ON c.id = b.person_id
+         LEFT JOIN person m ON m.id = b.mother_id
+         LEFT JOIN office o ON o.id = b.office_id
+WHERE c.birth_date BETWEEN $2::date AND $3::date
+  AND ($4::uuid IS NULL OR b.office_id = $4::uuid)
+  AND ($5::text IS NULL OR fold_name(o.municipality) = fold_name($5::text))
+GROUP BY 1, 2, 3, 4, 5
+ORDER BY 1
+`
+
+type BirthReportRowsParams struct {
+	Period       string      `json:"period"`
+	FromDate     pgtype.Date `json:"from_date"`
+	ToDate       pgtype.Date `json:"to_date"`
+	OfficeID     pgtype.UUID `json:"office_id"`
+	Municipality pgtype.Text `json:"municipality"`
+}
+
+type BirthReportRowsRow struct {
+	Period       pgtype.Date `json:"period"`
+	Office       string      `json:"office"`
+	Municipality string      `json:"municipality"`
+	Sex          string      `json:"sex"`
+	AgeGroup     string      `json:"age_group"`
+	Events       int64       `json:"events"`
+}
+
+// Births by the child's date of birth and sex; the age group is the
+// mother's age at the birth. Like the other report queries it counts at the
+// finest grain reports break down by, and records made before offices were
+// tracked report their registration office name and no municipality.
+func (q *Queries) BirthReportRows(ctx context.Context, arg BirthReportRowsParams) ([]BirthReportRowsRow, error) {
+	rows, err := q.db.Query(ctx, birthReportRows,
+		arg.Period,
+		arg.FromDate,
+		arg.ToDate,
+		arg.OfficeID,
+		arg.Municipality,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []BirthReportRowsRow
+	for rows.Next() {
+		var i BirthReportRowsRow
+		if err := rows.Scan(
+			&i.Period,
+			&i.Office,
+			&i.Municipality,
+			&i.Sex,
+			&i.AgeGroup,
+			&i.Events,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const deathReportRows = `-- name: DeathReportRows :many
+SELECT date_trunc($1::text, d.date_of_death::timestamp)::date AS period,
+       coalesce(o.name, d.registration_office)::text                        AS office,
+       coalesce(o.municipality, '')::text                                   AS municipality,
+       p.sex,
+       age_group(p.birth_date, d.date_of_death)::text                       AS age_group,
+       count(*)                                                             AS events
+FROM death_record d
+         JOIN person p ON p.id = d.person_id
+         LEFT JOIN office o ON o.id = d.office_id
+WHERE d.date_of_death BETWEEN $2::date AND $3::date
+  AND ($4::uuid IS NULL OR d.office_id = $4::uuid)
+  AND ($5::text IS NULL OR fold_name(o.municipality) = fold_name($5::text))
+GROUP BY 1, 2, 3, 4, 5
+ORDER BY 1
+`
+
+type DeathReportRowsParams struct {
+	Period       string      `json:"period"`
+	FromDate     pgtype.Date `json:"from_date"`
+	ToDate       pgtype.Date `json:"to_date"`
+	OfficeID     pgtype.UUID `json:"office_id"`
+	Municipality pgtype.Text `json:"municipality"`
+}
+
+type DeathReportRowsRow struct {
+	Period       pgtype.Date `json:"period"`
+	Office       string      `json:"office"`
+	Municipality string      `json:"municipality"`
+	Sex          string      `json:"sex"`
+	AgeGroup     string      `json:"age_group"`
+	Events       int64       `json:"events"`
+}
+
+// Deaths by date of death, with the deceased's sex and age at death.
+func (q *Queries) DeathReportRows(ctx context.Context, arg DeathReportRowsParams) ([]DeathReportRowsRow, error) {
+	rows, err := q.db.Query(ctx, deathReportRows,
+		arg.Period,
+		arg.FromDate,
+		arg.ToDate,
+		arg.OfficeID,
+		arg.Municipality,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []DeathReportRowsRow
+	for rows.Next() {
+		var i DeathReportRowsRow
+		if err := rows.Scan(
+			&i.Period,
+			&i.Office,
+			&i.Municipality,
+			&i.Sex,
+			&i.AgeGroup,
+			&i.Events,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const marriageReportRows = `-- name: MarriageReportRows :many
+SELECT date_trunc($1::text, m.registered_on::timestamp)::date AS period,
+       coalesce(o.name, m.registration_office)::text                        AS office,
+       coalesce(o.municipality, '')::text                                   AS municipality,
+       s.sex,
+       age_group(s.birth_date, m.registered_on)::text                       AS age_group,
+       count(*)                                                             AS events
+FROM marriage m
+         JOIN person s ON s.id IN (m.spouse1_id, m.spouse2_id)
+         LEFT JOIN office o ON o.id = m.office_id
+WHERE m.status <> 'annulled'
+  AND m.registered_on BETWEEN $2::date AND $3::date
+  AND ($4::uuid IS NULL OR m.office_id = $4::uuid)
+  AND ($5::text IS NULL OR fold_name(o.municipality) = fold_name($5::text))
+GROUP BY 1, 2, 3, 4, 5
+ORDER BY 1
+`
+
+type MarriageReportRowsParams struct {
+	Period       string      `json:"period"`
+	FromDate     pgtype.Date `json:"from_date"`
+	ToDate       pgtype.Date `json:"to_date"`
+	OfficeID     pgtype.UUID `json:"office_id"`
+	Municipality pgtype.Text `json:"municipality"`
+}
+
+type MarriageReportRowsRow struct {
+	Period       pgtype.Date `json:"period"`
+	Office       string      `json:"office"`
+	Municipality string      `json:"municipality"`
+	Sex          string      `json:"sex"`
+	AgeGroup     string      `json:"age_group"`
+	Events       int64       `json:"events"`
+}
+
+// Marriages by registration date, one row per spouse with their sex and
+// age at marriage, so events counts spouses. Annulled marriages are void
+// and left out.
+func (q *Queries) MarriageReportRows(ctx context.Context, arg MarriageReportRowsParams) ([]MarriageReportRowsRow, error) {
+	rows, err := q.db.Query(ctx, marriageReportRows,
+		arg.Period,
+		arg.FromDate,
+		arg.ToDate,
+		arg.OfficeID,
+		arg.Municipality,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []MarriageReportRowsRow
+	for rows.Next() {
+		var i MarriageReportRowsRow
+		if err := rows.Scan(
+			&i.Period,
+			&i.Office,
+			&i.Municipality,
+			&i.Sex,
+			&i.AgeGroup,
+			&i.Events,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
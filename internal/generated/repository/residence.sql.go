@@ -0,0 +1,363 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.29.0
+// source: residence.sql
+
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const createAddress = `-- name: CreateAddress :one
+INSERT INTO address (municipality, street, house, flat, postal_code)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING id, municipality, street, house, flat, postal_code, created_at
+`
+
+type CreateAddressParams struct {
+	Municipality string `json:"municipality"`
+	Street       string `json:"street"`
+	House        string `json:"house"`
+	Flat         string `json:"flat"`
+	PostalCode   string `json:"postal_code"`
+}
+
+func (q *Queries) CreateAddress(ctx context.Context, arg CreateAddressParams) (Address, error) {
+	row := q.db.QueryRow(ctx, createAddress,
+		arg.Municipality,
+		arg.Street,
+		arg.House,
+		arg.Flat,
+		arg.PostalCode,
+	)
+	var i Address
+	err := row.Scan(
+		&i.ID,
+		&i.Municipality,
+		&i.Street,
+		&i.House,
+		&i.Flat,
+		&i.PostalCode,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const createResidenceDeclaration = `-- name: CreateResidenceDeclaration :one
+INSERT INTO residence_declaration (person_id, address_id, start_date, declared_by, office_id, registrar_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, person_id, address_id, start_date, end_date, declared_by, declared_at, office_id, registrar_id
+`
+
+type CreateResidenceDeclarationParams struct {
+	PersonID    uuid.UUID   `json:"person_id"`
+	AddressID   uuid.UUID   `json:"address_id"`
+	StartDate   pgtype.Date `json:"start_date"`
+	DeclaredBy  string      `json:"declared_by"`
+	OfficeID    pgtype.UUID `json:"office_id"`
+	RegistrarID pgtype.UUID `json:"registrar_id"`
+}
+
+func (q *Queries) CreateResidenceDeclaration(ctx context.Context, arg CreateResidenceDeclarationParams) (ResidenceDeclaration, error) {
+	row := q.db.QueryRow(ctx, createResidenceDeclaration,
+		arg.PersonID,
+		arg.AddressID,
+		arg.StartDate,
+		arg.DeclaredBy,
+		arg.OfficeID,
+		arg.RegistrarID,
+	)
+	var i ResidenceDeclaration
+	err := row.Scan(
+		&i.ID,
+		&i.PersonID,
+		&i.AddressID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.DeclaredBy,
+		&i.DeclaredAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+	)
+	return i, err
+}
+
+const endResidenceDeclaration = `-- name: EndResidenceDeclaration :one
+UPDATE residence_declaration
+SET end_date = $1
+WHERE id = $2
+  AND end_date IS NULL
+RETURNING id, person_id, address_id, start_date, end_date, declared_by, declared_at, office_id, registrar_id
+`
+
+type EndResidenceDeclarationParams struct {
+	EndDate pgtype.Date `json:"end_date"`
+	ID      uuid.UUID   `json:"id"`
+}
+
+func (q *Queries) EndResidenceDeclaration(ctx context.Context, arg EndResidenceDeclarationParams) (ResidenceDeclaration, error) {
+	row := q.db.QueryRow(ctx, endResidenceDeclaration, arg.EndDate, arg.ID)
+	var i ResidenceDeclaration
+	err := row.Scan(
+		&i.ID,
+		&i.PersonID,
+		&i.AddressID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.DeclaredBy,
+		&i.DeclaredAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+	)
+	return i, err
+}
+
+const getAddressByID = `-- name: GetAddressByID :one
+SELECT id, municipality, street, house, flat, postal_code, created_at FROM address
+WHERE id = $1
+`
+
+func (q *Queries) GetAddressByID(ctx context.Context, id uuid.UUID) (Address, error) {
+	row := q.db.QueryRow(ctx, getAddressByID, id)
+	var i Address
+	err := row.Scan(
+		&i.ID,
+		&i.Municipality,
+		&i.Street,
+		&i.House,
+		&i.Flat,
+		&i.PostalCode,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getCurrentResidenceDeclaration = `-- name: GetCurrentResidenceDeclaration :one
+SELECT id, person_id, address_id, start_date, end_date, declared_by, declared_at, office_id, registrar_id FROM residence_declaration
+WHERE person_id = $1
+  AND end_date IS NULL
+`
+
+func (q *Queries) GetCurrentResidenceDeclaration(ctx context.Context, personID uuid.UUID) (ResidenceDeclaration, error) {
+	row := q.db.QueryRow(ctx, getCurrentResidenceDeclaration, personID)
+	var i ResidenceDeclaration
+	err := row.Scan(
+		&i.ID,
+		&i.PersonID,
+		&i.AddressID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.DeclaredBy,
+		&i.DeclaredAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+	)
+	return i, err
+}
+
+const getLatestResidenceDeclaration = `-- name: GetLatestResidenceDeclaration :one
+SELECT id, person_id, address_id, start_date, end_date, declared_by, declared_at, office_id, registrar_id FROM residence_declaration
+WHERE person_id = $1
+ORDER BY start_date DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestResidenceDeclaration(ctx context.Context, personID uuid.UUID) (ResidenceDeclaration, error) {
+	row := q.db.QueryRow(ctx, getLatestResidenceDeclaration, personID)
+	var i ResidenceDeclaration
+	err := row.Scan(
+		&i.ID,
+		&i.PersonID,
+		&i.AddressID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.DeclaredBy,
+		&i.DeclaredAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+	)
+	return i, err
+}
+
+const getResidenceDeclarationByID = `-- name: GetResidenceDeclarationByID :one
+SELECT id, person_id, address_id, start_date, end_date, declared_by, declared_at, office_id, registrar_id FROM residence_declaration
+WHERE id = $1
+`
+
+func (q *Queries) GetResidenceDeclarationByID(ctx context.Context, id uuid.UUID) (ResidenceDeclaration, error) {
+	row := q.db.QueryRow(ctx, getResidenceDeclarationByID, id)
+	var i ResidenceDeclaration
+	err := row.Scan(
+		&i.ID,
+		&i.PersonID,
+		&i.AddressID,
+		&i.StartDate,
+		&i.EndDate,
+		&i.DeclaredBy,
+		&i.DeclaredAt,
+		&i.OfficeID,
+		&i.RegistrarID,
+	)
+	return i, err
+}
+
+const listResidenceDeclarationsForPerson = `-- name: ListResidenceDeclarationsForPerson :many
+SELECT id, person_id, address_id, start_date, end_date, declared_by, declared_at, office_id, registrar_id FROM residence_declaration
+WHERE person_id = $1
+ORDER BY start_date
+`
+
+func (q *Queries) ListResidenceDeclarationsForPerson(ctx context.Context, personID uuid.UUID) ([]ResidenceDeclaration, error) {
+	rows, err := q.db.Query(ctx, listResidenceDeclarationsForPerson, personID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ResidenceDeclaration
+	for rows.Next() {
+		var i ResidenceDeclaration
+		if err := rows.Scan(
+			&i.ID,
+			&i.PersonID,
+			&i.AddressID,
+			&i.StartDate,
+			&i.EndDate,
+			&i.DeclaredBy,
+			&i.DeclaredAt,
+			&i.OfficeID,
+			&i.RegistrarID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listResidentsAtAddress = `-- name: ListResidentsAtAddress :many
+SELECT d.id, d.person_id, d.address_id, d.start_date, d.end_date, d.declared_by, d.declared_at, d.office_id, d.registrar_id, p.id, p.personal_code, p.first_name, p.last_name, p.birth_date, p.birth_place, p.sex, p.citizenship, p.status, p.version, p.created_at, p.updated_at, p.marital_status, p.merged_into, p.processing_restricted_at, p.office_id, p.registrar_id
+FROM residence_declaration d
+         JOIN person p ON p.id = d.person_id
+WHERE d.address_id = $1
+  AND d.start_date <= $2::date
+  AND (d.end_date IS NULL OR d.end_date > $2::date)
+ORDER BY p.last_name, p.first_name, p.id
+`
+
+type ListResidentsAtAddressParams struct {
+	AddressID uuid.UUID   `json:"address_id"`
+	Day       pgtype.Date `json:"day"`
+}
+
+type ListResidentsAtAddressRow struct {
+	ResidenceDeclaration ResidenceDeclaration `json:"residence_declaration"`
+	Person               Person               `json:"person"`
+}
+
+// Persons whose declaration at the address covers the given day.
+func (q *Queries) ListResidentsAtAddress(ctx context.Context, arg ListResidentsAtAddressParams) ([]ListResidentsAtAddressRow, error) {
+	rows, err := q.db.Query(ctx, listResidentsAtAddress, arg.AddressID, arg.Day)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListResidentsAtAddressRow
+	for rows.Next() {
+		var i ListResidentsAtAddressRow
+		if err := rows.Scan(
+			&i.ResidenceDeclaration.ID,
+			&i.ResidenceDeclaration.PersonID,
+			&i.ResidenceDeclaration.AddressID,
+			&i.ResidenceDeclaration.StartDate,
+			&i.ResidenceDeclaration.EndDate,
+			&i.ResidenceDeclaration.DeclaredBy,
+			&i.ResidenceDeclaration.DeclaredAt,
+			&i.ResidenceDeclaration.OfficeID,
+			&i.ResidenceDeclaration.RegistrarID,
+			&i.Person.ID,
+			&i.Person.PersonalCode,
+			&i.Person.FirstName,
+			&i.Person.LastName,
+			&i.Person.BirthDate,
+			&i.Person.BirthPlace,
+			&i.Person.Sex,
+			&i.Person.Citizenship,
+			&i.Person.Status,
+			&i.Person.Version,
+			&i.Person.CreatedAt,
+			&i.Person.UpdatedAt,
+			&i.Person.MaritalStatus,
+			&i.Person.MergedInto,
+			&i.Person.ProcessingRestrictedAt,
+			&i.Person.OfficeID,
+			&i.Person.RegistrarID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchAddresses = `-- name: SearchAddresses :many
+SELECT id, municipality, street, house, flat, postal_code, created_at FROM address
+WHERE ($1::text IS NULL OR fold_name(municipality) = fold_name($1))
+  AND ($2::text IS NULL OR fold_name(street) LIKE fold_name($2) || '%')
+  AND ($3::text IS NULL OR postal_code = $3)
+ORDER BY municipality, street, house, flat
+LIMIT $5 OFFSET $4
+`
+
+type SearchAddressesParams struct {
+	Municipality pgtype.Text `json:"municipality"`
+	Street       pgtype.Text `json:"street"`
+	PostalCode   pgtype.Text `json:"postal_code"`
+	Offset       int32       `json:"offset"`
+	Limit        int32       `json:"limit"`
+}
+
+// Names are compared without case and Lithuanian diacritics; street matches
+// by prefix.
+func (q *Queries) SearchAddresses(ctx context.Context, arg SearchAddressesParams) ([]Address, error) {
+	rows, err := q.db.Query(ctx, searchAddresses,
+		arg.Municipality,
+		arg.Street,
+		arg.PostalCode,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Address
+	for rows.Next() {
+		var i Address
+		if err := rows.Scan(
+			&i.ID,
+			&i.Municipality,
+			&i.Street,
+			&i.House,
+			&i.Flat,
+			&i.PostalCode,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,186 @@
+package personalcode
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func date(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name   string
+		code   string
+		birth  time.Time
+		sex    string
+		serial int
+	}{
+		{"male born 1987", "38703181745", date(1987, 3, 18), sexMale, 174},
+		{"female born 1990", "49002010965", date(1990, 2, 1), sexFemale, 96},
+		{"male born 1800", "10001010002", date(1800, 1, 1), sexMale, 0},
+		{"female born 1899", "29912319996", date(1899, 12, 31), sexFemale, 999},
+		{"male born 1900", "30001010004", date(1900, 1, 1), sexMale, 0},
+		{"female born 1999", "49912311237", date(1999, 12, 31), sexFemale, 123},
+		{"male born 2000", "50001010017", date(2000, 1, 1), sexMale, 1},
+		{"female born 2099", "69912319998", date(2099, 12, 31), sexFemale, 999},
+		{"leap day 2000", "50002290002", date(2000, 2, 29), sexMale, 0},
+		{"leap day 1996", "49602291230", date(1996, 2, 29), sexFemale, 123},
+		{"check digit from the second pass", "38703180157", date(1987, 3, 18), sexMale, 15},
+		{"both passes give 10", "38703180030", date(1987, 3, 18), sexMale, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.code)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.code, err)
+			}
+			if !got.BirthDate.Equal(tt.birth) {
+				t.Errorf("BirthDate = %s, want %s", got.BirthDate.Format(time.DateOnly), tt.birth.Format(time.DateOnly))
+			}
+			if got.Sex != tt.sex {
+				t.Errorf("Sex = %q, want %q", got.Sex, tt.sex)
+			}
+			if got.Serial != tt.serial {
+				t.Errorf("Serial = %d, want %d", got.Serial, tt.serial)
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want error
+	}{
+		{"empty", "", errLength},
+		{"too short", "3870318174", errLength},
+		{"too long", "387031817450", errLength},
+		{"not digits", "3870318174a", errLength},
+		{"spaces", "38703 81745", errLength},
+		{"century digit 0", "08703181745", errCentury},
+		{"century digit 7", "78703181745", errCentury},
+		{"century digit 9", "98703181745", errCentury},
+		{"month 0", "38700100001", errDate},
+		{"month 13", "38713010011", errDate},
+		{"day 0", "38701000012", errDate},
+		{"day 32", "38701320011", errDate},
+		{"February 30", "38702300013", errDate},
+		{"February 29 in 1900", "30002290000", errDate},
+		{"wrong check digit", "38703181746", errChecksum},
+		{"second pass digit off by one", "38703180158", errChecksum},
+		{"both passes give 10 but not 0", "38703180031", errChecksum},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.code); !errors.Is(err, tt.want) {
+				t.Errorf("Parse(%q) error = %v, want %v", tt.code, err, tt.want)
+			}
+			if err := Validate(tt.code); err == nil {
+				t.Errorf("Validate(%q) accepted an invalid code", tt.code)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		birth   time.Time
+		sex     string
+		wantErr bool
+	}{
+		{"matches", "38703181745", date(1987, 3, 18), sexMale, false},
+		{"matches a local time of day", "38703181745", time.Date(1987, 3, 18, 23, 30, 0, 0, time.Local), sexMale, false},
+		{"other birth date", "38703181745", date(1987, 3, 19), sexMale, true},
+		{"other century", "58703181747", date(1987, 3, 18), sexMale, true},
+		{"other sex", "38703181745", date(1987, 3, 18), sexFemale, true},
+		{"invalid code", "38703181746", date(1987, 3, 18), sexMale, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Check(tt.code, tt.birth, tt.sex)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Check(%q) error = %v, wantErr %v", tt.code, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		birth  time.Time
+		sex    string
+		serial int
+		want   string
+	}{
+		{date(1987, 3, 18), sexMale, 174, "38703181745"},
+		{date(1990, 2, 1), sexFemale, 96, "49002010965"},
+		{date(1800, 1, 1), sexMale, 0, "10001010002"},
+		{date(1899, 12, 31), sexFemale, 999, "29912319996"},
+		{date(1900, 1, 1), sexMale, 0, "30001010004"},
+		{date(2000, 1, 1), sexMale, 1, "50001010017"},
+		{date(2099, 12, 31), sexFemale, 999, "69912319998"},
+		{date(1987, 3, 18), sexMale, 15, "38703180157"},
+		{date(1987, 3, 18), sexMale, 3, "38703180030"},
+	}
+	for _, tt := range tests {
+		got, err := Generate(tt.birth, tt.sex, tt.serial)
+		if err != nil {
+			t.Errorf("Generate(%s, %s, %d): %v", tt.birth.Format(time.DateOnly), tt.sex, tt.serial, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Generate(%s, %s, %d) = %s, want %s", tt.birth.Format(time.DateOnly), tt.sex, tt.serial, got, tt.want)
+		}
+		if err := Check(got, tt.birth, tt.sex); err != nil {
+			t.Errorf("Check(Generate(...)) = %v", err)
+		}
+	}
+}
+
+func TestGenerateInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		birth  time.Time
+		sex    string
+		serial int
+	}{
+		{"before 1800", date(1799, 12, 31), sexMale, 1},
+		{"after 2099", date(2100, 1, 1), sexFemale, 1},
+		{"negative serial", date(1987, 3, 18), sexMale, -1},
+		{"serial past the maximum", date(1987, 3, 18), sexMale, MaxSerial + 1},
+		{"unknown sex", date(1987, 3, 18), "other", 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code, err := Generate(tt.birth, tt.sex, tt.serial); err == nil {
+				t.Errorf("Generate returned %s, want an error", code)
+			}
+		})
+	}
+}
+
+func TestCheckDigit(t *testing.T) {
+	tests := []struct {
+		name   string
+		digits []int
+		want   int
+	}{
+		{"first pass", []int{3, 8, 7, 0, 3, 1, 8, 1, 7, 4}, 5},
+		{"first pass remainder 0", []int{3, 0, 0, 0, 2, 2, 9, 0, 0, 0}, 0},
+		{"second pass", []int{3, 8, 7, 0, 3, 1, 8, 0, 1, 5}, 7},
+		{"both passes give 10", []int{3, 8, 7, 0, 3, 1, 8, 0, 0, 3}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := checkDigit(tt.digits); got != tt.want {
+				t.Errorf("checkDigit(%v) = %d, want %d", tt.digits, got, tt.want)
+			}
+		})
+	}
+}
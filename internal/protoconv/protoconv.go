@@ -0,0 +1,41 @@
+// Package protoconv converts nullable pgtype values to and from the
+// well-known protobuf types used by the generated gRPC services.
+package protoconv
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const dateLayout = "2006-01-02"
+
+// NullableTimestamp returns nil for a NULL timestamp.
+func NullableTimestamp(t pgtype.Timestamptz) *timestamppb.Timestamp {
+	if !t.Valid {
+		return nil
+	}
+	return timestamppb.New(t.Time)
+}
+
+// NullableDate formats a date as YYYY-MM-DD, or returns nil for NULL.
+func NullableDate(d pgtype.Date) *string {
+	if !d.Valid {
+		return nil
+	}
+	s := d.Time.Format(dateLayout)
+	return &s
+}
+
+// ParseDate parses an optional YYYY-MM-DD string; nil becomes NULL.
+func ParseDate(s *string) (pgtype.Date, error) {
+	if s == nil {
+		return pgtype.Date{}, nil
+	}
+	t, err := time.Parse(dateLayout, *s)
+	if err != nil {
+		return pgtype.Date{}, err
+	}
+	return pgtype.Date{Time: t, Valid: true}, nil
+}
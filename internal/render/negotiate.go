@@ -0,0 +1,73 @@
+package render
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Negotiate picks the offer that best matches the request's Accept header.
+// Offers are listed in order of server preference, which breaks ties; the
+// first one is used when the client sends no Accept header. An empty string
+// means none of the offers is acceptable.
+func Negotiate(r *http.Request, offers ...string) string {
+	if len(offers) == 0 {
+		return ""
+	}
+
+	header := r.Header.Get("Accept")
+	if strings.TrimSpace(header) == "" {
+		return offers[0]
+	}
+
+	type acceptRange struct {
+		mediaType string
+		q         float64
+	}
+	var ranges []acceptRange
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(v, 64); err == nil {
+				q = parsed
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType: mediaType, q: q})
+	}
+
+	best, bestQ := "", 0.0
+	for _, offer := range offers {
+		// The most specific matching range decides the offer's quality
+		q, specificity := 0.0, -1
+		for _, ar := range ranges {
+			if s := matchSpecificity(ar.mediaType, offer); s > specificity {
+				q, specificity = ar.q, s
+			}
+		}
+		if q > bestQ {
+			best, bestQ = offer, q
+		}
+	}
+
+	return best
+}
+
+// matchSpecificity reports how precisely accepted matches offer:
+// 2 for an exact match, 1 for "type/*", 0 for "*/*" and -1 for no match.
+func matchSpecificity(accepted, offer string) int {
+	if accepted == offer {
+		return 2
+	}
+	if accepted == "*/*" {
+		return 0
+	}
+	if strings.HasSuffix(accepted, "/*") && strings.HasPrefix(offer, strings.TrimSuffix(accepted, "*")) {
+		return 1
+	}
+	return -1
+}
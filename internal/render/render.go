@@ -0,0 +1,133 @@
+package render
+
+import (
+	"database/sql/driver"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+const (
+	ContentTypeJSON   = "application/json"
+	ContentTypeXML    = "application/xml"
+	ContentTypeCSV    = "text/csv"
+	ContentTypeNDJSON = "application/x-ndjson"
+	ContentTypePDF    = "application/pdf"
+	ContentTypeZIP    = "application/zip"
+)
+
+// Write encodes v as JSON or XML, as chosen by Negotiate.
+func Write(w http.ResponseWriter, status int, contentType string, v any) error {
+	switch contentType {
+	case ContentTypeXML:
+		w.Header().Set("Content-Type", ContentTypeXML+"; charset=utf-8")
+		w.WriteHeader(status)
+		if _, err := w.Write([]byte(xml.Header)); err != nil {
+			return err
+		}
+		return xml.NewEncoder(w).Encode(v)
+	case ContentTypeJSON:
+		w.Header().Set("Content-Type", ContentTypeJSON)
+		w.WriteHeader(status)
+		return json.NewEncoder(w).Encode(v)
+	default:
+		return fmt.Errorf("render: cannot encode %q", contentType)
+	}
+}
+
+// CSVRecorder is implemented by generated response models.
+type CSVRecorder interface {
+	CSVRecord() []string
+}
+
+// WriteCSV writes a header row followed by one record per row.
+func WriteCSV[T CSVRecorder](w http.ResponseWriter, status int, header []string, rows []T) error {
+	w.Header().Set("Content-Type", ContentTypeCSV+"; charset=utf-8")
+	w.WriteHeader(status)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		if err := cw.Write(row.CSVRecord()); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// CSVValue formats a column value for a CSV cell. NULL database values and
+// nil pointers become empty cells.
+func CSVValue(v any) string {
+	switch t := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return t
+	case time.Time:
+		return t.Format(time.RFC3339)
+	case driver.Valuer:
+		value, err := t.Value()
+		if err != nil || value == nil {
+			return ""
+		}
+		return CSVValue(value)
+	case fmt.Stringer:
+		return t.String()
+	}
+
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return ""
+		}
+		return CSVValue(rv.Elem().Interface())
+	}
+	return fmt.Sprint(v)
+}
+
+// Nullable turns a pgtype value/valid pair into a pointer that is nil for NULL.
+func Nullable[T any](v T, valid bool) *T {
+	if !valid {
+		return nil
+	}
+	return &v
+}
+
+// Date is a calendar date encoded as YYYY-MM-DD in every format.
+type Date time.Time
+
+func NullableDate(t time.Time, valid bool) *Date {
+	if !valid {
+		return nil
+	}
+	d := Date(t)
+	return &d
+}
+
+func (d Date) String() string {
+	return time.Time(d).Format(time.DateOnly)
+}
+
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d *Date) UnmarshalText(text []byte) error {
+	t, err := time.Parse(time.DateOnly, string(text))
+	if err != nil {
+		return err
+	}
+	*d = Date(t)
+	return nil
+}
+
+// IsZero reports whether the date was left empty.
+func (d Date) IsZero() bool {
+	return time.Time(d).IsZero()
+}
@@ -0,0 +1,122 @@
+package render
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+)
+
+// flushEvery is how many rows are written between flushes of a stream.
+const flushEvery = 100
+
+// NDJSONStream writes one JSON document per line, flushing periodically so
+// clients receive rows while the query is still running.
+type NDJSONStream struct {
+	rc      *http.ResponseController
+	enc     *json.Encoder
+	written int
+}
+
+func NewNDJSONStream(w http.ResponseWriter) *NDJSONStream {
+	w.Header().Set("Content-Type", ContentTypeNDJSON)
+	return &NDJSONStream{
+		rc:  http.NewResponseController(w),
+		enc: json.NewEncoder(w),
+	}
+}
+
+func (s *NDJSONStream) Write(v any) error {
+	if err := s.enc.Encode(v); err != nil {
+		return err
+	}
+	s.written++
+	if s.written%flushEvery == 0 {
+		return s.rc.Flush()
+	}
+	return nil
+}
+
+// Started reports whether any bytes have been sent, after which the status
+// code can no longer be changed.
+func (s *NDJSONStream) Started() bool {
+	return s.written > 0
+}
+
+func (s *NDJSONStream) Close() error {
+	return s.rc.Flush()
+}
+
+// CSVStream writes a header row followed by one record per row. The header
+// is sent with the first record, or on Close for an empty result.
+type CSVStream struct {
+	rc      *http.ResponseController
+	cw      *csv.Writer
+	header  []string
+	written int
+}
+
+func NewCSVStream(w http.ResponseWriter, header []string) *CSVStream {
+	w.Header().Set("Content-Type", ContentTypeCSV+"; charset=utf-8")
+	return &CSVStream{
+		rc:     http.NewResponseController(w),
+		cw:     csv.NewWriter(w),
+		header: header,
+	}
+}
+
+func (s *CSVStream) Write(record []string) error {
+	if s.written == 0 {
+		if err := s.cw.Write(s.header); err != nil {
+			return err
+		}
+	}
+	if err := s.cw.Write(record); err != nil {
+		return err
+	}
+	s.written++
+	if s.written%flushEvery == 0 {
+		return s.flush()
+	}
+	return nil
+}
+
+func (s *CSVStream) Started() bool {
+	return s.written > 0
+}
+
+func (s *CSVStream) Close() error {
+	if s.written == 0 {
+		if err := s.cw.Write(s.header); err != nil {
+			return err
+		}
+	}
+	return s.flush()
+}
+
+func (s *CSVStream) flush() error {
+	s.cw.Flush()
+	if err := s.cw.Error(); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// Stream is implemented by NDJSONStream and CSVStream.
+type Stream interface {
+	Started() bool
+	Close() error
+}
+
+// FinishStream completes s once the streaming query has returned queryErr.
+// A query that fails before the first row still gets a 500; after that the
+// status is already sent, so the body is cut short and the error is only
+// returned for logging.
+func FinishStream(w http.ResponseWriter, s Stream, queryErr error) error {
+	if queryErr != nil {
+		if !s.Started() {
+			http.Error(w, "Internal server error", http.StatusInternalServerError)
+		}
+		return queryErr
+	}
+	return s.Close()
+}
@@ -0,0 +1,118 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestValidKey(t *testing.T) {
+	tests := []struct {
+		key  string
+		want bool
+	}{
+		{digest([]byte("scan")), true},
+		{strings.Repeat("0", 64), true},
+		{"", false},
+		{strings.Repeat("0", 63), false},
+		{strings.Repeat("0", 65), false},
+		{strings.Repeat("A", 64), false},
+		{strings.Repeat("g", 64), false},
+		{"../" + strings.Repeat("0", 61), false},
+		{"ab/cd/" + strings.Repeat("0", 58), false},
+	}
+	for _, tt := range tests {
+		if got := ValidKey(tt.key); got != tt.want {
+			t.Errorf("ValidKey(%q) = %v, want %v", tt.key, got, tt.want)
+		}
+	}
+}
+
+func TestLocalStoreRejectsInvalidKeys(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocalStore(root)
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	ctx := context.Background()
+
+	for _, key := range []string{"", "../../outside", "ABCD" + strings.Repeat("0", 60), strings.Repeat("0", 10)} {
+		if err := s.Put(ctx, key, strings.NewReader("x"), 1); err == nil {
+			t.Errorf("Put(%q) succeeded", key)
+		}
+		if _, err := s.Open(ctx, key); err == nil {
+			t.Errorf("Open(%q) succeeded", key)
+		}
+		if err := s.Delete(ctx, key); err == nil {
+			t.Errorf("Delete(%q) succeeded", key)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(root), "outside")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("a file was written outside the root: %v", err)
+	}
+}
+
+func TestLocalStorePutOpenDelete(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocalStore(root)
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	ctx := context.Background()
+
+	content := []byte("\x89PNG marriage record scan")
+	key := digest(content)
+
+	if err := s.Put(ctx, key, bytes.NewReader(content), int64(len(content))); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, key[0:2], key[2:4], key)); err != nil {
+		t.Errorf("blob not fanned out by key: %v", err)
+	}
+
+	rc, err := s.Open(ctx, key)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	got, err := io.ReadAll(rc)
+	rc.Close()
+	if err != nil {
+		t.Fatalf("read blob: %v", err)
+	}
+	if !bytes.Equal(got, content) {
+		t.Errorf("Open returned %q, want %q", got, content)
+	}
+
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := s.Open(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open after Delete: err = %v, want ErrNotFound", err)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Errorf("Delete of a missing blob: %v", err)
+	}
+}
+
+func TestLocalStorePutRejectsMismatchedContent(t *testing.T) {
+	root := t.TempDir()
+	s, err := NewLocalStore(root)
+	if err != nil {
+		t.Fatalf("NewLocalStore: %v", err)
+	}
+	ctx := context.Background()
+
+	key := digest([]byte("what the key promises"))
+	content := []byte("something else")
+	if err := s.Put(ctx, key, bytes.NewReader(content), int64(len(content))); err == nil {
+		t.Fatal("Put of content not matching its key succeeded")
+	}
+	if _, err := s.Open(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open after rejected Put: err = %v, want ErrNotFound", err)
+	}
+}
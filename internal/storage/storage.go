@@ -0,0 +1,134 @@
+// Package storage keeps uploaded file contents under the lowercase hex
+// SHA-256 digest of the bytes, so identical files are stored once and a
+// stored blob can be checked against its key.
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"regexp"
+)
+
+// ErrNotFound is returned by Open for keys that were never stored or have
+// been deleted.
+var ErrNotFound = errors.New("blob not found")
+
+// ErrTooLarge is returned by Stage when the content exceeds its limit.
+var ErrTooLarge = errors.New("content too large")
+
+var keyPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+
+// BlobStore stores contents under their SHA-256 key.
+type BlobStore interface {
+	// Put stores size bytes read from r under key. Storing a key that is
+	// already present leaves the existing blob in place; content that does
+	// not hash to key is rejected.
+	Put(ctx context.Context, key string, r io.Reader, size int64) error
+	// Open returns the blob stored under key, or ErrNotFound.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob under key; deleting a missing key is not an
+	// error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Config selects and configures a BlobStore.
+type Config struct {
+	// Backend is "local" or "s3"
+	Backend string
+	// Dir is the local store's root directory
+	Dir string
+	S3  S3Config
+}
+
+// New returns the BlobStore described by cfg.
+func New(cfg Config) (BlobStore, error) {
+	switch cfg.Backend {
+	case "", "local":
+		return NewLocalStore(cfg.Dir)
+	case "s3":
+		return NewS3Store(cfg.S3)
+	}
+	return nil, fmt.Errorf("unknown blob store %q, want local or s3", cfg.Backend)
+}
+
+// ValidKey reports whether key is a lowercase hex SHA-256 digest.
+func ValidKey(key string) bool {
+	return keyPattern.MatchString(key)
+}
+
+// Staged is content copied to a temporary file while its key was computed.
+// Head holds its first bytes for content type sniffing. Close removes the
+// file.
+type Staged struct {
+	File *os.File
+	Key  string
+	Size int64
+	Head []byte
+}
+
+// Stage copies at most limit bytes of r to a temporary file, hashing them
+// on the way. Content longer than limit fails with ErrTooLarge.
+func Stage(r io.Reader, limit int64) (_ *Staged, err error) {
+	f, err := os.CreateTemp("", "blob-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create staging file: %w", err)
+	}
+	s := &Staged{File: f}
+	defer func() {
+		if err != nil {
+			s.Close()
+		}
+	}()
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, h), io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to stage content: %w", err)
+	}
+	if n > limit {
+		return nil, ErrTooLarge
+	}
+	s.Key = hex.EncodeToString(h.Sum(nil))
+	s.Size = n
+
+	s.Head = make([]byte, min(n, 512))
+	if _, err := f.ReadAt(s.Head, 0); err != nil && !errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("failed to read staged content: %w", err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to rewind staged content: %w", err)
+	}
+	return s, nil
+}
+
+// Close removes the staging file.
+func (s *Staged) Close() error {
+	s.File.Close()
+	return os.Remove(s.File.Name())
+}
+
+// verifyingReader fails at EOF when the bytes read do not hash to key.
+type verifyingReader struct {
+	r   io.Reader
+	h   hash.Hash
+	key string
+}
+
+func newVerifyingReader(r io.Reader, key string) *verifyingReader {
+	return &verifyingReader{r: r, h: sha256.New(), key: key}
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.r.Read(p)
+	v.h.Write(p[:n])
+	if errors.Is(err, io.EOF) && hex.EncodeToString(v.h.Sum(nil)) != v.key {
+		return n, fmt.Errorf("content does not match key %s", v.key)
+	}
+	return n, err
+}
@@ -0,0 +1,62 @@
+package telemetry
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// ConnectInterceptor records handler-layer metrics for every RPC of a
+// Connect service, under the same labels InstrumentHandler uses for the
+// matching HTTP route. gRPC answers failures with HTTP 200, so the outcome
+// is read from the returned error rather than the status code: codes that
+// the Connect protocol maps to a 5xx status are counted as errors.
+func ConnectInterceptor(feature string) connect.Interceptor {
+	return &connectInterceptor{feature: feature}
+}
+
+type connectInterceptor struct {
+	feature string
+}
+
+func (i *connectInterceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		start := time.Now()
+		resp, err := next(ctx, req)
+		observe(LayerHandler, i.feature, procedureName(req.Spec().Procedure), start, serverFault(err))
+		return resp, err
+	}
+}
+
+func (i *connectInterceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i *connectInterceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		start := time.Now()
+		err := next(ctx, conn)
+		observe(LayerHandler, i.feature, procedureName(conn.Spec().Procedure), start, serverFault(err))
+		return err
+	}
+}
+
+// procedureName trims "/civilregistry.post.v1.PostService/GetPostByID" down
+// to the method name, which matches the REST handler's operation label.
+func procedureName(procedure string) string {
+	return procedure[strings.LastIndex(procedure, "/")+1:]
+}
+
+func serverFault(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch connect.CodeOf(err) {
+	case connect.CodeUnknown, connect.CodeInternal, connect.CodeUnimplemented,
+		connect.CodeUnavailable, connect.CodeDataLoss, connect.CodeDeadlineExceeded:
+		return true
+	}
+	return false
+}
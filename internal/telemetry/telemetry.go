@@ -0,0 +1,98 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	LayerHandler = "handler"
+	LayerService = "service"
+)
+
+var labels = []string{"layer", "feature", "operation"}
+
+var (
+	requestsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "civilregistry",
+		Name:      "operation_requests_total",
+		Help:      "Number of generated API operations executed.",
+	}, labels)
+
+	errorsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "civilregistry",
+		Name:      "operation_errors_total",
+		Help:      "Number of generated API operations that failed.",
+	}, labels)
+
+	durationSeconds = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "civilregistry",
+		Name:      "operation_duration_seconds",
+		Help:      "Duration of generated API operations.",
+		Buckets:   prometheus.DefBuckets,
+	}, labels)
+)
+
+// Spans go to whatever TracerProvider is registered with otel.SetTracerProvider;
+// without one they are no-ops.
+var tracer = otel.Tracer("github.com/eif-courses/civilregistry")
+
+// Operation tracks a single service call from StartOperation until End.
+type Operation struct {
+	span      trace.Span
+	feature   string
+	operation string
+	start     time.Time
+}
+
+// StartOperation opens a span named "<feature>.<operation>" and starts the
+// service-layer timers for it.
+func StartOperation(ctx context.Context, feature, operation string) (context.Context, *Operation) {
+	ctx, span := tracer.Start(ctx, feature+"."+operation)
+	return ctx, &Operation{
+		span:      span,
+		feature:   feature,
+		operation: operation,
+		start:     time.Now(),
+	}
+}
+
+// End records the outcome of the operation and closes its span.
+func (o *Operation) End(err error) {
+	observe(LayerService, o.feature, o.operation, o.start, err != nil)
+
+	if err != nil {
+		o.span.RecordError(err)
+		o.span.SetStatus(codes.Error, err.Error())
+	}
+	o.span.End()
+}
+
+// InstrumentHandler records handler-layer metrics for next. Responses with a
+// 5xx status are counted as errors.
+func InstrumentHandler(feature, operation string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+		next(ww, r)
+
+		observe(LayerHandler, feature, operation, start, ww.Status() >= http.StatusInternalServerError)
+	}
+}
+
+func observe(layer, feature, operation string, start time.Time, failed bool) {
+	requestsTotal.WithLabelValues(layer, feature, operation).Inc()
+	durationSeconds.WithLabelValues(layer, feature, operation).Observe(time.Since(start).Seconds())
+	if failed {
+		errorsTotal.WithLabelValues(layer, feature, operation).Inc()
+	}
+}
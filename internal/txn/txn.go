@@ -0,0 +1,35 @@
+// Package txn runs several repository calls in one database transaction.
+package txn
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/jackc/pgx/v5"
+)
+
+// Beginner starts transactions; *pgxpool.Pool and pgx.Tx both satisfy it.
+type Beginner interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+}
+
+// Run calls fn with queries bound to a new transaction via Queries.WithTx.
+// The transaction commits when fn returns nil and rolls back otherwise;
+// fn's error is returned unchanged so callers can still classify it.
+func Run(ctx context.Context, db Beginner, queries *repository.Queries, fn func(q *repository.Queries) error) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(queries.WithTx(tx)); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,471 @@
+package application
+
+import (
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+	restapplication "github.com/eif-courses/civilregistry/internal/api/application"
+	"github.com/eif-courses/civilregistry/internal/api/attachment"
+	"github.com/eif-courses/civilregistry/internal/api/office"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/eif-courses/civilregistry/internal/web/ui"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// multipartMemory is how much of an upload is kept in memory; the rest
+// spills to a temporary file.
+const multipartMemory = 1 << 20
+
+type Handlers struct {
+	applications *restapplication.Service
+	offices      *office.Service
+	attachments  *attachment.Service
+	links        attachment.Links
+	logger       *zap.SugaredLogger
+}
+
+func NewHandlers(applications *restapplication.Service, offices *office.Service, attachments *attachment.Service, links attachment.Links, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		applications: applications,
+		offices:      offices,
+		attachments:  attachments,
+		links:        links,
+		logger:       logger,
+	}
+}
+
+// NewApplicationPage shows the public application form.
+func (h *Handlers) NewApplicationPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	h.showForm(w, r, ui.ApplicationForm{Kind: r.URL.Query().Get("kind"), Copies: "1"}, http.StatusOK, "")
+}
+
+// Submit handles the application form. Accepted applications show their
+// status page with the number to track them by.
+func (h *Handlers) Submit(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	form := ui.ApplicationForm{
+		Kind:            r.PostFormValue("kind"),
+		OfficeID:        r.PostFormValue("office_id"),
+		ApplicantName:   r.PostFormValue("applicant_name"),
+		ApplicantEmail:  r.PostFormValue("applicant_email"),
+		ApplicantPhone:  r.PostFormValue("applicant_phone"),
+		PersonalCode:    strings.TrimSpace(r.PostFormValue("personal_code")),
+		CertificateKind: r.PostFormValue("certificate_kind"),
+		Copies:          r.PostFormValue("copies"),
+		Purpose:         r.PostFormValue("purpose"),
+		NewFirstName:    r.PostFormValue("new_first_name"),
+		NewLastName:     r.PostFormValue("new_last_name"),
+		Reason:          r.PostFormValue("reason"),
+		Municipality:    r.PostFormValue("municipality"),
+		Street:          r.PostFormValue("street"),
+		House:           r.PostFormValue("house"),
+		Flat:            r.PostFormValue("flat"),
+		PostalCode:      r.PostFormValue("postal_code"),
+		MovedInOn:       r.PostFormValue("moved_in_on"),
+	}
+
+	arg, err := submitParams(form)
+	var c *restapplication.Case
+	if err == nil {
+		c, err = h.applications.Submit(r.Context(), arg)
+	}
+	if err != nil {
+		status, msg := apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to submit application: %v", err)
+		}
+		h.showForm(w, r, form, status, msg)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	h.render(w, r, ui.ApplicationStatusPage(*c, nil, "Your application was submitted. Its number is "+c.Application.Number+".", ""))
+}
+
+// TrackPage shows the tracking form.
+func (h *Handlers) TrackPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	h.render(w, r, ui.TrackApplicationPage(r.URL.Query().Get("number"), "", ""))
+}
+
+// Track shows an application's status to its applicant. The email address
+// travels in the form body so it stays out of URLs and logs.
+func (h *Handlers) Track(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	number := r.PostFormValue("number")
+	email := r.PostFormValue("email")
+
+	c, err := h.applications.Track(r.Context(), number, email)
+	if err != nil {
+		status, msg := apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to track application: %v", err)
+		}
+		w.WriteHeader(status)
+		h.render(w, r, ui.TrackApplicationPage(number, email, msg))
+		return
+	}
+
+	h.showStatus(w, r, c, http.StatusOK, "", "")
+}
+
+// Respond handles the applicant's answer to a request for information.
+func (h *Handlers) Respond(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+	number := r.PostFormValue("number")
+	email := r.PostFormValue("email")
+
+	c, err := h.applications.Respond(r.Context(), number, email, r.PostFormValue("note"))
+	if err != nil {
+		status, msg := apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to answer application: %v", err)
+		}
+		found, trackErr := h.applications.Track(r.Context(), number, email)
+		if trackErr != nil {
+			w.WriteHeader(status)
+			h.render(w, r, ui.TrackApplicationPage(number, email, msg))
+			return
+		}
+		h.showStatus(w, r, found, status, "", msg)
+		return
+	}
+
+	h.showStatus(w, r, c, http.StatusOK, "Your answer was sent to the registrar.", "")
+}
+
+// UploadForApplicant handles the document upload of the status page.
+func (h *Handlers) UploadForApplicant(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	file, header, uploadErr := h.readUpload(w, r)
+	if uploadErr == nil {
+		defer file.Close()
+	}
+	number := r.PostFormValue("number")
+	email := r.PostFormValue("email")
+	if uploadErr != nil && number == "" {
+		// The form did not parse, so there is no application to go back to
+		w.WriteHeader(http.StatusBadRequest)
+		h.render(w, r, ui.TrackApplicationPage("", "", uploadErr.Error()))
+		return
+	}
+	c, err := h.applications.Track(r.Context(), number, email)
+	if err != nil {
+		status, msg := apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to track application: %v", err)
+		}
+		w.WriteHeader(status)
+		h.render(w, r, ui.TrackApplicationPage(number, email, msg))
+		return
+	}
+	if uploadErr != nil {
+		h.showStatus(w, r, c, http.StatusBadRequest, "", uploadErr.Error())
+		return
+	}
+
+	if _, err := h.attachments.UploadForApplicant(r.Context(), number, email, header.Filename, file); err != nil {
+		status, msg := apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to upload attachment: %v", err)
+		}
+		h.showStatus(w, r, c, status, "", msg)
+		return
+	}
+
+	h.showStatus(w, r, c, http.StatusOK, "Your document was added to the application.", "")
+}
+
+// QueuePage shows the signed-in registrar's office queue, filtered by
+// status (default submitted), kind and assignment.
+func (h *Handlers) QueuePage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	query := r.URL.Query()
+	data := ui.ApplicationQueueData{
+		Status:     query.Get("status"),
+		Kind:       query.Get("kind"),
+		Assignment: query.Get("assignment"),
+	}
+	if data.Status == "" {
+		data.Status = restapplication.StatusSubmitted
+	}
+
+	counts, err := h.applications.CountByStatus(r.Context())
+	if err != nil {
+		h.clerkError(w, r, err)
+		return
+	}
+	data.Counts = counts
+
+	status := http.StatusOK
+	data.Applications, err = h.applications.Queue(r.Context(), restapplication.QueueFilter{
+		Status:     pgtype.Text{String: data.Status, Valid: true},
+		Kind:       pgtype.Text{String: data.Kind, Valid: data.Kind != ""},
+		Mine:       data.Assignment == "mine",
+		Unassigned: data.Assignment == "unassigned",
+	})
+	if err != nil {
+		var msg string
+		status, msg = apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to list applications: %v", err)
+		}
+		data.ErrMsg = msg
+	}
+
+	w.WriteHeader(status)
+	h.render(w, r, ui.ApplicationQueuePage(data))
+}
+
+// DetailPage shows an application of the registrar's office.
+func (h *Handlers) DetailPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.showDetail(w, r, id, http.StatusOK, "")
+}
+
+// Transition handles the status buttons of the detail page.
+func (h *Handlers) Transition(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	_, err = h.applications.Transition(r.Context(), id, restapplication.TransitionParams{
+		Status: r.PostFormValue("status"),
+		Note:   r.PostFormValue("note"),
+	})
+	if err != nil {
+		if errors.Is(err, apperr.ErrUnauthorized) {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+		status, msg := apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to change application status: %v", err)
+		}
+		h.showDetail(w, r, id, status, msg)
+		return
+	}
+
+	http.Redirect(w, r, "/applications/"+id.String(), http.StatusSeeOther)
+}
+
+// Upload handles the document upload of the detail page.
+func (h *Handlers) Upload(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	file, header, err := h.readUpload(w, r)
+	if err != nil {
+		h.showDetail(w, r, id, http.StatusBadRequest, err.Error())
+		return
+	}
+	defer file.Close()
+
+	_, err = h.attachments.Upload(r.Context(), attachment.UploadParams{
+		OwnerKind: attachment.OwnerApplication,
+		OwnerID:   id,
+		Filename:  header.Filename,
+		Content:   file,
+	})
+	if err != nil {
+		if errors.Is(err, apperr.ErrUnauthorized) {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+		status, msg := apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to upload attachment: %v", err)
+		}
+		h.showDetail(w, r, id, status, msg)
+		return
+	}
+
+	http.Redirect(w, r, "/applications/"+id.String(), http.StatusSeeOther)
+}
+
+// readUpload parses a multipart form no larger than the attachment limit
+// plus room for the other fields and returns its "file" part. Its errors
+// are meant for the page.
+func (h *Handlers) readUpload(w http.ResponseWriter, r *http.Request) (multipart.File, *multipart.FileHeader, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, h.attachments.MaxSize()+multipartMemory)
+	if err := r.ParseMultipartForm(multipartMemory); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return nil, nil, fmt.Errorf("the file is larger than %d MB", h.attachments.MaxSize()>>20)
+		}
+		return nil, nil, errors.New("the upload could not be read")
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, nil, errors.New("choose a file to upload")
+	}
+	return file, header, nil
+}
+
+// showStatus renders the applicant's status page with the documents they
+// can see.
+func (h *Handlers) showStatus(w http.ResponseWriter, r *http.Request, c *restapplication.Case, status int, notice, errMsg string) {
+	rows, err := h.attachments.ListForApplicant(r.Context(), c.Application.Number, c.Application.ApplicantEmail)
+	if err != nil {
+		h.logger.Errorf("Failed to list attachments: %v", err)
+	}
+
+	w.WriteHeader(status)
+	h.render(w, r, ui.ApplicationStatusPage(*c, attachment.NewApplicantResponses(rows, h.links), notice, errMsg))
+}
+
+func (h *Handlers) showForm(w http.ResponseWriter, r *http.Request, form ui.ApplicationForm, status int, errMsg string) {
+	offices, err := h.offices.ListOffices(r.Context())
+	if err != nil {
+		h.logger.Errorf("Failed to list offices: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(status)
+	h.render(w, r, ui.NewApplicationPage(offices, form, errMsg))
+}
+
+func (h *Handlers) showDetail(w http.ResponseWriter, r *http.Request, id uuid.UUID, status int, errMsg string) {
+	c, err := h.applications.Get(r.Context(), id)
+	if err != nil {
+		h.clerkError(w, r, err)
+		return
+	}
+
+	rows, err := h.attachments.List(r.Context(), attachment.OwnerApplication, id)
+	if err != nil {
+		h.clerkError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(status)
+	h.render(w, r, ui.ApplicationDetailPage(*c, attachment.NewAttachmentResponses(rows, h.links), errMsg))
+}
+
+// clerkError answers a failed clerk page; callers who are not signed in
+// are sent to the sign-in page.
+func (h *Handlers) clerkError(w http.ResponseWriter, r *http.Request, err error) {
+	status, msg := apperr.Status(err)
+	switch {
+	case errors.Is(err, apperr.ErrUnauthorized):
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+	case status == http.StatusNotFound:
+		http.NotFound(w, r)
+	case status >= http.StatusInternalServerError:
+		h.logger.Errorf("Failed to load applications: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	default:
+		http.Error(w, msg, status)
+	}
+}
+
+// submitParams reads the application form, keeping the section of the
+// chosen kind.
+func submitParams(form ui.ApplicationForm) (restapplication.SubmitParams, error) {
+	officeID, err := uuid.Parse(form.OfficeID)
+	if err != nil {
+		return restapplication.SubmitParams{}, apperr.Invalid("choose an office")
+	}
+	arg := restapplication.SubmitParams{
+		Kind:           form.Kind,
+		OfficeID:       officeID,
+		ApplicantName:  form.ApplicantName,
+		ApplicantEmail: form.ApplicantEmail,
+		ApplicantPhone: form.ApplicantPhone,
+		PersonalCode:   form.PersonalCode,
+	}
+
+	switch form.Kind {
+	case restapplication.KindCertificateRequest:
+		copies := 1
+		if form.Copies != "" {
+			if copies, err = strconv.Atoi(form.Copies); err != nil {
+				return restapplication.SubmitParams{}, apperr.Invalid("copies must be a number")
+			}
+		}
+		arg.Details = restapplication.Details{
+			CertificateKind: form.CertificateKind,
+			Copies:          copies,
+			Purpose:         form.Purpose,
+		}
+	case restapplication.KindNameChange:
+		arg.Details = restapplication.Details{
+			NewFirstName: form.NewFirstName,
+			NewLastName:  form.NewLastName,
+			Reason:       form.Reason,
+		}
+	case restapplication.KindResidenceDeclaration:
+		arg.Details = restapplication.Details{
+			Municipality: form.Municipality,
+			Street:       form.Street,
+			House:        form.House,
+			Flat:         form.Flat,
+			PostalCode:   form.PostalCode,
+		}
+		if form.MovedInOn != "" {
+			t, err := time.Parse(time.DateOnly, form.MovedInOn)
+			if err != nil {
+				return restapplication.SubmitParams{}, apperr.Invalid("moved in on must be YYYY-MM-DD")
+			}
+			d := render.Date(t)
+			arg.Details.MovedInOn = &d
+		}
+	}
+	return arg, nil
+}
+
+func (h *Handlers) render(w http.ResponseWriter, r *http.Request, component templ.Component) {
+	if err := component.Render(r.Context(), w); err != nil {
+		h.logger.Errorf("Failed to render page: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
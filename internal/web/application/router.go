@@ -0,0 +1,31 @@
+package application
+
+import (
+	restapplication "github.com/eif-courses/civilregistry/internal/api/application"
+	"github.com/eif-courses/civilregistry/internal/api/attachment"
+	"github.com/eif-courses/civilregistry/internal/api/office"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/storage"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func SetupRoutes(r chi.Router, db txn.Beginner, queries *repository.Queries, store storage.BlobStore, opts attachment.Options, log *zap.SugaredLogger) {
+	attachments := attachment.NewService(db, queries, store, opts, log)
+	handlers := NewHandlers(restapplication.NewService(db, queries, log), office.NewService(queries, log), attachments, opts.Links, log)
+
+	// Public application and tracking pages
+	r.Get("/applications/new", handlers.NewApplicationPage)
+	r.Post("/applications", handlers.Submit)
+	r.Get("/applications/track", handlers.TrackPage)
+	r.Post("/applications/track", handlers.Track)
+	r.Post("/applications/track/respond", handlers.Respond)
+	r.Post("/applications/track/attachments", handlers.UploadForApplicant)
+
+	// Clerk queue
+	r.Get("/applications", handlers.QueuePage)
+	r.Get("/applications/{id}", handlers.DetailPage)
+	r.Post("/applications/{id}/status", handlers.Transition)
+	r.Post("/applications/{id}/attachments", handlers.Upload)
+}
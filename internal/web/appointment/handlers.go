@@ -0,0 +1,343 @@
+package appointment
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+	restappointment "github.com/eif-courses/civilregistry/internal/api/appointment"
+	"github.com/eif-courses/civilregistry/internal/api/office"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/web/ui"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	appointments *restappointment.Service
+	offices      *office.Service
+	logger       *zap.SugaredLogger
+}
+
+func NewHandlers(appointments *restappointment.Service, offices *office.Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		appointments: appointments,
+		offices:      offices,
+		logger:       logger,
+	}
+}
+
+// AppointmentsPage lists free slots, filtered by office, kind and first day.
+func (h *Handlers) AppointmentsPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	query := r.URL.Query()
+	data := ui.AppointmentsPageData{
+		OfficeID: query.Get("office_id"),
+		Kind:     query.Get("kind"),
+		From:     query.Get("from"),
+	}
+
+	offices, err := h.offices.ListOffices(r.Context())
+	if err != nil {
+		h.logger.Errorf("Failed to list offices: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	data.Offices = offices
+
+	status := http.StatusOK
+	data.Slots, err = h.search(r, data)
+	if err != nil {
+		var msg string
+		status, msg = apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to list slots: %v", err)
+		}
+		data.ErrMsg = msg
+	}
+
+	w.WriteHeader(status)
+	h.render(w, r, ui.AppointmentsPage(data))
+}
+
+func (h *Handlers) search(r *http.Request, data ui.AppointmentsPageData) ([]repository.AppointmentSlot, error) {
+	filter := restappointment.SlotFilter{
+		Kind: pgtype.Text{String: data.Kind, Valid: data.Kind != ""},
+	}
+	if data.OfficeID != "" {
+		id, err := uuid.Parse(data.OfficeID)
+		if err != nil {
+			return nil, apperr.Invalid("unknown office")
+		}
+		filter.OfficeID = pgtype.UUID{Bytes: id, Valid: true}
+	}
+	if data.From != "" {
+		from, err := time.Parse(time.DateOnly, data.From)
+		if err != nil {
+			return nil, apperr.Invalid("date must be YYYY-MM-DD")
+		}
+		filter.From = pgtype.Date{Time: from, Valid: true}
+	}
+	return h.appointments.ListAvailableSlots(r.Context(), filter)
+}
+
+// BookSlotPage shows the booking form for a slot.
+func (h *Handlers) BookSlotPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.showSlot(w, r, id, ui.BookingForm{}, http.StatusOK, "")
+}
+
+// Book handles the booking form. Rejected bookings show the form again
+// with the service's message; accepted ones go to the booking's page.
+func (h *Handlers) Book(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	form := ui.BookingForm{
+		FullName:     r.PostFormValue("full_name"),
+		Email:        r.PostFormValue("email"),
+		Phone:        r.PostFormValue("phone"),
+		PersonalCode: strings.TrimSpace(r.PostFormValue("personal_code")),
+		Notes:        r.PostFormValue("notes"),
+	}
+
+	booking, err := h.appointments.Book(r.Context(), restappointment.BookParams{
+		SlotID:       id,
+		FullName:     form.FullName,
+		Email:        form.Email,
+		Phone:        form.Phone,
+		PersonalCode: pgtype.Text{String: form.PersonalCode, Valid: form.PersonalCode != ""},
+		Notes:        form.Notes,
+	})
+	if err != nil {
+		status, msg := apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to book appointment: %v", err)
+		}
+		h.showSlot(w, r, id, form, status, msg)
+		return
+	}
+
+	http.Redirect(w, r, "/appointments/"+booking.Appointment.Reference, http.StatusSeeOther)
+}
+
+// BookingPage shows a booking by its reference.
+func (h *Handlers) BookingPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	h.showBooking(w, r, chi.URLParam(r, "reference"), http.StatusOK, "")
+}
+
+// CancelBooking cancels a booking from its page.
+func (h *Handlers) CancelBooking(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	reference := chi.URLParam(r, "reference")
+	if _, err := h.appointments.Cancel(r.Context(), reference); err != nil {
+		status, msg := apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to cancel booking: %v", err)
+		}
+		h.showBooking(w, r, reference, status, msg)
+		return
+	}
+
+	http.Redirect(w, r, "/appointments/"+reference, http.StatusSeeOther)
+}
+
+// CalendarPage shows the signed-in registrar's office calendar for a week.
+func (h *Handlers) CalendarPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	h.showCalendar(w, r, r.URL.Query().Get("from"), ui.SlotForm{Capacity: "1"}, http.StatusOK, "")
+}
+
+// CreateSlot handles the calendar's add slot form.
+func (h *Handlers) CreateSlot(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	form := ui.SlotForm{
+		Kind:      r.PostFormValue("kind"),
+		Date:      r.PostFormValue("date"),
+		StartTime: r.PostFormValue("start_time"),
+		EndTime:   r.PostFormValue("end_time"),
+		Capacity:  r.PostFormValue("capacity"),
+	}
+	from := r.PostFormValue("from")
+
+	arg, err := slotParams(form)
+	if err == nil {
+		_, err = h.appointments.CreateSlot(r.Context(), arg)
+	}
+	if err != nil {
+		status, msg := apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to create slot: %v", err)
+		}
+		h.showCalendar(w, r, from, form, status, msg)
+		return
+	}
+
+	http.Redirect(w, r, calendarURL(from), http.StatusSeeOther)
+}
+
+// DeleteSlot removes an unbooked slot from the calendar.
+func (h *Handlers) DeleteSlot(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	from := r.PostFormValue("from")
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	if err := h.appointments.DeleteSlot(r.Context(), id); err != nil {
+		status, msg := apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to delete slot: %v", err)
+		}
+		h.showCalendar(w, r, from, ui.SlotForm{Capacity: "1"}, status, msg)
+		return
+	}
+
+	http.Redirect(w, r, calendarURL(from), http.StatusSeeOther)
+}
+
+// CancelCalendarBooking cancels a booking from the calendar.
+func (h *Handlers) CancelCalendarBooking(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	from := r.PostFormValue("from")
+	if _, err := h.appointments.Cancel(r.Context(), chi.URLParam(r, "reference")); err != nil {
+		status, msg := apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to cancel booking: %v", err)
+		}
+		h.showCalendar(w, r, from, ui.SlotForm{Capacity: "1"}, status, msg)
+		return
+	}
+
+	http.Redirect(w, r, calendarURL(from), http.StatusSeeOther)
+}
+
+func (h *Handlers) showSlot(w http.ResponseWriter, r *http.Request, id uuid.UUID, form ui.BookingForm, status int, errMsg string) {
+	slot, o, err := h.appointments.GetSlot(r.Context(), id)
+	if err != nil {
+		if status, _ := apperr.Status(err); status == http.StatusNotFound {
+			http.NotFound(w, r)
+			return
+		}
+		h.logger.Errorf("Failed to get slot: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(status)
+	h.render(w, r, ui.BookSlotPage(*slot, *o, form, errMsg))
+}
+
+func (h *Handlers) showBooking(w http.ResponseWriter, r *http.Request, reference string, status int, errMsg string) {
+	booking, err := h.appointments.GetBooking(r.Context(), reference)
+	if err != nil {
+		if status, _ := apperr.Status(err); status == http.StatusNotFound {
+			http.NotFound(w, r)
+			return
+		}
+		h.logger.Errorf("Failed to get booking: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(status)
+	h.render(w, r, ui.BookingPage(*booking, errMsg))
+}
+
+// showCalendar renders the calendar; callers who are not signed in are sent
+// to the sign-in page.
+func (h *Handlers) showCalendar(w http.ResponseWriter, r *http.Request, from string, form ui.SlotForm, status int, errMsg string) {
+	var day pgtype.Date
+	if t, err := time.Parse(time.DateOnly, from); err == nil {
+		day = pgtype.Date{Time: t, Valid: true}
+	}
+
+	cal, err := h.appointments.Calendar(r.Context(), day)
+	if err != nil {
+		calStatus, msg := apperr.Status(err)
+		switch {
+		case errors.Is(err, apperr.ErrUnauthorized):
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+		case calStatus >= http.StatusInternalServerError:
+			h.logger.Errorf("Failed to load calendar: %v", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		default:
+			http.Error(w, msg, calStatus)
+		}
+		return
+	}
+
+	w.WriteHeader(status)
+	h.render(w, r, ui.CalendarPage(*cal, form, errMsg))
+}
+
+// slotParams reads the add slot form, whose times are local to the office.
+func slotParams(form ui.SlotForm) (restappointment.CreateSlotParams, error) {
+	starts, err := time.ParseInLocation("2006-01-02 15:04", form.Date+" "+form.StartTime, time.Local)
+	if err != nil {
+		return restappointment.CreateSlotParams{}, apperr.Invalid("date must be YYYY-MM-DD and times HH:MM")
+	}
+	ends, err := time.ParseInLocation("2006-01-02 15:04", form.Date+" "+form.EndTime, time.Local)
+	if err != nil {
+		return restappointment.CreateSlotParams{}, apperr.Invalid("date must be YYYY-MM-DD and times HH:MM")
+	}
+	capacity, err := strconv.ParseInt(form.Capacity, 10, 32)
+	if err != nil {
+		return restappointment.CreateSlotParams{}, apperr.Invalid("places must be a number")
+	}
+	return restappointment.CreateSlotParams{
+		Kind:     form.Kind,
+		StartsAt: starts,
+		EndsAt:   ends,
+		Capacity: int32(capacity),
+	}, nil
+}
+
+func calendarURL(from string) string {
+	if _, err := time.Parse(time.DateOnly, from); err != nil {
+		return "/calendar"
+	}
+	return "/calendar?from=" + from
+}
+
+func (h *Handlers) render(w http.ResponseWriter, r *http.Request, component templ.Component) {
+	if err := component.Render(r.Context(), w); err != nil {
+		h.logger.Errorf("Failed to render page: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,27 @@
+package appointment
+
+import (
+	restappointment "github.com/eif-courses/civilregistry/internal/api/appointment"
+	"github.com/eif-courses/civilregistry/internal/api/office"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func SetupRoutes(r chi.Router, db txn.Beginner, queries *repository.Queries, log *zap.SugaredLogger) {
+	handlers := NewHandlers(restappointment.NewService(db, queries, log), office.NewService(queries, log), log)
+
+	// Public booking pages
+	r.Get("/appointments", handlers.AppointmentsPage)
+	r.Get("/appointments/slots/{id}", handlers.BookSlotPage)
+	r.Post("/appointments/slots/{id}", handlers.Book)
+	r.Get("/appointments/{reference}", handlers.BookingPage)
+	r.Post("/appointments/{reference}/cancel", handlers.CancelBooking)
+
+	// Clerk calendar
+	r.Get("/calendar", handlers.CalendarPage)
+	r.Post("/calendar/slots", handlers.CreateSlot)
+	r.Post("/calendar/slots/{id}/delete", handlers.DeleteSlot)
+	r.Post("/calendar/bookings/{reference}/cancel", handlers.CancelCalendarBooking)
+}
@@ -0,0 +1,183 @@
+package birth
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+	restbirth "github.com/eif-courses/civilregistry/internal/api/birth"
+	"github.com/eif-courses/civilregistry/internal/api/foreign"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/web/ui"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	births  *restbirth.Service
+	persons *person.Service
+	logger  *zap.SugaredLogger
+}
+
+func NewHandlers(births *restbirth.Service, persons *person.Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		births:  births,
+		persons: persons,
+		logger:  logger,
+	}
+}
+
+func (h *Handlers) BirthsPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	records, err := h.births.ListBirthRecords(r.Context(), foreign.Filter{}, 0, 0)
+	if errors.Is(err, apperr.ErrUnauthorized) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		h.logger.Errorf("Failed to list births: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	h.render(w, r, ui.BirthsPage(records))
+}
+
+func (h *Handlers) NewBirthPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	h.render(w, r, ui.BirthFormPage(ui.BirthForm{}, ""))
+}
+
+// CreateBirth handles the registration form. Rejected registrations show
+// the form again with the service's message and the submitted values.
+func (h *Handlers) CreateBirth(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	form := ui.BirthForm{
+		PersonalCode: strings.TrimSpace(r.PostFormValue("personal_code")),
+		FirstName:    r.PostFormValue("first_name"),
+		LastName:     r.PostFormValue("last_name"),
+		BirthDate:    r.PostFormValue("birth_date"),
+		BirthPlace:   r.PostFormValue("birth_place"),
+		Sex:          r.PostFormValue("sex"),
+		MotherCode:   strings.TrimSpace(r.PostFormValue("mother_code")),
+		FatherCode:   strings.TrimSpace(r.PostFormValue("father_code")),
+	}
+
+	reg, err := h.register(r, form)
+	if errors.Is(err, apperr.ErrUnauthorized) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		status, msg := apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to register birth: %v", err)
+		}
+		w.WriteHeader(status)
+		h.render(w, r, ui.BirthFormPage(form, msg))
+		return
+	}
+
+	http.Redirect(w, r, "/births/"+reg.Record.ID.String(), http.StatusSeeOther)
+}
+
+func (h *Handlers) BirthPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	reg, err := h.births.GetBirthRegistration(r.Context(), id)
+	if errors.Is(err, apperr.ErrUnauthorized) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		if status, _ := apperr.Status(err); status == http.StatusNotFound {
+			http.NotFound(w, r)
+			return
+		}
+		h.logger.Errorf("Failed to get birth record: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	h.render(w, r, ui.BirthDetailPage(reg.Record, reg.Child, reg.Mother, reg.Father, reg.Document))
+}
+
+func (h *Handlers) register(r *http.Request, form ui.BirthForm) (*restbirth.BirthRegistration, error) {
+	birthDate, err := time.Parse(time.DateOnly, form.BirthDate)
+	if err != nil {
+		return nil, apperr.Invalid("birth date must be YYYY-MM-DD")
+	}
+
+	mother, err := h.parentID(r, form.MotherCode, "mother")
+	if err != nil {
+		return nil, err
+	}
+	father, err := h.parentID(r, form.FatherCode, "father")
+	if err != nil {
+		return nil, err
+	}
+
+	return h.births.RegisterBirth(r.Context(), restbirth.RegisterBirthParams{
+		Child: repository.CreatePersonParams{
+			PersonalCode: form.PersonalCode,
+			FirstName:    form.FirstName,
+			LastName:     form.LastName,
+			BirthDate:    pgtype.Date{Time: birthDate, Valid: true},
+			BirthPlace:   pgtype.Text{String: form.BirthPlace, Valid: form.BirthPlace != ""},
+			Sex:          form.Sex,
+		},
+		MotherID: mother,
+		FatherID: father,
+	})
+}
+
+// parentID resolves a parent's personal code from the form; empty means
+// the parent is not recorded.
+func (h *Handlers) parentID(r *http.Request, code, role string) (pgtype.UUID, error) {
+	if code == "" {
+		return pgtype.UUID{}, nil
+	}
+
+	p, err := h.persons.GetPersonByPersonalCode(r.Context(), code)
+	if errors.Is(err, apperr.ErrUnauthorized) {
+		return pgtype.UUID{}, err
+	}
+	if err != nil {
+		var appErr *apperr.Error
+		if errors.As(err, &appErr) {
+			return pgtype.UUID{}, apperr.Invalid("%s: %s", role, appErr.Error())
+		}
+		if status, _ := apperr.Status(err); status == http.StatusNotFound {
+			return pgtype.UUID{}, apperr.Invalid("no person with personal code %s (%s)", code, role)
+		}
+		return pgtype.UUID{}, fmt.Errorf("look up %s: %w", role, err)
+	}
+	return pgtype.UUID{Bytes: p.ID, Valid: true}, nil
+}
+
+func (h *Handlers) render(w http.ResponseWriter, r *http.Request, component templ.Component) {
+	if err := component.Render(r.Context(), w); err != nil {
+		h.logger.Errorf("Failed to render page: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
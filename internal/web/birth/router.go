@@ -0,0 +1,20 @@
+package birth
+
+import (
+	restbirth "github.com/eif-courses/civilregistry/internal/api/birth"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func SetupRoutes(r chi.Router, db txn.Beginner, queries *repository.Queries, log *zap.SugaredLogger) {
+	handlers := NewHandlers(restbirth.NewService(db, queries, log), person.NewService(db, queries, log), log)
+
+	// Web routes
+	r.Get("/births", handlers.BirthsPage)
+	r.Get("/births/new", handlers.NewBirthPage)
+	r.Post("/births", handlers.CreateBirth)
+	r.Get("/births/{id}", handlers.BirthPage)
+}
@@ -0,0 +1,68 @@
+package certificate
+
+import (
+	"net/http"
+
+	"github.com/a-h/templ"
+	restcertificate "github.com/eif-courses/civilregistry/internal/api/certificate"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/web/ui"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	certificates *restcertificate.Service
+	verification restcertificate.Verification
+	logger       *zap.SugaredLogger
+}
+
+func NewHandlers(certificates *restcertificate.Service, verification restcertificate.Verification, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		certificates: certificates,
+		verification: verification,
+		logger:       logger,
+	}
+}
+
+// VerifyPage is the public page a certificate's QR code points to.
+func (h *Handlers) VerifyPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	w.Header().Set("Cache-Control", "no-store")
+
+	id, err := h.verification.Parse(chi.URLParam(r, "token"))
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		h.render(w, r, ui.VerifyPage(nil, ""))
+		return
+	}
+
+	cert, err := h.certificates.VerifyCertificate(r.Context(), id)
+	if err != nil {
+		status, _ := apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to verify certificate: %v", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(status)
+		h.render(w, r, ui.VerifyPage(nil, ""))
+		return
+	}
+
+	content, err := restcertificate.DecodeContent(*cert)
+	if err != nil {
+		h.logger.Errorf("Failed to verify certificate: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	h.render(w, r, ui.VerifyPage(cert, content.Title))
+}
+
+func (h *Handlers) render(w http.ResponseWriter, r *http.Request, component templ.Component) {
+	if err := component.Render(r.Context(), w); err != nil {
+		h.logger.Errorf("Failed to render page: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,15 @@
+package certificate
+
+import (
+	restcertificate "github.com/eif-courses/civilregistry/internal/api/certificate"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func SetupRoutes(r chi.Router, queries *repository.Queries, verification restcertificate.Verification, log *zap.SugaredLogger) {
+	handlers := NewHandlers(restcertificate.NewService(queries, log), verification, log)
+
+	// Web routes
+	r.Get("/verify/{token}", handlers.VerifyPage)
+}
@@ -0,0 +1,172 @@
+package death
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+	restdeath "github.com/eif-courses/civilregistry/internal/api/death"
+	"github.com/eif-courses/civilregistry/internal/api/foreign"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/web/ui"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	deaths  *restdeath.Service
+	persons *person.Service
+	logger  *zap.SugaredLogger
+}
+
+func NewHandlers(deaths *restdeath.Service, persons *person.Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		deaths:  deaths,
+		persons: persons,
+		logger:  logger,
+	}
+}
+
+func (h *Handlers) DeathsPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	records, err := h.deaths.ListDeathRecords(r.Context(), foreign.Filter{}, 0, 0)
+	if errors.Is(err, apperr.ErrUnauthorized) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		h.logger.Errorf("Failed to list deaths: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	h.render(w, r, ui.DeathsPage(records))
+}
+
+func (h *Handlers) NewDeathPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	h.render(w, r, ui.DeathFormPage(ui.DeathForm{}, ""))
+}
+
+// CreateDeath handles the registration form. Rejected registrations show
+// the form again with the service's message and the submitted values.
+func (h *Handlers) CreateDeath(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	form := ui.DeathForm{
+		PersonalCode:  strings.TrimSpace(r.PostFormValue("personal_code")),
+		DateOfDeath:   r.PostFormValue("date_of_death"),
+		PlaceOfDeath:  r.PostFormValue("place_of_death"),
+		CauseCode:     r.PostFormValue("cause_code"),
+		InformantName: r.PostFormValue("informant_name"),
+		InformantCode: strings.TrimSpace(r.PostFormValue("informant_code")),
+	}
+
+	reg, err := h.register(r, form)
+	if errors.Is(err, apperr.ErrUnauthorized) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		status, msg := apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to register death: %v", err)
+		}
+		w.WriteHeader(status)
+		h.render(w, r, ui.DeathFormPage(form, msg))
+		return
+	}
+
+	http.Redirect(w, r, "/deaths/"+reg.Record.ID.String(), http.StatusSeeOther)
+}
+
+func (h *Handlers) DeathPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	reg, err := h.deaths.GetDeathRegistration(r.Context(), id)
+	if errors.Is(err, apperr.ErrUnauthorized) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		if status, _ := apperr.Status(err); status == http.StatusNotFound {
+			http.NotFound(w, r)
+			return
+		}
+		h.logger.Errorf("Failed to get death record: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	h.render(w, r, ui.DeathDetailPage(reg.Record, reg.Deceased, reg.Document))
+}
+
+func (h *Handlers) register(r *http.Request, form ui.DeathForm) (*restdeath.DeathRegistration, error) {
+	dateOfDeath, err := time.Parse(time.DateOnly, form.DateOfDeath)
+	if err != nil {
+		return nil, apperr.Invalid("date of death must be YYYY-MM-DD")
+	}
+
+	deceased, err := h.personID(r, form.PersonalCode)
+	if err != nil {
+		return nil, err
+	}
+
+	var informant pgtype.UUID
+	if form.InformantCode != "" {
+		id, err := h.personID(r, form.InformantCode)
+		if err != nil {
+			return nil, err
+		}
+		informant = pgtype.UUID{Bytes: id, Valid: true}
+	}
+
+	return h.deaths.RegisterDeath(r.Context(), restdeath.RegisterDeathParams{
+		PersonID:          deceased,
+		DateOfDeath:       pgtype.Date{Time: dateOfDeath, Valid: true},
+		PlaceOfDeath:      form.PlaceOfDeath,
+		CauseCode:         form.CauseCode,
+		InformantName:     form.InformantName,
+		InformantPersonID: informant,
+	})
+}
+
+func (h *Handlers) personID(r *http.Request, code string) (uuid.UUID, error) {
+	p, err := h.persons.GetPersonByPersonalCode(r.Context(), code)
+	if err != nil {
+		status, msg := apperr.Status(err)
+		switch status {
+		case http.StatusNotFound:
+			return uuid.Nil, apperr.Invalid("no person with personal code %s", code)
+		case http.StatusBadRequest:
+			return uuid.Nil, apperr.Invalid("%s", msg)
+		}
+		return uuid.Nil, fmt.Errorf("look up %s: %w", code, err)
+	}
+	return p.ID, nil
+}
+
+func (h *Handlers) render(w http.ResponseWriter, r *http.Request, component templ.Component) {
+	if err := component.Render(r.Context(), w); err != nil {
+		h.logger.Errorf("Failed to render page: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,20 @@
+package death
+
+import (
+	restdeath "github.com/eif-courses/civilregistry/internal/api/death"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func SetupRoutes(r chi.Router, db txn.Beginner, queries *repository.Queries, log *zap.SugaredLogger) {
+	handlers := NewHandlers(restdeath.NewService(db, queries, log), person.NewService(db, queries, log), log)
+
+	// Web routes
+	r.Get("/deaths", handlers.DeathsPage)
+	r.Get("/deaths/new", handlers.NewDeathPage)
+	r.Post("/deaths", handlers.CreateDeath)
+	r.Get("/deaths/{id}", handlers.DeathPage)
+}
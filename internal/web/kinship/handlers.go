@@ -0,0 +1,114 @@
+package kinship
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/eif-courses/civilregistry/internal/api/kinship"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/web/ui"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+const defaultGenerations = 3
+
+type Handlers struct {
+	persons   *person.Service
+	relations *kinship.Service
+	logger    *zap.SugaredLogger
+}
+
+func NewHandlers(persons *person.Service, relations *kinship.Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		persons:   persons,
+		relations: relations,
+		logger:    logger,
+	}
+}
+
+// FamilyTreePage shows a person's family tree. A relative's personal code
+// in the query adds the relationship between the two below it.
+func (h *Handlers) FamilyTreePage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	status, errMsg := http.StatusOK, ""
+	generations := int32(defaultGenerations)
+	if value := r.URL.Query().Get("generations"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > kinship.MaxGenerations {
+			status, errMsg = http.StatusBadRequest, "generations must be between 1 and "+strconv.Itoa(kinship.MaxGenerations)
+		} else {
+			generations = int32(n)
+		}
+	}
+
+	tree, err := h.relations.FamilyTree(r.Context(), id, generations)
+	if errors.Is(err, apperr.ErrUnauthorized) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		if status, _ := apperr.Status(err); status == http.StatusNotFound {
+			http.NotFound(w, r)
+			return
+		}
+		h.logger.Errorf("Failed to load family tree: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	data := ui.FamilyTreePageData{
+		Layout:       layout(*tree),
+		Generations:  generations,
+		RelativeCode: strings.TrimSpace(r.URL.Query().Get("relative")),
+	}
+	for _, n := range tree.Nodes {
+		if n.Person.ID == id {
+			data.Person = n.Person
+		}
+	}
+
+	if data.RelativeCode != "" && errMsg == "" {
+		data.Relationship, err = h.relationship(r, id, data.RelativeCode)
+		if err != nil {
+			status, errMsg = apperr.Status(err)
+			if status >= http.StatusInternalServerError {
+				h.logger.Errorf("Failed to find relationship: %v", err)
+			}
+		}
+	}
+
+	w.WriteHeader(status)
+	h.render(w, r, ui.FamilyTreePage(data, errMsg))
+}
+
+func (h *Handlers) relationship(r *http.Request, id uuid.UUID, code string) (*kinship.Relationship, error) {
+	relative, err := h.persons.GetPersonByPersonalCode(r.Context(), code)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, apperr.NotFound("no person with personal code %s", code)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return h.relations.FindRelationship(r.Context(), id, relative.ID)
+}
+
+func (h *Handlers) render(w http.ResponseWriter, r *http.Request, component templ.Component) {
+	if err := component.Render(r.Context(), w); err != nil {
+		h.logger.Errorf("Failed to render page: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
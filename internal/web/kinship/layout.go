@@ -0,0 +1,115 @@
+package kinship
+
+import (
+	"github.com/eif-courses/civilregistry/internal/api/kinship"
+	"github.com/eif-courses/civilregistry/internal/api/marriage"
+	"github.com/eif-courses/civilregistry/internal/web/ui"
+	"github.com/google/uuid"
+)
+
+const (
+	boxWidth  = 170
+	boxHeight = 56
+	gapX      = 30
+	rowHeight = 110
+	margin    = 20
+)
+
+// layout places the tree on a grid: one row per generation, oldest at the
+// top, with each row centred and spouses kept side by side.
+func layout(tree kinship.Tree) ui.TreeLayout {
+	spouses := make(map[uuid.UUID][]uuid.UUID)
+	for _, e := range tree.Edges {
+		if e.Kind == kinship.EdgeSpouse {
+			spouses[e.From] = append(spouses[e.From], e.To)
+			spouses[e.To] = append(spouses[e.To], e.From)
+		}
+	}
+
+	// Nodes arrive grouped by generation, oldest first
+	var rows [][]kinship.Node
+	byID := make(map[uuid.UUID]kinship.Node, len(tree.Nodes))
+	for i, n := range tree.Nodes {
+		if i == 0 || n.Generation != tree.Nodes[i-1].Generation {
+			rows = append(rows, nil)
+		}
+		rows[len(rows)-1] = append(rows[len(rows)-1], n)
+		byID[n.Person.ID] = n
+	}
+
+	var ordered [][]kinship.Node
+	widest := 0
+	for _, row := range rows {
+		placed := make(map[uuid.UUID]bool, len(row))
+		var line []kinship.Node
+		for _, n := range row {
+			if placed[n.Person.ID] {
+				continue
+			}
+			line = append(line, n)
+			placed[n.Person.ID] = true
+			for _, id := range spouses[n.Person.ID] {
+				if s, ok := byID[id]; ok && s.Generation == n.Generation && !placed[id] {
+					line = append(line, s)
+					placed[id] = true
+				}
+			}
+		}
+		ordered = append(ordered, line)
+		widest = max(widest, len(line))
+	}
+
+	width := 2*margin + widest*boxWidth + max(widest-1, 0)*gapX
+	out := ui.TreeLayout{
+		Width:  width,
+		Height: 2*margin + len(ordered)*rowHeight - (rowHeight - boxHeight),
+	}
+	boxes := make(map[uuid.UUID]ui.TreeBox, len(tree.Nodes))
+	for r, line := range ordered {
+		rowWidth := len(line)*boxWidth + (len(line)-1)*gapX
+		x := (width - rowWidth) / 2
+		for _, n := range line {
+			box := ui.TreeBox{
+				Person: n.Person,
+				X:      x,
+				Y:      margin + r*rowHeight,
+				Width:  boxWidth,
+				Height: boxHeight,
+				Root:   n.Person.ID == tree.Root,
+			}
+			boxes[n.Person.ID] = box
+			out.Boxes = append(out.Boxes, box)
+			x += boxWidth + gapX
+		}
+	}
+
+	for _, e := range tree.Edges {
+		from, okFrom := boxes[e.From]
+		to, okTo := boxes[e.To]
+		if !okFrom || !okTo {
+			continue
+		}
+		if e.Kind == kinship.EdgeSpouse {
+			// Draw left to right between the facing sides
+			if from.X > to.X {
+				from, to = to, from
+			}
+			out.Links = append(out.Links, ui.TreeLink{
+				X1:     from.X + from.Width,
+				Y1:     from.Y + from.Height/2,
+				X2:     to.X,
+				Y2:     to.Y + to.Height/2,
+				Spouse: true,
+				Ended:  e.Status == marriage.StatusDivorced || e.Status == marriage.StatusAnnulled,
+			})
+			continue
+		}
+		out.Links = append(out.Links, ui.TreeLink{
+			X1: from.X + from.Width/2,
+			Y1: from.Y + from.Height,
+			X2: to.X + to.Width/2,
+			Y2: to.Y,
+		})
+	}
+	return out
+}
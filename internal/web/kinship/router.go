@@ -0,0 +1,17 @@
+package kinship
+
+import (
+	"github.com/eif-courses/civilregistry/internal/api/kinship"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func SetupRoutes(r chi.Router, db txn.Beginner, queries *repository.Queries, log *zap.SugaredLogger) {
+	handlers := NewHandlers(person.NewService(db, queries, log), kinship.NewService(queries, log), log)
+
+	// Web routes
+	r.Get("/persons/{id}/tree", handlers.FamilyTreePage)
+}
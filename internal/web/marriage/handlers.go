@@ -0,0 +1,211 @@
+package marriage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/a-h/templ"
+	"github.com/eif-courses/civilregistry/internal/api/foreign"
+	restmarriage "github.com/eif-courses/civilregistry/internal/api/marriage"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/web/ui"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	marriages *restmarriage.Service
+	persons   *person.Service
+	logger    *zap.SugaredLogger
+}
+
+func NewHandlers(marriages *restmarriage.Service, persons *person.Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		marriages: marriages,
+		persons:   persons,
+		logger:    logger,
+	}
+}
+
+func (h *Handlers) MarriagesPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	records, err := h.marriages.ListMarriages(r.Context(), foreign.Filter{}, 0, 0)
+	if errors.Is(err, apperr.ErrUnauthorized) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		h.logger.Errorf("Failed to list marriages: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	h.render(w, r, ui.MarriagesPage(records))
+}
+
+func (h *Handlers) NewMarriagePage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	h.render(w, r, ui.MarriageFormPage(ui.MarriageForm{}, ""))
+}
+
+// CreateMarriage handles the registration form. Rejected registrations show
+// the form again with the service's message and the submitted values.
+func (h *Handlers) CreateMarriage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	form := ui.MarriageForm{
+		Spouse1Code:    strings.TrimSpace(r.PostFormValue("spouse1_code")),
+		Spouse2Code:    strings.TrimSpace(r.PostFormValue("spouse2_code")),
+		Spouse1NewName: r.PostFormValue("spouse1_new_name"),
+		Spouse2NewName: r.PostFormValue("spouse2_new_name"),
+		RegisteredOn:   r.PostFormValue("registered_on"),
+	}
+
+	reg, err := h.register(r, form)
+	if errors.Is(err, apperr.ErrUnauthorized) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		status, msg := apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to register marriage: %v", err)
+		}
+		w.WriteHeader(status)
+		h.render(w, r, ui.MarriageFormPage(form, msg))
+		return
+	}
+
+	http.Redirect(w, r, "/marriages/"+reg.Record.ID.String(), http.StatusSeeOther)
+}
+
+func (h *Handlers) MarriagePage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.showMarriage(w, r, id, http.StatusOK, "")
+}
+
+func (h *Handlers) Divorce(w http.ResponseWriter, r *http.Request) {
+	h.end(w, r, h.marriages.Divorce)
+}
+
+func (h *Handlers) Annul(w http.ResponseWriter, r *http.Request) {
+	h.end(w, r, h.marriages.Annul)
+}
+
+func (h *Handlers) end(w http.ResponseWriter, r *http.Request, end func(ctx context.Context, id uuid.UUID, endedOn pgtype.Date) (*restmarriage.MarriageRegistration, error)) {
+	w.Header().Set("Content-Type", "text/html")
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	endedOn, err := time.Parse(time.DateOnly, r.PostFormValue("ended_on"))
+	if err != nil {
+		h.showMarriage(w, r, id, http.StatusBadRequest, "date must be YYYY-MM-DD")
+		return
+	}
+
+	_, err = end(r.Context(), id, pgtype.Date{Time: endedOn, Valid: true})
+	if errors.Is(err, apperr.ErrUnauthorized) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		status, msg := apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to end marriage: %v", err)
+		}
+		h.showMarriage(w, r, id, status, msg)
+		return
+	}
+
+	http.Redirect(w, r, "/marriages/"+id.String(), http.StatusSeeOther)
+}
+
+func (h *Handlers) showMarriage(w http.ResponseWriter, r *http.Request, id uuid.UUID, status int, errMsg string) {
+	reg, err := h.marriages.GetMarriage(r.Context(), id)
+	if errors.Is(err, apperr.ErrUnauthorized) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		if status, _ := apperr.Status(err); status == http.StatusNotFound {
+			http.NotFound(w, r)
+			return
+		}
+		h.logger.Errorf("Failed to get marriage: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(status)
+	h.render(w, r, ui.MarriageDetailPage(reg.Record, reg.Spouse1, reg.Spouse2, reg.Document, errMsg))
+}
+
+func (h *Handlers) register(r *http.Request, form ui.MarriageForm) (*restmarriage.MarriageRegistration, error) {
+	registeredOn, err := time.Parse(time.DateOnly, form.RegisteredOn)
+	if err != nil {
+		return nil, apperr.Invalid("date of marriage must be YYYY-MM-DD")
+	}
+
+	spouse1, err := h.personID(r, form.Spouse1Code)
+	if err != nil {
+		return nil, err
+	}
+	spouse2, err := h.personID(r, form.Spouse2Code)
+	if err != nil {
+		return nil, err
+	}
+
+	return h.marriages.RegisterMarriage(r.Context(), restmarriage.RegisterMarriageParams{
+		Spouse1ID:      spouse1,
+		Spouse2ID:      spouse2,
+		RegisteredOn:   pgtype.Date{Time: registeredOn, Valid: true},
+		Spouse1NewName: pgtype.Text{String: form.Spouse1NewName, Valid: form.Spouse1NewName != ""},
+		Spouse2NewName: pgtype.Text{String: form.Spouse2NewName, Valid: form.Spouse2NewName != ""},
+	})
+}
+
+func (h *Handlers) personID(r *http.Request, code string) (uuid.UUID, error) {
+	p, err := h.persons.GetPersonByPersonalCode(r.Context(), code)
+	if err != nil {
+		status, msg := apperr.Status(err)
+		switch status {
+		case http.StatusNotFound:
+			return uuid.Nil, apperr.Invalid("no person with personal code %s", code)
+		case http.StatusBadRequest:
+			return uuid.Nil, apperr.Invalid("%s", msg)
+		}
+		return uuid.Nil, fmt.Errorf("look up %s: %w", code, err)
+	}
+	return p.ID, nil
+}
+
+func (h *Handlers) render(w http.ResponseWriter, r *http.Request, component templ.Component) {
+	if err := component.Render(r.Context(), w); err != nil {
+		h.logger.Errorf("Failed to render page: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
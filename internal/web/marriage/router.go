@@ -0,0 +1,22 @@
+package marriage
+
+import (
+	restmarriage "github.com/eif-courses/civilregistry/internal/api/marriage"
+	"github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func SetupRoutes(r chi.Router, db txn.Beginner, queries *repository.Queries, log *zap.SugaredLogger) {
+	handlers := NewHandlers(restmarriage.NewService(db, queries, log), person.NewService(db, queries, log), log)
+
+	// Web routes
+	r.Get("/marriages", handlers.MarriagesPage)
+	r.Get("/marriages/new", handlers.NewMarriagePage)
+	r.Post("/marriages", handlers.CreateMarriage)
+	r.Get("/marriages/{id}", handlers.MarriagePage)
+	r.Post("/marriages/{id}/divorce", handlers.Divorce)
+	r.Post("/marriages/{id}/annul", handlers.Annul)
+}
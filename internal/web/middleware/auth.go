@@ -1 +1,78 @@
 package middleware
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// Authenticate resolves the caller's token, from an Authorization: Bearer
+// header or the web sign-in cookie, into an auth.Principal on the request
+// context. Requests without a token continue anonymously; services decide
+// what needs a caller. A rejected bearer token is a 401, while a stale
+// cookie is ignored so the sign-in page stays reachable.
+func Authenticate(queries *repository.Queries, adminToken string, log *zap.SugaredLogger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, fromHeader := bearerToken(r)
+			if !fromHeader {
+				if c, err := r.Cookie(auth.CookieName); err == nil {
+					token = c.Value
+				}
+			}
+			if token == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			p, err := Resolve(r, queries, adminToken, token)
+			if err != nil {
+				log.Errorf("Failed to resolve token: %v", err)
+				http.Error(w, "Internal server error", http.StatusInternalServerError)
+				return
+			}
+			if p == nil {
+				if fromHeader {
+					w.Header().Set("WWW-Authenticate", `Bearer realm="civilregistry"`)
+					http.Error(w, "invalid token", http.StatusUnauthorized)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), p)))
+		})
+	}
+}
+
+// Resolve looks up the caller a token belongs to; unknown tokens give nil.
+// An empty adminToken disables the bootstrap admin.
+func Resolve(r *http.Request, queries *repository.Queries, adminToken, token string) (*auth.Principal, error) {
+	if adminToken != "" && subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1 {
+		return &auth.Principal{Role: auth.RoleAdmin}, nil
+	}
+
+	row, err := queries.GetActiveRegistrarByTokenHash(r.Context(), auth.HashToken(token))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &auth.Principal{Registrar: &row.Registrar, Office: &row.Office, Role: row.Registrar.Role}, nil
+}
+
+func bearerToken(r *http.Request) (string, bool) {
+	header := r.Header.Get("Authorization")
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
@@ -0,0 +1,94 @@
+package office
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/a-h/templ"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/web/middleware"
+	"github.com/eif-courses/civilregistry/internal/web/ui"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	queries    *repository.Queries
+	adminToken string
+	logger     *zap.SugaredLogger
+}
+
+func NewHandlers(queries *repository.Queries, adminToken string, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		queries:    queries,
+		adminToken: adminToken,
+		logger:     logger,
+	}
+}
+
+// LoginPage shows the sign-in form.
+func (h *Handlers) LoginPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+	h.render(w, r, ui.LoginPage(""))
+}
+
+// Login checks the submitted token and keeps it in a cookie, which the
+// authentication middleware reads on later requests.
+func (h *Handlers) Login(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	token := strings.TrimSpace(r.PostFormValue("token"))
+	if token == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		h.render(w, r, ui.LoginPage("Enter your registrar token."))
+		return
+	}
+
+	p, err := middleware.Resolve(r, h.queries, h.adminToken, token)
+	if err != nil {
+		h.logger.Errorf("Failed to resolve token: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	if p == nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		h.render(w, r, ui.LoginPage("The token is not valid, or the registrar or office is deactivated."))
+		return
+	}
+
+	h.logger.Infof("Web sign-in by %s", p.Name())
+	http.SetCookie(w, sessionCookie(r, token, 0))
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// Logout clears the sign-in cookie.
+func (h *Handlers) Logout(w http.ResponseWriter, r *http.Request) {
+	http.SetCookie(w, sessionCookie(r, "", -1))
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// sessionCookie holds the token for the browser session; a negative maxAge
+// deletes it.
+func sessionCookie(r *http.Request, token string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     auth.CookieName,
+		Value:    token,
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	}
+}
+
+func (h *Handlers) render(w http.ResponseWriter, r *http.Request, component templ.Component) {
+	if err := component.Render(r.Context(), w); err != nil {
+		h.logger.Errorf("Failed to render page: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,16 @@
+package office
+
+import (
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func SetupRoutes(r chi.Router, queries *repository.Queries, adminToken string, log *zap.SugaredLogger) {
+	handlers := NewHandlers(queries, adminToken, log)
+
+	// Web routes
+	r.Get("/login", handlers.LoginPage)
+	r.Post("/login", handlers.Login)
+	r.Post("/logout", handlers.Logout)
+}
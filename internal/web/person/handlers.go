@@ -0,0 +1,153 @@
+package person
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/a-h/templ"
+	"github.com/eif-courses/civilregistry/internal/api/birth"
+	"github.com/eif-courses/civilregistry/internal/api/certificate"
+	"github.com/eif-courses/civilregistry/internal/api/death"
+	"github.com/eif-courses/civilregistry/internal/api/marriage"
+	restperson "github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/web/ui"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	persons      *restperson.Service
+	births       *birth.Service
+	marriages    *marriage.Service
+	deaths       *death.Service
+	certificates *certificate.Service
+	logger       *zap.SugaredLogger
+}
+
+func NewHandlers(persons *restperson.Service, births *birth.Service, marriages *marriage.Service, deaths *death.Service, certificates *certificate.Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		persons:      persons,
+		births:       births,
+		marriages:    marriages,
+		deaths:       deaths,
+		certificates: certificates,
+		logger:       logger,
+	}
+}
+
+func (h *Handlers) PersonPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	h.showPerson(w, r, id, http.StatusOK, "")
+}
+
+// IssueCertificate handles the "issue certificate" buttons on the person
+// page and returns to it, where the new certificate is listed.
+func (h *Handlers) IssueCertificate(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	recordID, err := uuid.Parse(r.PostFormValue("record_id"))
+	if err != nil {
+		h.showPerson(w, r, id, http.StatusBadRequest, "invalid record")
+		return
+	}
+
+	_, err = h.certificates.IssueCertificate(r.Context(), certificate.IssueCertificateParams{
+		Kind:     r.PostFormValue("kind"),
+		RecordID: recordID,
+		PersonID: id,
+	})
+	if err != nil {
+		status, msg := apperr.Status(err)
+		if status >= http.StatusInternalServerError {
+			h.logger.Errorf("Failed to issue certificate: %v", err)
+		}
+		h.showPerson(w, r, id, status, msg)
+		return
+	}
+
+	http.Redirect(w, r, "/persons/"+id.String(), http.StatusSeeOther)
+}
+
+func (h *Handlers) showPerson(w http.ResponseWriter, r *http.Request, id uuid.UUID, status int, errMsg string) {
+	data, err := h.load(r, id)
+	if errors.Is(err, apperr.ErrUnauthorized) {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+	if err != nil {
+		if status, _ := apperr.Status(err); status == http.StatusNotFound {
+			http.NotFound(w, r)
+			return
+		}
+		h.logger.Errorf("Failed to load person: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(status)
+	h.render(w, r, ui.PersonDetailPage(*data, errMsg))
+}
+
+func (h *Handlers) load(r *http.Request, id uuid.UUID) (*ui.PersonPageData, error) {
+	ctx := r.Context()
+
+	p, err := h.persons.GetPersonByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	data := ui.PersonPageData{Person: *p}
+
+	// Persons registered directly rather than by birth have no birth record
+	birthReg, err := h.births.GetBirthRegistrationByPersonID(ctx, id)
+	switch {
+	case err == nil:
+		data.Birth = &birthReg.Record
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, err
+	}
+
+	if data.Marriages, err = h.marriages.ListMarriagesForPerson(ctx, id); err != nil {
+		return nil, err
+	}
+
+	deathReg, err := h.deaths.GetDeathRegistrationByPersonID(ctx, id)
+	switch {
+	case err == nil:
+		data.Death = &deathReg.Record
+	case !errors.Is(err, pgx.ErrNoRows):
+		return nil, err
+	}
+
+	// Certificates carry full personal data and are shown to registrars only
+	if _, err := auth.RequireRegistrar(ctx); err == nil {
+		data.Registrar = true
+		if data.Certificates, err = h.certificates.ListCertificatesForPerson(ctx, id); err != nil {
+			return nil, err
+		}
+	}
+	return &data, nil
+}
+
+func (h *Handlers) render(w http.ResponseWriter, r *http.Request, component templ.Component) {
+	if err := component.Render(r.Context(), w); err != nil {
+		h.logger.Errorf("Failed to render page: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
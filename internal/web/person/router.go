@@ -0,0 +1,28 @@
+package person
+
+import (
+	"github.com/eif-courses/civilregistry/internal/api/birth"
+	"github.com/eif-courses/civilregistry/internal/api/certificate"
+	"github.com/eif-courses/civilregistry/internal/api/death"
+	"github.com/eif-courses/civilregistry/internal/api/marriage"
+	restperson "github.com/eif-courses/civilregistry/internal/api/person"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/eif-courses/civilregistry/internal/txn"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func SetupRoutes(r chi.Router, db txn.Beginner, queries *repository.Queries, log *zap.SugaredLogger) {
+	handlers := NewHandlers(
+		restperson.NewService(db, queries, log),
+		birth.NewService(db, queries, log),
+		marriage.NewService(db, queries, log),
+		death.NewService(db, queries, log),
+		certificate.NewService(queries, log),
+		log,
+	)
+
+	// Web routes
+	r.Get("/persons/{id}", handlers.PersonPage)
+	r.Post("/persons/{id}/certificates", handlers.IssueCertificate)
+}
@@ -0,0 +1,104 @@
+package report
+
+import (
+	"strconv"
+
+	"github.com/eif-courses/civilregistry/internal/api/report"
+	"github.com/eif-courses/civilregistry/internal/web/ui"
+)
+
+const (
+	chartWidth = 640
+	// Column charts of counts per period
+	columnHeight  = 200
+	columnTop     = 18
+	columnBottom  = 36
+	maxValueLabel = 24
+	// Bar charts of counts per group
+	barRowHeight  = 24
+	barLabelWidth = 170
+	barValueWidth = 60
+)
+
+// trendChart draws a column per period. Values are printed over the
+// columns and every period labelled while they fit; longer ranges label
+// every few periods.
+func trendChart(rep report.Report) ui.BarChart {
+	n := len(rep.Periods)
+	chart := ui.BarChart{Width: chartWidth, Height: columnTop + columnHeight + columnBottom}
+	if n == 0 {
+		return chart
+	}
+
+	slot := float64(chartWidth) / float64(n)
+	every := (n + maxValueLabel - 1) / maxValueLabel
+	top := maxCount(rep.Periods, func(p report.PeriodCount) int64 { return p.Count })
+	for i, p := range rep.Periods {
+		h := scale(p.Count, top, columnHeight)
+		x := int(float64(i)*slot + slot*0.15)
+		bar := ui.Bar{
+			X:      x,
+			Y:      columnTop + columnHeight - h,
+			Width:  max(int(slot*0.7), 1),
+			Height: h,
+			Title:  report.PeriodLabel(p.Period, rep.Period) + ": " + strconv.FormatInt(p.Count, 10),
+		}
+		centre := int(float64(i)*slot + slot/2)
+		if i%every == 0 {
+			bar.Label = report.PeriodLabel(p.Period, rep.Period)
+			bar.LabelX, bar.LabelY = centre, columnTop+columnHeight+16
+			bar.LabelAnchor = "middle"
+		}
+		if every == 1 {
+			bar.Value = strconv.FormatInt(p.Count, 10)
+			bar.ValueX, bar.ValueY = centre, bar.Y-4
+			bar.ValueAnchor = "middle"
+		}
+		chart.Bars = append(chart.Bars, bar)
+	}
+	return chart
+}
+
+// breakdownChart draws a horizontal bar per group, labelled on the left
+// with the count on the right.
+func breakdownChart(rep report.Report) ui.BarChart {
+	chart := ui.BarChart{Width: chartWidth, Height: len(rep.Groups)*barRowHeight + 8}
+	top := maxCount(rep.Groups, func(g report.GroupCount) int64 { return g.Count })
+	length := chartWidth - barLabelWidth - barValueWidth
+	for i, g := range rep.Groups {
+		y := 4 + i*barRowHeight
+		w := scale(g.Count, top, length)
+		chart.Bars = append(chart.Bars, ui.Bar{
+			X:           barLabelWidth,
+			Y:           y + 4,
+			Width:       w,
+			Height:      barRowHeight - 8,
+			Title:       g.Group + ": " + strconv.FormatInt(g.Count, 10),
+			Label:       g.Group,
+			LabelX:      barLabelWidth - 8,
+			LabelY:      y + barRowHeight/2 + 4,
+			LabelAnchor: "end",
+			Value:       strconv.FormatInt(g.Count, 10),
+			ValueX:      barLabelWidth + w + 6,
+			ValueY:      y + barRowHeight/2 + 4,
+			ValueAnchor: "start",
+		})
+	}
+	return chart
+}
+
+func maxCount[T any](items []T, count func(T) int64) int64 {
+	var top int64
+	for _, item := range items {
+		top = max(top, count(item))
+	}
+	return top
+}
+
+// scale maps n out of top onto size pixels; non-zero counts stay visible.
+func scale(n, top int64, size int) int {
+	if top == 0 || n == 0 {
+		return 0
+	}
+	return max(int(n*int64(size)/top), 1)
+}
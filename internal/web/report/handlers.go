@@ -0,0 +1,126 @@
+package report
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/a-h/templ"
+	"github.com/eif-courses/civilregistry/internal/api/office"
+	"github.com/eif-courses/civilregistry/internal/api/report"
+	"github.com/eif-courses/civilregistry/internal/apperr"
+	"github.com/eif-courses/civilregistry/internal/auth"
+	"github.com/eif-courses/civilregistry/internal/render"
+	"github.com/eif-courses/civilregistry/internal/web/ui"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+type Handlers struct {
+	reports *report.Service
+	offices *office.Service
+	logger  *zap.SugaredLogger
+}
+
+func NewHandlers(reports *report.Service, offices *office.Service, logger *zap.SugaredLogger) *Handlers {
+	return &Handlers{
+		reports: reports,
+		offices: offices,
+		logger:  logger,
+	}
+}
+
+// DashboardPage shows births, marriages and deaths side by side for the
+// filter in the query string.
+func (h *Handlers) DashboardPage(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/html")
+
+	if auth.FromContext(r.Context()) == nil {
+		http.Redirect(w, r, "/login", http.StatusSeeOther)
+		return
+	}
+
+	query := r.URL.Query()
+	data := ui.ReportDashboardData{
+		Period:       query.Get("period"),
+		By:           query.Get("by"),
+		From:         query.Get("from"),
+		To:           query.Get("to"),
+		OfficeID:     query.Get("office_id"),
+		Municipality: query.Get("municipality"),
+	}
+
+	offices, err := h.offices.ListOffices(r.Context())
+	if err != nil {
+		h.logger.Errorf("Failed to list offices: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+	data.Offices = offices
+
+	status := http.StatusOK
+	for _, event := range report.Events {
+		rep, err := h.report(r, event)
+		if errors.Is(err, apperr.ErrUnauthorized) {
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+			return
+		}
+		if err != nil {
+			status, data.ErrMsg = apperr.Status(err)
+			if status >= http.StatusInternalServerError {
+				h.logger.Errorf("Failed to load report: %v", err)
+			}
+			data.Cards = nil
+			break
+		}
+		data.Cards = append(data.Cards, ui.ReportCard{
+			Report:    *rep,
+			Trend:     trendChart(*rep),
+			Breakdown: breakdownChart(*rep),
+			CSVURL:    "/reports/" + event + ".csv?" + r.URL.RawQuery,
+		})
+	}
+	if len(data.Cards) > 0 && data.Period == "" {
+		data.Period = data.Cards[0].Report.Period
+	}
+
+	w.WriteHeader(status)
+	h.render(w, r, ui.ReportDashboardPage(data))
+}
+
+// CSV downloads one report of the dashboard.
+func (h *Handlers) CSV(w http.ResponseWriter, r *http.Request) {
+	rep, err := h.report(r, chi.URLParam(r, "event"))
+	if err != nil {
+		status, msg := apperr.Status(err)
+		switch {
+		case errors.Is(err, apperr.ErrUnauthorized):
+			http.Redirect(w, r, "/login", http.StatusSeeOther)
+		case status >= http.StatusInternalServerError:
+			h.logger.Errorf("Failed to load report: %v", err)
+			http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		default:
+			http.Error(w, msg, status)
+		}
+		return
+	}
+
+	w.Header().Set("Content-Disposition", `attachment; filename="`+rep.Event+"-"+rep.From.Format("2006-01-02")+"-"+rep.To.Format("2006-01-02")+`.csv"`)
+	if err := render.WriteCSV(w, http.StatusOK, report.ReportCSVHeader, report.NewReportRows(*rep)); err != nil {
+		h.logger.Errorf("Failed to write report: %v", err)
+	}
+}
+
+func (h *Handlers) report(r *http.Request, event string) (*report.Report, error) {
+	f, err := report.ParseFilter(r, event)
+	if err != nil {
+		return nil, err
+	}
+	return h.reports.Report(r.Context(), f)
+}
+
+func (h *Handlers) render(w http.ResponseWriter, r *http.Request, component templ.Component) {
+	if err := component.Render(r.Context(), w); err != nil {
+		h.logger.Errorf("Failed to render page: %v", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,17 @@
+package report
+
+import (
+	"github.com/eif-courses/civilregistry/internal/api/office"
+	"github.com/eif-courses/civilregistry/internal/api/report"
+	"github.com/eif-courses/civilregistry/internal/generated/repository"
+	"github.com/go-chi/chi/v5"
+	"go.uber.org/zap"
+)
+
+func SetupRoutes(r chi.Router, queries *repository.Queries, log *zap.SugaredLogger) {
+	handlers := NewHandlers(report.NewService(queries, log), office.NewService(queries, log), log)
+
+	// Statistics dashboard
+	r.Get("/reports", handlers.DashboardPage)
+	r.Get("/reports/{event}.csv", handlers.CSV)
+}
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE person
+(
+    id            UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    personal_code TEXT        NOT NULL UNIQUE CHECK (personal_code ~ '^[0-9]{11}$'),
+    first_name    TEXT        NOT NULL,
+    last_name     TEXT        NOT NULL,
+    birth_date    DATE        NOT NULL,
+    birth_place   TEXT,
+    sex           TEXT        NOT NULL CHECK (sex IN ('male', 'female')),
+    citizenship   TEXT        NOT NULL DEFAULT 'LT' CHECK (citizenship ~ '^[A-Z]{2}$'),
+    status        TEXT        NOT NULL DEFAULT 'alive' CHECK (status IN ('alive', 'deceased')),
+    version       BIGINT      NOT NULL DEFAULT 1,
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX person_name_idx ON person (lower(last_name), lower(first_name));
+CREATE INDEX person_birth_date_idx ON person (birth_date);
+
+CREATE TABLE person_address
+(
+    id          UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    person_id   UUID        NOT NULL REFERENCES person (id) ON DELETE CASCADE,
+    kind        TEXT        NOT NULL CHECK (kind IN ('residence', 'correspondence')),
+    line        TEXT        NOT NULL,
+    city        TEXT        NOT NULL,
+    postal_code TEXT        NOT NULL DEFAULT '',
+    country     TEXT        NOT NULL DEFAULT 'LT' CHECK (country ~ '^[A-Z]{2}$'),
+    created_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX person_address_person_id_idx ON person_address (person_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS person_address;
+DROP TABLE IF EXISTS person;
+-- +goose StatementEnd
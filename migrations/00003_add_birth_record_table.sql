@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE birth_record
+(
+    id                  UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    person_id           UUID        NOT NULL UNIQUE REFERENCES person (id),
+    mother_id           UUID REFERENCES person (id),
+    father_id           UUID REFERENCES person (id),
+    birth_place         TEXT        NOT NULL,
+    registration_office TEXT        NOT NULL,
+    registrar           TEXT        NOT NULL,
+    registered_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (mother_id IS DISTINCT FROM father_id),
+    CHECK (person_id <> mother_id AND person_id <> father_id)
+);
+
+CREATE INDEX birth_record_mother_id_idx ON birth_record (mother_id);
+CREATE INDEX birth_record_father_id_idx ON birth_record (father_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS birth_record;
+-- +goose StatementEnd
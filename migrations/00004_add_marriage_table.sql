@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE person
+    ADD COLUMN marital_status TEXT NOT NULL DEFAULT 'single'
+        CHECK (marital_status IN ('single', 'married', 'divorced', 'widowed'));
+
+CREATE TABLE marriage
+(
+    id                      UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    spouse1_id              UUID        NOT NULL REFERENCES person (id),
+    spouse2_id              UUID        NOT NULL REFERENCES person (id),
+    registered_on           DATE        NOT NULL,
+    registration_office     TEXT        NOT NULL,
+    registrar               TEXT        NOT NULL,
+    -- Surnames and marital statuses before the marriage, restored on annulment
+    spouse1_previous_name   TEXT        NOT NULL,
+    spouse2_previous_name   TEXT        NOT NULL,
+    spouse1_previous_status TEXT        NOT NULL,
+    spouse2_previous_status TEXT        NOT NULL,
+    -- Surnames taken at marriage; NULL keeps the previous surname
+    spouse1_new_name        TEXT,
+    spouse2_new_name        TEXT,
+    status                  TEXT        NOT NULL DEFAULT 'active'
+        CHECK (status IN ('active', 'divorced', 'annulled', 'widowed')),
+    ended_on                DATE,
+    created_at              TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at              TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (spouse1_id <> spouse2_id),
+    CHECK ((status = 'active') = (ended_on IS NULL)),
+    CHECK (ended_on IS NULL OR ended_on >= registered_on)
+);
+
+CREATE INDEX marriage_spouse1_id_idx ON marriage (spouse1_id);
+CREATE INDEX marriage_spouse2_id_idx ON marriage (spouse2_id);
+CREATE UNIQUE INDEX marriage_spouse1_active_idx ON marriage (spouse1_id) WHERE status = 'active';
+CREATE UNIQUE INDEX marriage_spouse2_active_idx ON marriage (spouse2_id) WHERE status = 'active';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS marriage;
+ALTER TABLE person DROP COLUMN IF EXISTS marital_status;
+-- +goose StatementEnd
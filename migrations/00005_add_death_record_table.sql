@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE death_record
+(
+    id                  UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    person_id           UUID        NOT NULL UNIQUE REFERENCES person (id),
+    date_of_death       DATE        NOT NULL,
+    place_of_death      TEXT        NOT NULL,
+    -- ICD-10 code of the underlying cause, e.g. I21.9
+    cause_code          TEXT        NOT NULL CHECK (cause_code ~ '^[A-Z][0-9]{2}(\.[0-9]{1,2})?$'),
+    informant_name      TEXT        NOT NULL,
+    informant_person_id UUID REFERENCES person (id),
+    registration_office TEXT        NOT NULL,
+    registrar           TEXT        NOT NULL,
+    registered_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (informant_person_id IS DISTINCT FROM person_id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS death_record;
+-- +goose StatementEnd
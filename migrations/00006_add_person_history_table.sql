@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- One row per changed attribute. A person's value on a date is the
+-- new_value of the latest row effective on or before it, or the old_value of
+-- the earliest row when every change came later.
+CREATE TABLE person_history
+(
+    id             UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    person_id      UUID        NOT NULL REFERENCES person (id) ON DELETE CASCADE,
+    attribute      TEXT        NOT NULL CHECK (attribute IN ('first_name', 'last_name', 'birth_place', 'citizenship',
+                                                             'marital_status', 'status')),
+    old_value      TEXT,
+    new_value      TEXT,
+    effective_date DATE        NOT NULL,
+    legal_basis    TEXT        NOT NULL CHECK (legal_basis <> ''),
+    -- person.version after the change; rows from one amendment share it
+    person_version BIGINT      NOT NULL,
+    recorded_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX person_history_person_id_idx ON person_history (person_id, effective_date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS person_history;
+-- +goose StatementEnd
@@ -0,0 +1,32 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE SEQUENCE certificate_serial_seq;
+
+-- An issued certificate. content is the snapshot of the printed fields taken
+-- at issuance, so a reprint shows what was certified even if the records
+-- were amended since.
+CREATE TABLE certificate
+(
+    id            UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    serial_number TEXT        NOT NULL UNIQUE
+        DEFAULT 'CR-' || lpad(nextval('certificate_serial_seq')::text, 8, '0'),
+    kind          TEXT        NOT NULL CHECK (kind IN ('birth', 'marriage', 'death')),
+    -- birth_record, marriage or death_record, depending on kind
+    record_id     UUID        NOT NULL,
+    person_id     UUID        NOT NULL REFERENCES person (id),
+    content       JSONB       NOT NULL,
+    issued_on     DATE        NOT NULL DEFAULT current_date,
+    issued_by     TEXT        NOT NULL CHECK (issued_by <> ''),
+    created_at    TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+ALTER SEQUENCE certificate_serial_seq OWNED BY certificate.serial_number;
+
+CREATE INDEX certificate_person_id_idx ON certificate (person_id);
+CREATE INDEX certificate_record_id_idx ON certificate (record_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS certificate;
+-- +goose StatementEnd
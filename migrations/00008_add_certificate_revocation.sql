@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE certificate
+    ADD COLUMN revoked_at        TIMESTAMPTZ,
+    ADD COLUMN revoked_by        TEXT,
+    ADD COLUMN revocation_reason TEXT,
+    ADD CONSTRAINT certificate_revocation_check
+        CHECK ((revoked_at IS NULL) = (revoked_by IS NULL)
+            AND (revoked_at IS NULL) = (revocation_reason IS NULL));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE certificate
+    DROP CONSTRAINT IF EXISTS certificate_revocation_check,
+    DROP COLUMN IF EXISTS revocation_reason,
+    DROP COLUMN IF EXISTS revoked_by,
+    DROP COLUMN IF EXISTS revoked_at;
+-- +goose StatementEnd
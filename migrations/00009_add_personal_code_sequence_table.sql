@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Last personal code serial number handed out per birth date. The row is
+-- locked by the registration that bumps it until that transaction ends, so
+-- concurrent births on the same date get consecutive serials.
+CREATE TABLE personal_code_sequence
+(
+    birth_date  DATE PRIMARY KEY,
+    last_serial INTEGER NOT NULL CHECK (last_serial > 0)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS personal_code_sequence;
+-- +goose StatementEnd
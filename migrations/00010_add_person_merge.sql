@@ -0,0 +1,47 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Lower-cases a name and folds Lithuanian letters to their base letters, so
+-- "Šarūnas Žukauskas" and "sarunas zukauskas" compare equal. The upper-case
+-- letters are translated explicitly because lower() leaves them alone under
+-- the C locale.
+CREATE FUNCTION fold_name(name TEXT) RETURNS TEXT
+    LANGUAGE sql
+    IMMUTABLE
+    PARALLEL SAFE
+AS
+$$
+SELECT lower(translate(btrim(name), 'ĄČĘĖĮŠŲŪŽąčęėįšųūž', 'ACEEISUUZaceeisuuz'))
+$$;
+
+CREATE INDEX person_folded_name_idx ON person (fold_name(last_name), fold_name(first_name));
+
+-- A merged duplicate stays in place, pointing at the surviving person
+ALTER TABLE person
+    ADD COLUMN merged_into UUID REFERENCES person (id),
+    ADD CHECK (merged_into <> id);
+
+CREATE TABLE person_merge
+(
+    id                 UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    survivor_id        UUID        NOT NULL REFERENCES person (id),
+    duplicate_id       UUID        NOT NULL UNIQUE REFERENCES person (id),
+    merged_by          TEXT        NOT NULL CHECK (merged_by <> ''),
+    reason             TEXT        NOT NULL CHECK (reason <> ''),
+    -- Number of rows re-pointed per table and column, e.g. {"marriage.spouse1_id": 1}
+    moved              JSONB       NOT NULL,
+    -- The duplicate as it was just before the merge
+    duplicate_snapshot JSONB       NOT NULL,
+    merged_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (survivor_id <> duplicate_id)
+);
+
+CREATE INDEX person_merge_survivor_id_idx ON person_merge (survivor_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS person_merge;
+ALTER TABLE person DROP COLUMN IF EXISTS merged_into;
+DROP INDEX IF EXISTS person_folded_name_idx;
+DROP FUNCTION IF EXISTS fold_name(TEXT);
+-- +goose StatementEnd
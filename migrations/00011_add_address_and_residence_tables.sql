@@ -0,0 +1,40 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Address register. street is empty for villages numbered without streets,
+-- flat is empty for houses.
+CREATE TABLE address
+(
+    id           UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    municipality TEXT        NOT NULL,
+    street       TEXT        NOT NULL DEFAULT '',
+    house        TEXT        NOT NULL,
+    flat         TEXT        NOT NULL DEFAULT '',
+    postal_code  TEXT        NOT NULL CHECK (postal_code ~ '^LT-[0-9]{5}$'),
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX address_key_idx ON address (fold_name(municipality), fold_name(street), lower(house), lower(flat));
+
+-- Declared places of residence. A declaration covers [start_date, end_date);
+-- an open one is the person's current residence, and there is at most one.
+CREATE TABLE residence_declaration
+(
+    id          UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    person_id   UUID        NOT NULL REFERENCES person (id),
+    address_id  UUID        NOT NULL REFERENCES address (id),
+    start_date  DATE        NOT NULL,
+    end_date    DATE CHECK (end_date > start_date),
+    declared_by TEXT        NOT NULL,
+    declared_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX residence_declaration_active_idx ON residence_declaration (person_id) WHERE end_date IS NULL;
+CREATE INDEX residence_declaration_person_idx ON residence_declaration (person_id, start_date);
+CREATE INDEX residence_declaration_address_idx ON residence_declaration (address_id, start_date);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS residence_declaration;
+DROP TABLE IF EXISTS address;
+-- +goose StatementEnd
@@ -0,0 +1,71 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Civil registration offices. code is a short upper-case office code, e.g.
+-- VIL for Vilnius.
+CREATE TABLE office
+(
+    id           UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    code         TEXT        NOT NULL UNIQUE CHECK (code ~ '^[A-Z]{3}$'),
+    name         TEXT        NOT NULL CHECK (name <> ''),
+    municipality TEXT        NOT NULL CHECK (municipality <> ''),
+    active       BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_at   TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+-- Registrars sign in with an API token; only its SHA-256 hash is stored.
+-- Admins additionally manage offices, registrars and jurisdictions.
+CREATE TABLE registrar
+(
+    id         UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    office_id  UUID        NOT NULL REFERENCES office (id),
+    full_name  TEXT        NOT NULL CHECK (full_name <> ''),
+    username   TEXT        NOT NULL UNIQUE CHECK (username ~ '^[a-z0-9._-]{3,}$'),
+    role       TEXT        NOT NULL DEFAULT 'registrar' CHECK (role IN ('registrar', 'admin')),
+    token_hash BYTEA       NOT NULL UNIQUE,
+    active     BOOLEAN     NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX registrar_office_id_idx ON registrar (office_id);
+
+-- Which events an office may register. A NULL place allows events anywhere;
+-- otherwise the event place must match, compared with fold_name.
+CREATE TABLE office_jurisdiction
+(
+    id         UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    office_id  UUID        NOT NULL REFERENCES office (id),
+    event_kind TEXT        NOT NULL CHECK (event_kind IN ('birth', 'marriage', 'death')),
+    place      TEXT CHECK (place <> ''),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE UNIQUE INDEX office_jurisdiction_rule_idx ON office_jurisdiction (office_id, event_kind, coalesce(fold_name(place), ''));
+
+-- Records made before offices were tracked keep only the office and
+-- registrar names
+ALTER TABLE birth_record
+    ADD COLUMN office_id    UUID REFERENCES office (id),
+    ADD COLUMN registrar_id UUID REFERENCES registrar (id);
+ALTER TABLE marriage
+    ADD COLUMN office_id    UUID REFERENCES office (id),
+    ADD COLUMN registrar_id UUID REFERENCES registrar (id);
+ALTER TABLE death_record
+    ADD COLUMN office_id    UUID REFERENCES office (id),
+    ADD COLUMN registrar_id UUID REFERENCES registrar (id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE death_record
+    DROP COLUMN IF EXISTS registrar_id,
+    DROP COLUMN IF EXISTS office_id;
+ALTER TABLE marriage
+    DROP COLUMN IF EXISTS registrar_id,
+    DROP COLUMN IF EXISTS office_id;
+ALTER TABLE birth_record
+    DROP COLUMN IF EXISTS registrar_id,
+    DROP COLUMN IF EXISTS office_id;
+DROP TABLE IF EXISTS office_jurisdiction;
+DROP TABLE IF EXISTS registrar;
+DROP TABLE IF EXISTS office;
+-- +goose StatementEnd
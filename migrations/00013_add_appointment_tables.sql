@@ -0,0 +1,76 @@
+-- +goose Up
+-- +goose StatementBegin
+-- btree_gist lets the exclusion constraints below mix equality on plain
+-- columns with range overlap
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+-- Bookable time in an office's calendar. booked counts the current
+-- appointments and is only changed while the slot row is locked; the CHECK
+-- is a backstop against overbooking. Slots of one kind never overlap within
+-- an office.
+CREATE TABLE appointment_slot
+(
+    id         UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    office_id  UUID        NOT NULL REFERENCES office (id),
+    kind       TEXT        NOT NULL CHECK (kind IN ('marriage_ceremony', 'office_visit')),
+    starts_at  TIMESTAMPTZ NOT NULL,
+    ends_at    TIMESTAMPTZ NOT NULL,
+    capacity   INTEGER     NOT NULL CHECK (capacity > 0),
+    booked     INTEGER     NOT NULL DEFAULT 0 CHECK (booked >= 0 AND booked <= capacity),
+    created_by UUID        NOT NULL REFERENCES registrar (id),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (ends_at > starts_at),
+    CONSTRAINT appointment_slot_no_overlap EXCLUDE USING gist (
+        office_id WITH =,
+        kind WITH =,
+        tstzrange(starts_at, ends_at) WITH &&
+        )
+);
+
+CREATE INDEX appointment_slot_starts_at_idx ON appointment_slot (starts_at);
+
+-- A citizen's booking. reference is the unguessable code the citizen uses
+-- to look up and cancel it. The slot's times are copied so that one email
+-- address cannot hold two overlapping bookings.
+CREATE TABLE appointment
+(
+    id            UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    slot_id       UUID        NOT NULL REFERENCES appointment_slot (id),
+    reference     TEXT        NOT NULL UNIQUE,
+    full_name     TEXT        NOT NULL CHECK (full_name <> ''),
+    email         TEXT        NOT NULL CHECK (email <> ''),
+    phone         TEXT        NOT NULL DEFAULT '',
+    personal_code TEXT,
+    notes         TEXT        NOT NULL DEFAULT '',
+    status        TEXT        NOT NULL DEFAULT 'booked' CHECK (status IN ('booked', 'cancelled')),
+    starts_at     TIMESTAMPTZ NOT NULL,
+    ends_at       TIMESTAMPTZ NOT NULL,
+    booked_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    cancelled_at  TIMESTAMPTZ,
+    cancelled_by  TEXT,
+    CHECK ((status = 'cancelled') = (cancelled_at IS NOT NULL)),
+    CONSTRAINT appointment_no_overlap EXCLUDE USING gist (
+        lower(email) WITH =,
+        tstzrange(starts_at, ends_at) WITH &&
+        ) WHERE (status = 'booked')
+);
+
+CREATE INDEX appointment_slot_id_idx ON appointment (slot_id);
+
+-- Reminders waiting to be sent to citizens, at most one per appointment.
+CREATE TABLE appointment_reminder
+(
+    id             UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    appointment_id UUID        NOT NULL UNIQUE REFERENCES appointment (id),
+    created_at     TIMESTAMPTZ NOT NULL DEFAULT now(),
+    sent_at        TIMESTAMPTZ
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS appointment_reminder;
+DROP TABLE IF EXISTS appointment;
+DROP TABLE IF EXISTS appointment_slot;
+DROP EXTENSION IF EXISTS btree_gist;
+-- +goose StatementEnd
@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE SEQUENCE application_number_seq;
+
+-- Applications citizens submit through the web form. number is shown to the
+-- applicant, who tracks the application with it and their email address.
+-- details holds the kind-specific fields as JSON.
+CREATE TABLE application
+(
+    id              UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    number          TEXT        NOT NULL UNIQUE,
+    kind            TEXT        NOT NULL CHECK (kind IN ('certificate_request', 'name_change', 'residence_declaration')),
+    status          TEXT        NOT NULL DEFAULT 'submitted'
+        CHECK (status IN ('submitted', 'in_review', 'needs_info', 'approved', 'rejected')),
+    office_id       UUID        NOT NULL REFERENCES office (id),
+    applicant_name  TEXT        NOT NULL CHECK (applicant_name <> ''),
+    applicant_email TEXT        NOT NULL CHECK (applicant_email <> ''),
+    applicant_phone TEXT        NOT NULL DEFAULT '',
+    personal_code   TEXT        NOT NULL CHECK (personal_code ~ '^[0-9]{11}$'),
+    details         JSONB       NOT NULL,
+    assigned_to     UUID REFERENCES registrar (id),
+    submitted_at    TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    decided_at      TIMESTAMPTZ,
+    CHECK ((status IN ('approved', 'rejected')) = (decided_at IS NOT NULL))
+);
+
+CREATE INDEX application_queue_idx ON application (office_id, status, submitted_at);
+
+-- Every status change, including the submission. from_status is NULL for
+-- the submission; changed_by is a registrar's name or 'applicant'.
+CREATE TABLE application_status_history
+(
+    id             UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    application_id UUID        NOT NULL REFERENCES application (id) ON DELETE CASCADE,
+    from_status    TEXT,
+    to_status      TEXT        NOT NULL,
+    note           TEXT        NOT NULL DEFAULT '',
+    changed_by     TEXT        NOT NULL,
+    changed_at     TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX application_status_history_application_id_idx ON application_status_history (application_id, changed_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS application_status_history;
+DROP TABLE IF EXISTS application;
+DROP SEQUENCE IF EXISTS application_number_seq;
+-- +goose StatementEnd
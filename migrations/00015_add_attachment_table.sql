@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Scanned documents attached to applications and registry records. The
+-- bytes live in the blob store under sha256; several attachments may share
+-- one blob. office_id is the office whose registrars may see them.
+CREATE TABLE attachment
+(
+    id           UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    owner_kind   TEXT        NOT NULL CHECK (owner_kind IN ('application', 'birth', 'marriage', 'death')),
+    owner_id     UUID        NOT NULL,
+    office_id    UUID        NOT NULL REFERENCES office (id),
+    filename     TEXT        NOT NULL CHECK (filename <> ''),
+    content_type TEXT        NOT NULL,
+    size_bytes   BIGINT      NOT NULL CHECK (size_bytes > 0),
+    sha256       TEXT        NOT NULL CHECK (sha256 ~ '^[0-9a-f]{64}$'),
+    uploaded_by  TEXT        NOT NULL,
+    uploaded_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX attachment_owner_idx ON attachment (owner_kind, owner_id, uploaded_at);
+CREATE INDEX attachment_sha256_idx ON attachment (sha256);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS attachment;
+-- +goose StatementEnd
@@ -0,0 +1,36 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Age band of someone born on born, as of on_date, for the statistical
+-- reports. NULL birth dates, e.g. of an unknown mother, are 'unknown'.
+CREATE FUNCTION age_group(born DATE, on_date DATE) RETURNS TEXT
+    LANGUAGE sql
+    IMMUTABLE
+    PARALLEL SAFE
+AS
+$$
+SELECT CASE
+           WHEN born IS NULL THEN 'unknown'
+           WHEN years < 20 THEN '0-19'
+           WHEN years < 25 THEN '20-24'
+           WHEN years < 30 THEN '25-29'
+           WHEN years < 35 THEN '30-34'
+           WHEN years < 40 THEN '35-39'
+           WHEN years < 50 THEN '40-49'
+           WHEN years < 65 THEN '50-64'
+           WHEN years < 80 THEN '65-79'
+           ELSE '80+'
+           END
+FROM (SELECT extract(YEAR FROM age(on_date::timestamp, born::timestamp))::int AS years) a
+$$;
+
+-- Reports select events by date
+CREATE INDEX marriage_registered_on_idx ON marriage (registered_on);
+CREATE INDEX death_record_date_of_death_idx ON death_record (date_of_death);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS death_record_date_of_death_idx;
+DROP INDEX IF EXISTS marriage_registered_on_idx;
+DROP FUNCTION IF EXISTS age_group(DATE, DATE);
+-- +goose StatementEnd
@@ -0,0 +1,80 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Last registry number handed out per office, record kind and year. The
+-- row is locked by the registration that bumps it until that transaction
+-- ends, so numbers are consecutive and a rolled-back registration gives its
+-- number back.
+CREATE TABLE registry_number_sequence
+(
+    office_id   UUID    NOT NULL REFERENCES office (id),
+    record_kind TEXT    NOT NULL CHECK (record_kind IN ('birth', 'marriage', 'death')),
+    year        INTEGER NOT NULL,
+    last_number INTEGER NOT NULL CHECK (last_number > 0),
+    PRIMARY KEY (office_id, record_kind, year)
+);
+
+-- Registry numbers look like VIL-2026-000123: the office code, the year of
+-- registration and the number within them. Records made before offices
+-- were tracked have none.
+ALTER TABLE birth_record
+    ADD COLUMN registry_number TEXT UNIQUE CHECK (registry_number ~ '^[A-Z]{3}-[0-9]{4}-[0-9]{6}$');
+ALTER TABLE marriage
+    ADD COLUMN registry_number TEXT UNIQUE CHECK (registry_number ~ '^[A-Z]{3}-[0-9]{4}-[0-9]{6}$');
+ALTER TABLE death_record
+    ADD COLUMN registry_number TEXT UNIQUE CHECK (registry_number ~ '^[A-Z]{3}-[0-9]{4}-[0-9]{6}$');
+
+-- Number the records made so far in registration order
+UPDATE birth_record r
+SET registry_number = n.number
+FROM (SELECT b.id,
+             o.code || '-' || extract(YEAR FROM b.registered_at)::int || '-' ||
+             lpad((row_number() OVER (PARTITION BY b.office_id, extract(YEAR FROM b.registered_at)
+                 ORDER BY b.registered_at, b.id))::text, 6, '0') AS number
+      FROM birth_record b
+               JOIN office o ON o.id = b.office_id) n
+WHERE n.id = r.id;
+
+UPDATE marriage r
+SET registry_number = n.number
+FROM (SELECT m.id,
+             o.code || '-' || extract(YEAR FROM m.created_at)::int || '-' ||
+             lpad((row_number() OVER (PARTITION BY m.office_id, extract(YEAR FROM m.created_at)
+                 ORDER BY m.created_at, m.id))::text, 6, '0') AS number
+      FROM marriage m
+               JOIN office o ON o.id = m.office_id) n
+WHERE n.id = r.id;
+
+UPDATE death_record r
+SET registry_number = n.number
+FROM (SELECT d.id,
+             o.code || '-' || extract(YEAR FROM d.registered_at)::int || '-' ||
+             lpad((row_number() OVER (PARTITION BY d.office_id, extract(YEAR FROM d.registered_at)
+                 ORDER BY d.registered_at, d.id))::text, 6, '0') AS number
+      FROM death_record d
+               JOIN office o ON o.id = d.office_id) n
+WHERE n.id = r.id;
+
+INSERT INTO registry_number_sequence (office_id, record_kind, year, last_number)
+SELECT office_id, 'birth', extract(YEAR FROM registered_at)::int, count(*)
+FROM birth_record
+WHERE office_id IS NOT NULL
+GROUP BY 1, 3
+UNION ALL
+SELECT office_id, 'marriage', extract(YEAR FROM created_at)::int, count(*)
+FROM marriage
+WHERE office_id IS NOT NULL
+GROUP BY 1, 3
+UNION ALL
+SELECT office_id, 'death', extract(YEAR FROM registered_at)::int, count(*)
+FROM death_record
+WHERE office_id IS NOT NULL
+GROUP BY 1, 3;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE death_record DROP COLUMN IF EXISTS registry_number;
+ALTER TABLE marriage DROP COLUMN IF EXISTS registry_number;
+ALTER TABLE birth_record DROP COLUMN IF EXISTS registry_number;
+DROP TABLE IF EXISTS registry_number_sequence;
+-- +goose StatementEnd
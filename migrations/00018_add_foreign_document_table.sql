@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Source documents of births, marriages and deaths that took place abroad
+-- and were transcribed into the register. country is where the event took
+-- place and the document was issued. Documents in a language other than
+-- Lithuanian come with a certified translation; unless the issuing country
+-- is exempt, they also carry an apostille or consular legalisation.
+CREATE TABLE foreign_document
+(
+    id                     UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    country                TEXT        NOT NULL CHECK (country ~ '^[A-Z]{2}$' AND country <> 'LT'),
+    issuing_authority      TEXT        NOT NULL CHECK (issuing_authority <> ''),
+    document_number        TEXT        NOT NULL CHECK (document_number <> ''),
+    issued_on              DATE        NOT NULL,
+    document_language      TEXT        NOT NULL CHECK (document_language ~ '^[a-z]{2}$'),
+    legalisation           TEXT        NOT NULL CHECK (legalisation IN ('apostille', 'legalisation', 'exempt')),
+    legalisation_authority TEXT CHECK (legalisation_authority <> ''),
+    legalisation_number    TEXT CHECK (legalisation_number <> ''),
+    legalised_on           DATE,
+    translator             TEXT CHECK (translator <> ''),
+    translated_on          DATE,
+    created_at             TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((legalisation = 'exempt') = (legalisation_authority IS NULL)
+        AND (legalisation = 'exempt') = (legalisation_number IS NULL)
+        AND (legalisation = 'exempt') = (legalised_on IS NULL)),
+    CHECK ((document_language = 'lt') = (translator IS NULL)
+        AND (document_language = 'lt') = (translated_on IS NULL))
+);
+
+CREATE INDEX foreign_document_country_idx ON foreign_document (country);
+
+-- A record transcribed from abroad points at its source document; records
+-- of events in Lithuania have none
+ALTER TABLE birth_record
+    ADD COLUMN foreign_document_id UUID UNIQUE REFERENCES foreign_document (id);
+ALTER TABLE marriage
+    ADD COLUMN foreign_document_id UUID UNIQUE REFERENCES foreign_document (id);
+ALTER TABLE death_record
+    ADD COLUMN foreign_document_id UUID UNIQUE REFERENCES foreign_document (id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE death_record
+    DROP COLUMN IF EXISTS foreign_document_id;
+ALTER TABLE marriage
+    DROP COLUMN IF EXISTS foreign_document_id;
+ALTER TABLE birth_record
+    DROP COLUMN IF EXISTS foreign_document_id;
+DROP TABLE IF EXISTS foreign_document;
+-- +goose StatementEnd
@@ -0,0 +1,101 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Supervisors are registrars who may open sealed records
+ALTER TABLE registrar
+    DROP CONSTRAINT registrar_role_check,
+    ADD CONSTRAINT registrar_role_check CHECK (role IN ('registrar', 'supervisor', 'admin'));
+
+-- An adoption makes the adoptive parents the person's legal parents. The
+-- birth record keeps the birth parents, sealed: only supervisors and admins
+-- may see them. A NULL adoptive parent leaves that side without a legal
+-- parent; in a step-parent adoption the parent who stays is named again.
+-- A revoked adoption gives the birth parents back.
+CREATE TABLE adoption
+(
+    id                  UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    person_id           UUID        NOT NULL REFERENCES person (id),
+    adoptive_mother_id  UUID REFERENCES person (id),
+    adoptive_father_id  UUID REFERENCES person (id),
+    court               TEXT        NOT NULL CHECK (court <> ''),
+    decision_number     TEXT        NOT NULL CHECK (decision_number <> ''),
+    decided_on          DATE        NOT NULL,
+    status              TEXT        NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'revoked')),
+    revoked_on          DATE,
+    registration_office TEXT        NOT NULL,
+    registrar           TEXT        NOT NULL,
+    office_id           UUID REFERENCES office (id),
+    registrar_id        UUID REFERENCES registrar (id),
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (adoptive_mother_id IS NOT NULL OR adoptive_father_id IS NOT NULL),
+    CHECK (adoptive_mother_id <> person_id AND adoptive_father_id <> person_id),
+    CHECK (adoptive_mother_id <> adoptive_father_id),
+    CHECK ((status = 'revoked') = (revoked_on IS NOT NULL))
+);
+
+CREATE UNIQUE INDEX adoption_active_person_idx ON adoption (person_id) WHERE status = 'active';
+CREATE INDEX adoption_adoptive_mother_id_idx ON adoption (adoptive_mother_id);
+CREATE INDEX adoption_adoptive_father_id_idx ON adoption (adoptive_father_id);
+
+-- Every opening of a sealed birth record, with who opened it and why
+CREATE TABLE sealed_record_access
+(
+    id           UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    adoption_id  UUID        NOT NULL REFERENCES adoption (id),
+    registrar_id UUID REFERENCES registrar (id),
+    accessed_by  TEXT        NOT NULL,
+    reason       TEXT        NOT NULL CHECK (reason <> ''),
+    accessed_at  TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX sealed_record_access_adoption_id_idx ON sealed_record_access (adoption_id);
+
+-- The parents a person has in law: the adoptive parents of an active
+-- adoption, otherwise those on the birth record. Kinship and certificates
+-- read parentage from here.
+CREATE VIEW legal_parentage AS
+SELECT b.person_id,
+       m.id AS mother_id,
+       f.id AS father_id,
+       a.id AS adoption_id
+FROM birth_record b
+         LEFT JOIN adoption a ON a.person_id = b.person_id AND a.status = 'active'
+         LEFT JOIN person m ON m.id = CASE WHEN a.id IS NULL THEN b.mother_id ELSE a.adoptive_mother_id END
+         LEFT JOIN person f ON f.id = CASE WHEN a.id IS NULL THEN b.father_id ELSE a.adoptive_father_id END;
+
+-- A guardian, or a curator for an adult of limited capacity, appointed by
+-- a court. It covers [start_date, end_date) and does not change parentage.
+CREATE TABLE guardianship
+(
+    id                  UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    ward_id             UUID        NOT NULL REFERENCES person (id),
+    guardian_id         UUID        NOT NULL REFERENCES person (id),
+    kind                TEXT        NOT NULL CHECK (kind IN ('guardianship', 'curatorship')),
+    court               TEXT        NOT NULL CHECK (court <> ''),
+    decision_number     TEXT        NOT NULL CHECK (decision_number <> ''),
+    decided_on          DATE        NOT NULL,
+    start_date          DATE        NOT NULL,
+    end_date            DATE,
+    registration_office TEXT        NOT NULL,
+    registrar           TEXT        NOT NULL,
+    office_id           UUID REFERENCES office (id),
+    registrar_id        UUID REFERENCES registrar (id),
+    created_at          TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK (ward_id <> guardian_id),
+    CHECK (end_date > start_date)
+);
+
+CREATE INDEX guardianship_ward_id_idx ON guardianship (ward_id);
+CREATE INDEX guardianship_guardian_id_idx ON guardianship (guardian_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS guardianship;
+DROP VIEW IF EXISTS legal_parentage;
+DROP TABLE IF EXISTS sealed_record_access;
+DROP TABLE IF EXISTS adoption;
+UPDATE registrar SET role = 'registrar' WHERE role = 'supervisor';
+ALTER TABLE registrar
+    DROP CONSTRAINT registrar_role_check,
+    ADD CONSTRAINT registrar_role_check CHECK (role IN ('registrar', 'admin'));
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A person who asked to restrict processing of their data. The registry
+-- keeps recording their civil status as the law requires, but leaves them
+-- out of searches and duplicate detection until the restriction is lifted.
+ALTER TABLE person
+    ADD COLUMN processing_restricted_at TIMESTAMPTZ;
+
+-- Requests a person made about their own data and how each was handled:
+-- a copy of everything held (access), a restriction and its lifting, or
+-- erasure. outcome holds what was exported, erased or retained and why.
+-- Exports keep the SHA-256 of their manifest and its signature so a copy
+-- can be checked against the register later.
+CREATE TABLE data_subject_request
+(
+    id              UUID PRIMARY KEY     DEFAULT uuid_generate_v4(),
+    person_id       UUID        NOT NULL REFERENCES person (id),
+    kind            TEXT        NOT NULL CHECK (kind IN ('access', 'restriction', 'lift_restriction', 'erasure')),
+    reason          TEXT        NOT NULL DEFAULT '',
+    outcome         JSONB       NOT NULL DEFAULT '{}',
+    manifest_sha256 TEXT CHECK (manifest_sha256 ~ '^[0-9a-f]{64}$'),
+    signature       TEXT,
+    handled_by      TEXT        NOT NULL,
+    office_id       UUID REFERENCES office (id),
+    registrar_id    UUID REFERENCES registrar (id),
+    created_at      TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CHECK ((kind = 'access') = (manifest_sha256 IS NOT NULL)),
+    CHECK ((manifest_sha256 IS NULL) = (signature IS NULL))
+);
+
+CREATE INDEX data_subject_request_person_id_idx ON data_subject_request (person_id, created_at);
+CREATE INDEX application_personal_code_idx ON application (personal_code);
+CREATE INDEX appointment_personal_code_idx ON appointment (personal_code);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS appointment_personal_code_idx;
+DROP INDEX IF EXISTS application_personal_code_idx;
+DROP TABLE IF EXISTS data_subject_request;
+ALTER TABLE person
+    DROP COLUMN IF EXISTS processing_restricted_at;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The registrar who revoked a certificate. Revocations made before
+-- registrars signed in keep only the revoked_by name.
+ALTER TABLE certificate
+    ADD COLUMN revoking_registrar_id UUID REFERENCES registrar (id),
+    ADD CONSTRAINT certificate_revoking_registrar_check
+        CHECK (revoking_registrar_id IS NULL OR revoked_at IS NOT NULL);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE certificate
+    DROP CONSTRAINT IF EXISTS certificate_revoking_registrar_check,
+    DROP COLUMN IF EXISTS revoking_registrar_id;
+-- +goose StatementEnd
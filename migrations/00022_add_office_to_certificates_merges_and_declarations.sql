@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The office and registrar who issued a certificate, merged two persons or
+-- took a residence declaration. Rows made before registrars signed in keep
+-- only the free-text name.
+ALTER TABLE certificate
+    ADD COLUMN office_id    UUID REFERENCES office (id),
+    ADD COLUMN registrar_id UUID REFERENCES registrar (id);
+ALTER TABLE person_merge
+    ADD COLUMN office_id    UUID REFERENCES office (id),
+    ADD COLUMN registrar_id UUID REFERENCES registrar (id);
+ALTER TABLE residence_declaration
+    ADD COLUMN office_id    UUID REFERENCES office (id),
+    ADD COLUMN registrar_id UUID REFERENCES registrar (id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE residence_declaration
+    DROP COLUMN IF EXISTS registrar_id,
+    DROP COLUMN IF EXISTS office_id;
+ALTER TABLE person_merge
+    DROP COLUMN IF EXISTS registrar_id,
+    DROP COLUMN IF EXISTS office_id;
+ALTER TABLE certificate
+    DROP COLUMN IF EXISTS registrar_id,
+    DROP COLUMN IF EXISTS office_id;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+-- The office and registrar who entered a person or added an address. Rows
+-- made before registrars signed in have neither.
+ALTER TABLE person
+    ADD COLUMN office_id    UUID REFERENCES office (id),
+    ADD COLUMN registrar_id UUID REFERENCES registrar (id);
+ALTER TABLE person_address
+    ADD COLUMN office_id    UUID REFERENCES office (id),
+    ADD COLUMN registrar_id UUID REFERENCES registrar (id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE person_address
+    DROP COLUMN IF EXISTS registrar_id,
+    DROP COLUMN IF EXISTS office_id;
+ALTER TABLE person
+    DROP COLUMN IF EXISTS registrar_id,
+    DROP COLUMN IF EXISTS office_id;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Applications and appointments name a person by personal code, so merges
+-- made before they were re-keyed left them on the duplicate's code. Move
+-- them to the code of the person each duplicate was finally merged into.
+WITH RECURSIVE chain AS (
+    SELECT id AS duplicate_id, merged_into AS survivor_id
+    FROM person
+    WHERE merged_into IS NOT NULL
+    UNION ALL
+    SELECT c.duplicate_id, p.merged_into
+    FROM chain c
+             JOIN person p ON p.id = c.survivor_id
+    WHERE p.merged_into IS NOT NULL
+),
+     final AS (
+         SELECT d.personal_code AS duplicate_code, s.personal_code AS survivor_code
+         FROM chain c
+                  JOIN person d ON d.id = c.duplicate_id
+                  JOIN person s ON s.id = c.survivor_id
+         WHERE s.merged_into IS NULL
+     ),
+     applications AS (
+         UPDATE application a
+             SET personal_code = f.survivor_code,
+                 updated_at = now()
+             FROM final f
+             WHERE a.personal_code = f.duplicate_code
+             RETURNING a.id
+     )
+UPDATE appointment a
+SET personal_code = f.survivor_code
+FROM final f
+WHERE a.personal_code = f.duplicate_code;
+-- +goose StatementEnd
+
+-- +goose Down
+-- The re-keyed rows cannot be told apart from the survivor's own, so they
+-- stay where they are.
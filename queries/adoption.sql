@@ -0,0 +1,81 @@
+-- name: CreateAdoption :one
+INSERT INTO adoption (person_id, adoptive_mother_id, adoptive_father_id, court, decision_number, decided_on,
+                      registration_office, registrar, office_id, registrar_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: GetAdoptionByID :one
+SELECT * FROM adoption
+WHERE id = $1;
+
+-- name: GetAdoptionForUpdate :one
+SELECT * FROM adoption
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListAdoptionsForPerson :many
+-- Adoptions of the person, and those that made them an adoptive parent.
+SELECT * FROM adoption
+WHERE person_id = sqlc.arg(person_id)
+   OR adoptive_mother_id = sqlc.arg(person_id)
+   OR adoptive_father_id = sqlc.arg(person_id)
+ORDER BY decided_on DESC, id;
+
+-- name: RevokeAdoption :one
+UPDATE adoption
+SET status     = 'revoked',
+    revoked_on = $2
+WHERE id = $1
+  AND status = 'active'
+RETURNING *;
+
+-- name: GetLegalParentage :one
+SELECT * FROM legal_parentage
+WHERE person_id = $1;
+
+-- name: ListLegalParentage :many
+SELECT * FROM legal_parentage
+WHERE person_id = ANY (sqlc.arg(person_ids)::uuid[]);
+
+-- name: CreateSealedRecordAccess :one
+INSERT INTO sealed_record_access (adoption_id, registrar_id, accessed_by, reason)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: ListSealedRecordAccess :many
+SELECT * FROM sealed_record_access
+WHERE adoption_id = $1
+ORDER BY accessed_at DESC, id;
+
+-- name: CreateGuardianship :one
+INSERT INTO guardianship (ward_id, guardian_id, kind, court, decision_number, decided_on, start_date,
+                          registration_office, registrar, office_id, registrar_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING *;
+
+-- name: GetGuardianshipForUpdate :one
+SELECT * FROM guardianship
+WHERE id = $1
+FOR UPDATE;
+
+-- name: ListGuardianshipsForPerson :many
+-- Guardianships over the person, and those in which they are the guardian.
+SELECT * FROM guardianship
+WHERE ward_id = sqlc.arg(person_id)
+   OR guardian_id = sqlc.arg(person_id)
+ORDER BY start_date DESC, id;
+
+-- name: CountOverlappingGuardianships :one
+-- Open or later-ending guardianships of the same guardian over the ward
+-- that overlap a new one starting on start_date.
+SELECT count(*) FROM guardianship
+WHERE ward_id = $1
+  AND guardian_id = $2
+  AND (end_date IS NULL OR end_date > sqlc.arg(start_date));
+
+-- name: EndGuardianship :one
+UPDATE guardianship
+SET end_date = $2
+WHERE id = $1
+  AND end_date IS NULL
+RETURNING *;
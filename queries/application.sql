@@ -0,0 +1,55 @@
+-- name: CreateApplication :one
+-- Numbers look like APP-2026-000123; the sequence runs across years.
+INSERT INTO application (number, kind, office_id, applicant_name, applicant_email, applicant_phone, personal_code, details)
+VALUES ('APP-' || to_char(now(), 'YYYY') || '-' || lpad(nextval('application_number_seq')::text, 6, '0'),
+        $1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetApplicationByID :one
+SELECT * FROM application
+WHERE id = $1;
+
+-- name: GetApplicationByNumber :one
+SELECT * FROM application
+WHERE number = $1;
+
+-- name: GetApplicationForUpdate :one
+SELECT * FROM application
+WHERE id = $1
+FOR UPDATE;
+
+-- name: SetApplicationStatus :one
+UPDATE application
+SET status      = sqlc.arg(status),
+    assigned_to = coalesce(sqlc.narg(assigned_to), assigned_to),
+    decided_at  = CASE WHEN sqlc.arg(status)::text IN ('approved', 'rejected') THEN now() END,
+    updated_at  = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListApplicationQueue :many
+-- An office's applications, oldest first. assigned_to filters on the
+-- registrar; unassigned keeps applications nobody has picked up.
+SELECT * FROM application
+WHERE office_id = sqlc.arg(office_id)
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status))
+  AND (sqlc.narg(kind)::text IS NULL OR kind = sqlc.narg(kind))
+  AND (sqlc.narg(assigned_to)::uuid IS NULL OR assigned_to = sqlc.narg(assigned_to))
+  AND (NOT sqlc.arg(unassigned)::boolean OR assigned_to IS NULL)
+ORDER BY submitted_at, number
+LIMIT sqlc.arg(row_limit) OFFSET sqlc.arg(row_offset);
+
+-- name: CountApplicationsByStatus :many
+SELECT status, count(*) AS count FROM application
+WHERE office_id = $1
+GROUP BY status;
+
+-- name: CreateApplicationStatusChange :one
+INSERT INTO application_status_history (application_id, from_status, to_status, note, changed_by)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: ListApplicationStatusHistory :many
+SELECT * FROM application_status_history
+WHERE application_id = $1
+ORDER BY changed_at, id;
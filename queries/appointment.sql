@@ -0,0 +1,109 @@
+-- name: CreateAppointmentSlot :one
+INSERT INTO appointment_slot (office_id, kind, starts_at, ends_at, capacity, created_by)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetAppointmentSlotByID :one
+SELECT * FROM appointment_slot
+WHERE id = $1;
+
+-- name: GetAppointmentSlotForUpdate :one
+SELECT * FROM appointment_slot
+WHERE id = $1
+FOR UPDATE;
+
+-- name: SetAppointmentSlotBooked :one
+UPDATE appointment_slot
+SET booked = sqlc.arg(booked)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: DeleteUnusedAppointmentSlot :execrows
+-- Removes a slot nobody has booked, not even with a cancelled booking.
+DELETE FROM appointment_slot s
+WHERE s.id = $1
+  AND NOT EXISTS (SELECT 1 FROM appointment a WHERE a.slot_id = s.id);
+
+-- name: ListAvailableAppointmentSlots :many
+-- Future slots with free places in active offices.
+SELECT s.* FROM appointment_slot s
+JOIN office o ON o.id = s.office_id
+WHERE o.active
+  AND s.booked < s.capacity
+  AND s.starts_at > now()
+  AND s.starts_at >= sqlc.arg(from_time)
+  AND s.starts_at < sqlc.arg(to_time)
+  AND (sqlc.narg(office_id)::uuid IS NULL OR s.office_id = sqlc.narg(office_id))
+  AND (sqlc.narg(kind)::text IS NULL OR s.kind = sqlc.narg(kind))
+ORDER BY s.starts_at, o.code
+LIMIT sqlc.arg(row_limit);
+
+-- name: ListAppointmentSlotsForOffice :many
+SELECT * FROM appointment_slot
+WHERE office_id = sqlc.arg(office_id)
+  AND starts_at >= sqlc.arg(from_time)
+  AND starts_at < sqlc.arg(to_time)
+ORDER BY starts_at, kind;
+
+-- name: CreateAppointment :one
+INSERT INTO appointment (slot_id, reference, full_name, email, phone, personal_code, notes, starts_at, ends_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetAppointmentByReference :one
+SELECT * FROM appointment
+WHERE reference = $1;
+
+-- name: GetAppointmentForUpdate :one
+SELECT * FROM appointment
+WHERE id = $1
+FOR UPDATE;
+
+-- name: CancelAppointment :one
+UPDATE appointment
+SET status       = 'cancelled',
+    cancelled_at = now(),
+    cancelled_by = sqlc.arg(cancelled_by)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: ListBookedAppointmentsForOffice :many
+SELECT a.* FROM appointment a
+JOIN appointment_slot s ON s.id = a.slot_id
+WHERE s.office_id = sqlc.arg(office_id)
+  AND a.status = 'booked'
+  AND a.starts_at >= sqlc.arg(from_time)
+  AND a.starts_at < sqlc.arg(to_time)
+ORDER BY a.starts_at, a.booked_at;
+
+-- name: CreateDueAppointmentReminders :many
+-- Adds a reminder for every booked appointment starting before until that
+-- has none yet, so running it again is harmless.
+INSERT INTO appointment_reminder (appointment_id)
+SELECT a.id FROM appointment a
+WHERE a.status = 'booked'
+  AND a.starts_at > now()
+  AND a.starts_at <= sqlc.arg(until)
+ON CONFLICT (appointment_id) DO NOTHING
+RETURNING *;
+
+-- name: ListPendingAppointmentReminders :many
+SELECT sqlc.embed(r), sqlc.embed(a) FROM appointment_reminder r
+JOIN appointment a ON a.id = r.appointment_id
+JOIN appointment_slot s ON s.id = a.slot_id
+WHERE s.office_id = $1
+  AND r.sent_at IS NULL
+  AND a.status = 'booked'
+  AND a.starts_at > now()
+ORDER BY a.starts_at;
+
+-- name: MarkAppointmentReminderSent :one
+UPDATE appointment_reminder r
+SET sent_at = now()
+FROM appointment a, appointment_slot s
+WHERE r.id = sqlc.arg(id)
+  AND r.sent_at IS NULL
+  AND a.id = r.appointment_id
+  AND s.id = a.slot_id
+  AND s.office_id = sqlc.arg(office_id)
+RETURNING r.*;
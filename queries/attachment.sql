@@ -0,0 +1,38 @@
+-- name: LockAttachmentBlob :exec
+-- Serializes uploads and deletions of the same content so a blob is never
+-- removed while a new attachment starts using it.
+SELECT pg_advisory_xact_lock(hashtextextended(sqlc.arg(sha256)::text, 0));
+
+-- name: LockAttachmentOwner :exec
+-- Serializes uploads to the same application or record so concurrent ones
+-- cannot together go past the per-owner limit.
+SELECT pg_advisory_xact_lock(hashtextextended(sqlc.arg(owner_kind)::text || '/' || sqlc.arg(owner_id)::uuid::text, 1));
+
+-- name: CreateAttachment :one
+INSERT INTO attachment (owner_kind, owner_id, office_id, filename, content_type, size_bytes, sha256, uploaded_by)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetAttachmentByID :one
+SELECT * FROM attachment
+WHERE id = $1;
+
+-- name: ListAttachmentsByOwner :many
+SELECT * FROM attachment
+WHERE owner_kind = $1
+  AND owner_id = $2
+ORDER BY uploaded_at, id;
+
+-- name: CountAttachmentsByOwner :one
+SELECT count(*) FROM attachment
+WHERE owner_kind = $1
+  AND owner_id = $2;
+
+-- name: DeleteAttachment :one
+DELETE FROM attachment
+WHERE id = $1
+RETURNING *;
+
+-- name: CountAttachmentsBySHA256 :one
+SELECT count(*) FROM attachment
+WHERE sha256 = $1;
@@ -0,0 +1,28 @@
+-- name: CreateBirthRecord :one
+INSERT INTO birth_record (person_id, mother_id, father_id, birth_place, registration_office, registrar,
+                          office_id, registrar_id, registry_number, foreign_document_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+RETURNING *;
+
+-- name: GetBirthRecordByRegistryNumber :one
+SELECT * FROM birth_record
+WHERE registry_number = $1;
+
+-- name: GetBirthRecordByID :one
+SELECT * FROM birth_record
+WHERE id = $1;
+
+-- name: GetBirthRecordByPersonID :one
+SELECT * FROM birth_record
+WHERE person_id = $1;
+
+-- name: ListBirthRecords :many
+-- origin is domestic or foreign; country keeps births abroad in that
+-- country.
+SELECT * FROM birth_record
+WHERE (sqlc.narg(origin)::text IS NULL
+    OR (sqlc.narg(origin) = 'foreign') = (foreign_document_id IS NOT NULL))
+  AND (sqlc.narg(country)::text IS NULL
+    OR foreign_document_id IN (SELECT id FROM foreign_document WHERE country = sqlc.narg(country)))
+ORDER BY registered_at DESC, id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
@@ -0,0 +1,24 @@
+-- name: CreateCertificate :one
+INSERT INTO certificate (kind, record_id, person_id, content, issued_by, office_id, registrar_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetCertificateByID :one
+SELECT * FROM certificate
+WHERE id = $1;
+
+-- name: ListCertificatesForPerson :many
+SELECT * FROM certificate
+WHERE person_id = $1
+ORDER BY created_at DESC;
+
+-- name: RevokeCertificate :one
+-- Revoked certificates stay revoked; no row is returned for them.
+UPDATE certificate
+SET revoked_at            = now(),
+    revoked_by            = $2,
+    revocation_reason     = $3,
+    revoking_registrar_id = $4
+WHERE id = $1
+  AND revoked_at IS NULL
+RETURNING *;
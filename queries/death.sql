@@ -0,0 +1,29 @@
+-- name: CreateDeathRecord :one
+INSERT INTO death_record (person_id, date_of_death, place_of_death, cause_code,
+                          informant_name, informant_person_id, registration_office, registrar,
+                          office_id, registrar_id, registry_number, foreign_document_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+RETURNING *;
+
+-- name: GetDeathRecordByRegistryNumber :one
+SELECT * FROM death_record
+WHERE registry_number = $1;
+
+-- name: GetDeathRecordByID :one
+SELECT * FROM death_record
+WHERE id = $1;
+
+-- name: GetDeathRecordByPersonID :one
+SELECT * FROM death_record
+WHERE person_id = $1;
+
+-- name: ListDeathRecords :many
+-- origin is domestic or foreign; country keeps deaths abroad in that
+-- country.
+SELECT * FROM death_record
+WHERE (sqlc.narg(origin)::text IS NULL
+    OR (sqlc.narg(origin) = 'foreign') = (foreign_document_id IS NOT NULL))
+  AND (sqlc.narg(country)::text IS NULL
+    OR foreign_document_id IN (SELECT id FROM foreign_document WHERE country = sqlc.narg(country)))
+ORDER BY registered_at DESC, id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
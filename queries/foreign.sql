@@ -0,0 +1,10 @@
+-- name: CreateForeignDocument :one
+INSERT INTO foreign_document (country, issuing_authority, document_number, issued_on, document_language,
+                              legalisation, legalisation_authority, legalisation_number, legalised_on,
+                              translator, translated_on)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING *;
+
+-- name: GetForeignDocumentByID :one
+SELECT * FROM foreign_document
+WHERE id = $1;
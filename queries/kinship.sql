@@ -0,0 +1,79 @@
+-- name: ListCommonAncestors :many
+-- Walks the legal parent links up from both persons and returns every
+-- ancestor they share, with the number of generations from each side. A
+-- person counts as their own ancestor at depth 0, so direct lines show up too.
+WITH RECURSIVE person_line (person_id, depth) AS (
+    SELECT sqlc.arg(person_id)::uuid, 0
+    UNION
+    SELECT parent.id, pl.depth + 1
+    FROM person_line pl
+             JOIN legal_parentage b ON b.person_id = pl.person_id
+             JOIN person parent ON parent.id IN (b.mother_id, b.father_id)
+    WHERE pl.depth < sqlc.arg(max_depth)::int
+),
+relative_line (person_id, depth) AS (
+    SELECT sqlc.arg(relative_id)::uuid, 0
+    UNION
+    SELECT parent.id, rl.depth + 1
+    FROM relative_line rl
+             JOIN legal_parentage b ON b.person_id = rl.person_id
+             JOIN person parent ON parent.id IN (b.mother_id, b.father_id)
+    WHERE rl.depth < sqlc.arg(max_depth)::int
+)
+SELECT pl.person_id AS ancestor_id,
+       min(pl.depth)::int AS person_depth,
+       min(rl.depth)::int AS relative_depth
+FROM person_line pl
+         JOIN relative_line rl ON rl.person_id = pl.person_id
+GROUP BY pl.person_id
+ORDER BY min(pl.depth) + min(rl.depth), pl.person_id;
+
+-- name: ListFamilyTree :many
+-- Collects a person's ancestors and descendants up to the given number of
+-- generations, plus everyone married to one of them. generation is positive
+-- for ancestors, negative for descendants and shared with the partner for
+-- spouses. mother_id and father_id are the member's legal parents.
+WITH RECURSIVE ancestors (person_id, generation) AS (
+    SELECT sqlc.arg(person_id)::uuid, 0
+    UNION
+    SELECT parent.id, a.generation + 1
+    FROM ancestors a
+             JOIN legal_parentage b ON b.person_id = a.person_id
+             JOIN person parent ON parent.id IN (b.mother_id, b.father_id)
+    WHERE a.generation < sqlc.arg(generations)::int
+),
+descendants (person_id, generation) AS (
+    SELECT sqlc.arg(person_id)::uuid, 0
+    UNION
+    SELECT b.person_id, d.generation - 1
+    FROM descendants d
+             JOIN legal_parentage b ON d.person_id IN (b.mother_id, b.father_id)
+    WHERE d.generation > -sqlc.arg(generations)::int
+),
+blood AS (
+    SELECT person_id, generation FROM ancestors
+    UNION
+    SELECT person_id, generation FROM descendants
+),
+members AS (
+    SELECT person_id, generation FROM blood
+    UNION
+    SELECT CASE WHEN m.spouse1_id = bl.person_id THEN m.spouse2_id ELSE m.spouse1_id END,
+           bl.generation
+    FROM blood bl
+             JOIN marriage m ON bl.person_id IN (m.spouse1_id, m.spouse2_id)
+)
+SELECT DISTINCT ON (p.id) sqlc.embed(p),
+       mb.generation::int AS generation,
+       b.mother_id,
+       b.father_id
+FROM members mb
+         JOIN person p ON p.id = mb.person_id
+         LEFT JOIN legal_parentage b ON b.person_id = p.id
+ORDER BY p.id, abs(mb.generation);
+
+-- name: ListMarriagesAmongPersons :many
+SELECT * FROM marriage
+WHERE spouse1_id = ANY (sqlc.arg(person_ids)::uuid[])
+  AND spouse2_id = ANY (sqlc.arg(person_ids)::uuid[])
+ORDER BY registered_on, id;
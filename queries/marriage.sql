@@ -0,0 +1,51 @@
+-- name: CreateMarriage :one
+INSERT INTO marriage (spouse1_id, spouse2_id, registered_on, registration_office, registrar,
+                      spouse1_previous_name, spouse2_previous_name,
+                      spouse1_previous_status, spouse2_previous_status,
+                      spouse1_new_name, spouse2_new_name, office_id, registrar_id, registry_number,
+                      foreign_document_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+RETURNING *;
+
+-- name: GetMarriageByRegistryNumber :one
+SELECT * FROM marriage
+WHERE registry_number = $1;
+
+-- name: GetMarriageByID :one
+SELECT * FROM marriage
+WHERE id = $1;
+
+-- name: GetMarriageForUpdate :one
+SELECT * FROM marriage
+WHERE id = $1
+FOR UPDATE;
+
+-- name: GetActiveMarriageForPerson :one
+SELECT * FROM marriage
+WHERE status = 'active'
+  AND (spouse1_id = $1 OR spouse2_id = $1);
+
+-- name: ListMarriagesForPerson :many
+SELECT * FROM marriage
+WHERE spouse1_id = $1 OR spouse2_id = $1
+ORDER BY registered_on DESC, id;
+
+-- name: ListMarriages :many
+-- origin is domestic or foreign; country keeps marriages abroad in that
+-- country.
+SELECT * FROM marriage
+WHERE (sqlc.narg(origin)::text IS NULL
+    OR (sqlc.narg(origin) = 'foreign') = (foreign_document_id IS NOT NULL))
+  AND (sqlc.narg(country)::text IS NULL
+    OR foreign_document_id IN (SELECT id FROM foreign_document WHERE country = sqlc.narg(country)))
+ORDER BY registered_on DESC, id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: EndMarriage :one
+UPDATE marriage
+SET status     = $2,
+    ended_on   = $3,
+    updated_at = now()
+WHERE id = $1
+  AND status = 'active'
+RETURNING *;
@@ -0,0 +1,117 @@
+-- name: ListDuplicateCandidates :many
+-- Pairs of unmerged persons that share a birth date and a first or last name,
+-- or share both names, with diacritics folded. Without person_id each pair
+-- comes once; with it, only that person's pairs. Persons who restricted
+-- processing of their data are left out.
+WITH pairs AS (
+    SELECT a.id AS person_id, b.id AS candidate_id
+    FROM person a
+             JOIN person b ON b.birth_date = a.birth_date AND b.id <> a.id
+    WHERE fold_name(a.first_name) = fold_name(b.first_name)
+       OR fold_name(a.last_name) = fold_name(b.last_name)
+    UNION
+    SELECT a.id, b.id
+    FROM person a
+             JOIN person b ON fold_name(b.last_name) = fold_name(a.last_name)
+        AND fold_name(b.first_name) = fold_name(a.first_name)
+        AND b.id <> a.id
+)
+SELECT pairs.person_id, pairs.candidate_id
+FROM pairs
+         JOIN person a ON a.id = pairs.person_id
+         JOIN person b ON b.id = pairs.candidate_id
+WHERE a.merged_into IS NULL
+  AND b.merged_into IS NULL
+  AND a.processing_restricted_at IS NULL
+  AND b.processing_restricted_at IS NULL
+  AND CASE
+          WHEN sqlc.narg(person_id)::uuid IS NULL THEN pairs.person_id < pairs.candidate_id
+          ELSE pairs.person_id = sqlc.narg(person_id)::uuid
+    END
+ORDER BY pairs.person_id, pairs.candidate_id
+LIMIT sqlc.arg('limit');
+
+-- name: ListPersonsByIDs :many
+SELECT * FROM person
+WHERE id = ANY (sqlc.arg(ids)::uuid[]);
+
+-- name: RepointBirthRecordPerson :execrows
+UPDATE birth_record SET person_id = sqlc.arg(survivor_id) WHERE person_id = sqlc.arg(duplicate_id);
+
+-- name: RepointBirthRecordMother :execrows
+UPDATE birth_record SET mother_id = sqlc.arg(survivor_id) WHERE mother_id = sqlc.arg(duplicate_id);
+
+-- name: RepointBirthRecordFather :execrows
+UPDATE birth_record SET father_id = sqlc.arg(survivor_id) WHERE father_id = sqlc.arg(duplicate_id);
+
+-- name: RepointMarriageSpouse1 :execrows
+UPDATE marriage SET spouse1_id = sqlc.arg(survivor_id), updated_at = now() WHERE spouse1_id = sqlc.arg(duplicate_id);
+
+-- name: RepointMarriageSpouse2 :execrows
+UPDATE marriage SET spouse2_id = sqlc.arg(survivor_id), updated_at = now() WHERE spouse2_id = sqlc.arg(duplicate_id);
+
+-- name: RepointDeathRecordPerson :execrows
+UPDATE death_record SET person_id = sqlc.arg(survivor_id) WHERE person_id = sqlc.arg(duplicate_id);
+
+-- name: RepointDeathRecordInformant :execrows
+UPDATE death_record SET informant_person_id = sqlc.arg(survivor_id) WHERE informant_person_id = sqlc.arg(duplicate_id);
+
+-- name: RepointCertificates :execrows
+UPDATE certificate SET person_id = sqlc.arg(survivor_id) WHERE person_id = sqlc.arg(duplicate_id);
+
+-- name: RepointPersonAddresses :execrows
+UPDATE person_address SET person_id = sqlc.arg(survivor_id) WHERE person_id = sqlc.arg(duplicate_id);
+
+-- name: RepointResidenceDeclarations :execrows
+UPDATE residence_declaration SET person_id = sqlc.arg(survivor_id) WHERE person_id = sqlc.arg(duplicate_id);
+
+-- name: RepointAdoptionPerson :execrows
+UPDATE adoption SET person_id = sqlc.arg(survivor_id) WHERE person_id = sqlc.arg(duplicate_id);
+
+-- name: RepointAdoptiveMother :execrows
+UPDATE adoption SET adoptive_mother_id = sqlc.arg(survivor_id) WHERE adoptive_mother_id = sqlc.arg(duplicate_id);
+
+-- name: RepointAdoptiveFather :execrows
+UPDATE adoption SET adoptive_father_id = sqlc.arg(survivor_id) WHERE adoptive_father_id = sqlc.arg(duplicate_id);
+
+-- name: RepointGuardianshipWard :execrows
+UPDATE guardianship SET ward_id = sqlc.arg(survivor_id) WHERE ward_id = sqlc.arg(duplicate_id);
+
+-- name: RepointGuardianshipGuardian :execrows
+UPDATE guardianship SET guardian_id = sqlc.arg(survivor_id) WHERE guardian_id = sqlc.arg(duplicate_id);
+
+-- name: RepointDataSubjectRequests :execrows
+UPDATE data_subject_request SET person_id = sqlc.arg(survivor_id) WHERE person_id = sqlc.arg(duplicate_id);
+
+-- name: RekeyApplications :execrows
+-- Applications name the person by personal code rather than by ID.
+UPDATE application
+SET personal_code = (SELECT p.personal_code FROM person p WHERE p.id = sqlc.arg(survivor_id)::uuid),
+    updated_at    = now()
+WHERE personal_code = (SELECT p.personal_code FROM person p WHERE p.id = sqlc.arg(duplicate_id)::uuid);
+
+-- name: RekeyAppointments :execrows
+-- Appointments name the person by personal code, when one was given.
+UPDATE appointment
+SET personal_code = (SELECT p.personal_code FROM person p WHERE p.id = sqlc.arg(survivor_id)::uuid)
+WHERE personal_code = (SELECT p.personal_code FROM person p WHERE p.id = sqlc.arg(duplicate_id)::uuid);
+
+-- name: MarkPersonMerged :one
+UPDATE person
+SET merged_into = sqlc.arg(survivor_id),
+    version     = version + 1,
+    updated_at  = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreatePersonMerge :one
+INSERT INTO person_merge (survivor_id, duplicate_id, merged_by, reason, moved, duplicate_snapshot, office_id,
+                          registrar_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: ListPersonMerges :many
+-- Merges the person took part in, as survivor or as duplicate.
+SELECT * FROM person_merge
+WHERE survivor_id = $1 OR duplicate_id = $1
+ORDER BY merged_at, id;
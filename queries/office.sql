@@ -0,0 +1,89 @@
+-- name: CreateOffice :one
+INSERT INTO office (code, name, municipality)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: GetOfficeByID :one
+SELECT * FROM office
+WHERE id = $1;
+
+-- name: ListOffices :many
+SELECT * FROM office
+ORDER BY code;
+
+-- name: SetOfficeActive :one
+UPDATE office
+SET active = sqlc.arg(active)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateRegistrar :one
+INSERT INTO registrar (office_id, full_name, username, role, token_hash)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetRegistrarByID :one
+SELECT * FROM registrar
+WHERE id = $1;
+
+-- name: ListRegistrarsForOffice :many
+SELECT * FROM registrar
+WHERE office_id = $1
+ORDER BY full_name, id;
+
+-- name: SetRegistrarActive :one
+UPDATE registrar
+SET active = sqlc.arg(active)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: SetRegistrarToken :one
+UPDATE registrar
+SET token_hash = sqlc.arg(token_hash)
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: GetActiveRegistrarByTokenHash :one
+-- The registrar signing in with the token, with their office. Inactive
+-- registrars and registrars of closed offices cannot sign in.
+SELECT sqlc.embed(r), sqlc.embed(o)
+FROM registrar r
+         JOIN office o ON o.id = r.office_id
+WHERE r.token_hash = $1
+  AND r.active
+  AND o.active;
+
+-- name: CreateJurisdiction :one
+INSERT INTO office_jurisdiction (office_id, event_kind, place)
+VALUES ($1, $2, $3)
+RETURNING *;
+
+-- name: ListJurisdictionsForOffice :many
+SELECT * FROM office_jurisdiction
+WHERE office_id = $1
+ORDER BY event_kind, place NULLS FIRST;
+
+-- name: DeleteJurisdiction :execrows
+DELETE FROM office_jurisdiction
+WHERE id = sqlc.arg(id)
+  AND office_id = sqlc.arg(office_id);
+
+-- name: OfficeHasJurisdiction :one
+-- Whether the office may register the kind of event at the place. A NULL
+-- place (marriages, which take place at the office) matches any rule.
+SELECT EXISTS (SELECT 1
+               FROM office_jurisdiction
+               WHERE office_id = sqlc.arg(office_id)
+                 AND event_kind = sqlc.arg(event_kind)
+                 AND (place IS NULL
+                   OR sqlc.narg(place)::text IS NULL
+                   OR fold_name(place) = fold_name(sqlc.narg(place)))) AS allowed;
+
+-- name: NextRegistryNumber :one
+-- Numbers start at 1 each year. The caller rejects numbers past 999999,
+-- which rolls the bump back.
+INSERT INTO registry_number_sequence (office_id, record_kind, year, last_number)
+VALUES ($1, $2, $3, 1)
+ON CONFLICT (office_id, record_kind, year) DO UPDATE
+    SET last_number = registry_number_sequence.last_number + 1
+RETURNING last_number;
@@ -0,0 +1,92 @@
+-- name: CreatePerson :one
+INSERT INTO person (personal_code, first_name, last_name, birth_date, birth_place, sex, citizenship, office_id,
+                    registrar_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: GetPersonByID :one
+SELECT * FROM person
+WHERE id = $1;
+
+-- name: GetPersonByPersonalCode :one
+SELECT * FROM person
+WHERE personal_code = $1;
+
+-- name: SearchPersons :many
+-- Every filter is optional; name matches first or last name case-insensitively.
+-- Persons merged into another record, or who restricted processing of their
+-- data, are left out.
+SELECT * FROM person
+WHERE (sqlc.narg('name')::text IS NULL
+        OR first_name ILIKE '%' || sqlc.narg('name') || '%'
+        OR last_name ILIKE '%' || sqlc.narg('name') || '%')
+  AND (sqlc.narg('birth_date')::date IS NULL OR birth_date = sqlc.narg('birth_date'))
+  AND (sqlc.narg('status')::text IS NULL OR status = sqlc.narg('status'))
+  AND merged_into IS NULL
+  AND processing_restricted_at IS NULL
+ORDER BY last_name, first_name, id
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: UpdatePerson :one
+-- Deceased persons are frozen; no row is returned for them.
+UPDATE person
+SET first_name  = $2,
+    last_name   = $3,
+    birth_place = $4,
+    citizenship = $5,
+    version     = version + 1,
+    updated_at  = now()
+WHERE id = $1
+  AND status = 'alive'
+RETURNING *;
+
+-- name: AddPersonAddress :one
+INSERT INTO person_address (person_id, kind, line, city, postal_code, country, office_id, registrar_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: ListPersonAddresses :many
+SELECT * FROM person_address
+WHERE person_id = $1
+ORDER BY created_at;
+
+-- name: GetPersonForUpdate :one
+-- Locks the person row for the rest of the transaction.
+SELECT * FROM person
+WHERE id = $1
+FOR UPDATE;
+
+-- name: SetPersonMaritalStatus :one
+UPDATE person
+SET marital_status = $2,
+    last_name      = $3,
+    version        = version + 1,
+    updated_at     = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: SetPersonDeceased :one
+UPDATE person
+SET status     = 'deceased',
+    version    = version + 1,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: CreatePersonHistory :exec
+INSERT INTO person_history (person_id, attribute, old_value, new_value, effective_date, legal_basis, person_version)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: ListPersonHistory :many
+-- Oldest first; AsOf in the person service relies on this order.
+SELECT * FROM person_history
+WHERE person_id = $1
+ORDER BY effective_date, recorded_at, id;
+
+-- name: NextPersonalCodeSerial :one
+-- Serials start at 1. The caller rejects serials past 999, which rolls the bump back.
+INSERT INTO personal_code_sequence (birth_date, last_serial)
+VALUES ($1, 1)
+ON CONFLICT (birth_date) DO UPDATE
+    SET last_serial = personal_code_sequence.last_serial + 1
+RETURNING last_serial;
@@ -1,4 +1,5 @@
 -- name: GetPublicPosts :many
+-- genapi:stream
 SELECT * from post;
 
 -- name: CreatePost :one
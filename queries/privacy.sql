@@ -0,0 +1,75 @@
+-- name: SetPersonProcessingRestricted :one
+-- A NULL restricted_at lifts the restriction.
+UPDATE person
+SET processing_restricted_at = sqlc.narg(restricted_at),
+    updated_at               = now()
+WHERE id = sqlc.arg(id)
+RETURNING *;
+
+-- name: CreateDataSubjectRequest :one
+INSERT INTO data_subject_request (person_id, kind, reason, outcome, manifest_sha256, signature, handled_by, office_id,
+                                  registrar_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING *;
+
+-- name: ListDataSubjectRequests :many
+SELECT * FROM data_subject_request
+WHERE person_id = $1
+ORDER BY created_at, id;
+
+-- name: ListLegalChildren :many
+-- Persons whose legal parent the person is, adoptions included.
+SELECT p.*
+FROM legal_parentage lp
+         JOIN person p ON p.id = lp.person_id
+WHERE lp.mother_id = sqlc.arg(person_id)::uuid
+   OR lp.father_id = sqlc.arg(person_id)::uuid
+ORDER BY p.birth_date, p.id;
+
+-- name: ListDeathRecordsByInformant :many
+SELECT * FROM death_record
+WHERE informant_person_id = $1
+ORDER BY registered_at, id;
+
+-- name: ListApplicationsByPersonalCode :many
+SELECT * FROM application
+WHERE personal_code = $1
+ORDER BY submitted_at, id;
+
+-- name: ListAppointmentsByPersonalCode :many
+SELECT * FROM appointment
+WHERE personal_code = $1
+ORDER BY starts_at, id;
+
+-- name: DeletePersonAddresses :execrows
+DELETE FROM person_address
+WHERE person_id = $1;
+
+-- name: EraseApplication :exec
+-- Keeps the application's number, kind, office and status history dates for
+-- the statistics; everything identifying the applicant goes. The personal
+-- code is required, so a code of zeros stands in for it.
+UPDATE application
+SET applicant_name  = '[erased]',
+    applicant_email = '[erased]',
+    applicant_phone = '',
+    personal_code   = '00000000000',
+    details         = '{}',
+    updated_at      = now()
+WHERE id = $1;
+
+-- name: EraseApplicationStatusNotes :exec
+UPDATE application_status_history
+SET note = ''
+WHERE application_id = $1;
+
+-- name: EraseAppointment :exec
+-- The email stays unique per appointment so that erased bookings never
+-- trip the overlap constraint on email.
+UPDATE appointment
+SET full_name     = '[erased]',
+    email         = 'erased-' || id || '@invalid',
+    phone         = '',
+    personal_code = NULL,
+    notes         = ''
+WHERE id = $1;
@@ -0,0 +1,57 @@
+-- name: BirthReportRows :many
+-- Births by the child's date of birth and sex; the age group is the
+-- mother's age at the birth. Like the other report queries it counts at the
+-- finest grain reports break down by, and records made before offices were
+-- tracked report their registration office name and no municipality.
+SELECT date_trunc(sqlc.arg(period)::text, c.birth_date::timestamp)::date AS period,
+       coalesce(o.name, b.registration_office)::text                     AS office,
+       coalesce(o.municipality, '')::text                                AS municipality,
+       c.sex,
+       age_group(m.birth_date, c.birth_date)::text                       AS age_group,
+       count(*)                                                          AS events
+FROM birth_record b
+         JOIN person c ON c.id = b.person_id
+         LEFT JOIN person m ON m.id = b.mother_id
+         LEFT JOIN office o ON o.id = b.office_id
+WHERE c.birth_date BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date
+  AND (sqlc.narg(office_id)::uuid IS NULL OR b.office_id = sqlc.narg(office_id)::uuid)
+  AND (sqlc.narg(municipality)::text IS NULL OR fold_name(o.municipality) = fold_name(sqlc.narg(municipality)::text))
+GROUP BY 1, 2, 3, 4, 5
+ORDER BY 1;
+
+-- name: MarriageReportRows :many
+-- Marriages by registration date, one row per spouse with their sex and
+-- age at marriage, so events counts spouses. Annulled marriages are void
+-- and left out.
+SELECT date_trunc(sqlc.arg(period)::text, m.registered_on::timestamp)::date AS period,
+       coalesce(o.name, m.registration_office)::text                        AS office,
+       coalesce(o.municipality, '')::text                                   AS municipality,
+       s.sex,
+       age_group(s.birth_date, m.registered_on)::text                       AS age_group,
+       count(*)                                                             AS events
+FROM marriage m
+         JOIN person s ON s.id IN (m.spouse1_id, m.spouse2_id)
+         LEFT JOIN office o ON o.id = m.office_id
+WHERE m.status <> 'annulled'
+  AND m.registered_on BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date
+  AND (sqlc.narg(office_id)::uuid IS NULL OR m.office_id = sqlc.narg(office_id)::uuid)
+  AND (sqlc.narg(municipality)::text IS NULL OR fold_name(o.municipality) = fold_name(sqlc.narg(municipality)::text))
+GROUP BY 1, 2, 3, 4, 5
+ORDER BY 1;
+
+-- name: DeathReportRows :many
+-- Deaths by date of death, with the deceased's sex and age at death.
+SELECT date_trunc(sqlc.arg(period)::text, d.date_of_death::timestamp)::date AS period,
+       coalesce(o.name, d.registration_office)::text                        AS office,
+       coalesce(o.municipality, '')::text                                   AS municipality,
+       p.sex,
+       age_group(p.birth_date, d.date_of_death)::text                       AS age_group,
+       count(*)                                                             AS events
+FROM death_record d
+         JOIN person p ON p.id = d.person_id
+         LEFT JOIN office o ON o.id = d.office_id
+WHERE d.date_of_death BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date
+  AND (sqlc.narg(office_id)::uuid IS NULL OR d.office_id = sqlc.narg(office_id)::uuid)
+  AND (sqlc.narg(municipality)::text IS NULL OR fold_name(o.municipality) = fold_name(sqlc.narg(municipality)::text))
+GROUP BY 1, 2, 3, 4, 5
+ORDER BY 1;
@@ -0,0 +1,60 @@
+-- name: CreateAddress :one
+INSERT INTO address (municipality, street, house, flat, postal_code)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetAddressByID :one
+SELECT * FROM address
+WHERE id = $1;
+
+-- name: SearchAddresses :many
+-- Names are compared without case and Lithuanian diacritics; street matches
+-- by prefix.
+SELECT * FROM address
+WHERE (sqlc.narg(municipality)::text IS NULL OR fold_name(municipality) = fold_name(sqlc.narg(municipality)))
+  AND (sqlc.narg(street)::text IS NULL OR fold_name(street) LIKE fold_name(sqlc.narg(street)) || '%')
+  AND (sqlc.narg(postal_code)::text IS NULL OR postal_code = sqlc.narg(postal_code))
+ORDER BY municipality, street, house, flat
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CreateResidenceDeclaration :one
+INSERT INTO residence_declaration (person_id, address_id, start_date, declared_by, office_id, registrar_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: GetResidenceDeclarationByID :one
+SELECT * FROM residence_declaration
+WHERE id = $1;
+
+-- name: GetLatestResidenceDeclaration :one
+SELECT * FROM residence_declaration
+WHERE person_id = $1
+ORDER BY start_date DESC
+LIMIT 1;
+
+-- name: GetCurrentResidenceDeclaration :one
+SELECT * FROM residence_declaration
+WHERE person_id = $1
+  AND end_date IS NULL;
+
+-- name: EndResidenceDeclaration :one
+UPDATE residence_declaration
+SET end_date = sqlc.arg(end_date)
+WHERE id = sqlc.arg(id)
+  AND end_date IS NULL
+RETURNING *;
+
+-- name: ListResidenceDeclarationsForPerson :many
+SELECT * FROM residence_declaration
+WHERE person_id = $1
+ORDER BY start_date;
+
+-- name: ListResidentsAtAddress :many
+-- Persons whose declaration at the address covers the given day.
+SELECT sqlc.embed(d), sqlc.embed(p)
+FROM residence_declaration d
+         JOIN person p ON p.id = d.person_id
+WHERE d.address_id = sqlc.arg(address_id)
+  AND d.start_date <= sqlc.arg(day)::date
+  AND (d.end_date IS NULL OR d.end_date > sqlc.arg(day)::date)
+ORDER BY p.last_name, p.first_name, p.id;